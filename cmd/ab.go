@@ -0,0 +1,373 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/diff"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ratelimit"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/template"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	abProvider    string
+	abTemplateA   string
+	abTemplateB   string
+	abPromptA     string
+	abPromptB     string
+	abInputs      string
+	abJudge       string
+	abOutput      string
+	abConcurrency int
+	abRPM         int
+	abNoDiff      bool
+)
+
+// 评审结果
+const (
+	abWinA = "A"
+	abWinB = "B"
+	abTie  = "tie"
+)
+
+// abCmd 比较两个提示词或模板的效果
+var abCmd = &cobra.Command{
+	Use:   "ab",
+	Short: "在同一组输入上比较两个提示词或模板",
+	Long: `对输入文件中的每条用例分别使用变体A和变体B生成回复，逐条显示两者的差异。
+指定 --judge 时由评审模型判断哪个回复更好，最后汇总两个变体的胜率。
+
+变体可以是模板（--template-a、--template-b，名称或YAML文件路径），也可以是直接给出的
+提示词（--prompt-a、--prompt-b，支持与模板相同的 {{.变量}} 语法）。
+输入文件与 batch 相同，每行一个JSON对象，所有字段都作为模板变量：
+  {"id": "c1", "lang": "Go", "code": "..."}
+
+评审时两个回复的先后顺序逐条交替，减少评审模型偏向某个位置的影响。
+有用例失败时返回状态码5。
+
+示例:
+  ai-chat-cli ab --template-a review-v1 --template-b review-v2 --inputs cases.jsonl
+  ai-chat-cli ab --template-a v1 --template-b v2 --inputs cases.jsonl --judge gpt-4o
+  ai-chat-cli ab --prompt-a "总结: {{.text}}" --prompt-b "用三句话总结: {{.text}}" --inputs docs.jsonl -o ab.jsonl`,
+	Args: cobra.NoArgs,
+	Run:  runAB,
+}
+
+// abResult 一条用例的比较结果，写为结果文件中的一行
+type abResult struct {
+	ID        string `json:"id"`
+	ResponseA string `json:"response_a,omitempty"`
+	ResponseB string `json:"response_b,omitempty"`
+	Winner    string `json:"winner,omitempty"` // A、B 或 tie，未评审时为空
+	Reason    string `json:"reason,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func runAB(cmd *cobra.Command, args []string) {
+	if abInputs == "" {
+		fail(ExitUsage, "%s", i18n.T("ab.inputs_required"))
+		return
+	}
+	variantA, err := loadABVariant("A", abTemplateA, abPromptA)
+	if err != nil {
+		fail(ExitUsage, "%v", err)
+		return
+	}
+	variantB, err := loadABVariant("B", abTemplateB, abPromptB)
+	if err != nil {
+		fail(ExitUsage, "%v", err)
+		return
+	}
+
+	jobs, err := loadBatchJobs(abInputs)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	if len(jobs) == 0 {
+		fail(ExitUsage, i18n.T("ab.inputs_empty"), abInputs)
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fail(ExitConfig, i18n.T("config.load_failed"), err)
+		hint("%s", i18n.T("config.init_hint"))
+		return
+	}
+	name, providerCfg, ok := selectProvider(cfg, abProvider)
+	if !ok {
+		return
+	}
+	// 与 batch 相同，由用例的限流器统一限流
+	rpm := providerCfg.RateLimit
+	if abRPM > 0 {
+		rpm = abRPM
+	}
+	providerCfg.RateLimit = 0
+	provider := newProvider(envFrom(cmd.Context()), cfg, name, providerCfg)
+	if p, ok := provider.(*dryRunProvider); ok {
+		temperature := defaultTemperature(providerCfg)
+		printBatchRequests(p, variantA, jobs, temperature)
+		printBatchRequests(p, variantB, jobs, temperature)
+		return
+	}
+
+	var out *json.Encoder
+	if abOutput != "" {
+		file, err := os.Create(abOutput)
+		if err != nil {
+			fail(ExitError, i18n.T("ab.create_failed"), err)
+			return
+		}
+		defer file.Close()
+		out = json.NewEncoder(file)
+	}
+
+	limiter := ratelimit.For(name, rpm)
+	temperature := defaultTemperature(providerCfg)
+	concurrency := abConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// 用例并发执行，结果按输入顺序输出
+	ctx := cmd.Context()
+	results := make([]abResult, len(jobs))
+	done := make([]chan struct{}, len(jobs))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	indexes := make(chan int)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			for i := range indexes {
+				results[i] = runABCase(ctx, provider, limiter, variantA, variantB, jobs[i], i%2 == 1, temperature)
+				close(done[i])
+			}
+		}()
+	}
+	go func() {
+		defer close(indexes)
+		for i := range jobs {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wins := map[string]int{}
+	var failed, identical, completed int
+	for i := range jobs {
+		select {
+		case <-done[i]:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		result := results[i]
+		completed++
+		printABResult(result, variantA.Name, variantB.Name)
+		if out != nil {
+			if err := out.Encode(result); err != nil {
+				fail(ExitError, i18n.T("ab.write_failed"), err)
+				return
+			}
+		}
+		switch {
+		case result.Error != "":
+			failed++
+		case result.Winner != "":
+			wins[result.Winner]++
+		}
+		if result.Error == "" && result.ResponseA == result.ResponseB {
+			identical++
+		}
+	}
+
+	fmt.Println()
+	if ctx.Err() != nil {
+		fmt.Println(i18n.T("ab.interrupted", completed, len(jobs)))
+	}
+	printABSummary(variantA.Name, variantB.Name, completed, failed, identical, wins)
+	if abOutput != "" {
+		ui.Success(i18n.T("ab.written"), abOutput)
+	}
+
+	if ctx.Err() != nil {
+		setExitCode(ExitInterrupted)
+		return
+	}
+	if failed > 0 {
+		setExitCode(ExitProvider)
+	}
+}
+
+// loadABVariant 加载一个变体，模板和提示词只能二选一
+func loadABVariant(label, templateName, prompt string) (*template.Template, error) {
+	lower := strings.ToLower(label)
+	switch {
+	case templateName != "" && prompt != "":
+		return nil, fmt.Errorf(i18n.T("ab.variant_conflict"), lower, lower)
+	case templateName != "":
+		return template.Load(templateName)
+	case prompt != "":
+		return &template.Template{Prompt: prompt}, nil
+	}
+	return nil, fmt.Errorf(i18n.T("ab.variant_required"), lower, lower, label)
+}
+
+// runABCase 用两个变体分别生成回复，指定了评审模型时再判断胜负。
+// swap 为 true 时评审模型先看到变体B的回复，变体没有指定温度时使用 temperature
+func runABCase(ctx context.Context, provider providers.Provider, limiter *ratelimit.Limiter, a, b *template.Template, job BatchJob, swap bool, temperature float64) abResult {
+	result := abResult{ID: job.ID}
+
+	resA := runBatchJob(ctx, provider, limiter, a, job, temperature)
+	if resA.Error != "" {
+		result.Error = "A: " + resA.Error
+		return result
+	}
+	resB := runBatchJob(ctx, provider, limiter, b, job, temperature)
+	if resB.Error != "" {
+		result.Error = "B: " + resB.Error
+		return result
+	}
+	result.ResponseA, result.ResponseB = resA.Response, resB.Response
+
+	if abJudge == "" {
+		return result
+	}
+	if err := limiter.Wait(ctx); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	winner, reason, err := judgeAB(ctx, provider, job, result.ResponseA, result.ResponseB, swap)
+	if err != nil {
+		result.Error = i18n.T("ab.judge_error", err)
+		return result
+	}
+	result.Winner, result.Reason = winner, reason
+	return result
+}
+
+// abJudgePrompt 评审模型的系统提示词
+const abJudgePrompt = `你是公正的评审。根据任务输入比较两个回复的质量（正确性、完整性、清晰度、是否遵循要求），
+不要因为回复的长度或先后顺序而偏向某一方。
+第一行只输出 1、2 或 TIE，表示回复1更好、回复2更好或不相上下；第二行用一句话说明理由。`
+
+// judgeAB 请评审模型比较两个回复，返回 A、B 或 tie 以及理由
+func judgeAB(ctx context.Context, provider providers.Provider, job BatchJob, responseA, responseB string, swap bool) (string, string, error) {
+	input, err := json.MarshalIndent(job.Vars, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+	first, second := responseA, responseB
+	if swap {
+		first, second = responseB, responseA
+	}
+
+	resp, err := provider.Chat(ctx, &providers.ChatRequest{
+		Messages: []providers.Message{
+			{Role: "system", Content: abJudgePrompt},
+			{Role: "user", Content: fmt.Sprintf("<input>\n%s\n</input>\n\n<response_1>\n%s\n</response_1>\n\n<response_2>\n%s\n</response_2>", input, first, second)},
+		},
+		Model:       abJudge,
+		Temperature: 0,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	verdict, reason, _ := strings.Cut(strings.TrimSpace(resp.Content), "\n")
+	verdict = strings.ToUpper(strings.Trim(strings.TrimSpace(verdict), "*.:：。 "))
+	reason = strings.TrimSpace(reason)
+	var winner string
+	switch {
+	case strings.HasPrefix(verdict, "TIE"):
+		return abTie, reason, nil
+	case strings.HasPrefix(verdict, "1"):
+		winner = abWinA
+	case strings.HasPrefix(verdict, "2"):
+		winner = abWinB
+	default:
+		return "", "", fmt.Errorf(i18n.T("ab.judge_unparsed"), strings.TrimSpace(resp.Content))
+	}
+	if swap {
+		winner = map[string]string{abWinA: abWinB, abWinB: abWinA}[winner]
+	}
+	return winner, reason, nil
+}
+
+// printABResult 输出一条用例的差异和评审结果
+func printABResult(result abResult, nameA, nameB string) {
+	fmt.Println(ui.Colors().Bold(fmt.Sprintf("━━ %s ━━", result.ID)))
+	if result.Error != "" {
+		ui.Error("%s", result.Error)
+		return
+	}
+	if !abNoDiff {
+		if unified := diff.Unified(abLabel(abWinA, nameA), abLabel(abWinB, nameB), result.ResponseA, result.ResponseB, 2); unified != "" {
+			printColoredDiff(unified)
+		} else {
+			fmt.Println(i18n.T("ab.identical"))
+		}
+	}
+	switch result.Winner {
+	case abWinA, abWinB:
+		fmt.Println(i18n.T("ab.winner", result.Winner, result.Reason))
+	case abTie:
+		fmt.Println(i18n.T("ab.tie", result.Reason))
+	}
+}
+
+// printABSummary 输出汇总和两个变体的胜率
+func printABSummary(nameA, nameB string, total, failed, identical int, wins map[string]int) {
+	fmt.Println(ui.Styled(ui.ElemStats, i18n.T("ab.summary", total, failed, identical)))
+	if abJudge == "" {
+		hint("%s", i18n.T("ab.judge_hint"))
+		return
+	}
+	judged := wins[abWinA] + wins[abWinB] + wins[abTie]
+	if judged == 0 {
+		return
+	}
+	rate := func(n int) float64 { return float64(n) * 100 / float64(judged) }
+	fmt.Println(i18n.T("ab.win_rates",
+		abLabel(abWinA, nameA), wins[abWinA], rate(wins[abWinA]), abLabel(abWinB, nameB), wins[abWinB], rate(wins[abWinB]), wins[abTie], rate(wins[abTie])))
+}
+
+// abLabel 变体的显示名称，使用模板时附上模板名称
+func abLabel(label, name string) string {
+	if name == "" {
+		return label
+	}
+	return label + " (" + name + ")"
+}
+
+func init() {
+	rootCmd.AddCommand(abCmd)
+
+	abCmd.Flags().StringVarP(&abProvider, "provider", "p", "", "指定AI提供商")
+	abCmd.Flags().StringVar(&abTemplateA, "template-a", "", "变体A的模板名称或文件路径")
+	abCmd.Flags().StringVar(&abTemplateB, "template-b", "", "变体B的模板名称或文件路径")
+	abCmd.Flags().StringVar(&abPromptA, "prompt-a", "", "变体A的提示词，支持 {{.变量}}")
+	abCmd.Flags().StringVar(&abPromptB, "prompt-b", "", "变体B的提示词，支持 {{.变量}}")
+	abCmd.Flags().StringVarP(&abInputs, "inputs", "i", "", "用例文件（JSONL，格式与 batch 相同）")
+	abCmd.Flags().StringVar(&abJudge, "judge", "", "评审模型，指定后判断胜负并统计胜率")
+	abCmd.Flags().StringVarP(&abOutput, "output", "o", "", "将每条用例的回复和评审结果写入JSONL文件")
+	abCmd.Flags().IntVarP(&abConcurrency, "concurrency", "c", 1, "并发执行的用例数")
+	abCmd.Flags().IntVar(&abRPM, "rpm", 0, "每分钟最大请求数，覆盖提供商的 rate_limit 配置")
+	abCmd.Flags().BoolVar(&abNoDiff, "no-diff", false, "不显示每条用例的差异，只显示评审结果和汇总")
+}
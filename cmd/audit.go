@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"ai-chat-cli/internal/audit"
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/server"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+
+	"github.com/spf13/cobra"
+)
+
+// auditCmd 审计日志
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "管理对话请求的审计日志",
+	Long: `配置 audit.enabled 为 true 后，每次对话请求都会追加一条审计记录到
+~/.ai-chat-cli/audit.jsonl（可通过 audit.file 修改），与 logging 的调试日志分开保存。
+
+每条记录包含时间、用户、主机、提供商、模型、token用量，以及提示词和回复的SHA-256；
+audit.content 设置为 full 时同时记录完整内容。每条记录都包含上一条记录的哈希，
+可以使用 audit verify 检查记录是否被修改或删除。`,
+}
+
+// auditVerifyCmd 校验审计日志
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify [file]",
+	Short: "校验审计日志的哈希链",
+	Long: `逐条校验审计日志的序号和哈希链，发现记录被修改、删除、插入或调换顺序时返回状态码1。
+不指定文件时校验 audit.file 配置的审计日志。
+
+注意：从末尾截断的记录无法通过哈希链发现，需要另外保存最后一条记录的哈希进行比对。`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAuditVerify,
+}
+
+func runAuditVerify(cmd *cobra.Command, args []string) {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			cfg = &config.Config{}
+		}
+		if path, err = auditPath(cfg.Audit); err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+	}
+
+	n, err := audit.Verify(path)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		if n > 0 {
+			hint("前 %d 条记录完整", n)
+		}
+		return
+	}
+	ui.Success("%s: %d 条记录，哈希链完整", path, n)
+}
+
+// auditPath 获取审计日志文件路径
+func auditPath(cfg config.AuditConfig) (string, error) {
+	if cfg.File != "" {
+		return expandHome(cfg.File)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ai-chat-cli", "audit.jsonl"), nil
+}
+
+var (
+	auditOnce sync.Once
+	auditLog  *audit.Log
+)
+
+// openAuditLog 按配置打开审计日志，未启用时返回nil
+func openAuditLog() *audit.Log {
+	auditOnce.Do(func() {
+		cfg, err := config.LoadConfig()
+		if err != nil || !cfg.Audit.Enabled {
+			return
+		}
+		path, err := auditPath(cfg.Audit)
+		if err == nil {
+			auditLog, err = audit.Open(path, cfg.Audit.Content)
+		}
+		if err != nil {
+			ui.Warn(i18n.T("audit.disabled"), err)
+		}
+	})
+	return auditLog
+}
+
+// auditedProvider 将每次对话请求写入审计日志
+type auditedProvider struct {
+	providers.Provider
+	log   *audit.Log
+	model string // 请求未指定模型时使用的默认模型
+}
+
+// newAuditedProvider 配置了 audit.enabled 时创建写入审计日志的提供商，否则原样返回
+func newAuditedProvider(p providers.Provider, model string) providers.Provider {
+	log := openAuditLog()
+	if log == nil {
+		return p
+	}
+	return &auditedProvider{Provider: p, log: log, model: model}
+}
+
+// Unwrap 返回被包装的提供商
+func (p *auditedProvider) Unwrap() providers.Provider {
+	return p.Provider
+}
+
+// Chat 发送对话请求并记录审计日志
+func (p *auditedProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	resp, err := p.Provider.Chat(ctx, req)
+	if err != nil {
+		p.record(ctx, req, "", 0, err)
+		return nil, err
+	}
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	p.record(ctx, &providers.ChatRequest{Model: model, Messages: req.Messages}, resp.Content, resp.Usage.TotalTokens, nil)
+	return resp, nil
+}
+
+// ChatStream 发送流式对话请求，回复结束后记录审计日志
+func (p *auditedProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	chunks, err := p.Provider.ChatStream(ctx, req)
+	if err != nil {
+		p.record(ctx, req, "", 0, err)
+		return nil, err
+	}
+
+	out := make(chan providers.StreamChunk)
+	go func() {
+		defer close(out)
+		var reply strings.Builder
+		var streamErr error
+		usage := providers.NewStreamUsage(req)
+		gone := false // 调用方已取消时继续读完剩余内容，但不再转发
+		for chunk := range chunks {
+			reply.WriteString(chunk.Content)
+			usage.Add(chunk)
+			if chunk.Error != nil {
+				streamErr = chunk.Error
+			}
+			if gone {
+				continue
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				gone, streamErr = true, ctx.Err()
+			}
+		}
+		p.record(ctx, req, reply.String(), usage.Usage().TotalTokens, streamErr)
+	}()
+	return out, nil
+}
+
+// record 写入一条审计记录，写入失败时只显示警告，不影响对话
+func (p *auditedProvider) record(ctx context.Context, req *providers.ChatRequest, reply string, tokens int, err error) {
+	rec := audit.Record{
+		User:     currentUser(),
+		Client:   server.ClientName(ctx),
+		Provider: p.GetName(),
+		Model:    req.Model,
+		Tokens:   tokens,
+	}
+	rec.Host, _ = os.Hostname()
+	if rec.Model == "" {
+		rec.Model = p.model
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	if werr := p.log.Append(rec, req.Messages, reply); werr != nil {
+		ui.Warn("%v", werr)
+	}
+}
+
+// currentUser 获取当前系统用户名
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditVerifyCmd)
+}
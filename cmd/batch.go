@@ -0,0 +1,484 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ratelimit"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/template"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchProvider    string
+	batchOutput      string
+	batchTemplate    string
+	batchConcurrency int
+	batchRPM         int
+)
+
+// BatchJob 批处理中的单条任务
+type BatchJob struct {
+	ID   string                 // 任务ID
+	Vars map[string]interface{} // 输入行的全部字段，作为模板变量
+}
+
+// BatchResult 批处理单条任务的结果，每条结果写为输出文件中的一行
+type BatchResult struct {
+	ID           string           `json:"id"`
+	Response     string           `json:"response,omitempty"`
+	Model        string           `json:"model,omitempty"`
+	Usage        *providers.Usage `json:"usage,omitempty"`
+	Stats        *providers.Stats `json:"stats,omitempty"`
+	FinishReason string           `json:"finish_reason,omitempty"`
+	Error        string           `json:"error,omitempty"`
+}
+
+// batchCmd represents the batch command
+var batchCmd = &cobra.Command{
+	Use:   "batch <prompts.jsonl>",
+	Short: "批量处理文件中的提示词",
+	Long: `逐行读取JSONL文件中的提示词并发送给AI，将回复、用量和错误逐行写入结果文件。
+
+输入文件每行一个JSON对象：
+  {"id": "q1", "prompt": "什么是Go语言?"}
+  {"id": "q2", "prompt": "解释一下goroutine", "system": "用一句话回答"}
+
+使用 --template 时，输入行的所有字段都作为模板变量：
+  {"id": "r1", "lang": "Go", "topic": "channel"}
+
+中断后使用相同的 --output 重新运行即可续跑，已成功的任务会被跳过，失败的任务会重试。
+
+使用 --concurrency 并发处理，请求速率受提供商配置中的 rate_limit（每分钟请求数）
+或 --rpm 限制，避免触发API的429限流错误。同时处理的相同请求只发送一次，共享回复。
+
+示例:
+  ai-chat-cli batch prompts.jsonl --output results.jsonl
+  ai-chat-cli batch cases.jsonl -o results.jsonl --template review
+  ai-chat-cli batch prompts.jsonl -o results.jsonl --concurrency 8 --rpm 120`,
+	Args: cobra.ExactArgs(1),
+	Run:  runBatch,
+}
+
+func runBatch(cmd *cobra.Command, args []string) {
+	if batchOutput == "" && !envFrom(cmd.Context()).dryRun {
+		fail(ExitUsage, "%s", i18n.T("batch.output_required"))
+		return
+	}
+
+	jobs, err := loadBatchJobs(args[0])
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	var tmpl *template.Template
+	if batchTemplate != "" {
+		if tmpl, err = template.Load(batchTemplate); err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+	}
+
+	done, err := loadCompletedBatchIDs(batchOutput)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	var pending []BatchJob
+	for _, job := range jobs {
+		if !done[job.ID] {
+			pending = append(pending, job)
+		}
+	}
+	if len(done) > 0 {
+		fmt.Println(i18n.T("batch.resuming", len(jobs)-len(pending), len(pending)))
+	}
+	if len(pending) == 0 {
+		ui.Success("%s", i18n.T("batch.all_done"))
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fail(ExitConfig, i18n.T("config.load_failed"), err)
+		hint("%s", i18n.T("config.init_hint"))
+		return
+	}
+	name, providerCfg, ok := selectProvider(cfg, batchProvider)
+	if !ok {
+		return
+	}
+	// 任务由下面的限流器统一限流，对所有类型的提供商都生效，提供商的请求不再重复限流
+	rpm := providerCfg.RateLimit
+	if batchRPM > 0 {
+		rpm = batchRPM
+	}
+	providerCfg.RateLimit = 0
+	provider := newProvider(envFrom(cmd.Context()), cfg, name, providerCfg)
+	if p, ok := provider.(*dryRunProvider); ok {
+		printBatchRequests(p, tmpl, pending, defaultTemperature(providerCfg))
+		return
+	}
+
+	// 模板展开后相同的任务同时处理时只发送一次请求
+	var merged atomic.Int64
+	provider = newDedupedProvider(provider, &merged)
+	limiter := ratelimit.For(name, rpm)
+	temperature := defaultTemperature(providerCfg)
+
+	out, err := openBatchOutput(batchOutput)
+	if err != nil {
+		fail(ExitError, i18n.T("batch.open_failed"), err)
+		return
+	}
+	defer out.Close()
+
+	concurrency := batchConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if rpm > 0 {
+		fmt.Println(i18n.T("batch.concurrency_rpm", concurrency, rpm))
+	} else {
+		fmt.Println(i18n.T("batch.concurrency", concurrency))
+	}
+
+	// 启动worker池，中断后不再开始新任务，被取消的任务不写入结果，重新运行时会继续处理
+	ctx := cmd.Context()
+	jobCh := make(chan BatchJob)
+	resultCh := make(chan BatchResult)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobCh {
+				result := runBatchJob(ctx, provider, limiter, tmpl, job, temperature)
+				if result.Error != "" && ctx.Err() != nil {
+					continue
+				}
+				resultCh <- result
+			}
+		}()
+	}
+	go func() {
+	feed:
+		for _, job := range pending {
+			select {
+			case jobCh <- job:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(jobCh)
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	// 结果统一由当前goroutine写入，保证每行完整
+	progress := newBatchProgress(len(pending))
+	encoder := json.NewEncoder(out)
+	for result := range resultCh {
+		if err := encoder.Encode(result); err != nil {
+			fmt.Println()
+			fail(ExitError, i18n.T("batch.write_failed"), err)
+			exit()
+		}
+		progress.record(result)
+	}
+	progress.finish()
+
+	if ctx.Err() != nil {
+		fmt.Println()
+		fmt.Println(i18n.T("batch.interrupted",
+			progress.completed-progress.failed, progress.failed, batchOutput))
+		setExitCode(ExitInterrupted)
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Styled(ui.ElemStats, i18n.T("batch.done", progress.completed-progress.failed, progress.failed, batchOutput)))
+	if n := merged.Load(); n > 0 {
+		fmt.Println(i18n.T("batch.merged", n))
+	}
+	if progress.failed > 0 {
+		setExitCode(ExitProvider)
+	}
+}
+
+// batchProgress 批处理进度显示
+type batchProgress struct {
+	total     int
+	completed int
+	failed    int
+	start     time.Time
+	live      bool // 终端中原地刷新进度行
+}
+
+// newBatchProgress 创建进度显示
+func newBatchProgress(total int) *batchProgress {
+	return &batchProgress{total: total, start: time.Now(), live: stdoutIsTerminal()}
+}
+
+// record 记录一条结果并刷新进度
+func (p *batchProgress) record(result BatchResult) {
+	p.completed++
+	if result.Error != "" {
+		p.failed++
+		if p.live {
+			ui.ClearLine()
+		}
+		ui.Error("%s: %s", result.ID, result.Error)
+	} else if !p.live {
+		ui.Success("%s", result.ID)
+	}
+
+	if p.live {
+		ui.ClearLine()
+		fmt.Print(p.line())
+	} else if p.completed%10 == 0 {
+		fmt.Println(p.line())
+	}
+}
+
+// finish 结束进度显示
+func (p *batchProgress) finish() {
+	if p.live {
+		ui.ClearLine()
+		fmt.Println(p.line())
+	}
+}
+
+// line 生成进度行：已完成/失败/预计剩余时间
+func (p *batchProgress) line() string {
+	elapsed := time.Since(p.start)
+	eta := "--"
+	if p.completed > 0 && p.completed < p.total {
+		remaining := time.Duration(float64(elapsed) / float64(p.completed) * float64(p.total-p.completed))
+		eta = remaining.Round(time.Second).String()
+	} else if p.completed == p.total {
+		eta = "0s"
+	}
+	return i18n.T("batch.progress",
+		p.completed, p.total, p.failed, elapsed.Round(time.Second), eta)
+}
+
+// runBatchJob 执行单条批处理任务，发送请求前先等待限流器放行
+func runBatchJob(ctx context.Context, provider providers.Provider, limiter *ratelimit.Limiter, tmpl *template.Template, job BatchJob, temperature float64) BatchResult {
+	result := BatchResult{ID: job.ID}
+
+	req, err := buildBatchRequest(tmpl, job, temperature)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if err := limiter.Wait(ctx); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	timer := providers.StartTimer()
+	resp, err := provider.Chat(ctx, req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Response = resp.Content
+	result.Model = resp.Model
+	result.Usage = &resp.Usage
+	result.Stats = timer.Stop(resp.Usage.CompletionTokens)
+	result.FinishReason = resp.FinishReason
+	return result
+}
+
+// printBatchRequests 演练模式下逐行输出每条任务将要发送的请求和token估算，不调用API
+func printBatchRequests(p *dryRunProvider, tmpl *template.Template, jobs []BatchJob, temperature float64) {
+	encoder := json.NewEncoder(os.Stdout)
+	total := 0
+	for _, job := range jobs {
+		req, err := buildBatchRequest(tmpl, job, temperature)
+		if err != nil {
+			fail(ExitError, "%s: %v", job.ID, err)
+			continue
+		}
+		payload, err := p.Payload(req, false)
+		if err != nil {
+			fail(ExitError, "%s: %v", job.ID, err)
+			continue
+		}
+
+		tokens := estimateRequestTokens(req)
+		total += tokens
+		encoder.Encode(map[string]interface{}{
+			"id":               job.ID,
+			"estimated_tokens": tokens,
+			"request":          json.RawMessage(payload),
+		})
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("batch.dry_run", len(jobs), total))
+}
+
+// buildBatchRequest 根据任务和可选的模板构建对话请求，模板没有指定温度时使用 temperature
+func buildBatchRequest(tmpl *template.Template, job BatchJob, temperature float64) (*providers.ChatRequest, error) {
+	req := &providers.ChatRequest{Temperature: temperature}
+
+	system, _ := job.Vars["system"].(string)
+	prompt, _ := job.Vars["prompt"].(string)
+	if model, ok := job.Vars["model"].(string); ok {
+		req.Model = model
+	}
+
+	if tmpl != nil {
+		var err error
+		if system, prompt, err = tmpl.Render(job.Vars); err != nil {
+			return nil, err
+		}
+		if tmpl.Model != "" && req.Model == "" {
+			req.Model = tmpl.Model
+		}
+		if tmpl.Temperature != nil {
+			req.Temperature = *tmpl.Temperature
+		}
+	}
+
+	if strings.TrimSpace(prompt) == "" {
+		return nil, errors.New(i18n.T("batch.empty_prompt"))
+	}
+
+	if system != "" {
+		req.Messages = append(req.Messages, providers.Message{Role: "system", Content: system})
+	}
+	if tmpl != nil {
+		for _, ex := range tmpl.Examples {
+			req.Messages = append(req.Messages,
+				providers.Message{Role: "user", Content: ex.User},
+				providers.Message{Role: "assistant", Content: ex.Assistant})
+		}
+	}
+	req.Messages = append(req.Messages, providers.Message{Role: "user", Content: prompt})
+	return req, nil
+}
+
+// loadBatchJobs 读取JSONL格式的任务文件，没有id字段的行使用行号作为ID
+func loadBatchJobs(filename string) ([]BatchJob, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("batch.read_failed"), err)
+	}
+	defer file.Close()
+
+	var jobs []BatchJob
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		vars := map[string]interface{}{}
+		if strings.HasPrefix(line, "\"") {
+			// 允许整行是一个JSON字符串
+			var prompt string
+			if err := json.Unmarshal([]byte(line), &prompt); err != nil {
+				return nil, fmt.Errorf(i18n.T("batch.line_invalid"), lineNo, err)
+			}
+			vars["prompt"] = prompt
+		} else if err := json.Unmarshal([]byte(line), &vars); err != nil {
+			return nil, fmt.Errorf(i18n.T("batch.line_invalid"), lineNo, err)
+		}
+
+		id := fmt.Sprint(lineNo)
+		if v, ok := vars["id"]; ok {
+			id = fmt.Sprint(v)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf(i18n.T("batch.duplicate_id"), lineNo, id)
+		}
+		seen[id] = true
+
+		jobs = append(jobs, BatchJob{ID: id, Vars: vars})
+	}
+
+	return jobs, scanner.Err()
+}
+
+// loadCompletedBatchIDs 读取已有结果文件中成功完成的任务ID，用于续跑
+func loadCompletedBatchIDs(filename string) (map[string]bool, error) {
+	done := map[string]bool{}
+
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("batch.read_results_failed"), err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var result BatchResult
+		// 中断时可能留下不完整的最后一行，直接忽略
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			continue
+		}
+		done[result.ID] = result.Error == ""
+	}
+
+	return done, scanner.Err()
+}
+
+// openBatchOutput 以追加方式打开结果文件，上次中断留下的不完整行会先补上换行
+func openBatchOutput(filename string) (*os.File, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := file.WriteString("\n"); err != nil {
+				file.Close()
+				return nil, err
+			}
+		}
+	}
+
+	return file, nil
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+
+	batchCmd.PersistentFlags().StringVarP(&batchProvider, "provider", "p", "", "指定AI提供商")
+	batchCmd.Flags().StringVarP(&batchOutput, "output", "o", "", "结果文件路径（JSONL）")
+	batchCmd.PersistentFlags().StringVarP(&batchTemplate, "template", "t", "", "提示词模板名称或文件路径")
+	batchCmd.Flags().IntVarP(&batchConcurrency, "concurrency", "c", 1, "并发请求数")
+	batchCmd.Flags().IntVar(&batchRPM, "rpm", 0, "每分钟最大请求数，覆盖提供商的 rate_limit 配置")
+}
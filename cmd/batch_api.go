@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/template"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchWait     bool
+	batchInterval time.Duration
+)
+
+// batchSubmitCmd 提交异步批处理任务
+var batchSubmitCmd = &cobra.Command{
+	Use:   "submit <prompts.jsonl>",
+	Short: "提交到OpenAI异步Batch API",
+	Long: `将任务文件提交到OpenAI的异步Batch API，24小时内完成，费用约为同步请求的一半。
+
+任务文件格式与 batch 命令相同，同样支持 --template。
+提交前按 filters 和 advanced.moderate_inputs 检查每个请求；启用 advanced.mask_pii 或 audit.enabled 时
+无法还原脱敏内容或记录审计日志，拒绝提交。
+
+示例:
+  ai-chat-cli batch submit prompts.jsonl
+  ai-chat-cli batch status batch_abc123 --wait
+  ai-chat-cli batch fetch batch_abc123 --output results.jsonl`,
+	Args: cobra.ExactArgs(1),
+	Run:  runBatchSubmit,
+}
+
+// batchStatusCmd 查询异步批处理任务状态
+var batchStatusCmd = &cobra.Command{
+	Use:   "status <batch_id>",
+	Short: "查询异步批处理任务状态",
+	Args:  cobra.ExactArgs(1),
+	Run:   runBatchStatus,
+}
+
+// batchFetchCmd 下载异步批处理任务结果
+var batchFetchCmd = &cobra.Command{
+	Use:   "fetch <batch_id>",
+	Short: "下载异步批处理任务结果",
+	Long:  `下载已完成的异步批处理任务结果，并以与 batch 命令相同的格式写入 --output 文件。`,
+	Args:  cobra.ExactArgs(1),
+	Run:   runBatchFetch,
+}
+
+func runBatchSubmit(cmd *cobra.Command, args []string) {
+	jobs, err := loadBatchJobs(args[0])
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	var tmpl *template.Template
+	if batchTemplate != "" {
+		if tmpl, err = template.Load(batchTemplate); err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+	}
+
+	provider, bp, ok := loadBatchProvider(envFrom(cmd.Context()))
+	if !ok {
+		return
+	}
+
+	if key := batchUnsupportedOption(provider); key != "" {
+		fail(ExitConfig, i18n.T("batch.unsupported_option"), key)
+		return
+	}
+
+	temperature := providerTemperature(provider.GetName())
+	reqs := make([]providers.BatchRequest, 0, len(jobs))
+	for _, job := range jobs {
+		req, err := buildBatchRequest(tmpl, job, temperature)
+		if err != nil {
+			fail(ExitError, i18n.T("batch.job_error"), job.ID, err)
+			return
+		}
+		reqs = append(reqs, providers.BatchRequest{CustomID: job.ID, Request: req})
+	}
+	if err := checkBatchRequests(cmd.Context(), provider, reqs); err != nil {
+		fail(errorExitCode(err), "%v", err)
+		return
+	}
+
+	fmt.Println(i18n.T("batch.uploading", len(reqs)))
+	info, err := bp.SubmitBatch(cmd.Context(), reqs)
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("batch.submit_failed"), err)
+		return
+	}
+
+	ui.Success(i18n.T("batch.created"), info.ID)
+	hint(i18n.T("batch.status_hint"), info.ID)
+	hint(i18n.T("batch.fetch_hint"), info.ID)
+}
+
+// minBatchInterval --wait 轮询的最小间隔，避免频繁请求批处理接口
+const minBatchInterval = time.Second
+
+// checkBatchInterval 检查 --wait 的轮询间隔
+func checkBatchInterval() bool {
+	if batchWait && batchInterval < minBatchInterval {
+		fail(ExitUsage, i18n.T("batch.interval_min"), minBatchInterval)
+		return false
+	}
+	return true
+}
+
+func runBatchStatus(cmd *cobra.Command, args []string) {
+	if !checkBatchInterval() {
+		return
+	}
+	_, bp, ok := loadBatchProvider(envFrom(cmd.Context()))
+	if !ok {
+		return
+	}
+
+	info, err := getBatch(cmd.Context(), bp, args[0])
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("batch.get_failed"), err)
+		return
+	}
+	printBatchInfo(info)
+}
+
+func runBatchFetch(cmd *cobra.Command, args []string) {
+	if batchOutput == "" {
+		fail(ExitUsage, "%s", i18n.T("batch.output_required"))
+		return
+	}
+	if !checkBatchInterval() {
+		return
+	}
+
+	_, bp, ok := loadBatchProvider(envFrom(cmd.Context()))
+	if !ok {
+		return
+	}
+
+	info, err := getBatch(cmd.Context(), bp, args[0])
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("batch.get_failed"), err)
+		return
+	}
+	if info.Status != "completed" {
+		printBatchInfo(info)
+		hint("%s", i18n.T("batch.unfinished_hint"))
+		return
+	}
+
+	outputs, err := bp.FetchBatchResults(cmd.Context(), info)
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("batch.download_failed"), err)
+		return
+	}
+
+	out, err := os.Create(batchOutput)
+	if err != nil {
+		fail(ExitError, i18n.T("batch.create_failed"), err)
+		return
+	}
+	defer out.Close()
+
+	failed := 0
+	encoder := json.NewEncoder(out)
+	for _, o := range outputs {
+		result := BatchResult{ID: o.CustomID, Error: o.Error}
+		if o.Response != nil {
+			result.Response = o.Response.Content
+			result.Model = o.Response.Model
+			result.Usage = &o.Response.Usage
+			result.FinishReason = o.Response.FinishReason
+		} else {
+			failed++
+		}
+		if err := encoder.Encode(result); err != nil {
+			fail(ExitError, i18n.T("batch.write_failed"), err)
+			return
+		}
+	}
+
+	ui.Success(i18n.T("batch.downloaded"), len(outputs), failed, batchOutput)
+}
+
+// loadBatchProvider 加载支持异步批处理的提供商，同时返回带有过滤、审核等包装的提供商
+func loadBatchProvider(env *providerEnv) (providers.Provider, providers.BatchProvider, bool) {
+	provider, ok := loadProvider(env, batchProvider)
+	if !ok {
+		return nil, nil, false
+	}
+
+	bp, ok := providers.Unwrap(provider).(providers.BatchProvider)
+	if !ok {
+		fail(ExitConfig, i18n.T("batch.unsupported"), provider.GetName())
+		return nil, nil, false
+	}
+	return provider, bp, true
+}
+
+// batchUnsupportedOption 脱敏和审计需要处理回复，而异步批处理的结果要在之后下载，
+// 提供商包装了这两项功能时返回对应的配置项，否则返回空字符串
+func batchUnsupportedOption(provider providers.Provider) string {
+	for p := provider; ; {
+		switch p.(type) {
+		case *maskingProvider:
+			return "advanced.mask_pii"
+		case *auditedProvider:
+			return "audit.enabled"
+		}
+
+		u, ok := p.(interface{ Unwrap() providers.Provider })
+		if !ok {
+			return ""
+		}
+		p = u.Unwrap()
+	}
+}
+
+// checkBatchRequests 异步批处理直接上传请求，不经过提供商的包装，提交前按包装链依次用过滤规则和内容审核检查每个请求
+func checkBatchRequests(ctx context.Context, provider providers.Provider, reqs []providers.BatchRequest) error {
+	for p := provider; ; {
+		switch w := p.(type) {
+		case *filteredProvider:
+			for _, r := range reqs {
+				if err := w.check(r.Request); err != nil {
+					return fmt.Errorf(i18n.T("batch.job_rejected"), r.CustomID, err)
+				}
+			}
+		case *providers.ModeratedProvider:
+			for _, r := range reqs {
+				if err := w.Check(ctx, r.Request); err != nil {
+					return fmt.Errorf(i18n.T("batch.job_rejected"), r.CustomID, err)
+				}
+			}
+		}
+
+		u, ok := p.(interface{ Unwrap() providers.Provider })
+		if !ok {
+			return nil
+		}
+		p = u.Unwrap()
+	}
+}
+
+// getBatch 查询批处理任务，指定 --wait 时轮询直到任务结束
+func getBatch(ctx context.Context, bp providers.BatchProvider, id string) (*providers.BatchInfo, error) {
+	for {
+		info, err := bp.GetBatch(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !batchWait || isBatchFinished(info.Status) {
+			return info, nil
+		}
+
+		fmt.Println(ui.Status(fmt.Sprintf("⏳ %s: %s (%d/%d)", time.Now().Format("15:04:05"), info.Status,
+			info.RequestCounts.Completed+info.RequestCounts.Failed, info.RequestCounts.Total)))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(batchInterval):
+		}
+	}
+}
+
+// isBatchFinished 判断批处理任务是否已经结束
+func isBatchFinished(status string) bool {
+	switch status {
+	case "completed", "failed", "expired", "cancelled":
+		return true
+	}
+	return false
+}
+
+// printBatchInfo 显示批处理任务信息
+func printBatchInfo(info *providers.BatchInfo) {
+	fmt.Println(i18n.T("batch.info", info.ID))
+	fmt.Printf("  %s\n", i18n.T("batch.status", info.Status))
+	fmt.Printf("  %s\n", i18n.T("batch.counts",
+		info.RequestCounts.Completed, info.RequestCounts.Failed, info.RequestCounts.Total))
+	if info.CreatedAt > 0 {
+		fmt.Printf("  %s\n", i18n.T("batch.created_at", time.Unix(info.CreatedAt, 0).Format("2006-01-02 15:04:05")))
+	}
+	if info.CompletedAt > 0 {
+		fmt.Printf("  %s\n", i18n.T("batch.completed_at", time.Unix(info.CompletedAt, 0).Format("2006-01-02 15:04:05")))
+	}
+}
+
+func init() {
+	batchCmd.AddCommand(batchSubmitCmd)
+	batchCmd.AddCommand(batchStatusCmd)
+	batchCmd.AddCommand(batchFetchCmd)
+
+	for _, c := range []*cobra.Command{batchStatusCmd, batchFetchCmd} {
+		c.Flags().BoolVar(&batchWait, "wait", false, "轮询等待任务结束")
+		c.Flags().DurationVar(&batchInterval, "interval", 30*time.Second, "轮询间隔，不能小于1秒")
+	}
+	batchFetchCmd.Flags().StringVarP(&batchOutput, "output", "o", "", "结果文件路径（JSONL）")
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"ai-chat-cli/internal/cache"
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheExpired bool
+
+// cacheCmd 回复缓存
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "管理本地回复缓存",
+	Long: `配置 advanced.cache 为 true 后，回复会缓存到 ~/.ai-chat-cli/cache，
+提供商、模型、消息和参数都相同的请求直接返回缓存的回复，不再发送请求，
+适合重复运行 batch、ab 等命令。缓存的有效期由 advanced.cache_ttl 设置（小时，默认24）。`,
+}
+
+// cacheClearCmd 清除缓存
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "删除缓存的回复",
+	Args:  cobra.NoArgs,
+	Run:   runCacheClear,
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	c, err := cache.OpenDefault(cacheTTL(cfg.Advanced))
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	n, err := c.Clear(cacheExpired)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	if cacheExpired {
+		ui.Success(i18n.T("cache.expired_cleared"), n)
+		return
+	}
+	ui.Success(i18n.T("cache.cleared"), n)
+}
+
+// cacheTTL 配置的缓存有效期，未设置时为0，由 cache.Open 使用默认值
+func cacheTTL(advanced config.AdvancedConfig) time.Duration {
+	return time.Duration(advanced.CacheTTL) * time.Hour
+}
+
+// cachedProvider 提供商、模型、消息和参数都相同的请求返回缓存的回复，只缓存成功的回复
+type cachedProvider struct {
+	providers.Provider
+
+	cache    *cache.Cache
+	defaults cache.Defaults // 提供商配置的默认值，请求未指定时使用
+	notify   bool           // 使用缓存时是否在标准错误输出中提示
+}
+
+// newCachedProvider 启用 advanced.cache 时创建使用回复缓存的提供商，否则原样返回。
+// 无法确定缓存目录时不使用缓存
+func newCachedProvider(p providers.Provider, providerCfg config.ProviderConfig, advanced config.AdvancedConfig, metadata map[string]string, notify bool) providers.Provider {
+	if !advanced.Cache {
+		return p
+	}
+	c, err := cache.OpenDefault(cacheTTL(advanced))
+	if err != nil {
+		ui.Warn(i18n.T("cache.unavailable"), err)
+		return p
+	}
+	defaults := cache.Defaults{
+		Model:     providerCfg.Model,
+		MaxTokens: providerCfg.MaxTokens,
+		Sampling:  providerSampling(providerCfg),
+		User:      providerCfg.UserID,
+		Metadata:  mergeMetadata(providerCfg.Metadata, metadata),
+		Compat: providers.Compat{
+			MaxTokensField:  providerCfg.Compat.MaxTokensField,
+			OmitTemperature: providerCfg.Compat.OmitTemperature,
+			StreamUsage:     providerCfg.Compat.StreamUsage,
+		},
+		Extra: providerCfg.Extra,
+	}
+	return &cachedProvider{Provider: p, cache: c, defaults: defaults, notify: notify}
+}
+
+// uncachedKey 上下文中带有该键时请求不使用回复缓存
+type uncachedKey struct{}
+
+// withoutCache 返回不读取也不写入回复缓存的上下文，用于需要得到不同回答的相同请求（chat --n）
+func withoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, uncachedKey{}, true)
+}
+
+// Unwrap 返回被包装的提供商
+func (p *cachedProvider) Unwrap() providers.Provider {
+	return p.Provider
+}
+
+// key 请求的缓存键
+func (p *cachedProvider) key(req *providers.ChatRequest) string {
+	return cache.Key(p.GetName(), p.defaults, req)
+}
+
+// hit 查找缓存的回复
+func (p *cachedProvider) hit(key string) (*providers.ChatResponse, bool) {
+	resp, ok := p.cache.Get(key)
+	if ok && p.notify {
+		fmt.Fprintln(os.Stderr, ui.Status(i18n.T("provider.cached")))
+	}
+	return resp, ok
+}
+
+// store 保存回复，保存失败不影响本次请求
+func (p *cachedProvider) store(key string, resp *providers.ChatResponse) {
+	if err := p.cache.Put(key, p.GetName(), resp); err != nil {
+		ui.Warn("%v", err)
+	}
+}
+
+// Chat 有缓存时直接返回缓存的回复，否则发送请求并缓存回复
+func (p *cachedProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	if ctx.Value(uncachedKey{}) != nil {
+		return p.Provider.Chat(ctx, req)
+	}
+	key := p.key(req)
+	if resp, ok := p.hit(key); ok {
+		return resp, nil
+	}
+	resp, err := p.Provider.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.store(key, resp)
+	return resp, nil
+}
+
+// ChatStream 有缓存时将缓存的回复作为一个数据块返回，否则发送流式请求，完整收到回复后缓存
+func (p *cachedProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	if ctx.Value(uncachedKey{}) != nil {
+		return p.Provider.ChatStream(ctx, req)
+	}
+	key := p.key(req)
+	if resp, ok := p.hit(key); ok {
+		out := make(chan providers.StreamChunk, 2)
+		usage := resp.Usage
+		out <- providers.StreamChunk{Content: resp.Content}
+		out <- providers.StreamChunk{Done: true, Usage: &usage, FinishReason: resp.FinishReason}
+		close(out)
+		return out, nil
+	}
+
+	chunks, err := p.Provider.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan providers.StreamChunk)
+	go func() {
+		defer close(out)
+		var content strings.Builder
+		usage := providers.NewStreamUsage(req)
+		for chunk := range chunks {
+			content.WriteString(chunk.Content)
+			usage.Add(chunk)
+			if chunk.Done && chunk.Error == nil {
+				resp := &providers.ChatResponse{Content: content.String(), Model: req.Model, Usage: usage.Usage(),
+					FinishReason: chunk.FinishReason}
+				if resp.Model == "" {
+					resp.Model = p.defaults.Model
+				}
+				p.store(key, resp)
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				for range chunks {
+				}
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+
+	cacheClearCmd.Flags().BoolVar(&cacheExpired, "expired", false, "只删除过期的缓存")
+}
@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/diff"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/memory"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/session"
+
+	"github.com/spf13/viper"
+)
+
+// errMemoryUnavailable 启动时读取记忆失败，本次对话无法使用记忆
+var errMemoryUnavailable error = i18n.Error("chat.memory_off")
+
+// runChatCommand 执行交互模式中以 / 开头的命令，同时更新正在记录的会话
+func (rt *chatRuntime) runChatCommand(ctx context.Context, input string, history *[]Message, reader *lineReader) {
+	fields := strings.Fields(input)
+	switch strings.ToLower(fields[0]) {
+	case "/drop":
+		i, ok := messageIndex(fields, *history)
+		if !ok {
+			return
+		}
+		*history = append((*history)[:i], (*history)[i+1:]...)
+		rt.session.removeMessage(i)
+		fmt.Println(i18n.T("chat.dropped", i+1))
+
+	case "/redact":
+		i, ok := messageIndex(fields, *history)
+		if !ok {
+			return
+		}
+
+		content := i18n.T("chat.redacted_content")
+		if len(fields) > 2 {
+			// 只隐藏指定的文本，如误粘贴的密钥
+			args := strings.TrimSpace(strings.TrimPrefix(input, fields[0]))
+			secret := strings.TrimSpace(strings.TrimPrefix(args, fields[1]))
+			if !strings.Contains((*history)[i].Content, secret) {
+				fmt.Println(i18n.T("chat.redact_not_found", i+1))
+				return
+			}
+			content = strings.ReplaceAll((*history)[i].Content, secret, "***")
+		}
+		(*history)[i].Content = content
+		rt.session.replaceMessage(i, content)
+		fmt.Println(i18n.T("chat.redacted", i+1))
+
+	case "/pin":
+		i, ok := messageIndex(fields, *history)
+		if !ok {
+			return
+		}
+		var pinned bool
+		if !rt.session.annotate(i, func(m *session.Message) {
+			m.Pinned = !m.Pinned
+			pinned = m.Pinned
+		}) {
+			fmt.Println(i18n.T("chat.mark_unsaved", i+1))
+			return
+		}
+		if pinned {
+			fmt.Println(i18n.T("chat.pinned", i+1))
+		} else {
+			fmt.Println(i18n.T("chat.unpinned", i+1))
+		}
+
+	case "/note":
+		i, ok := messageIndex(fields, *history)
+		if !ok {
+			return
+		}
+		// 备注可以加引号，为空时删除备注
+		args := strings.TrimSpace(strings.TrimPrefix(input, fields[0]))
+		note := strings.TrimSpace(strings.TrimPrefix(args, fields[1]))
+		if unquoted, err := strconv.Unquote(note); err == nil {
+			note = strings.TrimSpace(unquoted)
+		} else if len(note) >= 2 && note[0] == '\'' && note[len(note)-1] == '\'' {
+			note = strings.TrimSpace(note[1 : len(note)-1])
+		}
+		if !rt.session.annotate(i, func(m *session.Message) { m.Note = note }) {
+			fmt.Println(i18n.T("chat.mark_unsaved", i+1))
+			return
+		}
+		if note == "" {
+			fmt.Println(i18n.T("chat.note_removed", i+1))
+		} else {
+			fmt.Println(i18n.T("chat.noted", i+1))
+		}
+
+	case "/find":
+		query := strings.TrimSpace(strings.TrimPrefix(input, fields[0]))
+		if query == "" {
+			fmt.Println(i18n.T("chat.find_usage"))
+			return
+		}
+		findMessages(*history, query)
+
+	case "/diff":
+		diffReplies(fields, *history)
+
+	case "/undo":
+		n := len(*history)
+		// 只撤销完整的一轮问答，初始对话不会被撤销
+		if n < len(rt.seed)+2 || (*history)[n-1].Role != "assistant" || (*history)[n-2].Role != "user" {
+			fmt.Println(i18n.T("chat.undo_none"))
+			return
+		}
+		*history = (*history)[:n-2]
+		rt.session.truncate(n - 2)
+		fmt.Println(i18n.T("chat.undone"))
+
+	case "/remember":
+		rt.remember(strings.TrimSpace(strings.TrimPrefix(input, fields[0])))
+
+	case "/memory":
+		rt.manageMemory(fields[1:])
+
+	case "/continue":
+		rt.continueAnswer(ctx, history)
+
+	case "/models":
+		rt.switchModel(ctx, strings.TrimSpace(strings.TrimPrefix(input, fields[0])), reader)
+
+	default:
+		ui.Warn(i18n.T("chat.unknown_command"), fields[0])
+	}
+}
+
+// switchModel 执行 /models [筛选文字]：获取提供商的模型列表，选择的模型用于本次对话之后的请求
+func (rt *chatRuntime) switchModel(ctx context.Context, filter string, reader *lineReader) {
+	models, err := rt.provider.GetModels(ctx)
+	if err != nil {
+		ui.Warn(i18n.T("chat.models_failed"), err)
+		return
+	}
+	sort.Strings(models)
+
+	current := rt.model
+	if current == "" {
+		current = rt.cfg.Providers[rt.providerName].Model
+	}
+	readLine := func() (string, bool) {
+		line, ok, _ := reader.next(0)
+		return line, ok
+	}
+	model, ok := pickModel(os.Stdout, models, current, filter, readLine)
+	if !ok {
+		fmt.Println(i18n.T("chat.model_unchanged"))
+		return
+	}
+	rt.model = model
+	fmt.Println(i18n.T("chat.model_switched", model))
+}
+
+// memoryProfile 记忆所属的配置档：优先使用 advanced.memory_profile，
+// 否则按配置文件名区分，不同的 --config 使用各自的记忆
+func memoryProfile(cfg *config.Config) string {
+	if cfg.Advanced.MemoryProfile != "" {
+		return cfg.Advanced.MemoryProfile
+	}
+	base := filepath.Base(viper.ConfigFileUsed())
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	if name == "" || name == "." || name == "config" {
+		return memory.DefaultProfile
+	}
+	return name
+}
+
+// remember 执行 /remember key=value
+func (rt *chatRuntime) remember(arg string) {
+	key, value, err := memory.ParseFact(arg)
+	if err != nil {
+		fmt.Println(i18n.T("chat.remember_usage"))
+		return
+	}
+	if rt.memory == nil {
+		ui.Warn(i18n.T("chat.memory_failed"), errMemoryUnavailable)
+		return
+	}
+	if err := rt.memory.Set(key, value); err != nil {
+		ui.Warn(i18n.T("chat.memory_failed"), err)
+		return
+	}
+	fmt.Println(i18n.T("chat.remembered", key, value))
+}
+
+// manageMemory 执行 /memory list 和 /memory forget <key>
+func (rt *chatRuntime) manageMemory(args []string) {
+	if rt.memory == nil {
+		ui.Warn(i18n.T("chat.memory_failed"), errMemoryUnavailable)
+		return
+	}
+	switch {
+	case len(args) == 0 || (len(args) == 1 && strings.EqualFold(args[0], "list")):
+		facts := rt.memory.Facts()
+		if len(facts) == 0 {
+			fmt.Println(i18n.T("chat.memory_empty"))
+			return
+		}
+		fmt.Println(i18n.T("chat.memory_title", rt.memory.Profile()))
+		for _, f := range facts {
+			fmt.Printf("  • %s = %s\n", f.Key, f.Value)
+		}
+	case len(args) >= 2 && strings.EqualFold(args[0], "forget"):
+		key := strings.Join(args[1:], " ")
+		found, err := rt.memory.Forget(key)
+		switch {
+		case err != nil:
+			ui.Warn(i18n.T("chat.memory_failed"), err)
+		case !found:
+			fmt.Println(i18n.T("chat.forget_not_found", key))
+		default:
+			fmt.Println(i18n.T("chat.forgotten", key))
+		}
+	default:
+		fmt.Println(i18n.T("chat.memory_usage"))
+	}
+}
+
+// diffReplies 显示两条AI回复之间的差异，默认比较最后两条回复，也可以指定两条消息的编号
+func diffReplies(fields []string, history []Message) {
+	var a, b int
+	switch len(fields) {
+	case 1:
+		var replies []int
+		for i, m := range history {
+			if m.Role == "assistant" {
+				replies = append(replies, i)
+			}
+		}
+		if len(replies) < 2 {
+			fmt.Println(i18n.T("chat.diff_none"))
+			return
+		}
+		a, b = replies[len(replies)-2], replies[len(replies)-1]
+	case 3:
+		var ok bool
+		if a, ok = messageIndex(fields[:2], history); !ok {
+			return
+		}
+		if b, ok = messageIndex([]string{fields[0], fields[2]}, history); !ok {
+			return
+		}
+	default:
+		fmt.Println(i18n.T("chat.diff_usage"))
+		return
+	}
+
+	unified := diff.Unified(i18n.T("chat.diff_label", a+1), i18n.T("chat.diff_label", b+1), history[a].Content, history[b].Content, 3)
+	if unified == "" {
+		fmt.Println(i18n.T("chat.diff_same", a+1, b+1))
+		return
+	}
+	printColoredDiff(unified)
+}
+
+// messageIndex 解析命令中的消息编号（从1开始，与 history 命令显示的编号一致），返回下标
+func messageIndex(fields []string, history []Message) (int, bool) {
+	if len(fields) < 2 {
+		fmt.Println(i18n.T("chat.index_usage", fields[0]))
+		return 0, false
+	}
+	n, err := strconv.Atoi(fields[1])
+	if err != nil || n < 1 || n > len(history) {
+		fmt.Println(i18n.T("chat.index_invalid", fields[1], len(history)))
+		return 0, false
+	}
+	return n - 1, true
+}
+
+// findContextRunes 搜索结果中匹配文本前后显示的字数
+const findContextRunes = 30
+
+// findMessages 显示包含指定文本的消息及其编号，匹配的文本高亮显示，不区分大小写
+func findMessages(history []Message, query string) {
+	found := 0
+	for i, msg := range history {
+		snippet, ok := findSnippet(msg.Content, query)
+		if !ok {
+			continue
+		}
+		if found == 0 {
+			fmt.Println(i18n.T("chat.find_title", query))
+		}
+		found++
+
+		switch msg.Role {
+		case "user":
+			fmt.Printf("  %d. %s%s\n", i+1, ui.Styled(ui.ElemUser, i18n.T("chat.you")), snippet)
+		case "assistant":
+			fmt.Printf("  %d. %s%s\n", i+1, ui.Styled(ui.ElemAI, i18n.T("chat.ai")), snippet)
+		default:
+			fmt.Printf("  %d. %s%s: %s\n", i+1, ui.Symbol("⚙️  ", ""), msg.Role, snippet)
+		}
+	}
+	if found == 0 {
+		fmt.Println(i18n.T("chat.find_none", query))
+		return
+	}
+	fmt.Println(i18n.T("chat.find_total", found))
+}
+
+// findSnippet 截取消息中第一处匹配前后的内容并高亮其中所有匹配的文本，没有匹配时返回false
+func findSnippet(content, query string) (string, bool) {
+	runes := []rune(strings.Join(strings.Fields(content), " "))
+	q := []rune(query)
+
+	var matches []int
+	for i := 0; i+len(q) <= len(runes); i++ {
+		if equalFoldRunes(runes[i:i+len(q)], q) {
+			matches = append(matches, i)
+			i += len(q) - 1
+		}
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	start := matches[0] - findContextRunes
+	if start < 0 {
+		start = 0
+	}
+	end := matches[0] + len(q) + findContextRunes
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	pos := start
+	for _, m := range matches {
+		if m < start || m+len(q) > end {
+			continue
+		}
+		b.WriteString(string(runes[pos:m]))
+		b.WriteString(ui.Colors().Bold(ui.Colors().Yellow(string(runes[m : m+len(q)]))).String())
+		pos = m + len(q)
+	}
+	b.WriteString(string(runes[pos:end]))
+	if end < len(runes) {
+		b.WriteString("...")
+	}
+	return b.String(), true
+}
+
+// equalFoldRunes 比较两段文字是否相同，不区分大小写
+func equalFoldRunes(a, b []rune) bool {
+	for i := range a {
+		if unicode.ToLower(a[i]) != unicode.ToLower(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// splitChunks 按段落将文本切分为不超过size个字符的块，单个超长段落按行再切分。
+// size不是正数时不切分
+func splitChunks(text string, size int) []string {
+	chunks, _ := splitChunksWithSeparators(text, size)
+	return chunks
+}
+
+// chunkPiece 切分文本的最小单位及其与前一单位之间的分隔符
+type chunkPiece struct {
+	sep  string
+	text string
+}
+
+// splitChunksWithSeparators 与 splitChunks 相同，同时返回相邻两块之间原有的分隔符
+// （段落之间为空行，行之间为换行，单行硬切处为空字符串），seps[i] 位于 chunks[i] 和 chunks[i+1] 之间，
+// 按分隔符重新拼接即可还原原文
+func splitChunksWithSeparators(text string, size int) ([]string, []string) {
+	if size <= 0 || len([]rune(text)) <= size {
+		return []string{text}, nil
+	}
+
+	var pieces []chunkPiece
+	for i, paragraph := range strings.Split(text, "\n\n") {
+		sep := "\n\n"
+		if i == 0 {
+			sep = ""
+		}
+		if len([]rune(paragraph)) <= size {
+			pieces = append(pieces, chunkPiece{sep, paragraph})
+			continue
+		}
+
+		// 超长段落按行切分，单行仍超长时按字符硬切
+		for j, line := range strings.Split(paragraph, "\n") {
+			if j > 0 {
+				sep = "\n"
+			}
+			runes := []rune(line)
+			for len(runes) > size {
+				pieces = append(pieces, chunkPiece{sep, string(runes[:size])})
+				runes = runes[size:]
+				sep = ""
+			}
+			pieces = append(pieces, chunkPiece{sep, string(runes)})
+		}
+	}
+
+	var chunks, seps []string
+	var current strings.Builder
+	currentLen := 0
+	started := false
+	for _, p := range pieces {
+		pieceLen := len([]rune(p.text))
+		if started && currentLen+len(p.sep)+pieceLen > size {
+			chunks = append(chunks, current.String())
+			seps = append(seps, p.sep)
+			current.Reset()
+			currentLen = 0
+			started = false
+		}
+		if started {
+			current.WriteString(p.sep)
+			currentLen += len(p.sep)
+		}
+		current.WriteString(p.text)
+		currentLen += pieceLen
+		started = true
+	}
+	if started {
+		chunks = append(chunks, current.String())
+	}
+
+	return chunks, seps
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		size     int
+		want     []string
+		wantSeps []string
+	}{
+		{"不切分", "第一段\n\n第二段", 0, []string{"第一段\n\n第二段"}, nil},
+		{"短文本", "短文本", 10, []string{"短文本"}, nil},
+		{"按段落切分", "aaaa\n\nbbbb\n\ncc", 8, []string{"aaaa", "bbbb\n\ncc"}, []string{"\n\n"}},
+		{"超长段落按行切分", "aaa\nbbb\nccc", 7, []string{"aaa\nbbb", "ccc"}, []string{"\n"}},
+		{"超长行按字符硬切", "一二三四五六七", 3, []string{"一二三", "四五六", "七"}, []string{"", ""}},
+		{"段落与行混合", "ab\n\ncdef\ngh", 4, []string{"ab", "cdef", "gh"}, []string{"\n\n", "\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, seps := splitChunksWithSeparators(tt.text, tt.size)
+			if !reflect.DeepEqual(chunks, tt.want) {
+				t.Errorf("splitChunks(%q, %d) = %q，期望 %q", tt.text, tt.size, chunks, tt.want)
+			}
+			if !reflect.DeepEqual(seps, tt.wantSeps) {
+				t.Errorf("分隔符 = %q，期望 %q", seps, tt.wantSeps)
+			}
+			if got := splitChunks(tt.text, tt.size); !reflect.DeepEqual(got, chunks) {
+				t.Errorf("splitChunks 与 splitChunksWithSeparators 的结果不一致: %q", got)
+			}
+		})
+	}
+}
+
+func TestSplitChunksRestoresText(t *testing.T) {
+	text := strings.Repeat("段落内容，", 30) + "\n\n" +
+		strings.Repeat("第一行\n", 20) + "\n" +
+		strings.Repeat("很长的一行没有换行", 20)
+	for _, size := range []int{5, 17, 40, 100} {
+		chunks, seps := splitChunksWithSeparators(text, size)
+		if len(seps) != len(chunks)-1 {
+			t.Fatalf("size=%d: %d 块对应 %d 个分隔符", size, len(chunks), len(seps))
+		}
+		var b strings.Builder
+		for i, c := range chunks {
+			if n := len([]rune(c)); n > size {
+				t.Errorf("size=%d: 第 %d 块有 %d 个字符", size, i+1, n)
+			}
+			b.WriteString(c)
+			if i < len(seps) {
+				b.WriteString(seps[i])
+			}
+		}
+		if b.String() != text {
+			t.Errorf("size=%d: 按分隔符拼接后与原文不一致", size)
+		}
+	}
+}
+
+func TestSplitFileDiff(t *testing.T) {
+	header := "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n"
+	hunk1 := "@@ -1,2 +1,2 @@\n-a\n+b"
+	hunk2 := "@@ -10,2 +10,2 @@\n-c\n+d"
+	file := header + hunk1 + "\n" + hunk2
+
+	if got := splitFileDiff(file, len(file)+10); !reflect.DeepEqual(got, []string{file}) {
+		t.Errorf("放得下时不应切分: %q", got)
+	}
+
+	got := splitFileDiff(file, len(header)+len(hunk2)+1)
+	want := []string{header + hunk1, header + hunk2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitFileDiff = %q，期望 %q", got, want)
+	}
+
+	if got := splitFileDiff("Binary files differ", 5); len(got) < 2 {
+		t.Errorf("没有 hunk 时应按普通文本切分: %q", got)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"strings"
 
 	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/filelock"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -27,28 +30,28 @@ var configInitCmd = &cobra.Command{
 		// 获取配置文件路径
 		configPath, err := config.GetDefaultConfigPath()
 		if err != nil {
-			fmt.Printf("错误：无法获取配置文件路径: %v\n", err)
+			fail(ExitConfig, i18n.T("config.path_failed"), err)
 			return
 		}
 
 		// 检查配置文件是否已存在
 		if _, err := os.Stat(configPath); err == nil {
-			fmt.Printf("配置文件已存在: %s\n", configPath)
-			fmt.Println("如果要重新初始化，请先删除现有配置文件。")
+			fmt.Println(i18n.T("config.exists", configPath))
+			fmt.Println(i18n.T("config.exists_hint"))
 			return
 		}
 
 		// 创建示例配置
 		if err := createExampleConfig(configPath); err != nil {
-			fmt.Printf("错误：创建配置文件失败: %v\n", err)
+			fail(ExitConfig, i18n.T("config.create_failed"), err)
 			return
 		}
 
-		fmt.Printf("✓ 配置文件已创建: %s\n", configPath)
-		fmt.Println("\n请编辑配置文件，设置您的API密钥：")
-		fmt.Printf("  - OpenAI API密钥: 设置 OPENAI_API_KEY 环境变量\n")
-		fmt.Printf("  - Anthropic API密钥: 设置 ANTHROPIC_API_KEY 环境变量\n")
-		fmt.Println("\n或者直接在配置文件中设置 api_key 字段。")
+		ui.Success(i18n.T("config.created"), configPath)
+		fmt.Printf("\n%s\n", i18n.T("config.edit_keys"))
+		fmt.Printf("  %s\n", i18n.T("config.openai_key"))
+		fmt.Printf("  %s\n", i18n.T("config.anthropic_key"))
+		fmt.Printf("\n%s\n", i18n.T("config.key_field"))
 	},
 }
 
@@ -60,29 +63,31 @@ var configShowCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg, err := config.Load()
 		if err != nil {
-			fmt.Printf("错误：加载配置失败: %v\n", err)
+			fail(ExitConfig, i18n.T("config.load_failed"), err)
 			return
 		}
 
-		fmt.Println("当前配置:")
-		fmt.Printf("  默认提供商: %s\n", cfg.Default.Provider)
-		fmt.Printf("  流式输出: %t\n", cfg.Default.Stream)
-		fmt.Printf("  最大重试: %d\n", cfg.Advanced.MaxRetries)
-		fmt.Printf("  超时时间: %d秒\n", cfg.Advanced.Timeout)
-		fmt.Printf("  成本限制: $%.2f\n", cfg.Advanced.CostLimit)
+		fmt.Println(i18n.T("config.current"))
+		fmt.Printf("  %s\n", i18n.T("config.default_provider", cfg.Default.Provider))
+		fmt.Printf("  %s\n", i18n.T("config.stream", cfg.Default.Stream))
+		fmt.Printf("  %s\n", i18n.T("config.max_retries", cfg.Advanced.MaxRetries))
+		fmt.Printf("  %s\n", i18n.T("config.timeout", cfg.Advanced.Timeout))
+		fmt.Printf("  %s\n", i18n.T("config.cost_limit", cfg.Advanced.CostLimit))
 
-		fmt.Println("\n已配置的提供商:")
+		fmt.Printf("\n%s\n", i18n.T("config.providers"))
 		for name, provider := range cfg.Providers {
-			apiKeyStatus := "未设置"
-			if provider.APIKey != "" {
-				apiKeyStatus = "已设置"
+			apiKeyStatus := i18n.T("config.key_unset")
+			if provider.UsesToken() {
+				apiKeyStatus = i18n.T("config.key_token", provider.Auth.Type)
+			} else if provider.APIKey != "" {
+				apiKeyStatus = i18n.T("config.key_set")
 			} else if os.Getenv(strings.ToUpper(name)+"_API_KEY") != "" {
-				apiKeyStatus = "环境变量"
+				apiKeyStatus = i18n.T("config.key_env")
 			}
 			fmt.Printf("  %s:\n", name)
-			fmt.Printf("    模型: %s\n", provider.Model)
-			fmt.Printf("    API密钥: %s\n", apiKeyStatus)
-			fmt.Printf("    最大Token: %d\n", provider.MaxTokens)
+			fmt.Printf("    %s\n", i18n.T("config.model", provider.Model))
+			fmt.Printf("    %s\n", i18n.T("config.api_key", apiKeyStatus))
+			fmt.Printf("    %s\n", i18n.T("config.max_tokens", provider.MaxTokens))
 		}
 	},
 }
@@ -102,16 +107,20 @@ var configSetCmd = &cobra.Command{
 		key := args[0]
 		value := args[1]
 
-		// 设置配置值
-		viper.Set(key, value)
+		path := viper.ConfigFileUsed()
+		if path == "" {
+			fail(ExitConfig, "%s", i18n.T("config.not_found"))
+			hint("%s", i18n.T("config.init_hint"))
+			return
+		}
 
-		// 保存配置
-		if err := viper.WriteConfig(); err != nil {
-			fmt.Printf("错误：保存配置失败: %v\n", err)
+		// 加锁后重新读取配置文件再修改，同时运行的命令不会互相覆盖
+		if err := config.Set(path, key, value); err != nil {
+			fail(ExitConfig, i18n.T("config.save_failed"), err)
 			return
 		}
 
-		fmt.Printf("✓ 已设置 %s = %s\n", key, value)
+		ui.Success(i18n.T("config.set"), key, value)
 	},
 }
 
@@ -134,6 +143,15 @@ providers:
     base_url: "https://api.openai.com/v1"
     model: "gpt-4o"
     max_tokens: 4096
+    # 生成参数的默认值，可以按提供商分别设置，chat 的 --temperature、--top-p 优先
+    # temperature: 0.7     # 不设置时为 0.7
+    # top_p: 1
+    # presence_penalty: 0
+    # frequency_penalty: 0
+    # stop: ["END"]
+    # user_id: "alice@example.com"  # 随请求发送的 user 字段，供网关归属统计
+    # metadata:                     # 随请求发送的 metadata，--metadata key=value 追加或覆盖
+    #   team: "platform"
 
   anthropic:
     # API密钥（推荐使用环境变量 ANTHROPIC_API_KEY）
@@ -142,6 +160,31 @@ providers:
     model: "claude-3-sonnet-20240229"
     max_tokens: 4096
 
+  # 兼容OpenAI的本地服务，用 capabilities 列出支持的功能（streaming、tools、vision、embeddings），
+  # 使用不支持的功能时直接报错，不发送请求；不设置时为 streaming 和 vision
+  # ollama:
+  #   api_key: "ollama"
+  #   base_url: "http://localhost:11434/v1"
+  #   model: "llama3.1"
+  #   capabilities: ["streaming"]
+
+  # 请求格式与标准不同的服务，用 compat 调整字段，避免400错误
+  # o-series:
+  #   model: "o3-mini"
+  #   compat:
+  #     max_tokens_field: "max_completion_tokens"  # max_tokens（默认）、max_completion_tokens 或 none
+  #     omit_temperature: true                     # 不发送 temperature
+  #     stream_usage: true                         # 流式请求带上 stream_options.include_usage
+
+  # 使用 OAuth 令牌代替API密钥，令牌过期前自动刷新
+  # azure:
+  #   base_url: "https://<资源名>.openai.azure.com/openai/deployments/<部署名>"
+  #   auth:
+  #     type: "azure_ad"     # azure_ad、oauth（需要 token_url）或 google_adc（使用 gcloud 的应用默认凭据）
+  #     tenant_id: ""
+  #     client_id: ""
+  #     client_secret: ""
+
 # 默认设置
 default:
   provider: "openai"   # 默认使用的AI提供商
@@ -149,16 +192,83 @@ default:
 
 # 高级设置
 advanced:
-  max_retries: 3       # 最大重试次数
+  max_retries: 3       # 请求被限流（429）或服务端暂时故障（5xx）时的最大重试次数
+  stream_resumes: 2    # 流式回复中途断开时自动续写的最多次数，-1 表示不续写
   timeout: 30          # 请求超时时间（秒）
   cost_limit: 10.0     # 每日成本限制（美元）
   save_history: true   # 是否保存对话历史
   history_length: 10   # 保存的历史对话数量
+  # moderate_inputs: "warn"  # 发送前审核输入: warn（警告后继续）或 block（拒绝发送）
+  # title_model: "gpt-4o-mini"  # 自动生成会话标题使用的模型，默认使用当前模型，off 表示关闭
+  # confirm_input_tokens: 20000  # 输入超过该token数时发送前需要确认，-1 表示不检查
+  # check_updates: false  # 关闭每天一次的新版本检查（默认在终端中运行时后台检查）
+  # encrypt_sessions: true  # 加密保存的会话，密钥保存在系统钥匙串中
+  # mask_pii: true  # 发送前将邮箱、电话、证件号码和密钥替换为占位符，回复中自动恢复
+  # cache: true  # 缓存回复，相同的请求直接返回缓存的回复（cache clear 清除）
+  # cache_ttl: 24  # 缓存的有效期（小时）
+  # reply_language: "auto"  # 回答语言: auto 与问题相同，或 zh、en 等固定语言
+  # memory_profile: "work"  # /remember 记住的事实属于哪个配置档，默认按配置文件名区分
 
 # 日志设置
 logging:
   level: "info"        # 日志级别: debug, info, warn, error
-  requests: false      # 是否记录API请求日志
+  requests: false      # 是否记录API请求日志（方法、地址、状态码和耗时）
+  # file: "~/.ai-chat-cli/logs/serve.log"  # serve 的日志文件，按大小轮转
+  # max_size: 10       # 单个日志文件最大大小（MB）
+  # max_age: 30        # 轮转文件保留天数，0 表示不按时间清理
+  # max_backups: 5     # 最多保留的轮转文件数
+
+# 界面设置
+# ui:
+#   language: "en-US"  # 界面语言: zh-CN 或 en-US，默认根据 LANG 环境变量选择
+#   stats: "on"        # 回复后的用量统计: on、off 或 verbose（附加耗时和输出速度）
+#   theme: "default"   # 界面主题: default、vivid（着色）或 plain（不使用表情符号）
+#   symbols:           # 覆盖主题中的符号，元素: user、ai、stats、error、warn、hint、success
+#     user: "> "
+#   colors:            # 覆盖主题中的颜色: red、green、yellow、blue、magenta、cyan、white、gray、black、bold、none，可以组合
+#     ai: "bold cyan"
+#   idle_timeout: 30   # 交互模式空闲30分钟后保存会话并退出，0 表示不限制
+#   idle_action: "exit"  # exit 或 lock（清屏并清空内存中的对话，输入解锁口令后从加密的会话重新读取，需要 encrypt_sessions 和 session passphrase）
+
+# 会话分享设置（session share）
+# share:
+#   target: "gist"     # 分享目标: gist、0x0 或 paste
+#   gist_token: ""     # GitHub token，为空时使用 GITHUB_TOKEN 环境变量
+#   public: false      # 是否创建公开的 gist
+#   paste_url: "https://paste.example.com/api"  # 自定义粘贴服务，POST 原文后返回链接
+
+# issue --post 提交问题的设置
+# issues:
+#   tracker: "github"  # github 或 jira
+#   repo: "org/name"
+#   github_token: ""   # 为空时使用 GITHUB_TOKEN 环境变量
+#   jira_url: "https://example.atlassian.net"
+#   jira_email: ""
+#   jira_token: ""     # 为空时使用 JIRA_API_TOKEN 环境变量
+#   jira_project: "APP"
+#   jira_issue_type: "Bug"
+
+# 审计日志（哈希链，可用 audit verify 校验）
+# audit:
+#   enabled: true
+#   file: "~/.ai-chat-cli/audit.jsonl"
+#   content: "hash"    # hash 只记录提示词和回复的SHA-256，full 记录完整内容
+
+# 按问题复杂度自动选择模型（模型属于当前使用的提供商）
+# routing:
+#   enabled: true
+#   fast: "gpt-4o-mini"    # 简短对话
+#   smart: "gpt-4o"        # 长输入、代码和需要推理的问题
+#   classifier: "heuristic"  # heuristic 按长度、代码和关键词判断，model 请快速模型判断
+
+# 发送前检查提示词，防止内部主机名、客户编号等敏感内容发送到外部API
+# filters:
+#   - name: "内部主机名"
+#     pattern: '[a-z0-9-]+\.corp\.example\.com'  # 正则表达式
+#     action: "block"    # block 拒绝发送（默认），warn 警告后发送，confirm 确认后发送
+#   - name: "客户编号"
+#     keywords: ["CUST-"]  # 不区分大小写的关键词
+#     action: "confirm"
 `
 
 	// 确保目录存在
@@ -167,5 +277,10 @@ logging:
 	}
 
 	// 写入配置文件
-	return os.WriteFile(filename, []byte(configContent), 0644)
+	lock, err := filelock.Acquire(filename)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	return filelock.WriteFile(filename, []byte(configContent), 0644)
 }
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+)
+
+// maxContinuations --continue-output 一次回答最多请求剩余部分的次数，避免模型每次都写到上限时不断请求
+const maxContinuations = 5
+
+// continueInstruction 请求剩余部分时附加的系统指令，已收到的回答作为未完成的助手消息放在其后
+const continueInstruction = "Your previous response reached the output token limit. The assistant message that follows is what you " +
+	"have written so far. Continue exactly where it stops, mid-word or mid-line if necessary, without repeating any of it and without any preamble."
+
+// continuingProvider 回复因达到 max_tokens 上限被截断时（finish_reason 为 length）自动请求剩余部分，
+// 拼接为一个完整的回复，用于 chat --continue-output
+type continuingProvider struct {
+	providers.Provider
+}
+
+// newContinuingProvider 创建截断后自动请求剩余部分的提供商
+func newContinuingProvider(p providers.Provider) providers.Provider {
+	return &continuingProvider{Provider: p}
+}
+
+// Unwrap 返回被包装的提供商
+func (p *continuingProvider) Unwrap() providers.Provider {
+	return p.Provider
+}
+
+// Chat 发送对话请求，回复被截断时请求剩余部分并拼接，用量为各次请求之和
+func (p *continuingProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	resp, err := p.Provider.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < maxContinuations && resp.FinishReason == providers.FinishLength; i++ {
+		next, err := p.Provider.Chat(ctx, resumeRequest(req, resp.Content, continueInstruction))
+		if err != nil {
+			return nil, err
+		}
+		resp = &providers.ChatResponse{
+			Content:      resp.Content + remainder(resp.Content, next.Content),
+			Model:        resp.Model,
+			Usage:        addUsage(resp.Usage, next.Usage),
+			FinishReason: next.FinishReason,
+		}
+	}
+	return resp, nil
+}
+
+// ChatStream 发送流式对话请求，回复被截断时请求剩余部分，继续转发其内容
+func (p *continuingProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	chunks, err := p.Provider.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan providers.StreamChunk)
+	go func() {
+		defer close(out)
+		var reply strings.Builder
+		var usage *providers.Usage
+		skip, resumed := 0, false
+		for i := 0; ; i++ {
+			done, streamErr := relayStream(ctx, chunks, out, &reply, skip, resumed)
+			if streamErr != nil {
+				sendChunk(ctx, out, providers.StreamChunk{Error: streamErr})
+				return
+			}
+			// 有一次请求没有返回用量时不合计，由调用方按内容估算
+			if i == 0 {
+				usage = done.Usage
+			} else if usage != nil && done.Usage != nil {
+				total := addUsage(*usage, *done.Usage)
+				usage = &total
+			} else {
+				usage = nil
+			}
+			if done.FinishReason != providers.FinishLength || i >= maxContinuations {
+				done.Usage = usage
+				sendChunk(ctx, out, done)
+				return
+			}
+
+			next := resumeRequest(req, reply.String(), continueInstruction)
+			if chunks, err = p.Provider.ChatStream(ctx, next); err != nil {
+				sendChunk(ctx, out, providers.StreamChunk{Error: err})
+				return
+			}
+			skip, resumed = len(next.AssistantPrefix), true
+		}
+	}()
+	return out, nil
+}
+
+// remainder 续写请求返回的内容中新增的部分：去掉开头的已有内容，以及模型重新写出的与已有内容结尾重复的部分
+func remainder(partial, continued string) string {
+	return trimOverlap(partial, strings.TrimPrefix(continued, partial))
+}
+
+// addUsage 合计两次请求的用量
+func addUsage(a, b providers.Usage) providers.Usage {
+	return providers.Usage{
+		PromptTokens:     a.PromptTokens + b.PromptTokens,
+		CompletionTokens: a.CompletionTokens + b.CompletionTokens,
+		TotalTokens:      a.TotalTokens + b.TotalTokens,
+		Cost:             a.Cost + b.Cost,
+	}
+}
+
+// warnFinishReason 回复没有正常结束时提示：被 max_tokens 截断时交互模式中提示 /continue，单次对话提示 --continue-output；
+// 被内容安全策略过滤或模型拒绝回答时提示回答可能为空或不完整
+func (rt *chatRuntime) warnFinishReason(resp *providers.ChatResponse) {
+	switch resp.FinishReason {
+	case providers.FinishLength:
+		ui.Warn("%s", i18n.T("chat.length_truncated"))
+		switch {
+		case rt.interactive:
+			hint("%s", i18n.T("chat.continue_hint_repl"))
+		case !rt.continueOutput:
+			hint("%s", i18n.T("chat.continue_hint_once"))
+		}
+	case providers.FinishContentFilter:
+		ui.Warn("%s", i18n.T("chat.content_filtered"))
+	case providers.FinishRefusal:
+		ui.Warn("%s", i18n.T("chat.refused"))
+	}
+}
+
+// continueAnswer 执行 /continue：请求最后一条回答的剩余部分，输出新增的内容并接在原回答后面
+func (rt *chatRuntime) continueAnswer(ctx context.Context, history *[]Message) {
+	n := len(*history)
+	if n == 0 || (*history)[n-1].Role != "assistant" {
+		fmt.Println(i18n.T("chat.continue_no_answer"))
+		return
+	}
+	last := &(*history)[n-1]
+
+	var messages []providers.Message
+	for _, m := range rt.requestHistory((*history)[:n-1]) {
+		messages = append(messages, providers.Message{Role: m.Role, Content: m.Content})
+	}
+	req := &providers.ChatRequest{
+		Messages:    rt.withDirectives(messages),
+		Model:       rt.model,
+		Temperature: rt.temperature,
+		Sampling:    rt.sampling,
+	}
+	// 与普通回答一样以流式请求接收，网络中断时由 advanced.stream_resumes 自动续写
+	next := resumeRequest(req, last.Content, continueInstruction)
+	var resp *providers.ChatResponse
+	var err error
+	if rt.stream {
+		resp, err = receiveStream(ctx, rt.provider, next, providers.StartTimer(), providers.NewStreamUsage(next), nil)
+	} else {
+		resp, err = rt.provider.Chat(ctx, next)
+	}
+	if err != nil {
+		fmt.Println(i18n.T("chat.continue_failed", err))
+		return
+	}
+
+	rest := remainder(last.Content, resp.Content)
+	fmt.Print(ui.Styled(ui.ElemAI, i18n.T("chat.ai")))
+	fmt.Println(rest)
+	last.Content += rest
+	usage := resp.Usage
+	if last.Usage != nil {
+		usage = addUsage(*last.Usage, usage)
+	}
+	last.Usage = &usage
+	rt.session.update(func(s *session.Session) bool {
+		if n-1 >= len(s.Messages) {
+			return false
+		}
+		s.Messages[n-1].Content, s.Messages[n-1].Usage = last.Content, last.Usage
+		return true
+	})
+	rt.warnFinishReason(resp)
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"ai-chat-cli/internal/cache"
+	"ai-chat-cli/pkg/providers"
+)
+
+// dedupedProvider 合并同时进行的相同请求：提供商、模型、消息和参数都相同的请求只向上游发送一次，
+// 所有调用方共享结果。流式请求后加入的调用方会先收到已经返回的数据块。
+// 只要还有调用方在等待，上游请求就不会因为先发起请求的调用方取消而中断，
+// 所有调用方都取消后上游请求被取消，之后的相同请求重新发送
+type dedupedProvider struct {
+	providers.Provider
+
+	mu      sync.Mutex
+	chats   map[string]*chatFlight
+	streams map[string]*streamFlight
+	merged  *atomic.Int64 // 统计被合并、没有单独发送的请求数
+}
+
+// chatFlight 进行中的非流式请求
+type chatFlight struct {
+	done   chan struct{}
+	resp   *providers.ChatResponse
+	err    error
+	refs   int // 等待结果的调用方数量，为0时取消上游请求
+	cancel context.CancelFunc
+}
+
+// streamFlight 进行中的流式请求，保存收到的所有数据块
+type streamFlight struct {
+	started  chan struct{} // 上游请求已建立或失败
+	startErr error
+
+	mu      sync.Mutex
+	chunks  []providers.StreamChunk
+	done    bool
+	changed chan struct{} // 收到新数据块或结束时关闭并替换
+	refs    int
+	cancel  context.CancelFunc
+}
+
+// newDedupedProvider 创建合并相同请求的提供商，被合并的请求计入 merged，多个提供商可以共用一个计数
+func newDedupedProvider(p providers.Provider, merged *atomic.Int64) *dedupedProvider {
+	return &dedupedProvider{
+		Provider: p,
+		merged:   merged,
+		chats:    map[string]*chatFlight{},
+		streams:  map[string]*streamFlight{},
+	}
+}
+
+// Unwrap 返回被包装的提供商
+func (p *dedupedProvider) Unwrap() providers.Provider {
+	return p.Provider
+}
+
+// key 请求的合并键
+func (p *dedupedProvider) key(req *providers.ChatRequest) string {
+	return cache.Key(p.GetName(), cache.Defaults{}, req)
+}
+
+// Chat 有相同的请求正在进行时等待其结果，否则发送请求
+func (p *dedupedProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	key := p.key(req)
+
+	p.mu.Lock()
+	f, ok := p.chats[key]
+	if ok && f.refs > 0 {
+		f.refs++
+		p.merged.Add(1)
+	} else {
+		upstream, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &chatFlight{done: make(chan struct{}), refs: 1, cancel: cancel}
+		p.chats[key] = f
+		go func() {
+			defer cancel()
+			f.resp, f.err = p.Provider.Chat(upstream, req)
+			p.mu.Lock()
+			if p.chats[key] == f {
+				delete(p.chats, key)
+			}
+			p.mu.Unlock()
+			close(f.done)
+		}()
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.err != nil {
+			return nil, f.err
+		}
+		resp := *f.resp
+		return &resp, nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		if f.refs--; f.refs == 0 {
+			f.cancel()
+		}
+		p.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// ChatStream 有相同的流式请求正在进行时共享其数据块，否则发送流式请求
+func (p *dedupedProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	key := p.key(req)
+
+	p.mu.Lock()
+	f, ok := p.streams[key]
+	if ok && f.refs > 0 {
+		f.refs++
+		p.merged.Add(1)
+	} else {
+		upstream, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &streamFlight{started: make(chan struct{}), changed: make(chan struct{}), refs: 1, cancel: cancel}
+		p.streams[key] = f
+		go p.runStream(upstream, key, f, req)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-f.started:
+	case <-ctx.Done():
+		p.release(f)
+		return nil, ctx.Err()
+	}
+	if f.startErr != nil {
+		return nil, f.startErr
+	}
+
+	out := make(chan providers.StreamChunk)
+	go func() {
+		defer close(out)
+		for i := 0; ; {
+			f.mu.Lock()
+			if i < len(f.chunks) {
+				chunk := f.chunks[i]
+				f.mu.Unlock()
+				i++
+				select {
+				case out <- chunk:
+					continue
+				case <-ctx.Done():
+					p.release(f)
+					return
+				}
+			}
+			if f.done {
+				f.mu.Unlock()
+				return
+			}
+			changed := f.changed
+			f.mu.Unlock()
+
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				p.release(f)
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// runStream 发送上游流式请求，保存收到的数据块并通知等待的调用方
+func (p *dedupedProvider) runStream(ctx context.Context, key string, f *streamFlight, req *providers.ChatRequest) {
+	defer f.cancel()
+	chunks, err := p.Provider.ChatStream(ctx, req)
+	f.startErr = err
+	close(f.started)
+
+	if err == nil {
+		for chunk := range chunks {
+			f.mu.Lock()
+			f.chunks = append(f.chunks, chunk)
+			close(f.changed)
+			f.changed = make(chan struct{})
+			f.mu.Unlock()
+		}
+	}
+
+	// 先从进行中的请求中删除，之后的相同请求重新发送，已加入的调用方可以读完所有数据块
+	p.mu.Lock()
+	if p.streams[key] == f {
+		delete(p.streams, key)
+	}
+	p.mu.Unlock()
+
+	f.mu.Lock()
+	f.done = true
+	close(f.changed)
+	f.mu.Unlock()
+}
+
+// release 调用方不再等待流式请求的结果，没有调用方时取消上游请求
+func (p *dedupedProvider) release(f *streamFlight) {
+	p.mu.Lock()
+	if f.refs--; f.refs == 0 {
+		f.cancel()
+	}
+	p.mu.Unlock()
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// 回答语言的特殊取值
+const (
+	replyLanguageAuto = "auto" // 与问题使用相同的语言
+	replyLanguageOff  = "off"  // 不指定，--reply-language off 可以临时关闭配置
+)
+
+// languageNames 常用语言代码对应的名称，其他取值按原样写入指令
+var languageNames = map[string]string{
+	"zh":    "Simplified Chinese",
+	"zh-cn": "Simplified Chinese",
+	"zh-tw": "Traditional Chinese",
+	"en":    "English",
+	"en-us": "English",
+	"ja":    "Japanese",
+	"ko":    "Korean",
+	"fr":    "French",
+	"de":    "German",
+	"es":    "Spanish",
+	"ru":    "Russian",
+}
+
+// replyLanguageDirective 回答语言的系统指令，未指定语言时为空
+func replyLanguageDirective(language string) string {
+	switch strings.ToLower(language) {
+	case "", replyLanguageOff:
+		return ""
+	case replyLanguageAuto:
+		return "Always reply in the same language as the user's latest message, even if earlier messages, " +
+			"attached files or quoted text are in another language."
+	}
+	name := language
+	if n, ok := languageNames[strings.ToLower(language)]; ok {
+		name = n
+	}
+	return fmt.Sprintf("Always reply in %s, whatever language the question or attached content is in, "+
+		"unless the user explicitly asks for another language. Keep code, commands and identifiers unchanged.", name)
+}
+
+// withDirectives 在开头的系统消息之后加入程序管理的指令（回答语言和 /remember 记住的事实），
+// 指令每次请求时重新生成，不写入对话历史，修改配置或记忆后对已保存的会话同样生效
+func (rt *chatRuntime) withDirectives(messages []providers.Message) []providers.Message {
+	var parts []string
+	if directive := replyLanguageDirective(rt.replyLanguage); directive != "" {
+		parts = append(parts, directive)
+	}
+	if rt.memory != nil {
+		if facts := rt.memory.Prompt(); facts != "" {
+			parts = append(parts, facts)
+		}
+	}
+	if len(parts) == 0 {
+		return messages
+	}
+
+	i := 0
+	for i < len(messages) && messages[i].Role == "system" {
+		i++
+	}
+	result := append([]providers.Message{}, messages[:i]...)
+	result = append(result, providers.Message{Role: "system", Content: strings.Join(parts, "\n\n")})
+	return append(result, messages[i:]...)
+}
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// 诊断使用的超时和阈值
+const (
+	doctorDialTimeout = 5 * time.Second
+	doctorChatTimeout = 30 * time.Second
+	doctorMaxSkew     = 5 * time.Minute
+)
+
+var (
+	doctorProvider     string
+	doctorNoCompletion bool
+)
+
+// doctorCmd 检查配置和提供商的可用性
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "检查配置、网络和各个提供商是否可用",
+	Long: `逐项检查运行环境并给出修复建议:
+  • 配置文件能否解析
+  • 代理设置（HTTP_PROXY、HTTPS_PROXY、NO_PROXY）
+  • 每个提供商的API密钥、base_url 的网络连通性和本机时钟偏差
+  • 每个提供商发送一次只生成1个token的测试请求（--no-completion 跳过，不产生费用）
+
+有检查失败时返回状态码1。
+
+示例:
+  ai-chat-cli doctor
+  ai-chat-cli doctor -p openai
+  ai-chat-cli doctor --no-completion`,
+	Args: cobra.NoArgs,
+	Run:  runDoctor,
+}
+
+// 检查结果
+const (
+	doctorPass = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorResult 一项检查的结果
+type doctorResult struct {
+	status int
+	name   string
+	detail string
+	fix    string // 修复建议
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	var results []doctorResult
+	report := func(rs ...doctorResult) {
+		for _, r := range rs {
+			printDoctorResult(r)
+		}
+		results = append(results, rs...)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		report(doctorResult{status: doctorFail, name: i18n.T("doctor.config"), detail: err.Error(), fix: i18n.T("doctor.config_fix")})
+		finishDoctor(results)
+		return
+	}
+	report(doctorResult{status: doctorPass, name: i18n.T("doctor.config"), detail: viper.ConfigFileUsed()})
+	report(checkProxyEnv()...)
+
+	names := make([]string, 0, len(cfg.Providers))
+	for name := range cfg.Providers {
+		if doctorProvider == "" || name == doctorProvider {
+			names = append(names, name)
+		}
+	}
+	if doctorProvider != "" && len(names) == 0 {
+		if !providers.Standalone(doctorProvider) {
+			fail(ExitConfig, i18n.T("provider.not_found"), doctorProvider)
+			return
+		}
+		names = append(names, doctorProvider)
+	}
+	if len(names) == 0 {
+		report(doctorResult{status: doctorFail, name: i18n.T("doctor.provider"), detail: i18n.T("doctor.no_providers"), fix: i18n.T("doctor.no_providers_fix")})
+		finishDoctor(results)
+		return
+	}
+	sort.Strings(names)
+
+	// 各提供商并发检查，按名称顺序输出
+	checks := make([][]doctorResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			checks[i] = checkProvider(cmd.Context(), cfg, name)
+		}(i, name)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		fmt.Printf("\n%s\n", ui.Status("🔌 "+name))
+		report(checks[i]...)
+	}
+	finishDoctor(results)
+}
+
+// finishDoctor 输出检查汇总，有失败项时设置退出状态码
+func finishDoctor(results []doctorResult) {
+	var warned, failed int
+	for _, r := range results {
+		switch r.status {
+		case doctorWarn:
+			warned++
+		case doctorFail:
+			failed++
+		}
+	}
+	fmt.Println()
+	if failed > 0 {
+		fail(ExitError, i18n.T("doctor.failed"), failed, warned)
+		return
+	}
+	if warned > 0 {
+		fmt.Println(ui.Styled(ui.ElemWarn, i18n.T("doctor.passed_warnings", warned)))
+		return
+	}
+	ui.Success("%s", i18n.T("doctor.passed"))
+}
+
+// printDoctorResult 输出一项检查结果及修复建议
+func printDoctorResult(r doctorResult) {
+	line := r.name
+	if r.detail != "" {
+		line += ": " + r.detail
+	}
+	if ui.StdoutIsTerminal() {
+		elem := map[int]string{doctorPass: ui.ElemSuccess, doctorWarn: ui.ElemWarn, doctorFail: ui.ElemError}[r.status]
+		fmt.Println(ui.Styled(elem, line))
+	} else {
+		fmt.Println(map[int]string{doctorPass: "PASS", doctorWarn: "WARN", doctorFail: "FAIL"}[r.status] + " " + line)
+	}
+	if r.fix != "" && r.status != doctorPass {
+		fmt.Printf("   %s\n", ui.Styled(ui.ElemHint, r.fix))
+	}
+}
+
+// checkProxyEnv 检查代理环境变量的格式
+func checkProxyEnv() []doctorResult {
+	var results []doctorResult
+	for _, key := range []string{"HTTPS_PROXY", "HTTP_PROXY", "ALL_PROXY", "NO_PROXY"} {
+		v := os.Getenv(key)
+		if v == "" {
+			v = os.Getenv(strings.ToLower(key))
+		}
+		if v == "" {
+			continue
+		}
+		if key == "NO_PROXY" {
+			results = append(results, doctorResult{status: doctorPass, name: i18n.T("doctor.proxy"), detail: "NO_PROXY=" + v})
+			continue
+		}
+		u, err := url.Parse(v)
+		if err != nil || u.Host == "" {
+			results = append(results, doctorResult{status: doctorFail, name: i18n.T("doctor.proxy"), detail: i18n.T("doctor.proxy_invalid", key, v), fix: i18n.T("doctor.proxy_fix")})
+			continue
+		}
+		if u.User != nil {
+			u.User = url.User(u.User.Username())
+		}
+		results = append(results, doctorResult{status: doctorPass, name: i18n.T("doctor.proxy"), detail: key + "=" + u.String()})
+	}
+	if len(results) == 0 {
+		results = append(results, doctorResult{status: doctorPass, name: i18n.T("doctor.proxy"), detail: i18n.T("doctor.proxy_none")})
+	}
+	return results
+}
+
+// checkProvider 检查一个提供商的密钥、网络、时钟和测试请求
+func checkProvider(ctx context.Context, cfg *config.Config, name string) []doctorResult {
+	providerCfg := cfg.Providers[name]
+	var results []doctorResult
+
+	if path, ok := providers.FindPlugin(name); ok {
+		results = append(results, doctorResult{status: doctorPass, name: i18n.T("doctor.plugin"), detail: path})
+	} else if name == providers.MockName {
+		results = append(results, doctorResult{status: doctorPass, name: i18n.T("doctor.mock"), detail: i18n.T("doctor.mock_detail")})
+	} else {
+		if providerCfg.UsesToken() {
+			if _, err := tokenSource(name, providerCfg.Auth).Token(ctx); err != nil {
+				return append(results, doctorResult{status: doctorFail, name: i18n.T("doctor.auth"), detail: err.Error(),
+					fix: i18n.T("doctor.auth_fix", name, providerCfg.Auth.Type)})
+			}
+			results = append(results, doctorResult{status: doctorPass, name: i18n.T("doctor.auth"), detail: i18n.T("doctor.auth_ok", providerCfg.Auth.Type)})
+		} else if providerCfg.APIKey == "" {
+			return append(results, doctorResult{status: doctorFail, name: i18n.T("doctor.api_key"), detail: i18n.T("config.key_unset"),
+				fix: fmt.Sprintf("ai-chat-cli config set providers.%s.api_key YOUR_API_KEY", name)})
+		} else {
+			results = append(results, doctorResult{status: doctorPass, name: i18n.T("doctor.api_key"), detail: i18n.T("config.key_set")})
+		}
+
+		network := checkNetwork(ctx, providerCfg.BaseURL)
+		results = append(results, network...)
+		for _, r := range network {
+			if r.status == doctorFail {
+				return results
+			}
+		}
+	}
+
+	results = append(results, doctorResult{status: doctorPass, name: i18n.T("doctor.capabilities"), detail: formatCapabilities(providerCapabilities(name, providerCfg))})
+
+	if doctorNoCompletion {
+		return results
+	}
+	return append(results, checkCompletion(ctx, cfg, name, providerCfg))
+}
+
+// checkNetwork 检查 base_url 能否连接，并根据响应的 Date 头检查本机时钟偏差
+func checkNetwork(ctx context.Context, baseURL string) []doctorResult {
+	if baseURL == "" {
+		baseURL = providers.DefaultBaseURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return []doctorResult{{status: doctorFail, name: i18n.T("doctor.network"), detail: i18n.T("doctor.base_url_invalid", baseURL), fix: i18n.T("doctor.base_url_fix")}}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL, nil)
+	if err != nil {
+		return []doctorResult{{status: doctorFail, name: i18n.T("doctor.network"), detail: err.Error()}}
+	}
+	via := i18n.T("doctor.direct")
+	proxy, err := http.ProxyFromEnvironment(req)
+	proxied := err == nil && proxy != nil
+	if proxied {
+		via = i18n.T("doctor.via_proxy", proxy.Host)
+	}
+
+	client := &http.Client{Timeout: doctorDialTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		fix := i18n.T("doctor.network_fix")
+		if proxied {
+			fix = i18n.T("doctor.proxy_network_fix")
+		}
+		return []doctorResult{{status: doctorFail, name: i18n.T("doctor.network"), detail: i18n.T("doctor.unreachable", u.Host, via, err), fix: fix}}
+	}
+	resp.Body.Close()
+	elapsed := time.Since(start)
+
+	results := []doctorResult{{status: doctorPass, name: i18n.T("doctor.network"), detail: i18n.T("doctor.reachable", u.Host, via, elapsed.Milliseconds())}}
+
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		// Date 只精确到秒，再扣除请求耗时的误差
+		skew := time.Since(date) - elapsed/2
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > doctorMaxSkew {
+			results = append(results, doctorResult{status: doctorWarn, name: i18n.T("doctor.clock"), detail: i18n.T("doctor.clock_skew", skew.Round(time.Second)),
+				fix: i18n.T("doctor.clock_fix")})
+		} else {
+			results = append(results, doctorResult{status: doctorPass, name: i18n.T("doctor.clock"), detail: i18n.T("doctor.clock_ok", skew.Round(time.Second))})
+		}
+	}
+	return results
+}
+
+// checkCompletion 发送一次只生成1个token的测试请求
+func checkCompletion(ctx context.Context, cfg *config.Config, name string, providerCfg config.ProviderConfig) doctorResult {
+	provider := newProvider(envFrom(ctx), cfg, name, providerCfg)
+	ctx, cancel := context.WithTimeout(ctx, doctorChatTimeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := provider.Chat(ctx, &providers.ChatRequest{
+		Messages:  []providers.Message{{Role: "user", Content: "ping"}},
+		MaxTokens: 1,
+	})
+	if err != nil {
+		return doctorResult{status: doctorFail, name: i18n.T("doctor.completion"), detail: err.Error(), fix: completionFix(name, err)}
+	}
+	model := resp.Model
+	if model == "" {
+		model = providerCfg.Model
+	}
+	return doctorResult{status: doctorPass, name: i18n.T("doctor.completion"), detail: i18n.T("doctor.completion_ok", model, time.Since(start).Seconds())}
+}
+
+// completionFix 根据测试请求的错误给出修复建议
+func completionFix(name string, err error) string {
+	switch errorExitCode(err) {
+	case ExitAuth:
+		return i18n.T("doctor.auth_fix_key", name)
+	case ExitBudget:
+		return i18n.T("doctor.budget_fix")
+	case ExitInterrupted:
+		return ""
+	}
+	var pe *providers.ProviderError
+	if errors.As(err, &pe) && pe.Code == "http_404" {
+		return i18n.T("doctor.not_found_fix", name)
+	}
+	return i18n.T("doctor.retry_fix")
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().StringVarP(&doctorProvider, "provider", "p", "", "只检查指定的提供商")
+	doctorCmd.Flags().BoolVar(&doctorNoCompletion, "no-completion", false, "不发送测试请求，只检查配置和网络")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/pkg/providers"
+)
+
+// dryRunProvider 演练模式使用的提供商，打印第一个请求的完整内容和token估算后退出，不发送任何请求
+type dryRunProvider struct {
+	*providers.OpenAIProvider
+}
+
+// Chat 打印对话请求后退出
+func (p *dryRunProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	p.printRequest(req, false)
+	exit()
+	return nil, nil
+}
+
+// ChatStream 打印流式对话请求后退出
+func (p *dryRunProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	p.printRequest(req, true)
+	exit()
+	return nil, nil
+}
+
+// Moderate 打印内容审核请求后退出
+func (p *dryRunProvider) Moderate(ctx context.Context, input string) (*providers.ModerationResult, error) {
+	payload, _ := json.MarshalIndent(map[string]string{"input": input}, "", "  ")
+	fmt.Fprintln(os.Stderr, i18n.T("dryrun.request", p.Endpoint("/moderations")))
+	fmt.Println(string(payload))
+	exit()
+	return nil, nil
+}
+
+// printRequest 向标准输出打印请求的JSON内容，向标准错误打印地址和token估算
+func (p *dryRunProvider) printRequest(req *providers.ChatRequest, stream bool) {
+	payload, err := p.Payload(req, stream)
+	if err != nil {
+		fail(ExitError, i18n.T("dryrun.build_failed"), err)
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, i18n.T("dryrun.request", p.Endpoint("/chat/completions")))
+	fmt.Println(string(payload))
+	fmt.Fprintln(os.Stderr, i18n.T("dryrun.estimate", estimateRequestTokens(req), len(req.Messages)))
+}
+
+// estimateRequestTokens 估算请求的输入token数，包括预填的回复开头
+func estimateRequestTokens(req *providers.ChatRequest) int {
+	tokens := providers.EstimateMessages(req.Messages)
+	if req.AssistantPrefix != "" {
+		tokens += providers.EstimateMessages([]providers.Message{{Role: "assistant", Content: req.AssistantPrefix}})
+	}
+	return tokens
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	estimateFile      string
+	estimateModel     string
+	estimateProvider  string
+	estimateMaxOutput int
+)
+
+// defaultEstimateMaxOutput 提供商没有配置 max_tokens 时，输出成本上限按此token数估算
+const defaultEstimateMaxOutput = 4096
+
+// estimateCmd 估算成本
+var estimateCmd = &cobra.Command{
+	Use:   "estimate [文本]",
+	Short: "发送前估算输入的token数和成本",
+	Long: `估算一段输入的token数，并按价格计算输入成本和输出成本的范围，不会发送请求。
+
+价格优先使用提供商配置的 input_price 和 output_price（每百万token的价格，美元），
+没有配置时按模型名称查找内置的常用模型价格表。输出成本的下限按简短回答估算，
+上限按提供商的 max_tokens（或 --max-output）估算。token数按字节数粗略估算，中文等文本可能偏低。
+
+示例:
+  ai-chat-cli estimate --file big.txt -m gpt-4o
+  git diff | ai-chat-cli estimate -p deepseek`,
+	Run: runEstimate,
+}
+
+func runEstimate(cmd *cobra.Command, args []string) {
+	text, err := readInput(estimateFile, args)
+	if err != nil {
+		fail(ExitUsage, "%v", err)
+		return
+	}
+
+	var providerCfg config.ProviderConfig
+	name, model := estimateProvider, estimateModel
+	if cfg, err := config.LoadConfig(); err == nil {
+		if name == "" {
+			name = cfg.Default.Provider
+		}
+		var exists bool
+		providerCfg, exists = cfg.Providers[name]
+		if estimateProvider != "" && !exists {
+			fail(ExitConfig, i18n.T("provider.not_configured"), name)
+			return
+		}
+		if model == "" {
+			model = providerCfg.Model
+		}
+		if model == "" {
+			model = cfg.Default.Model
+		}
+	} else if estimateProvider != "" {
+		fail(ExitConfig, i18n.T("config.load_failed"), err)
+		return
+	}
+
+	input := providers.EstimateMessages([]providers.Message{{Role: "user", Content: text}})
+	maxOutput := estimateMaxOutput
+	if maxOutput <= 0 {
+		maxOutput = providerCfg.MaxTokens
+	}
+	if maxOutput <= 0 {
+		maxOutput = defaultEstimateMaxOutput
+	}
+	// 简短回答约为输入的十分之一，不少于100个token
+	minOutput := input / 10
+	if minOutput < 100 {
+		minOutput = 100
+	}
+	if minOutput > maxOutput {
+		minOutput = maxOutput
+	}
+
+	fmt.Println(i18n.T("estimate.title"))
+	if model != "" {
+		fmt.Printf("  %s\n", i18n.T("estimate.model", model))
+	}
+	fmt.Printf("  %s\n", i18n.T("estimate.input", formatCount(input), formatCount(len(text))))
+	fmt.Printf("  %s\n", i18n.T("estimate.output", formatCount(minOutput), formatCount(maxOutput)))
+
+	price, source, ok := estimatePrice(providerCfg, model)
+	if !ok && model == "" {
+		hint("%s", i18n.T("estimate.model_hint"))
+		return
+	}
+	if !ok {
+		ui.Warn(i18n.T("estimate.no_price"), model)
+		hint("%s", i18n.T("provider.price_hint"))
+		return
+	}
+	fmt.Printf("  %s\n", i18n.T("estimate.price", price.Input, price.Output, source))
+	fmt.Printf("  %s\n", i18n.T("estimate.input_cost", price.Cost(input, 0)))
+	fmt.Printf("  %s\n", i18n.T("estimate.output_cost", price.Cost(0, minOutput), price.Cost(0, maxOutput)))
+	fmt.Printf("  %s\n", i18n.T("estimate.total", price.Cost(input, minOutput), price.Cost(input, maxOutput)))
+}
+
+// estimatePrice 获取估算使用的价格：-m 指定了其他模型时不使用提供商配置的价格
+func estimatePrice(providerCfg config.ProviderConfig, model string) (providers.Price, string, bool) {
+	sameModel := estimateModel == "" || estimateModel == providerCfg.Model
+	if sameModel && (providerCfg.InputPrice > 0 || providerCfg.OutputPrice > 0) {
+		return providers.Price{Input: providerCfg.InputPrice, Output: providerCfg.OutputPrice}, i18n.T("estimate.source_config"), true
+	}
+	if price, ok := providers.LookupPrice(model); ok {
+		return price, i18n.T("estimate.source_builtin"), true
+	}
+	return providers.Price{}, "", false
+}
+
+func init() {
+	rootCmd.AddCommand(estimateCmd)
+
+	estimateCmd.Flags().StringVarP(&estimateFile, "file", "f", "", "从文件读取输入")
+	estimateCmd.Flags().StringVarP(&estimateModel, "model", "m", "", "按该模型的价格估算（默认为提供商配置的模型）")
+	estimateCmd.Flags().StringVarP(&estimateProvider, "provider", "p", "", "使用该提供商配置的模型和价格（默认为 default.provider）")
+	estimateCmd.Flags().IntVar(&estimateMaxOutput, "max-output", 0, "输出token数的上限（默认为提供商的 max_tokens）")
+}
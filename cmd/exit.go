@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+)
+
+// 退出状态码，便于脚本区分失败原因
+const (
+	ExitOK       = 0 // 成功
+	ExitError    = 1 // 一般错误，如读写文件失败
+	ExitUsage    = 2 // 命令参数错误
+	ExitConfig   = 3 // 配置错误，如配置文件缺失、提供商未配置
+	ExitAuth     = 4 // API密钥无效或没有权限
+	ExitProvider = 5 // 提供商请求失败
+	ExitBudget   = 6 // 超出预算或账户额度不足
+
+	ExitInterrupted = 130 // 被 Ctrl+C 或 SIGTERM 中断
+)
+
+// exitStatus 命令结束后进程的退出状态码，保留第一次设置的非零状态码。
+// 批处理等命令的多个goroutine可能同时设置
+type exitStatus struct {
+	code atomic.Int32
+}
+
+// set 设置退出状态码，已经设置过时不再修改
+func (s *exitStatus) set(code int) {
+	s.code.CompareAndSwap(ExitOK, int32(code))
+}
+
+// get 获取退出状态码
+func (s *exitStatus) get() int {
+	return int(s.code.Load())
+}
+
+// exitState 本次运行的退出状态码，只通过 fail、setExitCode 和 exit 访问
+var exitState exitStatus
+
+// fail 向标准错误输出错误信息并设置退出状态码，多次调用时保留第一次的状态码
+func fail(code int, format string, a ...interface{}) {
+	ui.Error(format, a...)
+	exitState.set(code)
+}
+
+// setExitCode 不输出错误信息，只设置退出状态码，如已经输出了中断或失败统计的命令
+func setExitCode(code int) {
+	exitState.set(code)
+}
+
+// exit 以当前的退出状态码结束进程
+func exit() {
+	os.Exit(exitState.get())
+}
+
+// hint 在终端中向标准错误输出操作提示，被脚本调用时不输出
+func hint(format string, a ...interface{}) {
+	ui.Hint(format, a...)
+}
+
+// errorExitCode 根据提供商返回的错误选择退出状态码
+func errorExitCode(err error) int {
+	if errors.Is(err, errInputTooLarge) {
+		return ExitBudget
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitInterrupted
+	}
+
+	var pe *providers.ProviderError
+	if !errors.As(err, &pe) {
+		return ExitProvider
+	}
+
+	switch pe.Code {
+	case "http_401", "http_403", providers.CodeAuthError:
+		return ExitAuth
+	case "http_402":
+		return ExitBudget
+	case "http_429":
+		// OpenAI 额度用完时同样返回429，与限流区分开
+		if strings.Contains(pe.Message, "insufficient_quota") {
+			return ExitBudget
+		}
+	}
+	return ExitProvider
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	explainProvider string
+	explainLevel    string
+)
+
+// explainLevels 讲解深度对应的提示
+var explainLevels = map[string]string{
+	"beginner":     "The reader is a beginner. Explain concepts and syntax step by step, avoid jargon or define it when used.",
+	"intermediate": "The reader is a working developer. Focus on what the code does, how it is structured and why.",
+	"expert":       "The reader is an expert. Be concise and focus on design decisions, edge cases, performance, concurrency and potential bugs.",
+}
+
+// languageByExt 文件扩展名对应的编程语言
+var languageByExt = map[string]string{
+	".go": "Go", ".py": "Python", ".js": "JavaScript", ".mjs": "JavaScript", ".ts": "TypeScript",
+	".tsx": "TypeScript (React)", ".jsx": "JavaScript (React)", ".java": "Java", ".kt": "Kotlin",
+	".rs": "Rust", ".c": "C", ".h": "C", ".cc": "C++", ".cpp": "C++", ".hpp": "C++", ".cs": "C#",
+	".rb": "Ruby", ".php": "PHP", ".swift": "Swift", ".scala": "Scala", ".sh": "Shell", ".bash": "Bash",
+	".zsh": "Zsh", ".ps1": "PowerShell", ".sql": "SQL", ".lua": "Lua", ".r": "R", ".dart": "Dart",
+	".html": "HTML", ".css": "CSS", ".scss": "SCSS", ".vue": "Vue", ".yaml": "YAML", ".yml": "YAML",
+	".json": "JSON", ".toml": "TOML", ".proto": "Protocol Buffers", ".tf": "Terraform",
+}
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain <文件[:起始行-结束行]>",
+	Short: "讲解源代码",
+	Long: `读取源代码文件（或其中的行范围），识别编程语言并由AI讲解代码。
+
+示例:
+  ai-chat-cli explain main.go
+  ai-chat-cli explain internal/config/config.go:60-80 --level beginner
+  ai-chat-cli explain cmd/root.go:43 --level expert`,
+	Args: cobra.ExactArgs(1),
+	Run:  runExplain,
+}
+
+func runExplain(cmd *cobra.Command, args []string) {
+	levelHint, ok := explainLevels[explainLevel]
+	if !ok {
+		fail(ExitUsage, i18n.T("explain.level_invalid"), explainLevel)
+		return
+	}
+
+	filename, start, end, err := parseFileRange(args[0])
+	if err != nil {
+		fail(ExitUsage, "%v", err)
+		return
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		fail(ExitError, i18n.T("file.read_failed"), err)
+		return
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if end == 0 || end > len(lines) {
+		end = len(lines)
+	}
+	if start > end {
+		fail(ExitUsage, i18n.T("explain.range_overflow"), len(lines))
+		return
+	}
+
+	language := detectCodeLanguage(filename)
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), explainProvider)
+	if !ok {
+		return
+	}
+
+	fmt.Println(i18n.T("explain.explaining", filename, start, end, language))
+
+	var code strings.Builder
+	for i := start; i <= end; i++ {
+		fmt.Fprintf(&code, "%4d | %s\n", i, lines[i-1])
+	}
+
+	system := fmt.Sprintf("You are a senior software engineer explaining %s code. %s\n"+
+		"Refer to line numbers when helpful. Answer in the user's language (Chinese if unsure), formatted as Markdown.", language, levelHint)
+	prompt := fmt.Sprintf("File: %s (lines %d-%d)\n\n```%s\n%s```", filename, start, end, strings.ToLower(language), code.String())
+
+	resp, err := complete(cmd.Context(), provider, system, prompt, 0.3)
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("explain.failed"), err)
+		return
+	}
+
+	out, err := ui.Markdown(resp.Content)
+	if err != nil {
+		fmt.Println(resp.Content)
+		return
+	}
+	fmt.Println(out)
+}
+
+// parseFileRange 解析 "文件[:起始行-结束行]" 形式的参数，行号从1开始，end为0表示到文件末尾
+func parseFileRange(arg string) (filename string, start, end int, err error) {
+	idx := strings.LastIndex(arg, ":")
+	if idx <= 0 {
+		return arg, 1, 0, nil
+	}
+
+	// Windows盘符等无法解析为行号的情况视为文件名的一部分
+	rangePart := arg[idx+1:]
+	startStr, endStr, isRange := strings.Cut(rangePart, "-")
+	start, err = strconv.Atoi(startStr)
+	if err != nil {
+		if _, statErr := os.Stat(arg); statErr == nil {
+			return arg, 1, 0, nil
+		}
+		return "", 0, 0, fmt.Errorf(i18n.T("explain.range_invalid"), rangePart)
+	}
+
+	end = start
+	if isRange {
+		if end, err = strconv.Atoi(endStr); err != nil {
+			return "", 0, 0, fmt.Errorf(i18n.T("explain.range_invalid"), rangePart)
+		}
+	}
+
+	if start < 1 || end < start {
+		return "", 0, 0, fmt.Errorf(i18n.T("explain.range_invalid"), rangePart)
+	}
+
+	return arg[:idx], start, end, nil
+}
+
+// detectCodeLanguage 根据文件名识别编程语言
+func detectCodeLanguage(filename string) string {
+	base := filepath.Base(filename)
+	switch base {
+	case "Makefile", "makefile", "GNUmakefile":
+		return "Makefile"
+	case "Dockerfile":
+		return "Dockerfile"
+	}
+
+	if language, ok := languageByExt[strings.ToLower(filepath.Ext(base))]; ok {
+		return language
+	}
+	return "unknown language"
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().StringVarP(&explainProvider, "provider", "p", "", "指定AI提供商")
+	explainCmd.Flags().StringVarP(&explainLevel, "level", "l", "intermediate", "讲解深度: beginner, intermediate, expert")
+}
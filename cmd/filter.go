@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/filter"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+)
+
+// buildOutboundFilter 根据配置中的过滤规则创建过滤器，没有规则时返回nil
+func buildOutboundFilter(rules []config.FilterRule) (*filter.Filter, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	f := &filter.Filter{}
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if err := f.Add(name, rule.Pattern, rule.Keywords, rule.Action); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// filteredProvider 发送前按过滤规则检查所有消息，匹配时警告、请用户确认或拒绝发送
+type filteredProvider struct {
+	providers.Provider
+	filter      *filter.Filter
+	err         error // 规则无效时拒绝发送任何请求，避免配置错误导致敏感内容被发出
+	interactive bool  // 为false时需要确认的内容直接拒绝，用于 serve
+
+	mu      sync.Mutex
+	allowed map[string]bool // 已警告或已确认的内容，对话历史再次发送时不重复提示
+}
+
+// newFilteredProvider 配置了 filters 时创建检查提示词的提供商，否则原样返回
+func newFilteredProvider(p providers.Provider, rules []config.FilterRule, interactive bool) providers.Provider {
+	f, err := buildOutboundFilter(rules)
+	if err == nil && f.Len() == 0 {
+		return p
+	}
+	return &filteredProvider{Provider: p, filter: f, err: err, interactive: interactive, allowed: map[string]bool{}}
+}
+
+// Unwrap 返回被包装的提供商
+func (p *filteredProvider) Unwrap() providers.Provider {
+	return p.Provider
+}
+
+// Chat 检查提示词后发送对话请求
+func (p *filteredProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	if err := p.check(req); err != nil {
+		return nil, err
+	}
+	return p.Provider.Chat(ctx, req)
+}
+
+// ChatStream 检查提示词后发送流式对话请求
+func (p *filteredProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	if err := p.check(req); err != nil {
+		return nil, err
+	}
+	return p.Provider.ChatStream(ctx, req)
+}
+
+// check 检查请求中的所有消息，返回拒绝发送的原因
+func (p *filteredProvider) check(req *providers.ChatRequest) error {
+	if p.err != nil {
+		return providers.NewProviderError(p.GetName(), "filter_blocked", i18n.T("filter.invalid"), p.err)
+	}
+
+	texts := []string{req.AssistantPrefix}
+	for _, m := range req.Messages {
+		texts = append(texts, m.Content)
+	}
+	matches := p.filter.Check(strings.Join(texts, "\n"))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var pending []filter.Match
+	for _, m := range matches {
+		if m.Action == filter.ActionBlock || !p.allowed[m.Rule+"\x00"+m.Text] {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var blocked []string
+	for _, m := range pending {
+		if m.Action == filter.ActionBlock {
+			blocked = append(blocked, fmt.Sprintf("%s: %s", m.Rule, m.Text))
+		}
+	}
+	if len(blocked) > 0 {
+		return providers.NewProviderError(p.GetName(), "filter_blocked",
+			i18n.T("filter.blocked", strings.Join(blocked, i18n.T("filter.separator"))), nil)
+	}
+
+	for _, m := range pending {
+		ui.Warn(i18n.T("filter.matched"), m.Rule, m.Text)
+	}
+	if filter.Strictest(pending) == filter.ActionConfirm {
+		if !p.interactive {
+			return providers.NewProviderError(p.GetName(), "filter_blocked", i18n.T("filter.confirm_required"), nil)
+		}
+		ok, err := confirmTerminal(i18n.T("filter.confirm"))
+		if err != nil || !ok {
+			return providers.NewProviderError(p.GetName(), "filter_blocked", i18n.T("filter.cancelled"), err)
+		}
+	}
+	for _, m := range pending {
+		p.allowed[m.Rule+"\x00"+m.Text] = true
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reviewProvider  string
+	reviewFormat    string
+	reviewStaged    bool
+	reviewChunkSize int
+)
+
+// ReviewComment 代码评审意见
+type ReviewComment struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"` // "error", "warning", "suggestion"
+	Body     string `json:"body"`
+}
+
+// githubReview GitHub Pull Request评审接口的请求结构
+type githubReview struct {
+	Body     string          `json:"body"`
+	Event    string          `json:"event"`
+	Comments []githubComment `json:"comments"`
+}
+
+// githubComment GitHub Pull Request评审中的单条行内评论
+type githubComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+// gitCmd represents the git command
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Git相关的AI辅助命令",
+	Long:  `基于Git仓库内容的AI辅助命令，如代码评审。`,
+}
+
+// gitReviewCmd 评审Git差异
+var gitReviewCmd = &cobra.Command{
+	Use:   "review [ref..ref]",
+	Short: "AI评审Git差异",
+	Long: `将Git差异交给AI评审，按文件输出结构化的评审意见。
+
+示例:
+  ai-chat-cli git review                  # 评审工作区相对HEAD的改动
+  ai-chat-cli git review --staged         # 评审暂存区的改动
+  ai-chat-cli git review main..feature    # 评审两个引用之间的差异
+  ai-chat-cli git review main..feature --format github > review.json
+
+--format github 输出可直接提交到 GitHub Pull Request Review 接口的JSON。`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runGitReview,
+}
+
+const reviewSystemPrompt = `You are a meticulous senior code reviewer. Review the given unified diff.
+Report only real problems or valuable improvements: bugs, security issues, error handling, concurrency, performance, readability.
+Respond with JSON only, no prose, using this schema:
+{"comments":[{"file":"path/in/diff","line":<line number in the new file>,"severity":"error|warning|suggestion","body":"review comment"}]}
+Use an empty list if there is nothing to report. Write comment bodies in the same language as the user's request (Chinese if unsure).`
+
+func runGitReview(cmd *cobra.Command, args []string) {
+	if reviewFormat != "text" && reviewFormat != "github" {
+		fail(ExitUsage, i18n.T("review.format_invalid"), reviewFormat)
+		return
+	}
+	if reviewChunkSize <= 0 {
+		fail(ExitUsage, "%s", i18n.T("chunk.size_invalid"))
+		return
+	}
+
+	gitArgs := []string{"diff", "--no-color", "--no-ext-diff"}
+	switch {
+	case len(args) > 0:
+		gitArgs = append(gitArgs, args[0])
+	case reviewStaged:
+		gitArgs = append(gitArgs, "--staged")
+	default:
+		gitArgs = append(gitArgs, "HEAD")
+	}
+
+	out, err := exec.Command("git", gitArgs...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			fail(ExitError, i18n.T("review.diff_failed"), strings.TrimSpace(string(exitErr.Stderr)))
+		} else {
+			fail(ExitError, i18n.T("review.diff_failed"), err)
+		}
+		return
+	}
+
+	diff := string(out)
+	if strings.TrimSpace(diff) == "" {
+		fmt.Fprintln(os.Stderr, ui.Styled(ui.ElemSuccess, i18n.T("review.no_changes")))
+		return
+	}
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), reviewProvider)
+	if !ok {
+		return
+	}
+
+	chunks := chunkDiff(diff, reviewChunkSize)
+	var comments []ReviewComment
+	for i, chunk := range chunks {
+		if len(chunks) > 1 {
+			fmt.Fprintln(os.Stderr, i18n.T("review.chunk", i+1, len(chunks)))
+		}
+
+		resp, err := complete(cmd.Context(), provider, reviewSystemPrompt, chunk, 0.2)
+		if err != nil {
+			fail(errorExitCode(err), i18n.T("review.failed"), err)
+			return
+		}
+
+		var result struct {
+			Comments []ReviewComment `json:"comments"`
+		}
+		if err := parseJSONReply(resp.Content, &result); err != nil {
+			ui.Warn(i18n.T("review.unparsed"), i+1, err)
+			continue
+		}
+		comments = append(comments, result.Comments...)
+	}
+
+	if reviewFormat == "github" {
+		printGitHubReview(comments)
+		return
+	}
+	printReviewComments(comments)
+}
+
+// chunkDiff 按文件切分差异，并将多个文件合并到不超过size个字符的块中
+func chunkDiff(diff string, size int) []string {
+	var files []string
+	for _, part := range strings.Split(diff, "\ndiff --git ") {
+		if part == "" {
+			continue
+		}
+		if !strings.HasPrefix(part, "diff --git ") {
+			part = "diff --git " + part
+		}
+		files = append(files, part)
+	}
+
+	var chunks []string
+	var current strings.Builder
+	for _, file := range files {
+		if current.Len() > 0 && current.Len()+len(file) > size {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		// 单个文件的差异过大时单独切分
+		if len(file) > size {
+			chunks = append(chunks, splitFileDiff(file, size)...)
+			continue
+		}
+		current.WriteString(file)
+		current.WriteString("\n")
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+
+	return chunks
+}
+
+// splitFileDiff 按hunk（@@）切分单个文件的差异，每块开头都重复文件头（diff --git、---、+++），
+// 让模型知道评审意见属于哪个文件。单个hunk仍然过大时按行切分
+func splitFileDiff(file string, size int) []string {
+	start := strings.Index(file, "\n@@")
+	if start < 0 {
+		return splitChunks(file, size)
+	}
+	header, body := file[:start+1], file[start+1:]
+
+	var hunks []string
+	for _, part := range strings.Split(body, "\n@@") {
+		if !strings.HasPrefix(part, "@@") {
+			part = "@@" + part
+		}
+		hunks = append(hunks, part)
+	}
+
+	room := size - len(header)
+	if room <= 0 {
+		room = size
+	}
+	var chunks []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, header+current.String())
+			current.Reset()
+		}
+	}
+	for _, hunk := range hunks {
+		if current.Len() > 0 && current.Len()+len(hunk)+1 > room {
+			flush()
+		}
+		if len(hunk) > room {
+			for _, piece := range splitChunks(hunk, room) {
+				chunks = append(chunks, header+piece)
+			}
+			continue
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(hunk)
+	}
+	flush()
+	return chunks
+}
+
+// printReviewComments 按文件分组输出评审意见
+func printReviewComments(comments []ReviewComment) {
+	if len(comments) == 0 {
+		ui.Success("%s", i18n.T("review.no_issues"))
+		return
+	}
+
+	byFile := map[string][]ReviewComment{}
+	var files []string
+	for _, c := range comments {
+		if _, exists := byFile[c.File]; !exists {
+			files = append(files, c.File)
+		}
+		byFile[c.File] = append(byFile[c.File], c)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		fileComments := byFile[file]
+		sort.Slice(fileComments, func(i, j int) bool { return fileComments[i].Line < fileComments[j].Line })
+
+		fmt.Printf("\n%s%s\n", ui.Symbol("📄 ", ""), ui.Colors().Bold(file))
+		for _, c := range fileComments {
+			var severity aurora.Value
+			switch c.Severity {
+			case "error":
+				severity = ui.Colors().Red("error")
+			case "warning":
+				severity = ui.Colors().Yellow("warning")
+			default:
+				severity = ui.Colors().Cyan("suggestion")
+			}
+			fmt.Printf("  L%-5d [%s] %s\n", c.Line, severity, c.Body)
+		}
+	}
+
+	fmt.Printf("\n%s\n", ui.Styled(ui.ElemStats, i18n.T("review.summary", len(comments), len(files))))
+}
+
+// printGitHubReview 输出GitHub Pull Request评审接口格式的JSON
+func printGitHubReview(comments []ReviewComment) {
+	review := githubReview{
+		Body:     fmt.Sprintf("AI review: %d comment(s)", len(comments)),
+		Event:    "COMMENT",
+		Comments: make([]githubComment, 0, len(comments)),
+	}
+	for _, c := range comments {
+		review.Comments = append(review.Comments, githubComment{
+			Path: c.File,
+			Line: c.Line,
+			Side: "RIGHT",
+			Body: fmt.Sprintf("**%s**: %s", c.Severity, c.Body),
+		})
+	}
+
+	data, _ := json.MarshalIndent(review, "", "  ")
+	fmt.Println(string(data))
+}
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+	gitCmd.AddCommand(gitReviewCmd)
+
+	gitReviewCmd.Flags().StringVarP(&reviewProvider, "provider", "p", "", "指定AI提供商")
+	gitReviewCmd.Flags().StringVar(&reviewFormat, "format", "text", "输出格式: text, github")
+	gitReviewCmd.Flags().BoolVar(&reviewStaged, "staged", false, "评审暂存区的改动")
+	gitReviewCmd.Flags().IntVar(&reviewChunkSize, "chunk-size", 20000, "每次发送的最大差异字符数")
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+)
+
+// defaultConfirmInputTokens 未配置 advanced.confirm_input_tokens 时，需要确认的输入token数
+const defaultConfirmInputTokens = 20000
+
+// errInputTooLarge 输入超过确认阈值且无法向用户确认，或用户取消发送
+var errInputTooLarge error = i18n.Error("guard.too_large")
+
+// guardedProvider 发送前估算输入token数，超过阈值时显示预计用量和成本并请用户确认
+type guardedProvider struct {
+	providers.Provider
+	limit int
+	price float64 // 每百万输入token的价格（美元），0表示未配置
+
+	mu        sync.Mutex
+	confirmed bool // 确认一次后本次运行不再询问
+}
+
+// newGuardedProvider 创建带输入大小确认的提供商，limit 为0时使用默认阈值，小于0时不检查
+func newGuardedProvider(p providers.Provider, limit int, price float64) providers.Provider {
+	if limit < 0 {
+		return p
+	}
+	if limit == 0 {
+		limit = defaultConfirmInputTokens
+	}
+	return &guardedProvider{Provider: p, limit: limit, price: price}
+}
+
+// Unwrap 返回被包装的提供商
+func (p *guardedProvider) Unwrap() providers.Provider {
+	return p.Provider
+}
+
+// Chat 确认输入大小后发送对话请求
+func (p *guardedProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	if err := p.check(req); err != nil {
+		return nil, err
+	}
+	return p.Provider.Chat(ctx, req)
+}
+
+// ChatStream 确认输入大小后发送流式对话请求
+func (p *guardedProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	if err := p.check(req); err != nil {
+		return nil, err
+	}
+	return p.Provider.ChatStream(ctx, req)
+}
+
+// check 输入超过阈值时请用户确认，不在终端中运行时直接拒绝
+func (p *guardedProvider) check(req *providers.ChatRequest) error {
+	tokens := estimateRequestTokens(req)
+	if tokens <= p.limit {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.confirmed {
+		return nil
+	}
+
+	estimate := i18n.T("guard.estimate", tokens)
+	if p.price > 0 {
+		estimate += i18n.T("guard.estimate_cost", float64(tokens)*p.price/1e6)
+	}
+	ui.Warn(i18n.T("guard.over_limit"), estimate, p.limit)
+
+	ok, err := confirmTerminal(i18n.T("guard.confirm"))
+	if err != nil {
+		hint("%s", i18n.T("guard.hint"))
+		return fmt.Errorf(i18n.T("guard.non_interactive"), errInputTooLarge, estimate)
+	}
+	if !ok {
+		return errInputTooLarge
+	}
+	p.confirmed = true
+	return nil
+}
+
+// confirmTerminal 在终端中请用户确认。标准输入被管道占用时改用 /dev/tty，没有终端时返回错误
+func confirmTerminal(prompt string) (bool, error) {
+	if !stdinIsPipe() {
+		return confirm(prompt), nil
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, err
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "%s [y/N]: ", prompt)
+	answer, _ := bufio.NewReader(tty).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
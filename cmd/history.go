@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/session"
+
+	"github.com/spf13/cobra"
+)
+
+var historyStatsBy []string
+
+// historyBarWidth 统计图中最长的条的宽度
+const historyBarWidth = 24
+
+// historyCmd 对话历史
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "分析保存的对话历史",
+}
+
+// historyStatsCmd 用量统计
+var historyStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "按月份、提供商和模型统计对话、消息、token和成本",
+	Long: `统计保存的会话（advanced.save_history 为 true 时自动保存）中的对话数、消息数、
+token用量和成本，按月份、提供商和模型分组，用条形图显示各组的token用量。
+
+回复没有记录成本时，按提供商配置的 input_price 和 output_price（每百万token的价格，美元）估算，
+估算的成本标有 ~。
+
+示例:
+  ai-chat-cli history stats
+  ai-chat-cli history stats --by month`,
+	Args: cobra.NoArgs,
+	Run:  runHistoryStats,
+}
+
+func runHistoryStats(cmd *cobra.Command, args []string) {
+	groupings := map[string]string{
+		session.GroupMonth:    i18n.T("history.by_month"),
+		session.GroupProvider: i18n.T("history.by_provider"),
+		session.GroupModel:    i18n.T("history.by_model"),
+	}
+	for _, by := range historyStatsBy {
+		if _, ok := groupings[by]; !ok {
+			fail(ExitUsage, i18n.T("history.by_invalid"), by)
+			return
+		}
+	}
+
+	store, err := openSessionStore()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	sessions, err := store.List()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	if len(sessions) == 0 {
+		fmt.Println(i18n.T("session.none"))
+		hint("%s", i18n.T("history.save_hint"))
+		return
+	}
+
+	price := historyPrices()
+	total := &session.UsageGroup{}
+	if all := session.SummarizeUsage(sessions, "", price); len(all) > 0 {
+		total = all[0]
+	}
+	fmt.Println(ui.Styled(ui.ElemStats, i18n.T("history.title")))
+	fmt.Printf("  %s\n", i18n.T("history.totals", total.Sessions, total.Messages))
+	fmt.Printf("  %s\n", i18n.T("history.tokens",
+		formatCount(total.TotalTokens()), formatCount(total.PromptTokens), formatCount(total.CompletionTokens)))
+	fmt.Printf("  %s\n", i18n.T("history.cost", formatCost(total)))
+
+	for _, by := range historyStatsBy {
+		fmt.Printf("\n%s:\n", groupings[by])
+		printUsageGroups(session.SummarizeUsage(sessions, by, price))
+	}
+}
+
+// historyPrices 读取各提供商配置的价格，读取配置失败时不估算成本
+func historyPrices() session.PriceFunc {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil
+	}
+	return func(provider string) (float64, float64) {
+		p := cfg.Providers[provider]
+		return p.InputPrice, p.OutputPrice
+	}
+}
+
+// printUsageGroups 每组一行，条的长度与该组的token用量成正比，都没有用量时按消息数
+func printUsageGroups(groups []*session.UsageGroup) {
+	value := func(g *session.UsageGroup) int { return g.TotalTokens() }
+	max, width := 0, 0
+	for _, g := range groups {
+		if v := value(g); v > max {
+			max = v
+		}
+		if w := utf8.RuneCountInString(g.Key); w > width {
+			width = w
+		}
+	}
+	if max == 0 {
+		value = func(g *session.UsageGroup) int { return g.Messages }
+		for _, g := range groups {
+			if v := value(g); v > max {
+				max = v
+			}
+		}
+	}
+
+	for _, g := range groups {
+		bar := 0
+		if max > 0 {
+			bar = (value(g)*historyBarWidth + max - 1) / max
+		}
+		fmt.Printf("  %s\n", i18n.T("history.row",
+			g.Key, strings.Repeat(" ", width-utf8.RuneCountInString(g.Key)),
+			strings.Repeat("█", bar), strings.Repeat("░", historyBarWidth-bar),
+			g.Sessions, g.Messages, formatCount(g.TotalTokens()), formatCost(g)))
+	}
+}
+
+// formatCount 以千位分隔符显示数量
+func formatCount(n int) string {
+	s := fmt.Sprint(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// formatCost 显示成本，包含估算值时前面加 ~，没有成本数据时显示 -
+func formatCost(g *session.UsageGroup) string {
+	switch {
+	case g.Cost == 0:
+		return "-"
+	case g.Estimated:
+		return fmt.Sprintf("~$%.4f", g.Cost)
+	default:
+		return fmt.Sprintf("$%.4f", g.Cost)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyStatsCmd)
+
+	historyStatsCmd.Flags().StringSliceVar(&historyStatsBy, "by", []string{session.GroupMonth, session.GroupProvider, session.GroupModel},
+		"分组方式: month、provider、model，多个用逗号分隔")
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	howtoProvider string
+	howtoExec     bool
+	howtoYes      bool
+	howtoPrint    bool
+)
+
+// howtoCmd represents the howto command
+var howtoCmd = &cobra.Command{
+	Use:   "howto <任务描述>",
+	Short: "查询完成任务的命令及说明",
+	Long: `描述要完成的任务，AI给出简短的说明和可以直接复制的命令（放在代码块中）。
+
+与 sh 只生成一条命令不同，howto 会解释命令的作用和需要注意的地方，
+需要多个步骤时按顺序给出多条命令。会自动识别当前的Shell和操作系统。
+
+示例:
+  ai-chat-cli howto "把文件夹里所有png缩小到50%"
+  ai-chat-cli howto "找出占用8080端口的进程" --exec     # 确认后执行代码块中的命令
+  ai-chat-cli howto "批量重命名为小写" --print          # 只输出命令，便于重定向到脚本`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runHowto,
+}
+
+// howtoSystemPrompt 生成命令和说明的系统提示词，参数依次为Shell、操作系统和架构
+const howtoSystemPrompt = `You are a command-line expert. The user describes a task; show how to do it from the terminal.
+Target shell: %s. Operating system: %s (%s).
+Reply in the user's language (Chinese if unsure), formatted as Markdown:
+1. One or two sentences explaining the approach, naming any tool that must be installed first.
+2. Exactly one fenced code block containing the exact command(s), one per line, ready to copy and run as-is.
+   Use placeholders like <file> only when a value cannot be inferred, and no comments or prompts ($, >) inside the block.
+3. Optionally a short bullet list of important flags or caveats (destructive effects, differences between platforms).
+Prefer standard, widely available tools and safe, non-destructive options; never use sudo unless explicitly asked.`
+
+func runHowto(cmd *cobra.Command, args []string) {
+	if howtoPrint && howtoExec {
+		fail(ExitUsage, "%s", i18n.T("howto.print_with_exec"))
+		return
+	}
+	shell := detectShell()
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), howtoProvider)
+	if !ok {
+		return
+	}
+
+	system := fmt.Sprintf(howtoSystemPrompt, shell, runtime.GOOS, runtime.GOARCH)
+	resp, err := complete(cmd.Context(), provider, system, strings.Join(args, " "), 0.2)
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("howto.failed"), err)
+		return
+	}
+
+	command := ""
+	if strings.Contains(resp.Content, "```") {
+		command = extractCodeBlock(resp.Content)
+	}
+	if howtoPrint {
+		if command == "" {
+			fail(ExitProvider, "%s", i18n.T("howto.no_command"))
+			return
+		}
+		fmt.Println(command)
+		return
+	}
+
+	if out, err := ui.Markdown(resp.Content); err == nil {
+		fmt.Println(out)
+	} else {
+		fmt.Println(resp.Content)
+	}
+
+	if !howtoExec {
+		return
+	}
+	if command == "" {
+		fail(ExitProvider, "%s", i18n.T("howto.no_command"))
+		return
+	}
+	if !howtoYes && !confirm(i18n.T("howto.confirm")) {
+		fmt.Println(i18n.T("confirm.cancelled"))
+		return
+	}
+	if err := runShellCommand(shell, command); err != nil {
+		fail(ExitError, i18n.T("howto.exec_failed"), err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(howtoCmd)
+
+	howtoCmd.Flags().StringVarP(&howtoProvider, "provider", "p", "", "指定AI提供商")
+	howtoCmd.Flags().BoolVar(&howtoExec, "exec", false, "确认后执行代码块中的命令")
+	howtoCmd.Flags().BoolVarP(&howtoYes, "yes", "y", false, "与 --exec 一起使用时不确认直接执行")
+	howtoCmd.Flags().BoolVar(&howtoPrint, "print", false, "只输出代码块中的命令，不显示说明")
+}
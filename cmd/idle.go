@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+)
+
+// lineReader 逐行读取输入，交互模式等待输入时可以检测空闲超时。
+// 只在调用 next 时读取一行，确认提示等其他读取标准输入的地方不会被抢先读走输入
+type lineReader struct {
+	scanner *bufio.Scanner
+	lines   chan string
+	pending bool  // 已开始读取下一行，尚未取走
+	done    bool  // 输入已结束
+	err     error // 读取结束的原因，done 为true后才可以读取
+}
+
+// newLineReader 创建逐行读取输入的读取器
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{scanner: bufio.NewScanner(r), lines: make(chan string)}
+}
+
+// next 读取下一行，timeout 为0时一直等待。输入结束时 ok 为false，超时未输入时 idle 为true，
+// 超时后仍在等待的输入由下一次调用取走
+func (lr *lineReader) next(timeout time.Duration) (line string, ok, idle bool) {
+	if lr.done {
+		return "", false, false
+	}
+	if !lr.pending {
+		lr.pending = true
+		go func() {
+			if lr.scanner.Scan() {
+				lr.lines <- lr.scanner.Text()
+				return
+			}
+			lr.err = lr.scanner.Err()
+			close(lr.lines)
+		}()
+	}
+
+	var timeoutC <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutC = timer.C
+	}
+	select {
+	case line, ok = <-lr.lines:
+		lr.pending = false
+		lr.done = !ok
+		return line, ok, false
+	case <-timeoutC:
+		return "", true, true
+	}
+}
+
+// resolveIdleAction 检查配置的空闲超时操作，lock 需要加密保存的会话和 session passphrase 设置的解锁口令，
+// 否则改为退出
+func (rt *chatRuntime) resolveIdleAction() string {
+	switch rt.cfg.UI.IdleAction {
+	case "", config.IdleExit:
+		return config.IdleExit
+	case config.IdleLock:
+		if rt.session == nil || !rt.cfg.Advanced.EncryptSessions {
+			ui.Warn("%s", i18n.T("chat.idle_lock_unavailable"))
+			return config.IdleExit
+		}
+		v, err := loadLockVerifier()
+		if err != nil {
+			ui.Warn(i18n.T("chat.idle_lock_bad_passphrase"), err)
+			return config.IdleExit
+		}
+		if v == nil {
+			ui.Warn("%s", i18n.T("chat.idle_lock_no_passphrase"))
+			return config.IdleExit
+		}
+		rt.lockVerifier = v
+		return config.IdleLock
+	default:
+		ui.Warn(i18n.T("chat.idle_action_invalid"), rt.cfg.UI.IdleAction)
+		return config.IdleExit
+	}
+}
+
+// lockChat 保存会话后清屏并丢弃内存中的对话，输入正确的解锁口令后重新读取会话密钥并解密会话恢复对话。
+// 返回false表示输入已结束或无法解锁
+func (rt *chatRuntime) lockChat(lr *lineReader, history *[]Message) bool {
+	id, saved := rt.session.lock()
+	if saved {
+		*history = nil
+	}
+	ui.ClearScreen()
+	fmt.Println(i18n.T("chat.idle_locked"))
+
+	readLine := func() (string, bool) {
+		line, ok, _ := lr.next(0)
+		return line, ok
+	}
+	for {
+		passphrase, ok := readPassphrase(i18n.T("chat.idle_passphrase"), readLine)
+		if !ok {
+			return false
+		}
+		if rt.lockVerifier.verify(passphrase) {
+			break
+		}
+		fmt.Println(i18n.T("chat.idle_passphrase_wrong"))
+	}
+	restored, err := rt.session.unlock(id, saved)
+	if err != nil {
+		ui.Error(i18n.T("chat.idle_unlock_failed"), err)
+		return false
+	}
+	if saved {
+		*history = sessionHistory(restored)
+	}
+	fmt.Println(i18n.T("chat.idle_unlocked", len(*history)/2))
+	fmt.Println("---")
+	return true
+}
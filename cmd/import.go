@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"ai-chat-cli/internal/importer"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/session"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat    string
+	importOverwrite bool
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <导出文件>",
+	Short: "导入ChatGPT或Claude的数据导出",
+	Long: `将ChatGPT或Claude官方数据导出中的对话导入到本地会话存储，
+导入后可以使用 session list/show/export 查看，或使用 chat --session <id> 继续对话。
+
+支持导出的zip包或其中的 conversations.json，格式默认根据内容自动识别。
+重复导入时会跳过已存在的会话，使用 --overwrite 覆盖。
+
+示例:
+  ai-chat-cli import chatgpt-export.zip
+  ai-chat-cli import claude-export/conversations.json --format claude`,
+	Args: cobra.ExactArgs(1),
+	Run:  runImport,
+}
+
+func runImport(cmd *cobra.Command, args []string) {
+	data, err := importer.ReadConversations(args[0])
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	format := importFormat
+	if format == "auto" {
+		if format, err = importer.DetectFormat(data); err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+	}
+
+	sessions, err := importer.Parse(data, format)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	store, err := openSessionStore()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	fmt.Println(ui.Status(fmt.Sprintf("📥 %s 导出中共有 %d 个对话", format, len(sessions))))
+
+	imported, skipped := 0, 0
+	for _, s := range sessions {
+		if !importOverwrite {
+			if _, err := store.Get(s.ID); !errors.Is(err, session.ErrNotFound) {
+				skipped++
+				continue
+			}
+		}
+		if err := store.Save(s); err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+		imported++
+	}
+
+	if skipped > 0 {
+		ui.Success("已导入 %d 个会话，跳过 %d 个已存在的会话", imported, skipped)
+	} else {
+		ui.Success("已导入 %d 个会话", imported)
+	}
+	hint("使用 'ai-chat-cli session list' 查看导入的会话")
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importFormat, "format", "auto", "导出格式: auto, chatgpt, claude")
+	importCmd.Flags().BoolVar(&importOverwrite, "overwrite", false, "覆盖已存在的会话")
+}
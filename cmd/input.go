@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"ai-chat-cli/internal/ui"
+)
+
+// stdinIsPipe 判断标准输入是否来自管道或文件重定向
+func stdinIsPipe() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice == 0
+}
+
+// readInput 读取命令输入：优先读取指定文件，其次是管道输入，最后使用命令行参数
+func readInput(file string, args []string) (string, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("读取文件失败: %w", err)
+		}
+		return string(data), nil
+	}
+
+	if stdinIsPipe() {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("读取标准输入失败: %w", err)
+		}
+		return string(data), nil
+	}
+
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+
+	return "", fmt.Errorf("没有输入内容，请通过参数、--file 或标准输入提供")
+}
+
+// confirm 显示提示并等待用户确认，只有输入 y/yes 时返回true
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N]: ", prompt)
+
+	reader := bufio.NewReader(os.Stdin)
+	answer, err := reader.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// stdoutIsTerminal 判断标准输出是否为终端
+func stdoutIsTerminal() bool {
+	return ui.StdoutIsTerminal()
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/issue"
+	"ai-chat-cli/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	issueProvider string
+	issueRepo     string
+	issueJira     string
+	issueLabels   []string
+	issuePost     bool
+	issueYes      bool
+)
+
+// issueCmd 起草问题
+var issueCmd = &cobra.Command{
+	Use:   "issue <问题描述>",
+	Short: "起草结构化的问题报告，可提交到 GitHub 或 Jira",
+	Long: `根据简短的描述起草结构化的问题报告：标题、概述、复现步骤、预期结果、实际结果和环境信息，
+描述中没有的信息留作占位，不会编造。管道输入（如错误日志）作为补充材料一起发送。
+
+默认只输出草稿（Markdown，第一行为标题）。使用 --post 时先预览草稿，确认后提交：
+  GitHub  提交到 --repo 或 issues.repo 指定的仓库，需要 issues.github_token 或 GITHUB_TOKEN 环境变量
+  Jira    使用 --jira <项目键> 或 issues.tracker: jira，需要 issues.jira_url、jira_email 和 jira_token
+          （或 JIRA_API_TOKEN 环境变量）
+
+示例:
+  ai-chat-cli issue "部署后用户反馈502"
+  kubectl logs deploy/api --tail 50 | ai-chat-cli issue "部署后用户反馈502" --repo org/name --post
+  ai-chat-cli issue "导出PDF时中文乱码" --jira APP --post`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runIssue,
+}
+
+// issueSystemPrompt 起草问题报告的系统提示词
+const issueSystemPrompt = `You are an experienced engineer writing a bug report for an issue tracker.
+Turn the user's short description (and any attached logs or output) into a clear, structured issue.
+Reply in the user's language (Chinese if unsure) using exactly this layout:
+the first line is a concise, specific title (no prefix, no Markdown), then a blank line, then Markdown with these sections:
+## Summary, ## Steps to Reproduce (numbered), ## Expected Behavior, ## Actual Behavior, ## Environment, ## Additional Context.
+Translate the section headings into the reply language. Quote relevant log lines in fenced code blocks.
+Never invent facts: where information is missing, write a short placeholder such as "_TODO: 补充…_".`
+
+func runIssue(cmd *cobra.Command, args []string) {
+	opts, ok := issueOptions()
+	if !ok {
+		return
+	}
+	if issuePost && !envFrom(cmd.Context()).dryRun {
+		if err := opts.Validate(); err != nil {
+			fail(ExitConfig, "%v", err)
+			return
+		}
+	}
+
+	prompt := strings.Join(args, " ")
+	if stdinIsPipe() {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fail(ExitError, i18n.T("chat.stdin_failed"), err)
+			return
+		}
+		if attached := strings.TrimSpace(string(data)); attached != "" {
+			prompt += "\n\n补充材料:\n```\n" + attached + "\n```"
+		}
+	}
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), issueProvider)
+	if !ok {
+		return
+	}
+	resp, err := complete(cmd.Context(), provider, issueSystemPrompt, prompt, 0.3)
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("issue.draft_failed"), err)
+		return
+	}
+	draft, err := issue.ParseDraft(resp.Content)
+	if err != nil {
+		fail(ExitProvider, "%v", err)
+		return
+	}
+	draft.Labels = issueLabels
+
+	if !issuePost {
+		markdown := fmt.Sprintf("# %s\n\n%s", draft.Title, draft.Body)
+		if !stdoutIsTerminal() {
+			fmt.Println(markdown)
+			return
+		}
+		if out, err := ui.Markdown(markdown); err == nil {
+			fmt.Println(out)
+		} else {
+			fmt.Println(markdown)
+		}
+		hint("%s", i18n.T("issue.post_hint"))
+		return
+	}
+
+	// 提交前预览，标准输入被管道占用时在终端中确认
+	preview := fmt.Sprintf("# %s\n\n%s", draft.Title, draft.Body)
+	if out, err := ui.Markdown(preview); err == nil {
+		preview = out
+	}
+	fmt.Println(preview)
+	if !issueYes {
+		ok, err := confirmTerminal(i18n.T("issue.confirm", opts.Target()))
+		if err != nil {
+			fail(ExitUsage, i18n.T("issue.confirm_failed"), err)
+			return
+		}
+		if !ok {
+			fmt.Println(i18n.T("confirm.cancelled"))
+			return
+		}
+	}
+
+	url, err := issue.Post(cmd.Context(), draft, opts)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	ui.Success(i18n.T("issue.created"), url)
+}
+
+// issueOptions 合并配置文件、环境变量和命令行参数中的提交设置
+func issueOptions() (issue.Options, bool) {
+	var ic config.IssuesConfig
+	if cfg, err := config.LoadConfig(); err == nil {
+		ic = cfg.Issues
+	}
+
+	opts := issue.Options{
+		Tracker:   ic.Tracker,
+		Repo:      ic.Repo,
+		JiraURL:   ic.JiraURL,
+		JiraEmail: ic.JiraEmail,
+		Project:   ic.JiraProject,
+		IssueType: ic.JiraIssueType,
+	}
+	if opts.Tracker == "" {
+		opts.Tracker = issue.TrackerGitHub
+	}
+	if issueRepo != "" && issueJira != "" {
+		fail(ExitUsage, "%s", i18n.T("issue.repo_with_jira"))
+		return opts, false
+	}
+	if issueRepo != "" {
+		opts.Tracker, opts.Repo = issue.TrackerGitHub, issueRepo
+	}
+	if issueJira != "" {
+		opts.Tracker, opts.Project = issue.TrackerJira, issueJira
+	}
+
+	switch opts.Tracker {
+	case issue.TrackerGitHub:
+		opts.Token = ic.GitHubToken
+		if opts.Token == "" {
+			opts.Token = os.Getenv("GITHUB_TOKEN")
+		}
+	case issue.TrackerJira:
+		opts.Token = ic.JiraToken
+		if opts.Token == "" {
+			opts.Token = os.Getenv("JIRA_API_TOKEN")
+		}
+	default:
+		fail(ExitConfig, i18n.T("issue.tracker_invalid"), opts.Tracker)
+		return opts, false
+	}
+	return opts, true
+}
+
+func init() {
+	rootCmd.AddCommand(issueCmd)
+
+	issueCmd.Flags().StringVarP(&issueProvider, "provider", "p", "", "指定AI提供商")
+	issueCmd.Flags().StringVar(&issueRepo, "repo", "", "提交到的 GitHub 仓库（owner/name，覆盖 issues.repo）")
+	issueCmd.Flags().StringVar(&issueJira, "jira", "", "提交到 Jira 的项目键（覆盖 issues.jira_project）")
+	issueCmd.Flags().StringSliceVar(&issueLabels, "label", nil, "GitHub issue 的标签，多个用逗号分隔")
+	issueCmd.Flags().BoolVar(&issuePost, "post", false, "预览并确认后提交到 GitHub 或 Jira")
+	issueCmd.Flags().BoolVarP(&issueYes, "yes", "y", false, "与 --post 一起使用时不确认直接提交")
+}
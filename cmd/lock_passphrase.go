@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// lockPassphraseFile 保存解锁口令校验值的文件，位于 ~/.ai-chat-cli
+	lockPassphraseFile = "lock.json"
+	// lockPassphraseIterations 由口令派生校验值时 PBKDF2-SHA256 的迭代次数
+	lockPassphraseIterations = 600000
+)
+
+// lockVerifier 解锁口令的校验值：只保存随机盐和 PBKDF2 派生的哈希，不保存口令本身
+type lockVerifier struct {
+	Salt       []byte `json:"salt"`
+	Iterations int    `json:"iterations"`
+	Hash       []byte `json:"hash"`
+}
+
+// newLockVerifier 使用随机盐为口令生成校验值
+func newLockVerifier(passphrase string) (*lockVerifier, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	hash, err := pbkdf2.Key(sha256.New, passphrase, salt, lockPassphraseIterations, sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+	return &lockVerifier{Salt: salt, Iterations: lockPassphraseIterations, Hash: hash}, nil
+}
+
+// verify 检查口令是否与校验值一致
+func (v *lockVerifier) verify(passphrase string) bool {
+	hash, err := pbkdf2.Key(sha256.New, passphrase, v.Salt, v.Iterations, len(v.Hash))
+	return err == nil && subtle.ConstantTimeCompare(hash, v.Hash) == 1
+}
+
+// lockPassphrasePath 解锁口令校验值文件的路径
+func lockPassphrasePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ai-chat-cli", lockPassphraseFile), nil
+}
+
+// loadLockVerifier 读取解锁口令的校验值，没有设置口令时返回nil
+func loadLockVerifier() (*lockVerifier, error) {
+	path, err := lockPassphrasePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var v lockVerifier
+	if err := json.Unmarshal(data, &v); err != nil || len(v.Salt) == 0 || len(v.Hash) == 0 || v.Iterations <= 0 {
+		return nil, fmt.Errorf("%s 格式无效", path)
+	}
+	return &v, nil
+}
+
+// saveLockVerifier 保存解锁口令的校验值，只有当前用户可以读写
+func saveLockVerifier(v *lockVerifier) (string, error) {
+	path, err := lockPassphrasePath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// readPassphrase 关闭终端回显后用 readLine 读取一行口令，输入结束时返回false
+func readPassphrase(prompt string, readLine func() (string, bool)) (string, bool) {
+	fmt.Print(prompt)
+	if err := ui.SetStdinEcho(false); err == nil {
+		defer ui.SetStdinEcho(true)
+	}
+	line, ok := readLine()
+	fmt.Println()
+	return strings.TrimRight(line, "\r\n"), ok
+}
+
+// sessionPassphraseCmd 设置空闲锁定后的解锁口令
+var sessionPassphraseCmd = &cobra.Command{
+	Use:   "passphrase",
+	Short: "设置交互模式空闲锁定后的解锁口令",
+	Long: `设置 ui.idle_action 为 lock 时解锁对话需要输入的口令。
+
+只保存随机盐和 PBKDF2-SHA256 派生的校验值（~/.ai-chat-cli/lock.json），不保存口令本身。
+没有设置口令时空闲超时后直接保存会话并退出。`,
+	Args: cobra.NoArgs,
+	Run:  runSessionPassphrase,
+}
+
+func runSessionPassphrase(cmd *cobra.Command, args []string) {
+	if stdinIsPipe() {
+		fail(ExitUsage, "%s", i18n.T("lock.passphrase_terminal"))
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	readLine := func() (string, bool) {
+		line, err := reader.ReadString('\n')
+		return line, err == nil || line != ""
+	}
+	passphrase, ok := readPassphrase(i18n.T("lock.passphrase_new"), readLine)
+	if !ok || passphrase == "" {
+		fail(ExitUsage, "%s", i18n.T("lock.passphrase_empty"))
+		return
+	}
+	again, _ := readPassphrase(i18n.T("lock.passphrase_again"), readLine)
+	if again != passphrase {
+		fail(ExitUsage, "%s", i18n.T("lock.passphrase_mismatch"))
+		return
+	}
+
+	v, err := newLockVerifier(passphrase)
+	if err != nil {
+		fail(ExitError, i18n.T("lock.passphrase_failed"), err)
+		return
+	}
+	path, err := saveLockVerifier(v)
+	if err != nil {
+		fail(ExitError, i18n.T("lock.passphrase_failed"), err)
+		return
+	}
+	ui.Success(i18n.T("lock.passphrase_saved"), path)
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionPassphraseCmd)
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	modelsProvider string
+	modelsFilter   string
+	modelsPick     bool
+	modelsSave     bool
+)
+
+// pickerPageSize 选择模型时一次最多列出的模型数，更多的模型需要输入文字筛选
+const pickerPageSize = 30
+
+// modelsCmd 列出和选择模型
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "列出提供商的可用模型，或交互式选择模型",
+	Long: `从提供商的 /models 接口获取可用模型列表。
+
+--pick 在终端中列出模型，输入编号选择，输入文字按名称筛选。选择的模型输出到标准输出，
+可以用在其他命令中；加上 --save 时写入配置文件，作为该提供商默认使用的模型。
+交互模式中可以用 /models [筛选文字] 选择本次对话使用的模型。
+
+示例:
+  ai-chat-cli models
+  ai-chat-cli models -p openai --filter gpt-4
+  ai-chat-cli models --pick --save`,
+	Args: cobra.NoArgs,
+	Run:  runModels,
+}
+
+func runModels(cmd *cobra.Command, args []string) {
+	if modelsSave && !modelsPick {
+		fail(ExitUsage, "%s", i18n.T("models.save_needs_pick"))
+		return
+	}
+	if modelsPick && (stdinIsPipe() || !ui.StderrIsTerminal()) {
+		fail(ExitUsage, "%s", i18n.T("models.pick_needs_terminal"))
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fail(ExitConfig, i18n.T("config.load_failed"), err)
+		hint("%s", i18n.T("config.init_hint"))
+		return
+	}
+	name, providerCfg, ok := selectProvider(cfg, modelsProvider)
+	if !ok {
+		return
+	}
+	provider := newProvider(envFrom(cmd.Context()), cfg, name, providerCfg)
+
+	models, err := provider.GetModels(cmd.Context())
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("chat.models_failed"), err)
+		return
+	}
+	sort.Strings(models)
+
+	if !modelsPick {
+		matched := filterModels(models, modelsFilter)
+		if len(matched) == 0 {
+			fmt.Fprintln(os.Stderr, i18n.T("models.none_matched", modelsFilter))
+			return
+		}
+		for _, m := range matched {
+			if m == providerCfg.Model && stdoutIsTerminal() {
+				fmt.Printf("%s %s\n", m, ui.Colors().Green(i18n.T("models.current")))
+			} else {
+				fmt.Println(m)
+			}
+		}
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	readLine := func() (string, bool) {
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", false
+		}
+		return line, true
+	}
+	model, ok := pickModel(os.Stderr, models, providerCfg.Model, modelsFilter, readLine)
+	if !ok {
+		fmt.Fprintln(os.Stderr, i18n.T("models.cancelled"))
+		return
+	}
+	fmt.Println(model)
+
+	if !modelsSave {
+		hint(i18n.T("models.save_hint"), model, name)
+		return
+	}
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		fail(ExitConfig, "%s", i18n.T("models.no_config"))
+		return
+	}
+	if err := config.Set(path, "providers."+name+".model", model); err != nil {
+		fail(ExitConfig, i18n.T("models.save_failed"), err)
+		return
+	}
+	ui.Success(i18n.T("models.saved"), name, model)
+}
+
+// filterModels 按名称筛选模型，不区分大小写，多个词时需要都包含
+func filterModels(models []string, filter string) []string {
+	words := strings.Fields(strings.ToLower(filter))
+	var matched []string
+	for _, m := range models {
+		lower := strings.ToLower(m)
+		all := true
+		for _, w := range words {
+			if !strings.Contains(lower, w) {
+				all = false
+				break
+			}
+		}
+		if all {
+			matched = append(matched, m)
+		}
+	}
+	return matched
+}
+
+// pickModel 列出模型并读取用户的选择：输入编号选择，输入文字重新筛选，输入空行取消。
+// 当前使用的模型标有 *，列表和提示写入 out，readLine 读取一行输入，输入结束时返回false
+func pickModel(out io.Writer, models []string, current, filter string, readLine func() (string, bool)) (string, bool) {
+	for {
+		matched := filterModels(models, filter)
+		switch {
+		case len(matched) == 0:
+			fmt.Fprintln(out, i18n.T("models.none_matched", filter))
+		case filter != "":
+			fmt.Fprintln(out, i18n.T("models.matched", filter, len(matched)))
+		default:
+			fmt.Fprintln(out, i18n.T("models.available", len(matched)))
+		}
+
+		shown := matched
+		if len(shown) > pickerPageSize {
+			shown = shown[:pickerPageSize]
+		}
+		for i, m := range shown {
+			mark := " "
+			if m == current {
+				mark = "*"
+			}
+			fmt.Fprintf(out, "  %s %3d. %s\n", mark, i+1, m)
+		}
+		if len(shown) < len(matched) {
+			fmt.Fprintln(out, i18n.T("models.more", len(matched)-len(shown)))
+		}
+
+		fmt.Fprint(out, i18n.T("models.prompt"))
+		line, ok := readLine()
+		if !ok {
+			fmt.Fprintln(out)
+			return "", false
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return "", false
+		}
+		if n, err := strconv.Atoi(line); err == nil {
+			if n >= 1 && n <= len(shown) {
+				return shown[n-1], true
+			}
+			fmt.Fprintln(out, i18n.T("models.invalid", n))
+			continue
+		}
+		filter = line
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(modelsCmd)
+
+	modelsCmd.Flags().StringVarP(&modelsProvider, "provider", "p", "", "指定AI提供商")
+	modelsCmd.Flags().StringVar(&modelsFilter, "filter", "", "只列出名称包含这些文字的模型，多个词用空格分隔")
+	modelsCmd.Flags().BoolVar(&modelsPick, "pick", false, "在终端中交互式选择模型，选择的模型输出到标准输出")
+	modelsCmd.Flags().BoolVar(&modelsSave, "save", false, "将选择的模型写入配置文件，作为该提供商的默认模型")
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	moderateProvider string
+	moderateFile     string
+	moderateScores   bool
+)
+
+// moderateCmd represents the moderate command
+var moderateCmd = &cobra.Command{
+	Use:   "moderate [文本]",
+	Short: "检查文本是否违反内容政策",
+	Long: `使用提供商的内容审核接口检查文本，内容被标记时以状态码1退出，便于在脚本中预先检查。
+
+要在每次发送前自动审核用户输入，可在配置文件中设置:
+  advanced.moderate_inputs: warn    # 被标记时警告后继续发送
+  advanced.moderate_inputs: block   # 被标记时拒绝发送
+
+示例:
+  ai-chat-cli moderate "一段需要检查的文本"
+  cat comment.txt | ai-chat-cli moderate --scores`,
+	Run: runModerate,
+}
+
+func runModerate(cmd *cobra.Command, args []string) {
+	text, err := readInput(moderateFile, args)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	if text == "" {
+		fail(ExitUsage, "请提供要检查的文本")
+		return
+	}
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), moderateProvider)
+	if !ok {
+		return
+	}
+
+	moderator, ok := providers.Unwrap(provider).(providers.Moderator)
+	if !ok {
+		fail(ExitConfig, "提供商 '%s' 不支持内容审核", provider.GetName())
+		return
+	}
+
+	result, err := moderator.Moderate(cmd.Context(), text)
+	if err != nil {
+		fail(errorExitCode(err), "内容审核失败: %v", err)
+		return
+	}
+
+	if !result.Flagged {
+		ui.Success("未发现违规内容")
+	} else {
+		ui.Warn("内容被标记:")
+		for _, c := range result.Categories {
+			fmt.Printf("  • %s (%.2f)\n", c, result.Scores[c])
+		}
+	}
+
+	if moderateScores {
+		names := make([]string, 0, len(result.Scores))
+		for name := range result.Scores {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Println(ui.Styled(ui.ElemStats, "各类别得分:"))
+		for _, name := range names {
+			fmt.Printf("  %-28s %.4f\n", name, result.Scores[name])
+		}
+	}
+
+	if result.Flagged {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(moderateCmd)
+
+	moderateCmd.Flags().StringVarP(&moderateProvider, "provider", "p", "", "指定AI提供商")
+	moderateCmd.Flags().StringVarP(&moderateFile, "file", "f", "", "从文件读取文本")
+	moderateCmd.Flags().BoolVar(&moderateScores, "scores", false, "显示所有类别的得分")
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"ai-chat-cli/internal/clipboard"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/pkg/providers"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	ocrProvider      string
+	ocrModel         string
+	ocrAsk           string
+	ocrFromClipboard bool
+)
+
+// ocrMaxImageSize 图片文件的大小上限，超过时大多数API会拒绝请求
+const ocrMaxImageSize = 20 << 20
+
+// ocrCmd represents the ocr command
+var ocrCmd = &cobra.Command{
+	Use:   "ocr [图片文件]",
+	Short: "识别截图和图片中的文字",
+	Long: `将图片发送给支持图片输入（vision）的模型，提取其中的文字，或按 --ask 的要求分析图片。
+支持 PNG、JPEG、GIF 和 WebP，结果输出到标准输出，便于重定向到文件。
+
+--from-clipboard 读取剪贴板中的图片（如刚截的屏），macOS 使用 osascript，
+Windows 使用 PowerShell，Linux 需要安装 wl-clipboard（Wayland）或 xclip（X11）。
+
+示例:
+  ai-chat-cli ocr screenshot.png
+  ai-chat-cli ocr invoice.jpg --ask "把表格提取为CSV" > invoice.csv
+  ai-chat-cli ocr --from-clipboard --ask "这个报错是什么原因"`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runOCR,
+}
+
+// ocrPrompt 没有 --ask 时的提示词
+const ocrPrompt = `Extract all text from this image exactly as it appears, preserving line breaks and reading order.
+Render tables as Markdown tables and code as fenced code blocks. Output only the extracted text, without commentary.`
+
+func runOCR(cmd *cobra.Command, args []string) {
+	if ocrFromClipboard == (len(args) == 1) {
+		fail(ExitUsage, "%s", i18n.T("ocr.input_required"))
+		return
+	}
+
+	var data []byte
+	var err error
+	if ocrFromClipboard {
+		data, err = clipboard.ReadImage()
+	} else {
+		data, err = readImageFile(args[0])
+	}
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	img, err := providers.NewImage(data)
+	if err != nil {
+		fail(ExitUsage, "%v", err)
+		return
+	}
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), ocrProvider, providers.CapVision)
+	if !ok {
+		return
+	}
+
+	prompt := ocrPrompt
+	if ocrAsk != "" {
+		prompt = ocrAsk
+	}
+	resp, err := provider.Chat(cmd.Context(), &providers.ChatRequest{
+		Model:       ocrModel,
+		Messages:    []providers.Message{{Role: "user", Content: prompt, Images: []providers.Image{img}}},
+		Temperature: 0,
+	})
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("ocr.failed"), err)
+		return
+	}
+	fmt.Println(resp.Content)
+}
+
+// readImageFile 读取图片文件，文件过大时返回错误
+func readImageFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("ocr.read_failed"), err)
+	}
+	if info.Size() > ocrMaxImageSize {
+		return nil, fmt.Errorf(i18n.T("ocr.too_large"), path, float64(info.Size())/(1<<20), ocrMaxImageSize>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("ocr.read_failed"), err)
+	}
+	return data, nil
+}
+
+func init() {
+	rootCmd.AddCommand(ocrCmd)
+
+	ocrCmd.Flags().StringVarP(&ocrProvider, "provider", "p", "", "指定AI提供商（需要支持图片输入）")
+	ocrCmd.Flags().StringVarP(&ocrModel, "model", "m", "", "指定支持图片输入的模型（默认使用提供商配置的模型）")
+	ocrCmd.Flags().StringVar(&ocrAsk, "ask", "", "对图片的要求或问题（默认提取全部文字）")
+	ocrCmd.Flags().BoolVar(&ocrFromClipboard, "from-clipboard", false, "读取剪贴板中的图片")
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/pii"
+	"ai-chat-cli/pkg/providers"
+)
+
+// maskingProvider 发送前将邮箱、电话、证件号码和密钥替换为占位符，并在回复中恢复原值。
+// 每次请求使用新的替换器，serve 的不同客户端之间不会共享占位符
+type maskingProvider struct {
+	providers.Provider
+
+	mu       sync.Mutex
+	reported map[string]bool // 已报告过的值，对话历史再次发送时不重复报告
+}
+
+// newMaskingProvider 启用 advanced.mask_pii 时创建替换敏感信息的提供商，否则原样返回
+func newMaskingProvider(p providers.Provider, enabled bool) providers.Provider {
+	if !enabled {
+		return p
+	}
+	return &maskingProvider{Provider: p, reported: map[string]bool{}}
+}
+
+// Unwrap 返回被包装的提供商
+func (p *maskingProvider) Unwrap() providers.Provider {
+	return p.Provider
+}
+
+// Chat 替换敏感信息后发送对话请求，并恢复回复（包括请求多个回答时的其他回答）中的占位符
+func (p *maskingProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	masker, masked := p.mask(req)
+	resp, err := p.Provider.Chat(ctx, masked)
+	if err != nil {
+		return nil, err
+	}
+	restored := *resp
+	restored.Content = masker.Restore(resp.Content)
+	if len(resp.Alternatives) > 0 {
+		restored.Alternatives = make([]providers.Choice, len(resp.Alternatives))
+		for i, alt := range resp.Alternatives {
+			alt.Content = masker.Restore(alt.Content)
+			restored.Alternatives[i] = alt
+		}
+	}
+	return &restored, nil
+}
+
+// ChatStream 替换敏感信息后发送流式对话请求，并恢复数据块中的占位符
+func (p *maskingProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	masker, masked := p.mask(req)
+	chunks, err := p.Provider.ChatStream(ctx, masked)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan providers.StreamChunk)
+	go func() {
+		defer close(out)
+		restorer := masker.NewStreamRestorer()
+		for chunk := range chunks {
+			chunk.Content = restorer.Write(chunk.Content)
+			if chunk.Done || chunk.Error != nil {
+				chunk.Content += restorer.Flush()
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				for range chunks {
+				}
+				return
+			}
+		}
+		if rest := restorer.Flush(); rest != "" {
+			select {
+			case out <- providers.StreamChunk{Content: rest}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out, nil
+}
+
+// mask 复制请求并替换所有消息中的敏感信息，向标准错误报告新替换的值
+func (p *maskingProvider) mask(req *providers.ChatRequest) (*pii.Masker, *providers.ChatRequest) {
+	masker := pii.NewMasker()
+	masked := *req
+	masked.Messages = make([]providers.Message, len(req.Messages))
+	for i, m := range req.Messages {
+		m.Content = masker.Mask(m.Content)
+		masked.Messages[i] = m
+	}
+	masked.AssistantPrefix = masker.Mask(req.AssistantPrefix)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var items []string
+	for _, e := range masker.Entries() {
+		if p.reported[e.Value] {
+			continue
+		}
+		p.reported[e.Value] = true
+		items = append(items, fmt.Sprintf("%s %s", e.Placeholder, pii.Redact(e.Value)))
+	}
+	if len(items) > 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("pii.masked", len(items), strings.Join(items, i18n.T("list.separator"))))
+	}
+	return masker, &masked
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// echoChoicesProvider 将最后一条消息原样作为所有回答返回，回答中带有请求中的占位符
+type echoChoicesProvider struct {
+	providers.Provider
+	sent string
+}
+
+func (p *echoChoicesProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	p.sent = req.Messages[len(req.Messages)-1].Content
+	resp := &providers.ChatResponse{Content: p.sent}
+	for i := 1; i < req.N; i++ {
+		resp.Alternatives = append(resp.Alternatives, providers.Choice{Content: p.sent})
+	}
+	return resp, nil
+}
+
+func TestMaskingProviderRestoresAlternatives(t *testing.T) {
+	upstream := &echoChoicesProvider{}
+	p := newMaskingProvider(upstream, true)
+
+	question := "给 alice@example.com 回信"
+	resp, err := p.Chat(context.Background(), &providers.ChatRequest{
+		Messages: []providers.Message{{Role: "user", Content: question}},
+		N:        3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(upstream.sent, "alice@example.com") {
+		t.Fatalf("发送的请求中没有替换邮箱: %q", upstream.sent)
+	}
+
+	if resp.Content != question {
+		t.Errorf("回答为 %q，应恢复为 %q", resp.Content, question)
+	}
+	if len(resp.Alternatives) != 2 {
+		t.Fatalf("其他回答有 %d 个，应为2个", len(resp.Alternatives))
+	}
+	for i, alt := range resp.Alternatives {
+		if alt.Content != question {
+			t.Errorf("回答 %d 为 %q，应恢复为 %q", i+2, alt.Content, question)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pingCount    int
+	pingInterval time.Duration
+	pingModel    string
+)
+
+// pingCmd 测量提供商的响应延迟
+var pingCmd = &cobra.Command{
+	Use:   "ping [提供商...]",
+	Short: "测量提供商的响应延迟和首个token时间",
+	Long: `向提供商多次发送只生成1个token的流式请求，统计总延迟和首个token时间（TTFT）的
+最小值、平均值和P95，用于比较不同网关或接入点的速度。
+
+指定多个提供商时依次测量并分别统计；不指定时使用默认提供商。
+每次请求会产生少量费用。按 Ctrl+C 停止时输出已完成请求的统计。
+
+示例:
+  ai-chat-cli ping
+  ai-chat-cli ping openai deepseek -n 10
+  ai-chat-cli ping openai --model gpt-4o-mini --interval 1s`,
+	Run: runPing,
+}
+
+// pingStats 一个提供商的测量结果
+type pingStats struct {
+	latencies   []time.Duration
+	firstTokens []time.Duration
+	failed      int
+}
+
+func runPing(cmd *cobra.Command, args []string) {
+	if pingCount <= 0 {
+		fail(ExitUsage, "%s", i18n.T("ping.count_invalid"))
+		return
+	}
+	names := args
+	if len(names) == 0 {
+		names = []string{""}
+	}
+
+	ctx := cmd.Context()
+	var unreachable []string
+	for i, name := range names {
+		provider, ok := loadProvider(envFrom(cmd.Context()), name, providers.CapStreaming)
+		if !ok {
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		stats := pingProvider(ctx, provider)
+		printPingStats(provider.GetName(), stats)
+		if len(stats.latencies) == 0 && ctx.Err() == nil {
+			unreachable = append(unreachable, provider.GetName())
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if ctx.Err() != nil {
+		fail(ExitInterrupted, "%s", i18n.T("ping.interrupted"))
+		return
+	}
+	if len(unreachable) > 0 {
+		fail(ExitProvider, i18n.T("ping.all_failed"), strings.Join(unreachable, i18n.T("list.separator")))
+	}
+}
+
+// pingProvider 依次发送测试请求并记录每次的延迟
+func pingProvider(ctx context.Context, provider providers.Provider) *pingStats {
+	label := provider.GetName()
+	if pingModel != "" {
+		label += " (" + pingModel + ")"
+	}
+	fmt.Println(i18n.T("ping.header", label, pingCount))
+
+	stats := &pingStats{}
+	for i := 1; i <= pingCount; i++ {
+		if i > 1 && pingInterval > 0 {
+			select {
+			case <-time.After(pingInterval):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		latency, firstToken, err := pingOnce(ctx, provider)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			stats.failed++
+			fmt.Fprintf(os.Stderr, "  %d: %s\n", i, ui.Styled(ui.ElemError, err.Error()))
+			continue
+		}
+		stats.latencies = append(stats.latencies, latency)
+		if firstToken > 0 {
+			stats.firstTokens = append(stats.firstTokens, firstToken)
+			fmt.Printf("  %s\n", i18n.T("ping.reply", i, formatMs(latency), formatMs(firstToken)))
+		} else {
+			fmt.Printf("  %d: %s\n", i, formatMs(latency))
+		}
+	}
+	return stats
+}
+
+// pingOnce 发送一次只生成1个token的流式请求，返回总延迟和首个token时间
+func pingOnce(ctx context.Context, provider providers.Provider) (time.Duration, time.Duration, error) {
+	start := time.Now()
+	chunks, err := provider.ChatStream(ctx, &providers.ChatRequest{
+		Messages:  []providers.Message{{Role: "user", Content: "ping"}},
+		Model:     pingModel,
+		MaxTokens: 1,
+		Stream:    true,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var firstToken time.Duration
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			return 0, 0, chunk.Error
+		}
+		if chunk.Content != "" && firstToken == 0 {
+			firstToken = time.Since(start)
+		}
+	}
+	return time.Since(start), firstToken, nil
+}
+
+// printPingStats 输出最小值、平均值、P95和最大值
+func printPingStats(name string, stats *pingStats) {
+	total := len(stats.latencies) + stats.failed
+	fmt.Println(i18n.T("ping.stats_title", name))
+	fmt.Println(i18n.T("ping.stats_counts", total, len(stats.latencies), stats.failed))
+	if len(stats.latencies) > 0 {
+		fmt.Println(i18n.T("ping.stats_latency", durationSummary(stats.latencies)))
+	}
+	if len(stats.firstTokens) > 0 {
+		fmt.Println(i18n.T("ping.stats_first_token", durationSummary(stats.firstTokens)))
+	}
+}
+
+// durationSummary 计算最小值、平均值、P95和最大值，单位为毫秒
+func durationSummary(values []time.Duration) string {
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, v := range sorted {
+		sum += v
+	}
+	avg := sum / time.Duration(len(sorted))
+	// 最近秩法：取第 ceil(0.95*n) 个值
+	p95 := sorted[int(math.Ceil(0.95*float64(len(sorted))))-1]
+
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	return fmt.Sprintf("%.1f/%.1f/%.1f/%.1f ms", ms(sorted[0]), ms(avg), ms(p95), ms(sorted[len(sorted)-1]))
+}
+
+// formatMs 以毫秒显示耗时
+func formatMs(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+}
+
+func init() {
+	rootCmd.AddCommand(pingCmd)
+
+	pingCmd.Flags().IntVarP(&pingCount, "count", "n", 5, "每个提供商的请求次数")
+	pingCmd.Flags().DurationVar(&pingInterval, "interval", 0, "两次请求之间的间隔，如 500ms、1s")
+	pingCmd.Flags().StringVarP(&pingModel, "model", "m", "", "使用的模型（默认使用提供商配置的模型）")
+}
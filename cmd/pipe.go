@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var pipeProvider string
+
+// pipeCmd represents the pipe command
+var pipeCmd = &cobra.Command{
+	Use:   "pipe <指令>",
+	Short: "作为Unix管道过滤器处理标准输入",
+	Long: `读取标准输入，按照指令处理后只将结果写到标准输出。
+
+不输出任何提示、表情或统计信息，失败时以非零状态码退出，适合放在Shell管道中间。
+
+示例:
+  cat data.csv | ai-chat-cli pipe "转换为Markdown表格" > table.md
+  git log --oneline -20 | ai-chat-cli pipe "按功能分组总结这些提交" | tee notes.md`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runPipe,
+}
+
+const pipeSystemPrompt = `You are a text-processing filter in a Unix pipeline.
+Apply the user's instruction to the input text and output ONLY the resulting text.
+Never add explanations, greetings, Markdown code fences or commentary unless the instruction asks for them.`
+
+func runPipe(cmd *cobra.Command, args []string) {
+	if !stdinIsPipe() {
+		pipeFail(ExitUsage, "没有标准输入，请通过管道或重定向提供输入")
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		pipeFail(ExitError, "读取标准输入失败: %v", err)
+	}
+	input := string(data)
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), pipeProvider)
+	if !ok {
+		exit()
+	}
+
+	prompt := fmt.Sprintf("Instruction: %s\n\nInput:\n%s", strings.Join(args, " "), input)
+	resp, err := complete(cmd.Context(), provider, pipeSystemPrompt, prompt, 0.2)
+	if err != nil {
+		pipeFail(errorExitCode(err), "%v", err)
+	}
+
+	output := resp.Content
+	if strings.HasSuffix(input, "\n") && !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	if _, err := io.WriteString(os.Stdout, output); err != nil {
+		pipeFail(ExitError, "写入标准输出失败: %v", err)
+	}
+}
+
+// pipeFail 向标准错误输出错误信息并以指定的状态码退出
+func pipeFail(code int, format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, "ai-chat-cli pipe: "+format+"\n", a...)
+	os.Exit(code)
+}
+
+func init() {
+	rootCmd.AddCommand(pipeCmd)
+
+	pipeCmd.Flags().StringVarP(&pipeProvider, "provider", "p", "", "指定AI提供商")
+}
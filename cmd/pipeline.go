@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/pkg/providers"
+)
+
+// refinePrompt 请第二个模型修订草稿的提示词
+const refinePrompt = `以下是另一个模型针对上述问题写的草稿。请检查其中的错误和遗漏，改进结构和表达，
+直接输出完整的最终回答，不要提及草稿或修改过程。
+
+<draft>
+%s
+</draft>`
+
+// draftPipeline 先由便宜的模型起草，再由更强的模型参考草稿修订
+type draftPipeline struct {
+	draft  string
+	refine string
+}
+
+// parsePipeline 解析 --pipeline 的值，格式为 draft=<模型>,refine=<模型>
+func parsePipeline(spec string) (*draftPipeline, error) {
+	p := &draftPipeline{}
+	for _, part := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			return nil, fmt.Errorf(i18n.T("chat.pipeline_invalid"), spec)
+		}
+		switch strings.TrimSpace(key) {
+		case "draft":
+			p.draft = value
+		case "refine":
+			p.refine = value
+		default:
+			return nil, fmt.Errorf(i18n.T("chat.pipeline_invalid"), spec)
+		}
+	}
+	if p.draft == "" || p.refine == "" {
+		return nil, fmt.Errorf(i18n.T("chat.pipeline_invalid"), spec)
+	}
+	return p, nil
+}
+
+// chat 先生成草稿再修订，返回修订后的回复，用量为两次请求之和
+func (p *draftPipeline) chat(ctx context.Context, provider providers.Provider, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	fmt.Fprintln(os.Stderr, i18n.T("chat.pipeline_draft", p.draft))
+	draftReq := *req
+	draftReq.Model = p.draft
+	draftReq.AssistantPrefix = ""
+	draft, err := provider.Chat(ctx, &draftReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// 草稿附在最后一条用户消息后面，保持消息角色交替
+	messages := append([]providers.Message(nil), req.Messages...)
+	last := &messages[len(messages)-1]
+	last.Content += "\n\n" + fmt.Sprintf(refinePrompt, strings.TrimSpace(draft.Content))
+
+	fmt.Fprintln(os.Stderr, i18n.T("chat.pipeline_refine", p.refine))
+	refineReq := *req
+	refineReq.Messages = messages
+	refineReq.Model = p.refine
+	resp, err := provider.Chat(ctx, &refineReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Usage.PromptTokens += draft.Usage.PromptTokens
+	resp.Usage.CompletionTokens += draft.Usage.CompletionTokens
+	resp.Usage.TotalTokens += draft.Usage.TotalTokens
+	resp.Usage.Cost += draft.Usage.Cost
+	return resp, nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"ai-chat-cli/internal/diff"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	proofreadProvider  string
+	proofreadWrite     bool
+	proofreadChunkSize int
+)
+
+// proofreadCmd represents the proofread command
+var proofreadCmd = &cobra.Command{
+	Use:   "proofread <文件>",
+	Short: "校对文本的语法和拼写",
+	Long: `使用AI校对文件中的语法、拼写和标点错误，并以彩色差异显示修改内容。
+
+默认只显示差异，使用 --write 将修改写回原文件。
+
+示例:
+  ai-chat-cli proofread README.md
+  ai-chat-cli proofread docs/intro.md --write`,
+	Args: cobra.ExactArgs(1),
+	Run:  runProofread,
+}
+
+const proofreadSystemPrompt = `You are a meticulous proofreader. Correct grammar, spelling, punctuation and obvious typos in the user's text.
+Rules:
+- Keep the original language, meaning, tone and wording wherever it is already correct.
+- Preserve the formatting exactly: Markdown syntax, line breaks, indentation, code blocks, links and URLs.
+- Never change content inside code blocks or inline code.
+- Output only the corrected text, without comments or explanations.`
+
+func runProofread(cmd *cobra.Command, args []string) {
+	if proofreadChunkSize <= 0 {
+		fail(ExitUsage, "%s", i18n.T("chunk.size_invalid"))
+		return
+	}
+
+	filename := args[0]
+	info, err := os.Stat(filename)
+	if err != nil {
+		fail(ExitError, i18n.T("file.read_failed"), err)
+		return
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		fail(ExitError, i18n.T("file.read_failed"), err)
+		return
+	}
+	original := string(data)
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), proofreadProvider)
+	if !ok {
+		return
+	}
+
+	chunks, seps := splitChunksWithSeparators(original, proofreadChunkSize)
+	var result strings.Builder
+	for i, chunk := range chunks {
+		if len(chunks) > 1 {
+			fmt.Println(i18n.T("proofread.chunk", i+1, len(chunks)))
+		}
+		resp, err := complete(cmd.Context(), provider, proofreadSystemPrompt, chunk, 0)
+		if err != nil {
+			fail(errorExitCode(err), i18n.T("proofread.failed"), err)
+			return
+		}
+		// 保留原文块末尾的换行，再用原有的分隔符拼接，不引入原文中没有的空行
+		trailing := chunk[len(strings.TrimRight(chunk, "\n")):]
+		result.WriteString(strings.TrimRight(resp.Content, "\n") + trailing)
+		if i < len(seps) {
+			result.WriteString(seps[i])
+		}
+	}
+
+	unified := diff.Unified(filename, i18n.T("proofread.diff_label", filename), original, result.String(), 2)
+	if unified == "" {
+		ui.Success("%s", i18n.T("proofread.no_changes"))
+		return
+	}
+	printColoredDiff(unified)
+
+	if !proofreadWrite {
+		fmt.Println()
+		hint("%s", i18n.T("proofread.write_hint"))
+		return
+	}
+
+	if err := os.WriteFile(filename, []byte(result.String()), info.Mode().Perm()); err != nil {
+		fail(ExitError, i18n.T("file.write_failed"), err)
+		return
+	}
+	fmt.Println()
+	ui.Success(i18n.T("proofread.written"), filename)
+}
+
+// printColoredDiff 以彩色输出统一格式的差异
+func printColoredDiff(unified string) {
+	for _, line := range strings.Split(strings.TrimSuffix(unified, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Println(ui.Colors().Bold(line))
+		case strings.HasPrefix(line, "@@"):
+			fmt.Println(ui.Colors().Cyan(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Println(ui.Colors().Green(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Println(ui.Colors().Red(line))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(proofreadCmd)
+
+	proofreadCmd.Flags().StringVarP(&proofreadProvider, "provider", "p", "", "指定AI提供商")
+	proofreadCmd.Flags().BoolVarP(&proofreadWrite, "write", "w", false, "将修改写回原文件")
+	proofreadCmd.Flags().IntVar(&proofreadChunkSize, "chunk-size", 8000, "每次校对的最大字符数")
+}
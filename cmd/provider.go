@@ -0,0 +1,438 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/oauth"
+	"ai-chat-cli/internal/ratelimit"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+)
+
+// providerEnv 创建提供商时使用的命令行全局参数，由根命令在执行子命令前生成，之后不再修改
+type providerEnv struct {
+	// transport 发送请求使用的传输层，由 --record 或 --replay 设置，为nil时直接发送
+	transport http.RoundTripper
+	// middlewares 所有提供商的HTTP请求共用的中间件，如 logging.requests 的请求日志
+	middlewares []providers.Middleware
+	// metadata --metadata 指定的请求 metadata
+	metadata map[string]string
+	// dryRun 演练模式：只打印将要发送的请求，不调用API
+	dryRun bool
+}
+
+// withMiddleware 返回追加了中间件的副本，如 serve 的请求统计，不影响原来的参数
+func (e *providerEnv) withMiddleware(m providers.Middleware) *providerEnv {
+	env := *e
+	env.middlewares = append(append([]providers.Middleware(nil), e.middlewares...), m)
+	return &env
+}
+
+type providerEnvKey struct{}
+
+// withProviderEnv 返回带有提供商参数的上下文
+func withProviderEnv(ctx context.Context, env *providerEnv) context.Context {
+	return context.WithValue(ctx, providerEnvKey{}, env)
+}
+
+// envFrom 获取上下文中的提供商参数，没有时（如测试中）使用默认值
+func envFrom(ctx context.Context) *providerEnv {
+	if env, ok := ctx.Value(providerEnvKey{}).(*providerEnv); ok {
+		return env
+	}
+	return &providerEnv{}
+}
+
+// selectProvider 选择要使用的提供商，未指定名称时自动选择第一个已设置API密钥的提供商。
+// 内置的 mock 提供商和 PATH 中的插件不需要配置。选择失败时向标准错误打印提示信息并返回false。
+func selectProvider(cfg *config.Config, name string) (string, config.ProviderConfig, bool) {
+	if providers.Standalone(name) {
+		return name, cfg.Providers[name], true
+	}
+
+	if name == "" {
+		for n, providerCfg := range cfg.Providers {
+			if providerCfg.HasCredentials() {
+				name = n
+				fmt.Fprintln(os.Stderr, i18n.T("provider.auto", n))
+				break
+			}
+		}
+	}
+
+	providerCfg, exists := cfg.Providers[name]
+	if !exists {
+		fail(ExitConfig, i18n.T("provider.not_found"), name)
+		fmt.Fprintln(os.Stderr, i18n.T("provider.available"))
+		for n := range cfg.Providers {
+			fmt.Fprintf(os.Stderr, "  • %s\n", n)
+		}
+		for _, n := range providers.ListPlugins() {
+			fmt.Fprintf(os.Stderr, "  • %s (%s%s)\n", n, providers.PluginPrefix, n)
+		}
+		return "", config.ProviderConfig{}, false
+	}
+
+	if !providerCfg.HasCredentials() {
+		fail(ExitConfig, i18n.T("provider.no_key"), name)
+		hint(i18n.T("provider.key_hint"), name)
+		return "", config.ProviderConfig{}, false
+	}
+
+	return name, providerCfg, true
+}
+
+// newProvider 创建命令行使用的提供商实例，输入超过 advanced.confirm_input_tokens 时发送前请用户确认，
+// 配置了 filters 时发送前检查提示词，启用 advanced.cache 时相同的请求使用缓存的回复，
+// 流式回复中断时按 advanced.stream_resumes 自动续写
+func newProvider(env *providerEnv, cfg *config.Config, name string, providerCfg config.ProviderConfig) providers.Provider {
+	advanced := cfg.Advanced
+	p := buildProvider(env, name, providerCfg, advanced)
+	if env.dryRun {
+		return p
+	}
+	p = newResumingProvider(p, advanced.StreamResumes)
+	p = newAuditedProvider(p, providerCfg.Model)
+	p = newCachedProvider(p, providerCfg, advanced, env.metadata, true)
+	p = newMaskingProvider(p, advanced.MaskPII)
+	if name == providers.MockName {
+		return p
+	}
+	p = newGuardedProvider(p, advanced.ConfirmInputTokens, providerCfg.InputPrice)
+	return newFilteredProvider(p, cfg.Filters, true)
+}
+
+// buildProvider 根据提供商配置创建提供商实例，配置了 advanced.moderate_inputs 时发送前审核用户输入。
+// 提供商的类型由注册表按名称确定（mock、PATH 中的 ai-chat-provider-<name> 插件或兼容OpenAI的API），
+// mock 和插件不支持输入审核。演练模式下返回只打印请求的提供商，也不会发送审核请求
+func buildProvider(env *providerEnv, name string, providerCfg config.ProviderConfig, advanced config.AdvancedConfig) providers.Provider {
+	pcfg := providers.Config{
+		APIKey:      providerCfg.APIKey,
+		BaseURL:     providerCfg.BaseURL,
+		Model:       providerCfg.Model,
+		MaxTokens:   providerCfg.MaxTokens,
+		Timeout:     time.Duration(advanced.Timeout) * time.Second,
+		TokenSource: tokenSource(name, providerCfg.Auth),
+		Transport:   env.transport,
+		MaxRetries:  advanced.MaxRetries,
+		Compat:      providerCompat(name, providerCfg.Compat),
+		Sampling:    providerSampling(providerCfg),
+		User:        providerCfg.UserID,
+		Metadata:    providerMetadata(providerCfg, env.metadata),
+		Middlewares: requestMiddlewares(env, name, providerCfg.RateLimit),
+		Extra:       providerCfg.Extra,
+		Warn:        ui.Warn,
+	}
+	if env.dryRun {
+		return &dryRunProvider{providers.NewOpenAIProvider(name, pcfg)}
+	}
+
+	reg := providers.Resolve(name)
+	p, err := reg.New(name, pcfg)
+	if err != nil {
+		// 插件在查找后被删除等情况，发送请求时报告错误
+		return brokenProvider{name: name, err: err}
+	}
+	if reg.Match != nil {
+		return p
+	}
+
+	switch advanced.ModerateInputs {
+	case "":
+		return p
+	case providers.ModerationWarn, providers.ModerationBlock:
+		moderator, ok := p.(providers.Moderator)
+		if !ok {
+			return p
+		}
+		return providers.NewModeratedProvider(p, moderator, advanced.ModerateInputs, warnModeration)
+	default:
+		ui.Warn(i18n.T("moderation.unknown"), advanced.ModerateInputs)
+		return p
+	}
+}
+
+// providerMetadata 合并提供商配置的 metadata 和 --metadata，同名的键以命令行为准。
+// 合并后超过API限制时提示，并只发送命令行指定的部分
+func providerMetadata(providerCfg config.ProviderConfig, requested map[string]string) map[string]string {
+	if len(providerCfg.Metadata) == 0 {
+		return requested
+	}
+	merged := mergeMetadata(providerCfg.Metadata, requested)
+	if err := providers.ValidateMetadata(merged); err != nil {
+		ui.Warn(i18n.T("provider.metadata_invalid"), err)
+		return requested
+	}
+	return merged
+}
+
+// mergeMetadata 合并两组 metadata，同名的键以 override 为准，都为空时返回nil
+func mergeMetadata(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+// providerSampling 提供商配置的默认采样参数，请求未指定时使用
+func providerSampling(providerCfg config.ProviderConfig) providers.Sampling {
+	return providers.Sampling{
+		TopP:             providerCfg.TopP,
+		PresencePenalty:  providerCfg.PresencePenalty,
+		FrequencyPenalty: providerCfg.FrequencyPenalty,
+		Stop:             providerCfg.Stop,
+	}
+}
+
+// defaultTemperature 对话请求默认的温度参数：提供商配置的 temperature，未配置时为0.7
+func defaultTemperature(providerCfg config.ProviderConfig) float64 {
+	if providerCfg.Temperature != nil {
+		return *providerCfg.Temperature
+	}
+	return 0.7
+}
+
+// providerTemperature 按名称读取提供商配置的默认温度，读取配置失败时为0.7
+func providerTemperature(name string) float64 {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return defaultTemperature(config.ProviderConfig{})
+	}
+	return defaultTemperature(cfg.Providers[name])
+}
+
+// providerCompat 将配置中的兼容设置转换为提供商使用的格式，无效的字段名改用默认值
+func providerCompat(name string, c config.CompatConfig) providers.Compat {
+	compat := providers.Compat{
+		MaxTokensField:  c.MaxTokensField,
+		OmitTemperature: c.OmitTemperature,
+		StreamUsage:     c.StreamUsage,
+	}
+	if err := compat.Validate(); err != nil {
+		ui.Warn(i18n.T("provider.compat_invalid"), name, err)
+		compat.MaxTokensField = ""
+	}
+	return compat
+}
+
+// requestMiddlewares 提供商的HTTP请求使用的中间件，rate_limit 大于0时按提供商名称共享限流器
+func requestMiddlewares(env *providerEnv, name string, rpm int) []providers.Middleware {
+	middlewares := append([]providers.Middleware(nil), env.middlewares...)
+	if limiter := ratelimit.For(name, rpm); limiter != nil {
+		middlewares = append(middlewares, providers.RateLimit(limiter))
+	}
+	return middlewares
+}
+
+// brokenProvider 无法创建的提供商，每次请求时报告创建失败的原因
+type brokenProvider struct {
+	name string
+	err  error
+}
+
+// GetName 获取提供商名称
+func (p brokenProvider) GetName() string { return p.name }
+
+// Chat 返回创建失败的原因
+func (p brokenProvider) Chat(ctx context.Context, req *providers.ChatRequest) (*providers.ChatResponse, error) {
+	return nil, p.err
+}
+
+// ChatStream 返回创建失败的原因
+func (p brokenProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	return nil, p.err
+}
+
+// GetModels 返回创建失败的原因
+func (p brokenProvider) GetModels(ctx context.Context) ([]string, error) { return nil, p.err }
+
+// ValidateConfig 返回创建失败的原因
+func (p brokenProvider) ValidateConfig() error { return p.err }
+
+// providerCapabilities 提供商支持的功能，配置了 providers.<name>.capabilities 时以配置为准，
+// 否则使用提供商类型的默认值
+func providerCapabilities(name string, providerCfg config.ProviderConfig) providers.Capabilities {
+	if len(providerCfg.Capabilities) == 0 {
+		return providers.Resolve(name).Capabilities
+	}
+	caps := providers.Capabilities{}
+	for _, c := range providerCfg.Capabilities {
+		capability, err := providers.ParseCapability(c)
+		if err != nil {
+			ui.Warn("providers.%s.capabilities: %v", name, err)
+			continue
+		}
+		caps = append(caps, capability)
+	}
+	return caps
+}
+
+// formatCapabilities 以逗号分隔列出支持的功能
+func formatCapabilities(caps providers.Capabilities) string {
+	if len(caps) == 0 {
+		return i18n.T("provider.capabilities_none")
+	}
+	names := make([]string, len(caps))
+	for i, c := range caps {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
+
+// requireCapabilities 检查提供商是否支持命令需要的功能，不支持时打印错误并返回false，
+// 避免发送注定失败的请求
+func requireCapabilities(name string, providerCfg config.ProviderConfig, required ...providers.Capability) bool {
+	caps := providerCapabilities(name, providerCfg)
+	for _, c := range required {
+		if !caps.Has(c) {
+			fail(ExitUsage, i18n.T("provider.unsupported"), name, c)
+			hint(i18n.T("provider.capabilities_hint"), name)
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	tokenSourcesMu sync.Mutex
+	// tokenSources 按提供商名称缓存的令牌来源，同一次运行中创建的多个提供商实例共享令牌
+	tokenSources = map[string]providers.TokenSource{}
+)
+
+// tokenSource 根据 auth 配置创建令牌来源，使用API密钥时返回nil。
+// 配置无效时返回的令牌来源在每次请求时报告错误
+func tokenSource(name string, auth config.AuthConfig) providers.TokenSource {
+	if auth.Type == "" || auth.Type == config.AuthAPIKey {
+		return nil
+	}
+
+	tokenSourcesMu.Lock()
+	defer tokenSourcesMu.Unlock()
+	if ts, ok := tokenSources[name]; ok {
+		return ts
+	}
+
+	var ts providers.TokenSource
+	var err error
+	switch auth.Type {
+	case config.AuthAzureAD:
+		ts, err = oauth.NewAzureAD(auth.TenantID, auth.ClientID, auth.ClientSecret, auth.Scope)
+	case config.AuthOAuth:
+		ts, err = oauth.NewClientCredentials(auth.TokenURL, auth.ClientID, auth.ClientSecret, auth.Scope)
+	case config.AuthGoogleADC:
+		ts, err = oauth.NewGoogleADC(auth.CredentialsFile, auth.Scope)
+	default:
+		err = fmt.Errorf(i18n.T("provider.auth_unsupported"), auth.Type)
+	}
+	if err != nil {
+		ts = brokenTokenSource{err}
+	}
+	tokenSources[name] = ts
+	return ts
+}
+
+// brokenTokenSource 认证配置无效时使用的令牌来源
+type brokenTokenSource struct {
+	err error
+}
+
+// Token 返回配置错误
+func (s brokenTokenSource) Token(ctx context.Context) (string, error) {
+	return "", s.err
+}
+
+// warnModeration 输入被标记或审核失败时向标准错误打印警告
+func warnModeration(result *providers.ModerationResult, err error) {
+	if err != nil {
+		ui.Warn(i18n.T("moderation.failed"), err)
+		return
+	}
+	ui.Warn(i18n.T("moderation.flagged"), result)
+}
+
+// loadProvider 加载配置并创建指定的提供商，required 为命令需要的功能，失败或提供商不支持时打印提示信息并返回false
+func loadProvider(env *providerEnv, name string, required ...providers.Capability) (providers.Provider, bool) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fail(ExitConfig, i18n.T("config.load_failed"), err)
+		hint("%s", i18n.T("config.init_hint"))
+		return nil, false
+	}
+
+	name, providerCfg, ok := selectProvider(cfg, name)
+	if !ok {
+		return nil, false
+	}
+	if !requireCapabilities(name, providerCfg, required...) {
+		return nil, false
+	}
+
+	return newProvider(env, cfg, name, providerCfg), true
+}
+
+// complete 以系统提示词和用户输入发送一次性对话请求
+func complete(ctx context.Context, provider providers.Provider, system, prompt string, temperature float64) (*providers.ChatResponse, error) {
+	var messages []providers.Message
+	if system != "" {
+		messages = append(messages, providers.Message{Role: "system", Content: system})
+	}
+	messages = append(messages, providers.Message{Role: "user", Content: prompt})
+
+	return provider.Chat(ctx, &providers.ChatRequest{
+		Messages:    messages,
+		Temperature: temperature,
+	})
+}
+
+// parseJSONReply 从模型回复中解析JSON，兼容被Markdown代码块包裹或夹杂说明文字的情况
+func parseJSONReply(reply string, v interface{}) error {
+	text := strings.TrimSpace(reply)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+
+	if start := strings.IndexAny(text, "{["); start > 0 {
+		text = text[start:]
+	}
+	if end := strings.LastIndexAny(text, "}]"); end >= 0 {
+		text = text[:end+1]
+	}
+
+	return json.Unmarshal([]byte(text), v)
+}
+
+// extractCodeBlock 提取回复中第一个Markdown代码块的内容，没有代码块时返回去除首尾空白的原文
+func extractCodeBlock(reply string) string {
+	start := strings.Index(reply, "```")
+	if start < 0 {
+		return strings.TrimSpace(reply)
+	}
+
+	body := reply[start+3:]
+	// 跳过代码块的语言标记
+	if newline := strings.Index(body, "\n"); newline >= 0 {
+		body = body[newline+1:]
+	}
+	if end := strings.Index(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+	return strings.TrimSpace(body)
+}
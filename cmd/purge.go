@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/logfile"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/session"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	purgeOlderThan string
+	purgeProvider  string
+	purgeAll       bool
+	purgeYes       bool
+)
+
+// purgeCmd 按保留策略删除历史数据
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "按保留策略删除会话历史和日志",
+	Long: `删除符合条件的会话、会话归档和日志文件，用于执行数据保留策略。
+
+删除的内容:
+  会话  ~/.ai-chat-cli/sessions 中的会话，按最后更新时间筛选，token用量记录保存在会话中会一并删除
+  归档  session compact 生成的会话副本，按归档时间筛选
+  日志  logging.file 配置的日志文件及其轮转文件，按修改时间筛选。
+        日志包含所有提供商的请求，指定 --provider 时不删除日志
+
+--older-than 支持 d（天）、w（周）和 Go 的时间格式，如 90d、2w、12h。
+配置文件、模板、定时任务和会话密钥不会被删除。
+使用 --dry-run 只列出将要删除的内容。
+
+示例:
+  ai-chat-cli purge --older-than 90d
+  ai-chat-cli purge --older-than 30d --provider openai --dry-run
+  ai-chat-cli purge --all --yes`,
+	Args: cobra.NoArgs,
+	Run:  runPurge,
+}
+
+// purgeItem 一项待删除的数据
+type purgeItem struct {
+	kind   string    // 会话、归档或日志
+	name   string    // 显示名称
+	time   time.Time // 用于筛选的时间，为零值时不显示
+	remove func() error
+}
+
+func runPurge(cmd *cobra.Command, args []string) {
+	if !purgeAll && purgeOlderThan == "" && purgeProvider == "" {
+		fail(ExitUsage, "%s", i18n.T("purge.filter_required"))
+		return
+	}
+
+	var cutoff time.Time
+	if purgeOlderThan != "" {
+		age, err := parseAge(purgeOlderThan)
+		if err != nil {
+			fail(ExitUsage, "%v", err)
+			return
+		}
+		cutoff = time.Now().Add(-age)
+	}
+	match := func(provider string, t time.Time) bool {
+		if purgeProvider != "" && provider != purgeProvider {
+			return false
+		}
+		return cutoff.IsZero() || t.Before(cutoff)
+	}
+
+	store, err := openSessionStore()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	var items []purgeItem
+	if purgeAll {
+		// 全部删除时不读取会话内容，缺少解密密钥或文件损坏的会话也一并删除
+		dir, err := session.DefaultDir()
+		if err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+		if _, err := os.Stat(dir); err == nil {
+			items = append(items, purgeItem{kind: i18n.T("purge.kind_session"), name: dir, remove: func() error { return os.RemoveAll(dir) }})
+		}
+	} else {
+		sessions, err := store.List()
+		if err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+		for _, s := range sessions {
+			if match(s.Provider, s.UpdatedAt) {
+				id := s.ID
+				items = append(items, purgeItem{kind: i18n.T("purge.kind_session"), name: id + "  " + s.Title, time: s.UpdatedAt, remove: func() error { return store.Delete(id) }})
+			}
+		}
+
+		archives, err := store.Archives()
+		if err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+		for _, a := range archives {
+			if match(a.Provider, a.ArchivedAt) {
+				path := a.Path
+				items = append(items, purgeItem{kind: i18n.T("purge.kind_archive"), name: path, time: a.ArchivedAt, remove: func() error { return os.Remove(path) }})
+			}
+		}
+	}
+
+	if purgeProvider == "" {
+		logs, ok := purgeLogFiles(cutoff)
+		if !ok {
+			return
+		}
+		items = append(items, logs...)
+	}
+
+	if len(items) == 0 {
+		fmt.Println(i18n.T("purge.none"))
+		return
+	}
+
+	for _, item := range items {
+		if item.time.IsZero() {
+			fmt.Printf("  %s  %s\n", item.kind, item.name)
+			continue
+		}
+		fmt.Printf("  %s  %s  %s\n", item.kind, item.time.Format("2006-01-02 15:04"), item.name)
+	}
+	if envFrom(cmd.Context()).dryRun {
+		fmt.Println(i18n.T("purge.dry_run", len(items)))
+		return
+	}
+	if !purgeYes && !confirm(i18n.T("purge.confirm", len(items))) {
+		fmt.Println(i18n.T("confirm.cancelled"))
+		return
+	}
+
+	removed := 0
+	for _, item := range items {
+		if err := item.remove(); err != nil && !os.IsNotExist(err) {
+			fail(ExitError, i18n.T("purge.delete_failed"), item.name, err)
+			continue
+		}
+		removed++
+	}
+	ui.Success(i18n.T("purge.deleted"), removed)
+}
+
+// purgeLogFiles 列出修改时间早于 cutoff 的日志文件，cutoff 为零值时列出全部
+func purgeLogFiles(cutoff time.Time) ([]purgeItem, bool) {
+	cfg, err := config.LoadConfig()
+	if err != nil || cfg.Logging.File == "" {
+		return nil, true
+	}
+	path, err := expandHome(cfg.Logging.File)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return nil, false
+	}
+
+	var items []purgeItem
+	for _, name := range append([]string{path}, logfile.Backups(path)...) {
+		info, err := os.Stat(name)
+		if err != nil || (!cutoff.IsZero() && !info.ModTime().Before(cutoff)) {
+			continue
+		}
+		name := name
+		items = append(items, purgeItem{kind: i18n.T("purge.kind_log"), name: name, time: info.ModTime(), remove: func() error { return os.Remove(name) }})
+	}
+	return items, true
+}
+
+// parseAge 解析时长，除 Go 的时间格式外支持 d（天）和 w（周），如 90d、2w
+func parseAge(s string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit > 0 {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf(i18n.T("purge.age_invalid"), s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf(i18n.T("purge.age_invalid"), s)
+	}
+	return d, nil
+}
+
+func init() {
+	rootCmd.AddCommand(purgeCmd)
+
+	purgeCmd.Flags().StringVar(&purgeOlderThan, "older-than", "", "只删除早于该时长的数据，如 90d、2w、12h")
+	purgeCmd.Flags().StringVarP(&purgeProvider, "provider", "p", "", "只删除该提供商的会话和归档")
+	purgeCmd.Flags().BoolVar(&purgeAll, "all", false, "删除全部会话、归档和日志")
+	purgeCmd.Flags().BoolVarP(&purgeYes, "yes", "y", false, "不确认直接删除（用于脚本）")
+	purgeCmd.MarkFlagsMutuallyExclusive("all", "older-than")
+	purgeCmd.MarkFlagsMutuallyExclusive("all", "provider")
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/queue"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	queueWait     bool
+	queueInterval time.Duration
+)
+
+// queueCmd 管理网络不可用时保存的请求
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "管理离线时加入队列的请求",
+	Long: `网络不可用时，chat --queue 会把一次性问题保存到 ~/.ai-chat-cli/queue 中，
+联网后运行 queue flush 依次发送并输出回答。
+
+示例:
+  ai-chat-cli chat --queue "总结今天的会议记录"
+  ai-chat-cli queue list
+  ai-chat-cli queue flush
+  ai-chat-cli queue flush --wait     # 等待网络恢复后发送`,
+	Run: runQueueList,
+}
+
+// queueListCmd 列出队列中的请求
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出队列中的请求",
+	Args:  cobra.NoArgs,
+	Run:   runQueueList,
+}
+
+// queueFlushCmd 发送队列中的请求
+var queueFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "发送队列中的请求并输出回答",
+	Long: `按加入的先后顺序发送队列中的请求，回答输出到标准输出，发送成功的请求从队列中删除。
+
+网络仍不可用时停止发送，剩余的请求保留在队列中；使用 --wait 时每隔 --interval 重试，
+直到网络恢复。其他原因失败的请求也保留在队列中，可以稍后重试或用 queue remove 删除。`,
+	Args: cobra.NoArgs,
+	Run:  runQueueFlush,
+}
+
+// queueRemoveCmd 删除队列中的请求
+var queueRemoveCmd = &cobra.Command{
+	Use:   "remove <id>...",
+	Short: "删除队列中的请求",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runQueueRemove,
+}
+
+func runQueueList(cmd *cobra.Command, args []string) {
+	_, items, ok := loadQueue()
+	if !ok {
+		return
+	}
+	if len(items) == 0 {
+		fmt.Println(i18n.T("queue.empty"))
+		return
+	}
+	for _, it := range items {
+		fmt.Printf("%s  %s  %s\n", it.ID, it.Provider, session.DefaultTitle(it.Prompt()))
+		if it.LastError != "" {
+			fmt.Printf("          %s\n", i18n.T("queue.attempts", it.Attempts, it.LastError))
+		}
+	}
+	fmt.Printf("\n%s\n", i18n.T("queue.total", len(items)))
+}
+
+func runQueueFlush(cmd *cobra.Command, args []string) {
+	q, items, ok := loadQueue()
+	if !ok {
+		return
+	}
+	if len(items) == 0 {
+		fmt.Println(i18n.T("queue.empty"))
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fail(ExitConfig, i18n.T("config.load_failed"), err)
+		hint("%s", i18n.T("config.init_hint"))
+		return
+	}
+
+	ctx := cmd.Context()
+	var sent, failed int
+	for len(items) > 0 {
+		remaining, offline := flushQueue(ctx, cfg, q, items)
+		sent += len(items) - len(remaining)
+		items = remaining
+		if ctx.Err() != nil {
+			fail(ExitInterrupted, i18n.T("queue.interrupted"), len(items))
+			return
+		}
+		if !offline {
+			failed = len(items)
+			break
+		}
+		if !queueWait {
+			fail(ExitProvider, i18n.T("queue.offline"), len(items))
+			hint("%s", i18n.T("queue.wait_hint"))
+			return
+		}
+		ui.Warn(i18n.T("queue.retrying"), queueInterval, len(items))
+		select {
+		case <-time.After(queueInterval):
+		case <-ctx.Done():
+		}
+	}
+
+	if failed > 0 {
+		fail(ExitProvider, i18n.T("queue.partial"), sent, failed)
+		return
+	}
+	ui.Success(i18n.T("queue.flushed"), sent)
+}
+
+// flushQueue 依次发送请求，返回未发送成功的请求，网络不可用时停止发送并返回 offline 为 true
+func flushQueue(ctx context.Context, cfg *config.Config, q *queue.Queue, items []*queue.Item) ([]*queue.Item, bool) {
+	var remaining []*queue.Item
+	for i, it := range items {
+		if ctx.Err() != nil {
+			return append(remaining, items[i:]...), false
+		}
+		resp, err := sendQueued(ctx, cfg, it)
+		if err != nil {
+			if providers.IsNetworkUnreachable(err) {
+				return append(remaining, items[i:]...), true
+			}
+			if ctx.Err() != nil {
+				return append(remaining, items[i:]...), false
+			}
+			it.Attempts++
+			it.LastError = err.Error()
+			if saveErr := q.Save(it); saveErr != nil {
+				ui.Warn("%v", saveErr)
+			}
+			ui.Error("%s: %v", it.ID, err)
+			remaining = append(remaining, it)
+			continue
+		}
+
+		fmt.Printf("━━ %s · %s · %s\n", it.ID, it.Created.Format("2006-01-02 15:04"), it.Provider)
+		fmt.Printf("> %s\n\n", session.DefaultTitle(it.Prompt()))
+		fmt.Println(resp.Content)
+		fmt.Println()
+		if err := q.Remove(it.ID); err != nil {
+			ui.Warn(i18n.T("queue.remove_failed"), it.ID, err)
+		}
+	}
+	return remaining, false
+}
+
+// sendQueued 使用请求保存时的提供商、模型和采样参数发送请求，
+// 之前保存的请求没有记录温度时使用提供商配置的默认值
+func sendQueued(ctx context.Context, cfg *config.Config, it *queue.Item) (*providers.ChatResponse, error) {
+	providerCfg, exists := cfg.Providers[it.Provider]
+	if !exists && !providers.Standalone(it.Provider) {
+		return nil, fmt.Errorf(i18n.T("provider.not_configured"), it.Provider)
+	}
+	temperature := defaultTemperature(providerCfg)
+	if it.Temperature != nil {
+		temperature = *it.Temperature
+	}
+	provider := newProvider(envFrom(ctx), cfg, it.Provider, providerCfg)
+	return provider.Chat(ctx, &providers.ChatRequest{
+		Messages:        it.Messages,
+		Model:           it.Model,
+		Temperature:     temperature,
+		Sampling:        it.Sampling,
+		AssistantPrefix: it.AssistantPrefix,
+	})
+}
+
+// enqueueRequest 将网络不可用时未能发送的请求加入队列
+func enqueueRequest(provider string, req *providers.ChatRequest) (*queue.Item, error) {
+	q, err := queue.OpenDefault()
+	if err != nil {
+		return nil, err
+	}
+	temperature := req.Temperature
+	it := &queue.Item{
+		Provider:        provider,
+		Model:           req.Model,
+		Messages:        req.Messages,
+		Temperature:     &temperature,
+		Sampling:        req.Sampling,
+		AssistantPrefix: req.AssistantPrefix,
+	}
+	return it, q.Add(it)
+}
+
+func runQueueRemove(cmd *cobra.Command, args []string) {
+	q, err := queue.OpenDefault()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	for _, id := range args {
+		if err := q.Remove(id); err != nil {
+			if errors.Is(err, queue.ErrNotFound) {
+				fail(ExitUsage, i18n.T("queue.not_found"), id)
+			} else {
+				fail(ExitError, i18n.T("queue.delete_failed"), id, err)
+			}
+			continue
+		}
+		ui.Success(i18n.T("queue.deleted"), id)
+	}
+}
+
+// loadQueue 打开默认队列并读取所有请求
+func loadQueue() (*queue.Queue, []*queue.Item, bool) {
+	q, err := queue.OpenDefault()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return nil, nil, false
+	}
+	items, err := q.List()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return nil, nil, false
+	}
+	return q, items, true
+}
+
+func init() {
+	rootCmd.AddCommand(queueCmd)
+	queueCmd.AddCommand(queueListCmd)
+	queueCmd.AddCommand(queueFlushCmd)
+	queueCmd.AddCommand(queueRemoveCmd)
+
+	queueFlushCmd.Flags().BoolVar(&queueWait, "wait", false, "网络不可用时等待，恢复后自动发送")
+	queueFlushCmd.Flags().DurationVar(&queueInterval, "interval", 30*time.Second, "等待网络恢复时的重试间隔")
+}
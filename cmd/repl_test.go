@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+)
+
+// runREPL 以 input 作为标准输入运行交互模式，返回标准输出的内容
+func runREPL(t *testing.T, rt *chatRuntime, input string, history *[]Message) string {
+	t.Helper()
+	stdin, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdoutR, stdout, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdin, stdout
+	defer func() { os.Stdin, os.Stdout = oldStdin, oldStdout }()
+
+	go func() {
+		io.WriteString(stdinW, input)
+		stdinW.Close()
+	}()
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(stdoutR)
+		output <- string(data)
+	}()
+
+	rt.runInteractiveChatWithHistory(context.Background(), history)
+	rt.session.wait()
+	stdout.Close()
+	return <-output
+}
+
+func TestInteractiveChatWithMockProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		stream    bool
+		input     string
+		want      []string // 按顺序保存到会话中的消息
+		wantInOut []string
+	}{
+		{
+			name:      "问答后退出",
+			stream:    true,
+			input:     "你好\nquit\n",
+			want:      []string{"user:你好", "assistant:Mock: 你好"},
+			wantInOut: []string{"Mock: 你好"},
+		},
+		{
+			name:      "非流式请求",
+			input:     "你好\n",
+			want:      []string{"user:你好", "assistant:Mock: 你好"},
+			wantInOut: []string{"Mock: 你好"},
+		},
+		{
+			name:      "撤销上一轮问答",
+			stream:    true,
+			input:     "第一个问题\n第二个问题\n/undo\nhistory\nexit\n",
+			want:      []string{"user:第一个问题", "assistant:Mock: 第一个问题"},
+			wantInOut: []string{"Mock: 第一个问题", "Mock: 第二个问题"},
+		},
+		{
+			name:   "reset 后开始新的会话",
+			stream: true,
+			input:  "旧问题\nreset\n新问题\nquit\n",
+			want:   []string{"user:新问题", "assistant:Mock: 新问题"},
+		},
+		{
+			name:   "只有命令和空行",
+			stream: true,
+			input:  "\n   \nhelp\n/undo\nquit\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := session.NewStore(t.TempDir())
+			current := store.New(providers.MockName, "")
+			rt := &chatRuntime{
+				cfg:          &config.Config{},
+				providerName: providers.MockName,
+				provider:     providers.NewMockProvider(providers.MockName, providers.MockConfig{}),
+				session:      &chatSessionState{store: store, current: current},
+				historyTurns: -1,
+				stream:       tt.stream,
+				interactive:  true,
+			}
+
+			var history []Message
+			out := runREPL(t, rt, tt.input, &history)
+			for _, s := range tt.wantInOut {
+				if !strings.Contains(out, s) {
+					t.Errorf("输出中没有 %q:\n%s", s, out)
+				}
+			}
+
+			var got []string
+			for _, m := range history {
+				got = append(got, m.Role+":"+m.Content)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("对话历史为 %q，期望 %q", got, tt.want)
+			}
+
+			// reset 后问答保存到新的会话
+			saved, err := store.Get(rt.session.current.ID)
+			if len(tt.want) == 0 {
+				// 没有问答时不保存会话
+				if err == nil && len(saved.Messages) > 0 {
+					t.Errorf("会话中有 %d 条消息，期望没有", len(saved.Messages))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("读取会话失败: %v", err)
+			}
+			got = got[:0]
+			for _, m := range saved.Messages {
+				got = append(got, m.Role+":"+m.Content)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("保存的会话为 %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
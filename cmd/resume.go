@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// defaultStreamResumes 未配置 advanced.stream_resumes 时，一次回复最多续写的次数
+const defaultStreamResumes = 2
+
+// resumeInstruction 续写请求附加的系统指令，已收到的内容作为未完成的助手消息放在其后
+const resumeInstruction = "Your previous response was cut off by a network error. The assistant message that follows is what you had " +
+	"written so far. Continue exactly where it stops, mid-word or mid-line if necessary, without repeating any of it and without any preamble."
+
+// 续写内容开头与已收到内容结尾重复时去掉重复的部分：最多检查 maxResumeOverlap 字节，
+// 至少重复 minResumeOverlap 字节才去掉，避免误删本来就相同的短词
+const (
+	maxResumeOverlap = 200
+	minResumeOverlap = 16
+)
+
+// resumingProvider 流式回复在完成前中断时（连接被重置、网关超时等），以已收到的内容作为助手回复的开头
+// 发送续写请求，并将续写的内容接在后面，调用方收到的是一个完整的回复。续写请求在 logging.requests 的请求日志中可见。
+// 只包装 ChatStream：chat 的回答和 /continue 都以流式请求接收，非流式请求中断时没有可以续写的内容
+type resumingProvider struct {
+	providers.Provider
+	attempts int
+}
+
+// newResumingProvider 创建中断后自动续写的提供商，attempts 为一次回复最多续写的次数，0时使用默认值，小于0时不续写
+func newResumingProvider(p providers.Provider, attempts int) providers.Provider {
+	if attempts < 0 {
+		return p
+	}
+	if attempts == 0 {
+		attempts = defaultStreamResumes
+	}
+	return &resumingProvider{Provider: p, attempts: attempts}
+}
+
+// Unwrap 返回被包装的提供商
+func (p *resumingProvider) Unwrap() providers.Provider {
+	return p.Provider
+}
+
+// ChatStream 发送流式对话请求，中断时续写并拼接回复
+func (p *resumingProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	chunks, err := p.Provider.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan providers.StreamChunk)
+	go func() {
+		defer close(out)
+		var reply strings.Builder
+		skip, resumed := 0, false
+		for resumes := 0; ; resumes++ {
+			done, streamErr := relayStream(ctx, chunks, out, &reply, skip, resumed)
+			if streamErr == nil {
+				sendChunk(ctx, out, done)
+				return
+			}
+			if resumes >= p.attempts || ctx.Err() != nil || !providers.IsStreamInterrupted(streamErr) {
+				sendChunk(ctx, out, providers.StreamChunk{Error: streamErr})
+				return
+			}
+
+			next := req
+			if reply.Len() > 0 {
+				next = resumeRequest(req, reply.String(), resumeInstruction)
+			}
+			if chunks, err = p.Provider.ChatStream(ctx, next); err != nil {
+				sendChunk(ctx, out, providers.StreamChunk{Error: err})
+				return
+			}
+			skip, resumed = len(next.AssistantPrefix), next != req
+		}
+	}()
+	return out, nil
+}
+
+// resumeRequest 续写请求：附加续写指令，已收到的内容（包含原来的 AssistantPrefix）作为助手回复的开头
+func resumeRequest(req *providers.ChatRequest, partial, instruction string) *providers.ChatRequest {
+	resumed := *req
+	resumed.Messages = append(append([]providers.Message{}, req.Messages...), providers.Message{Role: "system", Content: instruction})
+	resumed.AssistantPrefix = partial
+	return &resumed
+}
+
+// relayStream 将内容数据块转发给调用方，并把转发的内容追加到 reply。回复正常结束时返回不带内容的
+// Done 数据块（用量和结束原因）由调用方决定是否转发，否则返回错误。提供商返回的内容以 AssistantPrefix 开头，
+// 续写时跳过开头已收到的 skip 字节；resumed 为true时先缓存续写内容的开头，去掉与 reply 结尾重复的部分后再转发
+func relayStream(ctx context.Context, chunks <-chan providers.StreamChunk, out chan<- providers.StreamChunk,
+	reply *strings.Builder, skip int, resumed bool) (providers.StreamChunk, error) {
+	var pending strings.Builder
+	forward := func(content string) bool {
+		if content == "" {
+			return true
+		}
+		reply.WriteString(content)
+		return sendChunk(ctx, out, providers.StreamChunk{Content: content})
+	}
+	flush := func() bool {
+		if !resumed {
+			return true
+		}
+		resumed = false
+		return forward(trimOverlap(reply.String(), pending.String()))
+	}
+
+	for chunk := range chunks {
+		content := chunk.Content
+		if skip > 0 {
+			n := min(skip, len(content))
+			content, skip = content[n:], skip-n
+		}
+
+		ok := true
+		switch {
+		case chunk.Error != nil:
+			flush()
+			go drain(chunks)
+			return providers.StreamChunk{}, chunk.Error
+		case resumed:
+			pending.WriteString(content)
+			if pending.Len() >= maxResumeOverlap {
+				ok = flush()
+			}
+		default:
+			ok = forward(content)
+		}
+		if ok && chunk.Done {
+			if flush() {
+				go drain(chunks)
+				return providers.StreamChunk{Done: true, Usage: chunk.Usage, FinishReason: chunk.FinishReason}, nil
+			}
+			ok = false
+		}
+		if !ok {
+			go drain(chunks)
+			return providers.StreamChunk{}, ctx.Err()
+		}
+	}
+	if !flush() {
+		return providers.StreamChunk{}, ctx.Err()
+	}
+	return providers.StreamChunk{Done: true}, nil
+}
+
+// trimOverlap 去掉续写内容开头与已收到内容结尾重复的部分，模型有时会从中断前的几个词重新写起
+func trimOverlap(prev, next string) string {
+	for n := min(len(prev), len(next), maxResumeOverlap); n >= minResumeOverlap; n-- {
+		if strings.HasSuffix(prev, next[:n]) {
+			return next[n:]
+		}
+	}
+	return next
+}
+
+// sendChunk 向调用方发送数据块，调用方已取消时返回false
+func sendChunk(ctx context.Context, out chan<- providers.StreamChunk, chunk providers.StreamChunk) bool {
+	select {
+	case out <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// drain 读完剩余的数据块，使提供商的goroutine可以退出
+func drain(chunks <-chan providers.StreamChunk) {
+	for range chunks {
+	}
+}
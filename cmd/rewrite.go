@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"ai-chat-cli/internal/diff"
+	"ai-chat-cli/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rewriteProvider string
+	rewriteFile     string
+	rewriteYes      bool
+)
+
+// rewriteCmd represents the rewrite command
+var rewriteCmd = &cobra.Command{
+	Use:   "rewrite --file <文件> <修改要求>",
+	Short: "按要求修改文件并预览差异",
+	Long: `让AI按照要求修改文件，显示修改前后的差异，确认后才写回文件。
+
+示例:
+  ai-chat-cli rewrite --file handler.go "改为使用context并为错误添加包装"
+  ai-chat-cli rewrite --file README.md --yes "补充安装章节"`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runRewrite,
+}
+
+func runRewrite(cmd *cobra.Command, args []string) {
+	if rewriteFile == "" {
+		fail(ExitUsage, "请使用 --file 指定要修改的文件")
+		return
+	}
+
+	data, err := os.ReadFile(rewriteFile)
+	if err != nil {
+		fail(ExitError, "读取文件失败: %v", err)
+		return
+	}
+	original := string(data)
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), rewriteProvider)
+	if !ok {
+		return
+	}
+
+	language := detectCodeLanguage(rewriteFile)
+	system := fmt.Sprintf(`You are an expert software engineer editing a %s file.
+Apply the user's instruction to the file and return the COMPLETE modified file.
+Rules:
+- Output only the full file content, without explanations and without wrapping it in a Markdown code fence.
+- Change only what the instruction requires; keep formatting, comments and unrelated code intact.`, language)
+	prompt := fmt.Sprintf("Instruction: %s\n\nFile %s:\n%s", strings.Join(args, " "), rewriteFile, original)
+
+	fmt.Println(ui.Status(fmt.Sprintf("✏️  正在修改 %s ...", rewriteFile)))
+	resp, err := complete(cmd.Context(), provider, system, prompt, 0.2)
+	if err != nil {
+		fail(errorExitCode(err), "修改失败: %v", err)
+		return
+	}
+
+	result := stripOuterFence(resp.Content)
+	if strings.HasSuffix(original, "\n") && !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+
+	unified := diff.Unified(rewriteFile, rewriteFile+" (修改后)", original, result, 3)
+	if unified == "" {
+		ui.Success("文件没有变化")
+		return
+	}
+	printColoredDiff(unified)
+	fmt.Println()
+
+	if !rewriteYes && !confirm("💾 应用以上修改?") {
+		fmt.Println("已取消，文件未修改")
+		return
+	}
+
+	if err := os.WriteFile(rewriteFile, []byte(result), 0644); err != nil {
+		fail(ExitError, "写入文件失败: %v", err)
+		return
+	}
+	ui.Success("已写入 %s", rewriteFile)
+}
+
+// stripOuterFence 去掉包裹整个回复的Markdown代码块围栏，内部的代码块保持不变
+func stripOuterFence(reply string) string {
+	text := strings.TrimSpace(reply)
+	if !strings.HasPrefix(text, "```") || !strings.HasSuffix(text, "```") {
+		return reply
+	}
+
+	firstNewline := strings.Index(text, "\n")
+	lastNewline := strings.LastIndex(text, "\n")
+	if firstNewline < 0 || lastNewline <= firstNewline {
+		return reply
+	}
+	return text[firstNewline+1 : lastNewline+1]
+}
+
+func init() {
+	rootCmd.AddCommand(rewriteCmd)
+
+	rewriteCmd.Flags().StringVarP(&rewriteProvider, "provider", "p", "", "指定AI提供商")
+	rewriteCmd.Flags().StringVarP(&rewriteFile, "file", "f", "", "要修改的文件")
+	rewriteCmd.Flags().BoolVarP(&rewriteYes, "yes", "y", false, "不确认直接写回文件")
+}
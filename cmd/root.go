@@ -1,14 +1,26 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"os"
+	"os/signal"
+	"syscall"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/recorder"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-var cfgFile string
+var (
+	cfgFile string
+	noColor bool
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -29,31 +41,109 @@ var rootCmd = &cobra.Command{
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRun: setupProviderEnv,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	// Ctrl+C 或 SIGTERM 时取消命令的上下文，由命令结束进行中的请求、保存结果后退出；
+	// 取消后恢复默认的信号处理，再次按 Ctrl+C 立即退出
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
-		os.Exit(1)
+		// cobra 返回的错误都是命令或参数用法错误
+		os.Exit(ExitUsage)
 	}
+	printUpdateHint()
+	exit()
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initConfig, initOutput)
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "配置文件路径 (默认在 $HOME/.ai-chat-cli/config.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "关闭彩色输出（也可以设置 NO_COLOR 环境变量）")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "只打印将要发送的请求和token估算，不调用API")
+	rootCmd.PersistentFlags().String("record", "", "将与提供商的请求和响应录制到指定目录")
+	rootCmd.PersistentFlags().String("replay", "", "从指定目录回放录制的响应，不发送网络请求")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.PersistentFlags().StringToString("metadata", nil, "随请求发送的 metadata（key=value，可多次指定），与提供商配置的 metadata 合并")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
+// initOutput 根据 --no-color、NO_COLOR 和终端检测设置输出方式，并使用 ui.theme 设置的主题
+func initOutput() {
+	ui.Setup(noColor)
+	if err := ui.SetTheme(viper.GetString("ui.theme"), viper.GetStringMapString("ui.symbols"), viper.GetStringMapString("ui.colors")); err != nil {
+		ui.Warn(i18n.T("ui.theme_invalid"), err)
+	}
+	i18n.SetDecorator(ui.Status)
+}
+
+// setupProviderEnv 根据 --dry-run、--record、--replay、--metadata 和 logging.requests 生成提供商参数，
+// 放入命令的上下文中供子命令创建提供商时使用，并在需要时检查新版本
+func setupProviderEnv(cmd *cobra.Command, args []string) {
+	env, err := newProviderEnv(cmd)
+	if err != nil {
+		fail(ExitUsage, "%v", err)
+		exit()
+	}
+	cmd.SetContext(withProviderEnv(cmd.Context(), env))
+
+	replaying := cmd.Flags().Changed("replay")
+	initUpdateCheck(env.dryRun || replaying)
+}
+
+// newProviderEnv 读取全局参数：--record 或 --replay 设置传输层，--metadata 需要符合API的限制，
+// 配置了 logging.requests 时记录发送给提供商的每个HTTP请求（方法、地址、状态码和耗时）
+func newProviderEnv(cmd *cobra.Command) (*providerEnv, error) {
+	flags := cmd.Flags()
+	env := &providerEnv{}
+	var err error
+	if env.dryRun, err = flags.GetBool("dry-run"); err != nil {
+		return nil, err
+	}
+	if env.metadata, err = flags.GetStringToString("metadata"); err != nil {
+		return nil, err
+	}
+	if len(env.metadata) == 0 {
+		env.metadata = nil
+	}
+	if err := providers.ValidateMetadata(env.metadata); err != nil {
+		return nil, err
+	}
+
+	recordDir, _ := flags.GetString("record")
+	replayDir, _ := flags.GetString("replay")
+	switch {
+	case recordDir != "":
+		env.transport, err = recorder.NewRecorder(recordDir, nil)
+	case replayDir != "":
+		env.transport, err = recorder.NewReplayer(replayDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if viper.GetBool("logging.requests") {
+		env.middlewares = append(env.middlewares, providers.Logging(log.Printf))
+	}
+	return env, nil
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if cfgFile != "" {
@@ -75,6 +165,9 @@ func initConfig() {
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "使用配置文件:", viper.ConfigFileUsed())
+		i18n.Setup(viper.GetString("ui.language"))
+		fmt.Fprintln(os.Stderr, i18n.T("config.using", viper.ConfigFileUsed()))
+	} else {
+		i18n.Setup("")
 	}
 }
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/router"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+)
+
+// chat --route 的取值
+const (
+	routeAuto = "auto"
+	routeOff  = "off"
+)
+
+// 判断复杂度的方式
+const (
+	classifierHeuristic = "heuristic"
+	classifierModel     = "model"
+)
+
+// modelRouter 按问题复杂度在快速模型和推理模型之间选择
+type modelRouter struct {
+	provider   providers.Provider
+	fast       string
+	smart      string
+	classifier string
+	mode       string // auto、fast、smart 或 off
+}
+
+// newModelRouter 根据 routing 配置和 --route 创建路由，mode 为空时按 routing.enabled 决定是否自动选择
+func newModelRouter(provider providers.Provider, cfg config.RoutingConfig, mode string) (*modelRouter, error) {
+	if mode == "" {
+		mode = routeOff
+		if cfg.Enabled {
+			mode = routeAuto
+		}
+	}
+	switch mode {
+	case routeAuto, routeOff, router.TierFast, router.TierSmart:
+	default:
+		return nil, fmt.Errorf(i18n.T("chat.route_invalid"), mode)
+	}
+	switch cfg.Classifier {
+	case "", classifierHeuristic, classifierModel:
+	default:
+		ui.Warn(i18n.T("chat.route_classifier_invalid"), cfg.Classifier)
+	}
+	return &modelRouter{provider: provider, fast: cfg.Fast, smart: cfg.Smart, classifier: cfg.Classifier, mode: mode}, nil
+}
+
+// parseRouteOverride 解析交互模式中的 /fast <问题> 和 /smart <问题>
+func parseRouteOverride(input string) (tier, question string, ok bool) {
+	for _, t := range []string{router.TierFast, router.TierSmart} {
+		if rest, found := strings.CutPrefix(input, "/"+t+" "); found && strings.TrimSpace(rest) != "" {
+			return t, strings.TrimSpace(rest), true
+		}
+	}
+	return "", "", false
+}
+
+// model 选择回答问题使用的模型，override 为本条消息指定的档位。
+// 返回空字符串时使用提供商配置的默认模型
+func (r *modelRouter) model(ctx context.Context, question, override string) string {
+	if r == nil {
+		return ""
+	}
+	mode := r.mode
+	if override != "" {
+		mode = override
+	}
+
+	var d router.Decision
+	switch mode {
+	case routeOff:
+		return ""
+	case router.TierFast, router.TierSmart:
+		d = router.Decision{Tier: mode, Reason: router.ReasonOverride}
+	default:
+		d = r.classify(ctx, question)
+	}
+
+	model := r.fast
+	if d.Tier == router.TierSmart {
+		model = r.smart
+	}
+	if model == "" {
+		ui.Warn(i18n.T("chat.route_unconfigured"), d.Tier)
+		return ""
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("chat.route", d.Tier, model, i18n.T("chat.route_reason_"+d.Reason)))
+	return model
+}
+
+// classify 判断问题的复杂度，分类模型失败时改用启发式规则
+func (r *modelRouter) classify(ctx context.Context, question string) router.Decision {
+	if r.classifier != classifierModel || envFrom(ctx).dryRun {
+		return router.Classify(question)
+	}
+	d, err := router.ClassifyWithModel(ctx, r.provider, r.fast, question)
+	if err != nil {
+		ui.Warn(i18n.T("chat.route_classifier_failed"), err)
+		return router.Classify(question)
+	}
+	return d
+}
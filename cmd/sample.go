@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+)
+
+// maxSamples --n 允许的最大回答数
+const maxSamples = 10
+
+// judgePrompt 请评审模型从多个候选回答中选出最好的一个，%s 依次为问题和编号的候选回答
+const judgePrompt = `You are judging candidate answers to the same request. Pick the single best one: correctness first, ` +
+	`then completeness and clarity; for code, whether it would work as written.
+
+Request:
+%s
+
+%sReply with only the number of the best candidate.`
+
+// judgeNumber 评审回复中的候选编号
+var judgeNumber = regexp.MustCompile(`\d+`)
+
+// parseBestOf 解析 --best-of 的参数 judge=<模型>，返回评审模型
+func parseBestOf(spec string) (string, error) {
+	key, model, ok := strings.Cut(spec, "=")
+	model = strings.TrimSpace(model)
+	if !ok || strings.TrimSpace(key) != "judge" || model == "" {
+		return "", i18n.Error("chat.best_of_invalid")
+	}
+	return model, nil
+}
+
+// bestOf 获取 rt.samples 个回答。指定了评审模型时返回评审选出的回答，否则（或评审失败时）返回第一个回答，
+// 同时返回所有回答供显示。返回的回答的用量为所有请求（包括评审）之和
+func (rt *chatRuntime) bestOf(ctx context.Context, question string, req *providers.ChatRequest) (*providers.ChatResponse, []*providers.ChatResponse, error) {
+	answers, err := sampleAnswers(ctx, rt.provider, req, rt.samples, rt.cfg.Providers[rt.providerName].Compat.MultipleChoices)
+	if err != nil {
+		return nil, nil, err
+	}
+	total := providers.Usage{}
+	for _, a := range answers {
+		total = addUsage(total, a.Usage)
+	}
+
+	chosen := *answers[0]
+	if rt.judge == "" || len(answers) < 2 {
+		chosen.Usage = total
+		return &chosen, answers, nil
+	}
+
+	i, usage, err := judgeAnswers(ctx, rt.provider, rt.judge, question, answers)
+	total = addUsage(total, usage)
+	if err != nil {
+		ui.Warn(i18n.T("chat.judge_failed"), err)
+		chosen.Usage = total
+		return &chosen, answers, nil
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("chat.judge_chose", rt.judge, i+1, len(answers)))
+	chosen = *answers[i]
+	chosen.Usage = total
+	return &chosen, nil, nil
+}
+
+// sampleAnswers 获取 n 个回答：服务支持 n 参数（compat.multiple_choices）时一次请求返回多个回答，
+// 返回的回答不足时（服务不支持或忽略 n）并发发送其余的请求。部分请求失败时只提示，全部失败时返回错误。
+// 这些请求完全相同，不使用回复缓存，否则会得到同一个缓存的回答
+func sampleAnswers(ctx context.Context, provider providers.Provider, req *providers.ChatRequest, n int, native bool) ([]*providers.ChatResponse, error) {
+	ctx = withoutCache(ctx)
+	var answers []*providers.ChatResponse
+	if native {
+		multi := *req
+		multi.N = n
+		resp, err := provider.Chat(ctx, &multi)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, resp)
+		for _, alt := range resp.Alternatives {
+			answers = append(answers, &providers.ChatResponse{Content: alt.Content, Model: resp.Model, FinishReason: alt.FinishReason})
+		}
+		resp.Alternatives = nil
+		if len(answers) > n {
+			answers = answers[:n]
+		}
+	}
+
+	missing := n - len(answers)
+	results := make([]*providers.ChatResponse, missing)
+	errs := make([]error, missing)
+	var wg sync.WaitGroup
+	for i := 0; i < missing; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = provider.Chat(ctx, req)
+		}(i)
+	}
+	wg.Wait()
+
+	var firstErr error
+	failed := 0
+	for i, resp := range results {
+		if errs[i] != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		answers = append(answers, resp)
+	}
+	if len(answers) == 0 {
+		return nil, firstErr
+	}
+	if failed > 0 {
+		ui.Warn(i18n.T("chat.samples_failed"), failed, n, firstErr)
+	}
+	return answers, nil
+}
+
+// judgeAnswers 请评审模型选出最好的回答，返回其下标和评审请求的用量
+func judgeAnswers(ctx context.Context, provider providers.Provider, model, question string, answers []*providers.ChatResponse) (int, providers.Usage, error) {
+	var candidates strings.Builder
+	for i, a := range answers {
+		fmt.Fprintf(&candidates, "Candidate %d:\n%s\n\n", i+1, a.Content)
+	}
+	resp, err := provider.Chat(ctx, &providers.ChatRequest{
+		Messages: []providers.Message{{Role: "user", Content: fmt.Sprintf(judgePrompt, question, candidates.String())}},
+		Model:    model,
+	})
+	if err != nil {
+		return 0, providers.Usage{}, err
+	}
+
+	n, err := strconv.Atoi(judgeNumber.FindString(resp.Content))
+	if err != nil || n < 1 || n > len(answers) {
+		return 0, resp.Usage, fmt.Errorf(i18n.T("chat.judge_unparsed"), strings.TrimSpace(resp.Content))
+	}
+	return n - 1, resp.Usage, nil
+}
+
+// formatSamples 将多个回答按编号依次排列，用于显示所有回答
+func formatSamples(answers []*providers.ChatResponse) string {
+	parts := make([]string, len(answers))
+	for i, a := range answers {
+		parts[i] = fmt.Sprintf("### %s\n\n%s", i18n.T("chat.sample_heading", i+1, len(answers)), a.Content)
+	}
+	return strings.Join(parts, "\n\n---\n\n")
+}
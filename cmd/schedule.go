@@ -0,0 +1,434 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-chat-cli/internal/cron"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/schedule"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/session"
+	"ai-chat-cli/pkg/template"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleTemplate string
+	scheduleOutput   string
+	scheduleProvider string
+	scheduleVars     []string
+)
+
+// scheduleMu 保护守护进程中对任务文件的并发读写
+var scheduleMu sync.Mutex
+
+// scheduleCmd 定时任务管理
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "按cron表达式定时执行提示词",
+	Long: `管理定时执行的提示词任务，适合每日摘要、周报等场景。
+
+任务保存在 ~/.ai-chat-cli/schedules.json 中，由 schedule run 启动的守护进程按cron表达式执行，
+结果写入指定的目录或文件。
+
+cron表达式使用标准的5个字段：分 时 日 月 周，也支持 @hourly、@daily、@weekly、@monthly。
+
+模板中除 --var 指定的变量外，还可以使用内置变量：
+  {{.date}}     执行日期，如 2024-01-02
+  {{.time}}     执行时间，如 09:00
+  {{.weekday}}  星期，如 星期二
+
+示例:
+  ai-chat-cli schedule add "0 9 * * *" --template daily-summary --output ~/notes/
+  ai-chat-cli schedule add "0 18 * * 5" "总结本周Go社区的新闻" -o ~/notes/weekly.md
+  ai-chat-cli schedule list
+  ai-chat-cli schedule run`,
+}
+
+// scheduleAddCmd 添加定时任务
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <cron表达式> [提示词]",
+	Short: "添加定时任务",
+	Long: `添加定时任务，使用 --template 指定提示词模板或直接给出提示词。
+
+--output 以 / 结尾或为已存在的目录时，每次执行都会在目录中新建文件，
+如 daily-summary-2024-01-02-0900.md；否则结果追加到该文件末尾。
+不指定 --output 时结果打印到守护进程的标准输出。`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  runScheduleAdd,
+}
+
+// scheduleListCmd 列出定时任务
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出定时任务",
+	Run:   runScheduleList,
+}
+
+// scheduleRemoveCmd 删除定时任务
+var scheduleRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "删除定时任务",
+	Args:  cobra.ExactArgs(1),
+	Run:   runScheduleRemove,
+}
+
+// scheduleRunCmd 运行定时任务守护进程
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run [id...]",
+	Short: "启动守护进程按计划执行任务",
+	Long: `在前台启动守护进程，每分钟检查一次任务，执行到期的任务。
+守护进程每次检查时都会重新读取任务文件，添加或删除任务后无需重启。
+
+指定任务ID时立即执行这些任务一次后退出，便于测试。
+
+示例:
+  ai-chat-cli schedule run
+  nohup ai-chat-cli schedule run >> ~/.ai-chat-cli/schedule.log 2>&1 &
+  ai-chat-cli schedule run 1a2b3c4d`,
+	Run: runScheduleRun,
+}
+
+func runScheduleAdd(cmd *cobra.Command, args []string) {
+	expr := args[0]
+	if _, err := cron.Parse(expr); err != nil {
+		fail(ExitUsage, "%v", err)
+		return
+	}
+
+	job := &schedule.Job{
+		ID:       schedule.NewID(),
+		Cron:     expr,
+		Template: scheduleTemplate,
+		Output:   scheduleOutput,
+		Provider: scheduleProvider,
+	}
+	if len(args) > 1 {
+		job.Prompt = args[1]
+	}
+	if job.Template == "" && strings.TrimSpace(job.Prompt) == "" {
+		fail(ExitUsage, "%s", i18n.T("schedule.prompt_required"))
+		return
+	}
+	if job.Template != "" {
+		if _, err := template.Load(job.Template); err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+	}
+
+	if len(scheduleVars) > 0 {
+		job.Vars = map[string]string{}
+		for _, kv := range scheduleVars {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok || key == "" {
+				fail(ExitUsage, i18n.T("schedule.var_invalid"), kv)
+				return
+			}
+			job.Vars[key] = value
+		}
+	}
+
+	if job.Output != "" {
+		output, err := expandHome(job.Output)
+		if err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+		// 保留结尾的 /，用于区分目录和文件
+		if strings.HasSuffix(job.Output, "/") && !strings.HasSuffix(output, "/") {
+			output += "/"
+		}
+		job.Output = output
+	}
+
+	path, jobs, ok := loadScheduleJobs()
+	if !ok {
+		return
+	}
+	jobs = append(jobs, job)
+	if err := schedule.Save(path, jobs); err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	ui.Success(i18n.T("schedule.added"), job.ID)
+	if s, err := cron.Parse(job.Cron); err == nil {
+		fmt.Println(i18n.T("schedule.next", s.Next(time.Now()).Format("2006-01-02 15:04")))
+	}
+	hint("%s", i18n.T("schedule.run_hint"))
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) {
+	_, jobs, ok := loadScheduleJobs()
+	if !ok {
+		return
+	}
+	if len(jobs) == 0 {
+		fmt.Println(i18n.T("schedule.none"))
+		hint("%s", i18n.T("schedule.add_hint"))
+		return
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		what := job.Prompt
+		if job.Template != "" {
+			what = i18n.T("schedule.template", job.Template)
+		}
+		fmt.Printf("%s  %-15s %s\n", job.ID, job.Cron, session.DefaultTitle(what))
+
+		next := i18n.T("schedule.cron_invalid")
+		if s, err := cron.Parse(job.Cron); err == nil {
+			next = s.Next(now).Format("2006-01-02 15:04")
+		}
+		output := job.Output
+		if output == "" {
+			output = i18n.T("schedule.stdout")
+		}
+		fmt.Printf("          %s\n", i18n.T("schedule.next_output", next, output))
+		if !job.LastRun.IsZero() {
+			status := i18n.T("schedule.succeeded")
+			if job.LastErr != "" {
+				status = i18n.T("schedule.failed_status", job.LastErr)
+			}
+			fmt.Printf("          %s\n", i18n.T("schedule.last_run", job.LastRun.Format("2006-01-02 15:04"), status))
+		}
+	}
+}
+
+func runScheduleRemove(cmd *cobra.Command, args []string) {
+	path, jobs, ok := loadScheduleJobs()
+	if !ok {
+		return
+	}
+
+	for i, job := range jobs {
+		if job.ID == args[0] {
+			jobs = append(jobs[:i], jobs[i+1:]...)
+			if err := schedule.Save(path, jobs); err != nil {
+				fail(ExitError, "%v", err)
+				return
+			}
+			ui.Success(i18n.T("schedule.removed"), args[0])
+			return
+		}
+	}
+	fail(ExitError, i18n.T("schedule.not_found"), args[0])
+}
+
+func runScheduleRun(cmd *cobra.Command, args []string) {
+	if len(args) > 0 {
+		_, jobs, ok := loadScheduleJobs()
+		if !ok {
+			return
+		}
+		for _, id := range args {
+			job, err := schedule.Find(jobs, id)
+			if err != nil {
+				fail(ExitError, i18n.T("schedule.not_found"), id)
+				continue
+			}
+			runScheduledJob(cmd.Context(), job, time.Now())
+		}
+		return
+	}
+
+	ctx := cmd.Context()
+	fmt.Println(i18n.T("schedule.daemon_started"))
+
+	var wg sync.WaitGroup
+	for {
+		// 在每分钟开始时检查
+		now := time.Now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			fmt.Println(i18n.T("schedule.daemon_stopped"))
+			return
+		case <-time.After(next.Sub(now)):
+		}
+
+		_, jobs, ok := loadScheduleJobs()
+		if !ok {
+			continue
+		}
+		for _, job := range jobs {
+			s, err := cron.Parse(job.Cron)
+			if err != nil {
+				ui.Warn(i18n.T("schedule.job_invalid"), job.ID, err)
+				continue
+			}
+			if !s.Matches(next) {
+				continue
+			}
+			wg.Add(1)
+			go func(job *schedule.Job) {
+				defer wg.Done()
+				runScheduledJob(ctx, job, next)
+			}(job)
+		}
+	}
+}
+
+// runScheduledJob 执行一次定时任务并记录执行结果
+func runScheduledJob(ctx context.Context, job *schedule.Job, at time.Time) {
+	fmt.Println(i18n.T("schedule.running", at.Format("2006-01-02 15:04"), job.ID))
+
+	output, err := executeScheduledJob(ctx, job, at)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] %s\n", time.Now().Format("2006-01-02 15:04"), ui.Styled(ui.ElemError, i18n.T("schedule.job_failed", job.ID, err)))
+	} else if output != "" {
+		fmt.Printf("[%s] %s\n", time.Now().Format("2006-01-02 15:04"), ui.Styled(ui.ElemSuccess, i18n.T("schedule.job_done", job.ID, output)))
+	}
+
+	// 执行期间任务文件可能被修改，重新读取后只更新执行记录
+	scheduleMu.Lock()
+	defer scheduleMu.Unlock()
+
+	path, jobs, ok := loadScheduleJobs()
+	if !ok {
+		return
+	}
+	current, findErr := schedule.Find(jobs, job.ID)
+	if findErr != nil {
+		return
+	}
+	current.LastRun = at
+	current.LastErr = ""
+	if err != nil {
+		current.LastErr = err.Error()
+	}
+	if err := schedule.Save(path, jobs); err != nil {
+		ui.Warn(i18n.T("schedule.record_failed"), err)
+	}
+}
+
+// executeScheduledJob 发送任务的提示词并写入结果，返回结果文件路径
+func executeScheduledJob(ctx context.Context, job *schedule.Job, at time.Time) (string, error) {
+	var tmpl *template.Template
+	if job.Template != "" {
+		var err error
+		if tmpl, err = template.Load(job.Template); err != nil {
+			return "", err
+		}
+	}
+
+	vars := map[string]interface{}{
+		"date":    at.Format("2006-01-02"),
+		"time":    at.Format("15:04"),
+		"weekday": weekdayNames[at.Weekday()],
+	}
+	for k, v := range job.Vars {
+		vars[k] = v
+	}
+	if job.Prompt != "" {
+		vars["prompt"] = job.Prompt
+	}
+
+	provider, ok := loadProvider(envFrom(ctx), job.Provider)
+	if !ok {
+		return "", errors.New(i18n.T("schedule.provider_failed"))
+	}
+
+	req, err := buildBatchRequest(tmpl, BatchJob{ID: job.ID, Vars: vars}, providerTemperature(provider.GetName()))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := provider.Chat(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return writeScheduleOutput(job, at, resp.Content)
+}
+
+// writeScheduleOutput 写入任务结果。输出为目录时每次新建文件，为文件时追加到末尾
+func writeScheduleOutput(job *schedule.Job, at time.Time, content string) (string, error) {
+	if job.Output == "" {
+		fmt.Printf("\n%s\n\n", content)
+		return "", nil
+	}
+
+	output := job.Output
+	info, err := os.Stat(output)
+	isDir := strings.HasSuffix(output, "/") || (err == nil && info.IsDir())
+
+	if isDir {
+		if err := os.MkdirAll(output, 0755); err != nil {
+			return "", fmt.Errorf(i18n.T("schedule.mkdir_failed"), err)
+		}
+		output = filepath.Join(output, fmt.Sprintf("%s-%s.md", job.Name(), at.Format("2006-01-02-1504")))
+		if err := os.WriteFile(output, []byte(content+"\n"), 0644); err != nil {
+			return "", fmt.Errorf(i18n.T("schedule.write_failed"), err)
+		}
+		return output, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return "", fmt.Errorf(i18n.T("schedule.mkdir_failed"), err)
+	}
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("schedule.write_failed"), err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "## %s\n\n%s\n\n", at.Format("2006-01-02 15:04"), content); err != nil {
+		return "", fmt.Errorf(i18n.T("schedule.write_failed"), err)
+	}
+	return output, nil
+}
+
+// loadScheduleJobs 读取任务文件，失败时打印提示信息并返回false
+func loadScheduleJobs() (string, []*schedule.Job, bool) {
+	path, err := schedule.DefaultPath()
+	if err != nil {
+		fail(ExitError, i18n.T("schedule.path_failed"), err)
+		return "", nil, false
+	}
+	jobs, err := schedule.Load(path)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return "", nil, false
+	}
+	return path, jobs, true
+}
+
+// expandHome 将路径开头的 ~ 展开为用户主目录
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("path.home_failed"), err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// weekdayNames 星期的中文名称
+var weekdayNames = [...]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+
+	scheduleAddCmd.Flags().StringVarP(&scheduleTemplate, "template", "t", "", "提示词模板名称或文件路径")
+	scheduleAddCmd.Flags().StringVarP(&scheduleOutput, "output", "o", "", "结果输出目录或文件")
+	scheduleAddCmd.Flags().StringVarP(&scheduleProvider, "provider", "p", "", "指定AI提供商")
+	scheduleAddCmd.Flags().StringArrayVar(&scheduleVars, "var", nil, "模板变量，格式 key=value，可重复使用")
+}
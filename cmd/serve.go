@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/logfile"
+	"ai-chat-cli/internal/server"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	serveHost     string
+	servePort     int
+	serveProvider string
+
+	// serveMerged 与同时进行的相同请求合并的请求数，重新加载配置后继续累计
+	serveMerged atomic.Int64
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "启动本地OpenAI兼容网关",
+	Long: `启动一个本地HTTP服务，提供OpenAI兼容的 /v1/chat/completions（含流式）和 /v1/models 接口，
+请求会转发到配置文件中的提供商，任何OpenAI客户端都可以直接接入。
+浏览器访问 http://127.0.0.1:8080/ 即可使用内置的网页聊天界面。
+
+模型路由规则:
+• "<提供商>/<模型>"：使用指定提供商的指定模型，如 free-oai/gpt-4.1-nano
+• "<提供商>"：使用指定提供商的默认模型
+• 其他模型名：使用默认提供商（--provider 或 default.provider）
+
+会话接口（与CLI共用 ~/.ai-chat-cli/sessions 中的会话）:
+  GET    /v1/sessions                 列出会话
+  POST   /v1/sessions                 创建会话 {"title", "model"}
+  GET    /v1/sessions/{id}            获取会话内容
+  DELETE /v1/sessions/{id}            删除会话
+  POST   /v1/sessions/{id}/messages   发送消息 {"content", "model", "stream"}
+
+在配置文件 serve.keys 中添加访问密钥后，所有 /v1/ 接口都需要通过
+"Authorization: Bearer <密钥>" 认证，每个密钥可以单独限制请求频率、可用模型、每日token配额和每日成本，
+且只能访问自己创建的会话。每日用量保存在 ~/.ai-chat-cli/serve_usage.json，服务重启后继续累计。
+
+服务运行期间修改配置文件会自动生效（提供商、默认提供商和访问密钥），
+新配置无效时继续使用之前的配置。
+
+示例:
+  ai-chat-cli serve --port 8080
+  export OPENAI_BASE_URL=http://127.0.0.1:8080/v1`,
+	Run: runServe,
+}
+
+func runServe(cmd *cobra.Command, args []string) {
+	env := envFrom(cmd.Context())
+	if env.dryRun {
+		fail(ExitUsage, "%s", i18n.T("serve.dry_run"))
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fail(ExitConfig, i18n.T("config.load_failed"), err)
+		hint("%s", i18n.T("config.init_hint"))
+		return
+	}
+
+	// 统计所有提供商的上游请求，停止服务时输出
+	metrics := &providers.Metrics{}
+	env = env.withMiddleware(metrics.Middleware())
+
+	ps, defaultName, err := buildServeProviders(env, cfg, serveProvider)
+	if err != nil {
+		fail(ExitConfig, "%v", err)
+		return
+	}
+
+	store, err := openSessionStore()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	if cfg.Logging.File != "" {
+		path, err := expandHome(cfg.Logging.File)
+		if err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+		w, err := logfile.Open(path, logfile.Options{
+			MaxSizeMB:  cfg.Logging.MaxSize,
+			MaxAgeDays: cfg.Logging.MaxAge,
+			MaxBackups: cfg.Logging.MaxBackups,
+		})
+		if err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+		defer w.Close()
+		log.SetOutput(w)
+		fmt.Println(i18n.T("serve.log_file", cfg.Logging.File))
+	}
+
+	addr := net.JoinHostPort(serveHost, strconv.Itoa(servePort))
+	fmt.Println(i18n.T("serve.started", addr))
+	fmt.Println(i18n.T("serve.default_provider", defaultName))
+	if len(cfg.Serve.Keys) > 0 {
+		fmt.Println(i18n.T("serve.keys_enabled", len(cfg.Serve.Keys)))
+	} else if serveHost != "127.0.0.1" && serveHost != "localhost" {
+		ui.Warn("%s", i18n.T("serve.no_keys"))
+	}
+	hint("%s", i18n.T("serve.stop_hint"))
+
+	keys, err := serveClientKeys(cfg)
+	if err != nil {
+		fail(ExitConfig, "%v", err)
+		return
+	}
+	warnUnpricedProviders(cfg, ps, keys)
+
+	usageFile, err := serveUsagePath()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	srv := server.New(server.Options{
+		Providers:       ps,
+		Capabilities:    serveCapabilities(cfg, ps),
+		Temperatures:    serveTemperatures(cfg, ps),
+		Price:           servePrice(cfg),
+		DefaultProvider: defaultName,
+		Sessions:        store,
+		Keys:            keys,
+		AutoTitle:       cfg.Advanced.TitleModel != config.TitleModelOff,
+		TitleModel:      cfg.Advanced.TitleModel,
+		UsageFile:       usageFile,
+	})
+	watchServeConfig(srv, env)
+
+	// 收到 Ctrl+C 或 SIGTERM 时停止接受新请求，等待进行中的请求完成后退出
+	httpServer := &http.Server{Addr: addr, Handler: srv}
+	go func() {
+		<-cmd.Context().Done()
+		ctx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		httpServer.Shutdown(ctx)
+	}()
+
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fail(ExitError, i18n.T("serve.failed"), err)
+		return
+	}
+	if m := metrics.Snapshot(); m.Requests > 0 {
+		fmt.Println(ui.Styled(ui.ElemStats, i18n.T("serve.metrics", m.Requests, m.Failures, m.AvgLatency.Round(time.Millisecond))))
+	}
+	if n := serveMerged.Load(); n > 0 {
+		fmt.Println(i18n.T("serve.merged", n))
+	}
+	fmt.Println(i18n.T("serve.stopped"))
+}
+
+// serveShutdownTimeout 停止服务时等待进行中的请求完成的最长时间
+const serveShutdownTimeout = 10 * time.Second
+
+// buildServeProviders 为所有已设置API密钥的提供商创建实例，并确定默认提供商。
+// 启动和重新加载配置时共用，只返回错误，由调用方决定退出还是保留之前的配置
+func buildServeProviders(env *providerEnv, cfg *config.Config, preferred string) (map[string]providers.Provider, string, error) {
+	ps := map[string]providers.Provider{}
+	var names []string
+	for name, providerCfg := range cfg.Providers {
+		if !providerCfg.HasCredentials() && !providers.Standalone(name) {
+			continue
+		}
+		p := newAuditedProvider(buildProvider(env, name, providerCfg, cfg.Advanced), providerCfg.Model)
+		p = newCachedProvider(p, providerCfg, cfg.Advanced, env.metadata, false)
+		p = newMaskingProvider(p, cfg.Advanced.MaskPII)
+		if name != providers.MockName {
+			p = newFilteredProvider(p, cfg.Filters, false)
+		}
+		p = newDedupedProvider(p, &serveMerged)
+		ps[name] = p
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(ps) == 0 {
+		return nil, "", errors.New(i18n.T("serve.no_providers"))
+	}
+
+	defaultName := preferred
+	if defaultName == "" {
+		defaultName = cfg.Default.Provider
+	}
+	if _, exists := ps[defaultName]; !exists {
+		if preferred != "" {
+			return nil, "", fmt.Errorf(i18n.T("serve.provider_unavailable"), preferred)
+		}
+		defaultName = names[0]
+	}
+
+	return ps, defaultName, nil
+}
+
+// serveCapabilities 网关中各提供商支持的功能
+func serveCapabilities(cfg *config.Config, ps map[string]providers.Provider) map[string]providers.Capabilities {
+	caps := make(map[string]providers.Capabilities, len(ps))
+	for name := range ps {
+		caps[name] = providerCapabilities(name, cfg.Providers[name])
+	}
+	return caps
+}
+
+// serveTemperatures 网关中各提供商配置的默认温度
+func serveTemperatures(cfg *config.Config, ps map[string]providers.Provider) map[string]float64 {
+	temps := make(map[string]float64, len(ps))
+	for name := range ps {
+		temps[name] = defaultTemperature(cfg.Providers[name])
+	}
+	return temps
+}
+
+// servePrice 计算访问密钥成本上限使用的价格：提供商默认模型使用配置中的 input_price、output_price，
+// 其他模型和没有配置价格的提供商使用内置价格表，都找不到时不计成本
+func servePrice(cfg *config.Config) server.PriceFunc {
+	return func(name, model string) providers.Price {
+		providerCfg := cfg.Providers[name]
+		if model == "" {
+			model = providerCfg.Model
+		}
+		if model == providerCfg.Model && (providerCfg.InputPrice > 0 || providerCfg.OutputPrice > 0) {
+			return providers.Price{Input: providerCfg.InputPrice, Output: providerCfg.OutputPrice}
+		}
+		price, _ := providers.LookupPrice(model)
+		return price
+	}
+}
+
+// warnUnpricedProviders 有访问密钥设置了每日成本上限时，提示无法确定默认模型价格的提供商，其用量不计入成本
+func warnUnpricedProviders(cfg *config.Config, ps map[string]providers.Provider, keys []server.ClientKey) {
+	limited := false
+	for _, k := range keys {
+		limited = limited || k.DailyCost > 0
+	}
+	if !limited {
+		return
+	}
+
+	price := servePrice(cfg)
+	var names []string
+	for name := range ps {
+		if price(name, "") == (providers.Price{}) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ui.Warn(i18n.T("serve.unpriced"), name)
+	}
+	if len(names) > 0 {
+		hint("%s", i18n.T("provider.price_hint"))
+	}
+}
+
+// serveUsagePath 保存访问密钥每日用量的文件 ~/.ai-chat-cli/serve_usage.json
+func serveUsagePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("path.home_failed"), err)
+	}
+	return filepath.Join(home, ".ai-chat-cli", "serve_usage.json"), nil
+}
+
+// serveClientKeys 将配置中的访问密钥转换为网关使用的格式，密钥重复时返回错误
+func serveClientKeys(cfg *config.Config) ([]server.ClientKey, error) {
+	keys := make([]server.ClientKey, 0, len(cfg.Serve.Keys))
+	seen := map[string]string{}
+	for _, k := range cfg.Serve.Keys {
+		if k.Key == "" {
+			ui.Warn(i18n.T("serve.key_empty"), k.Name)
+			continue
+		}
+		if other, exists := seen[k.Key]; exists {
+			return nil, fmt.Errorf(i18n.T("serve.key_duplicate"), k.Name, other)
+		}
+		seen[k.Key] = k.Name
+		keys = append(keys, server.ClientKey{
+			Name:        k.Name,
+			Key:         k.Key,
+			RateLimit:   k.RateLimit,
+			Models:      k.Models,
+			DailyTokens: k.DailyTokens,
+			DailyCost:   k.DailyCost,
+		})
+	}
+	return keys, nil
+}
+
+// watchServeConfig 监听配置文件变化并更新网关，新配置无效时保留当前配置
+func watchServeConfig(srv *server.Server, env *providerEnv) {
+	filename := viper.ConfigFileUsed()
+
+	_, err := config.Watch(filename, func(cfg *config.Config, err error) {
+		stamp := time.Now().Format("15:04:05")
+		if err != nil {
+			ui.Warn(i18n.T("serve.reload_invalid"), stamp, err)
+			return
+		}
+
+		// 在监听配置的goroutine中运行，只提示错误，不修改退出码
+		ps, defaultName, err := buildServeProviders(env, cfg, serveProvider)
+		if err != nil {
+			ui.Warn(i18n.T("serve.reload_invalid"), stamp, err)
+			return
+		}
+		keys, err := serveClientKeys(cfg)
+		if err != nil {
+			ui.Warn(i18n.T("serve.reload_invalid"), stamp, err)
+			return
+		}
+
+		srv.Update(server.Options{
+			Providers:       ps,
+			Capabilities:    serveCapabilities(cfg, ps),
+			Temperatures:    serveTemperatures(cfg, ps),
+			Price:           servePrice(cfg),
+			DefaultProvider: defaultName,
+			Keys:            keys,
+			AutoTitle:       cfg.Advanced.TitleModel != config.TitleModelOff,
+			TitleModel:      cfg.Advanced.TitleModel,
+		})
+		fmt.Println(i18n.T("serve.reloaded",
+			stamp, len(ps), defaultName, len(keys)))
+	})
+	if err != nil {
+		ui.Warn(i18n.T("serve.watch_failed"), err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "监听地址")
+	serveCmd.Flags().IntVar(&servePort, "port", 8080, "监听端口")
+	serveCmd.Flags().StringVarP(&serveProvider, "provider", "p", "", "默认提供商")
+}
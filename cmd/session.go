@@ -0,0 +1,885 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/export"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/secrets"
+	"ai-chat-cli/internal/share"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+
+	"github.com/spf13/cobra"
+)
+
+// sessionCmd 会话管理
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "管理保存的对话会话",
+	Long: `管理保存在 ~/.ai-chat-cli/sessions 中的对话会话。
+
+配置 advanced.save_history 为 true 时，chat 命令会自动保存会话，
+可以使用 chat --session <id> 继续之前的对话。`,
+}
+
+var (
+	sessionListTags  []string
+	sessionTagRemove bool
+)
+
+// sessionListCmd 列出会话
+var sessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出保存的会话",
+	Long: `列出保存的会话，按最近更新时间倒序排列。
+
+示例:
+  ai-chat-cli session list
+  ai-chat-cli session list --tag golang
+  ai-chat-cli session list --tag work,golang   # 同时有两个标签的会话`,
+	Run: runSessionList,
+}
+
+// sessionTagCmd 管理会话标签
+var sessionTagCmd = &cobra.Command{
+	Use:   "tag <id> [标签,...]",
+	Short: "为会话添加或移除标签",
+	Long: `为会话添加逗号分隔的标签，便于使用 session list --tag 筛选。不指定标签时显示会话当前的标签。
+
+示例:
+  ai-chat-cli session tag 20240102-150405-a1b2c3 work,golang
+  ai-chat-cli session tag 20240102-150405-a1b2c3 work --remove`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  runSessionTag,
+}
+
+var (
+	sessionShowPinned bool
+	sessionShowTools  bool
+)
+
+// toolPreviewLines session show 默认显示的工具参数和结果的行数
+const toolPreviewLines = 6
+
+// sessionShowCmd 显示会话内容
+var sessionShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "显示会话内容",
+	Long: `显示会话内容和消息编号。交互模式中用 /pin 标记的消息前显示 📌，
+用 /note 添加的备注显示在消息之后，--pinned 只显示标记过或有备注的消息。
+
+助手回复中的工具调用（如导入的对话中代码解释器和网页搜索的调用）显示在回复之后，
+包含工具名称、耗时、参数和结果，参数和结果默认只显示前几行，--tools 显示完整内容。`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSessionShow,
+}
+
+// sessionDeleteCmd 删除会话
+var sessionDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "删除会话",
+	Args:  cobra.ExactArgs(1),
+	Run:   runSessionDelete,
+}
+
+var (
+	sessionExportFormat string
+	sessionExportOutput string
+)
+
+// sessionExportCmd 导出会话
+var sessionExportCmd = &cobra.Command{
+	Use:   "export <id>",
+	Short: "导出会话为Markdown、HTML或PDF",
+	Long: `将会话导出为Markdown、单个自包含的HTML文件（代码高亮、长消息折叠）或PDF，便于分享。
+
+PDF 包含会话信息、渲染后的Markdown和高亮的代码块，适合发给不使用命令行的人。
+PDF 不嵌入字体，中文使用阅读器自带的中文字体（Acrobat、macOS 预览、浏览器等都支持），表情符号不显示。
+
+示例:
+  ai-chat-cli session export 20240102-150405-a1b2c3 --format html -o chat.html
+  ai-chat-cli session export 20240102-150405-a1b2c3 --format pdf -o chat.pdf
+  ai-chat-cli session export 20240102-150405-a1b2c3 > chat.md`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSessionExport,
+}
+
+var (
+	sessionCompactKeep     int
+	sessionCompactModel    string
+	sessionCompactProvider string
+)
+
+// sessionCompactCmd 压缩会话
+var sessionCompactCmd = &cobra.Command{
+	Use:   "compact <id>",
+	Short: "将会话中较早的消息压缩为摘要",
+	Long: `请模型将会话中较早的消息总结为一条摘要消息，只原样保留最近几轮对话，
+减少继续该会话时发送的token数。压缩前的完整会话会保存到 ~/.ai-chat-cli/sessions/archive。
+
+默认使用 advanced.title_model 配置的模型生成摘要，未配置时使用提供商的默认模型。
+
+示例:
+  ai-chat-cli session compact 20240102-150405-a1b2c3
+  ai-chat-cli session compact 20240102-150405-a1b2c3 --keep 4 --model gpt-4o-mini`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSessionCompact,
+}
+
+var (
+	sessionShareTarget       string
+	sessionSharePublic       bool
+	sessionShareAllowSecrets bool
+)
+
+// sessionShareCmd 分享会话
+var sessionShareCmd = &cobra.Command{
+	Use:   "share <id>",
+	Short: "将会话上传为 GitHub Gist 或粘贴链接",
+	Long: `将会话导出为Markdown并上传到配置的分享目标，打印访问链接。
+
+分享目标:
+  gist   GitHub Gist，需要 share.gist_token 或 GITHUB_TOKEN 环境变量，默认创建私密 gist
+  0x0    https://0x0.st，不需要账号，链接公开可访问
+  paste  share.paste_url 配置的粘贴服务，以POST请求发送Markdown原文，响应内容为链接
+
+上传前会检查内容中是否包含疑似API密钥、token或私钥，发现时拒绝上传。
+可以先在交互模式中使用 /redact 隐藏这些内容，或确认无误后使用 --allow-secrets。
+使用 --dry-run 时只打印将要上传的内容。
+
+示例:
+  ai-chat-cli session share 20240102-150405-a1b2c3
+  ai-chat-cli session share 20240102-150405-a1b2c3 --target 0x0`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSessionShare,
+}
+
+// sessionEncryptCmd 加密已保存的会话
+var sessionEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "加密所有已保存的会话",
+	Long: `使用 AES-256-GCM 加密 ~/.ai-chat-cli/sessions 中所有未加密的会话。
+
+密钥保存在系统钥匙串中（macOS 钥匙串或 Linux 的 libsecret），第一次使用时自动生成；
+没有钥匙串时保存到 ~/.ai-chat-cli/session.key，也可以通过 AI_CHAT_CLI_SESSION_KEY 环境变量提供。
+加密后 session、chat --session 等命令会自动解密，不需要额外操作。
+
+配置 advanced.encrypt_sessions 为 true 后新保存的会话也会加密。`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		rewriteSessions(true)
+	},
+}
+
+// sessionDecryptCmd 解密已保存的会话
+var sessionDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "将所有已加密的会话恢复为明文",
+	Long: `将 ~/.ai-chat-cli/sessions 中所有已加密的会话恢复为明文JSON文件。
+
+需要同时将 advanced.encrypt_sessions 设置为 false，否则之后保存的会话仍会加密。`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		rewriteSessions(false)
+	},
+}
+
+func runSessionList(cmd *cobra.Command, args []string) {
+	store, err := openSessionStore()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	sessions, err := store.List()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	if len(sessionListTags) > 0 {
+		var matched []*session.Session
+		for _, s := range sessions {
+			if hasAllTags(s, sessionListTags) {
+				matched = append(matched, s)
+			}
+		}
+		sessions = matched
+	}
+	if len(sessions) == 0 {
+		if len(sessionListTags) > 0 {
+			fmt.Println(i18n.T("session.none_tagged", strings.Join(sessionListTags, ", ")))
+			return
+		}
+		fmt.Println(i18n.T("session.none"))
+		return
+	}
+
+	fmt.Println(i18n.T("session.list_title"))
+	for _, s := range sessions {
+		title := s.Title
+		if len(s.Tags) > 0 {
+			title += "  [" + strings.Join(s.Tags, ", ") + "]"
+		}
+		fmt.Printf("  %s\n", i18n.T("session.list_row",
+			s.ID, s.UpdatedAt.Format("2006-01-02 15:04"), s.Provider, len(s.Messages), title))
+	}
+}
+
+// hasAllTags 判断会话是否同时有所有指定的标签
+func hasAllTags(s *session.Session, tags []string) bool {
+	for _, tag := range tags {
+		if !s.HasTag(strings.TrimSpace(tag)) {
+			return false
+		}
+	}
+	return true
+}
+
+func runSessionTag(cmd *cobra.Command, args []string) {
+	store, err := openSessionStore()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	s, ok := loadSession(args[0])
+	if !ok {
+		return
+	}
+
+	if len(args) == 1 {
+		if len(s.Tags) == 0 {
+			fmt.Println(i18n.T("session.no_tags"))
+			return
+		}
+		fmt.Println(ui.Status("🏷️  " + strings.Join(s.Tags, ", ")))
+		return
+	}
+
+	tags := session.ParseTags(args[1])
+	if len(tags) == 0 {
+		fail(ExitUsage, "%s", i18n.T("session.tags_required"))
+		return
+	}
+	if sessionTagRemove {
+		s.RemoveTags(tags...)
+	} else {
+		s.AddTags(tags...)
+	}
+
+	if err := store.Save(s); err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	if len(s.Tags) == 0 {
+		ui.Success(i18n.T("session.tags_cleared"), s.ID)
+		return
+	}
+	ui.Success(i18n.T("session.tags_updated"), s.ID, strings.Join(s.Tags, ", "))
+}
+
+func runSessionShow(cmd *cobra.Command, args []string) {
+	s, ok := loadSession(args[0])
+	if !ok {
+		return
+	}
+
+	fmt.Println(ui.Status("📝 " + s.Title))
+	fmt.Printf("  ID: %s\n", s.ID)
+	if s.Provider != "" {
+		fmt.Printf("  %s\n", i18n.T("session.provider", s.Provider))
+	}
+	if s.Model != "" {
+		fmt.Printf("  %s\n", i18n.T("session.model", s.Model))
+	}
+	if len(s.Tags) > 0 {
+		fmt.Printf("  %s\n", i18n.T("session.tags", strings.Join(s.Tags, ", ")))
+	}
+	if s.Dir != "" {
+		fmt.Printf("  %s\n", i18n.T("session.dir", s.Dir))
+	}
+	fmt.Printf("  %s\n", i18n.T("session.created", s.CreatedAt.Format("2006-01-02 15:04:05")))
+	marked := s.Marked()
+	if len(marked) > 0 {
+		numbers := make([]string, len(marked))
+		for i, idx := range marked {
+			numbers[i] = strconv.Itoa(idx + 1)
+		}
+		fmt.Printf("  %s\n", i18n.T("session.marked", strings.Join(numbers, ", ")))
+	}
+	fmt.Println("---")
+
+	if sessionShowPinned && len(marked) == 0 {
+		fmt.Println(i18n.T("session.no_marked"))
+		return
+	}
+	for i, m := range s.Messages {
+		if sessionShowPinned && !m.Pinned && m.Note == "" {
+			continue
+		}
+		pin := ""
+		if m.Pinned {
+			pin = ui.Symbol("📌 ", "* ")
+		}
+		switch m.Role {
+		case "user":
+			fmt.Printf("%d. %s%s%s\n", i+1, pin, ui.Styled(ui.ElemUser, i18n.T("chat.you")), m.Content)
+		case "assistant":
+			fmt.Printf("%d. %s%s%s\n", i+1, pin, ui.Styled(ui.ElemAI, i18n.T("chat.ai")), m.Content)
+		default:
+			fmt.Printf("%d. %s%s%s: %s\n", i+1, pin, ui.Symbol("⚙️  ", ""), m.Role, m.Content)
+		}
+		for _, c := range m.ToolCalls {
+			printToolCall(c, sessionShowTools)
+		}
+		if m.Truncated {
+			fmt.Println("   " + i18n.T("session.truncated"))
+		}
+		if m.Note != "" {
+			fmt.Printf("   %s%s\n", ui.Symbol("📝 ", "note: "), m.Note)
+		}
+		fmt.Println()
+	}
+}
+
+// printToolCall 显示一次工具调用，full 为 false 时参数和结果超过 toolPreviewLines 行的部分被折叠
+func printToolCall(c session.ToolCall, full bool) {
+	label := "🔧 " + export.ToolCallLabel(c)
+	if c.Error {
+		fmt.Printf("   %s\n", ui.Colors().Red(label))
+	} else {
+		fmt.Printf("   %s\n", ui.Colors().Bold(label))
+	}
+	parts := []struct{ label, text string }{
+		{i18n.T("session.tool_args"), c.Arguments},
+		{i18n.T("session.tool_result"), c.Result},
+	}
+	for _, part := range parts {
+		if part.text == "" {
+			continue
+		}
+		lines := strings.Split(strings.TrimRight(part.text, "\n"), "\n")
+		fmt.Printf("   │ %s:\n", part.label)
+		shown := lines
+		if !full && len(lines) > toolPreviewLines {
+			shown = lines[:toolPreviewLines]
+		}
+		for _, line := range shown {
+			fmt.Printf("   │   %s\n", line)
+		}
+		if len(shown) < len(lines) {
+			fmt.Printf("   │   %s\n", ui.Colors().Faint(i18n.T("session.tool_folded", len(lines)-len(shown))))
+		}
+	}
+}
+
+func runSessionDelete(cmd *cobra.Command, args []string) {
+	store, err := openSessionStore()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	if err := store.Delete(args[0]); err != nil {
+		fail(ExitError, "%v: %s", err, args[0])
+		return
+	}
+	ui.Success(i18n.T("session.deleted"), args[0])
+}
+
+func runSessionExport(cmd *cobra.Command, args []string) {
+	var render func(io.Writer, *session.Session) error
+	switch sessionExportFormat {
+	case "markdown", "md":
+		render = export.Markdown
+	case "html":
+		render = export.HTML
+	case "pdf":
+		if sessionExportOutput == "" && stdoutIsTerminal() {
+			fail(ExitUsage, "%s", i18n.T("session.pdf_terminal"))
+			return
+		}
+		render = export.PDF
+	default:
+		fail(ExitUsage, i18n.T("session.format_invalid"), sessionExportFormat)
+		return
+	}
+
+	s, ok := loadSession(args[0])
+	if !ok {
+		return
+	}
+
+	if sessionExportOutput == "" {
+		if err := render(os.Stdout, s); err != nil {
+			fail(ExitError, i18n.T("session.export_failed"), err)
+		}
+		return
+	}
+
+	out, err := os.Create(sessionExportOutput)
+	if err != nil {
+		fail(ExitError, i18n.T("session.create_failed"), err)
+		return
+	}
+	defer out.Close()
+
+	if err := render(out, s); err != nil {
+		fail(ExitError, i18n.T("session.export_failed"), err)
+		return
+	}
+	ui.Success(i18n.T("session.exported"), sessionExportOutput)
+}
+
+func runSessionCompact(cmd *cobra.Command, args []string) {
+	if sessionCompactKeep < 0 {
+		fail(ExitUsage, "%s", i18n.T("session.keep_negative"))
+		return
+	}
+
+	store, err := openSessionStore()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	s, ok := loadSession(args[0])
+	if !ok {
+		return
+	}
+
+	start := s.CompactPoint(sessionCompactKeep)
+	if start < 0 {
+		fmt.Println(i18n.T("session.compact_none", sessionCompactKeep))
+		return
+	}
+
+	name := sessionCompactProvider
+	if name == "" {
+		name = s.Provider
+	}
+	provider, ok := loadProvider(envFrom(cmd.Context()), name)
+	if !ok {
+		return
+	}
+
+	model := sessionCompactModel
+	if model == "" {
+		if cfg, err := config.LoadConfig(); err == nil && cfg.Advanced.TitleModel != config.TitleModelOff {
+			model = cfg.Advanced.TitleModel
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, i18n.T("session.compacting", start))
+	synopsis, err := session.GenerateSynopsis(cmd.Context(), provider, model, s.Messages[:start])
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("session.summary_failed"), err)
+		return
+	}
+
+	archive, err := store.Archive(s)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	before := len(s.Messages)
+	s.Compact(start, synopsis)
+	if err := store.Save(s); err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	ui.Success(i18n.T("session.compacted"), s.ID, before, len(s.Messages))
+	fmt.Println(i18n.T("session.archive", archive))
+}
+
+func runSessionShare(cmd *cobra.Command, args []string) {
+	s, ok := loadSession(args[0])
+	if !ok {
+		return
+	}
+
+	opts := share.Options{
+		Target:   share.TargetGist,
+		Token:    os.Getenv("GITHUB_TOKEN"),
+		Public:   sessionSharePublic,
+		Filename: s.ID + ".md",
+	}
+	if cfg, err := config.LoadConfig(); err == nil {
+		if cfg.Share.Target != "" {
+			opts.Target = cfg.Share.Target
+		}
+		if cfg.Share.GistToken != "" {
+			opts.Token = cfg.Share.GistToken
+		}
+		opts.Public = opts.Public || cfg.Share.Public
+		opts.PasteURL = cfg.Share.PasteURL
+	}
+	if sessionShareTarget != "" {
+		opts.Target = sessionShareTarget
+	}
+
+	dryRun := envFrom(cmd.Context()).dryRun
+	switch opts.Target {
+	case share.TargetGist:
+		if opts.Token == "" && !dryRun {
+			fail(ExitConfig, "%s", i18n.T("session.gist_token"))
+			hint("%s", i18n.T("session.gist_token_hint"))
+			return
+		}
+	case share.TargetPaste:
+		if opts.PasteURL == "" && !dryRun {
+			fail(ExitConfig, "%s", i18n.T("session.paste_url"))
+			return
+		}
+	case share.TargetZeroX:
+	default:
+		fail(ExitUsage, i18n.T("session.share_target_invalid"), opts.Target)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.Markdown(&buf, s); err != nil {
+		fail(ExitError, i18n.T("session.export_failed"), err)
+		return
+	}
+	content := buf.String()
+
+	if findings := secrets.Scan(content); len(findings) > 0 && !sessionShareAllowSecrets {
+		ui.Warn(i18n.T("session.secrets_found"), len(findings))
+		for _, f := range findings {
+			fmt.Fprintf(os.Stderr, "   %s\n", i18n.T("session.secret_line", f.Line, f.Kind, f.Match))
+		}
+		fail(ExitError, "%s", i18n.T("session.share_cancelled"))
+		hint("%s", i18n.T("session.share_redact_hint"))
+		return
+	}
+
+	if dryRun {
+		fmt.Print(content)
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, i18n.T("session.uploading", opts.Target))
+	url, err := share.Upload(cmd.Context(), content, opts)
+	if err != nil {
+		code := ExitError
+		if errors.Is(err, context.Canceled) {
+			code = ExitInterrupted
+		}
+		fail(code, i18n.T("session.share_failed"), err)
+		return
+	}
+	fmt.Println(url)
+}
+
+// rewriteSessions 按指定方式重新保存所有会话，用于加密或解密已有的会话
+func rewriteSessions(encrypt bool) {
+	store, err := session.OpenDefault()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	// 先获取密钥，避免因缺少密钥跳过已加密的会话
+	key, err := sessionKey(encrypt)
+	if err != nil {
+		fail(ExitConfig, "%v", err)
+		return
+	}
+	if err := store.SetKey(key, encrypt); err != nil {
+		fail(ExitConfig, "%v", err)
+		return
+	}
+
+	sessions, err := store.List()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	for _, s := range sessions {
+		if err := store.Save(s); err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+	}
+
+	if encrypt {
+		ui.Success(i18n.T("session.encrypted"), len(sessions))
+		if cfg, err := config.LoadConfig(); err == nil && !cfg.Advanced.EncryptSessions {
+			hint("%s", i18n.T("session.encrypt_hint"))
+		}
+		return
+	}
+	ui.Success(i18n.T("session.decrypted"), len(sessions))
+}
+
+// loadSession 从默认存储中读取会话
+func loadSession(id string) (*session.Session, bool) {
+	store, err := openSessionStore()
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return nil, false
+	}
+
+	s, err := store.Get(id)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			fail(ExitError, i18n.T("session.not_found"), id)
+			hint("%s", i18n.T("session.list_hint"))
+		} else {
+			fail(ExitError, "%v", err)
+		}
+		return nil, false
+	}
+	return s, true
+}
+
+// chatSessionState 当前chat命令正在记录的会话
+type chatSessionState struct {
+	store   *session.Store
+	current *session.Session
+
+	// provider 和 titleModel 用于在第一轮对话后自动生成标题，provider为nil时不生成
+	provider   providers.Provider
+	titleModel string
+
+	mu     sync.Mutex
+	titles sync.WaitGroup
+}
+
+// sync 将对话历史中新增的消息追加到会话并保存
+func (cs *chatSessionState) sync(history []Message) {
+	if cs == nil {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.current == nil || len(history) < len(cs.current.Messages) {
+		return
+	}
+
+	for _, m := range history[len(cs.current.Messages):] {
+		if m.Role == "assistant" {
+			cs.current.AppendReply(m.Content, m.Usage, m.Stats)
+			cs.current.Messages[len(cs.current.Messages)-1].Truncated = m.Truncated
+		} else {
+			cs.current.Append(m.Role, m.Content)
+		}
+	}
+	if err := cs.store.Save(cs.current); err != nil {
+		ui.Warn(i18n.T("session.save_failed"), err)
+		return
+	}
+
+	if cs.provider != nil && cs.current.NeedsTitle() {
+		cs.generateTitle(cs.current)
+	}
+}
+
+// generateTitle 在后台请模型生成会话标题，调用方需持有锁
+func (cs *chatSessionState) generateTitle(s *session.Session) {
+	snapshot := *s
+	snapshot.Messages = append([]session.Message(nil), s.Messages...)
+
+	cs.titles.Add(1)
+	go func() {
+		defer cs.titles.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		title, err := session.GenerateTitle(ctx, cs.provider, cs.titleModel, &snapshot)
+		if err != nil {
+			return
+		}
+
+		cs.mu.Lock()
+		defer cs.mu.Unlock()
+		s.Title = title
+		if err := cs.store.Save(s); err != nil {
+			ui.Warn(i18n.T("session.title_failed"), err)
+		}
+	}()
+}
+
+// wait 等待后台生成标题完成
+func (cs *chatSessionState) wait() {
+	if cs == nil {
+		return
+	}
+	cs.titles.Wait()
+}
+
+// removeMessage 从会话中删除一条消息并保存
+func (cs *chatSessionState) removeMessage(i int) {
+	cs.update(func(s *session.Session) bool {
+		if i >= len(s.Messages) {
+			return false
+		}
+		s.Messages = append(s.Messages[:i], s.Messages[i+1:]...)
+		return true
+	})
+}
+
+// replaceMessage 替换会话中一条消息的内容并保存，文件中不再保留原内容
+func (cs *chatSessionState) replaceMessage(i int, content string) {
+	cs.update(func(s *session.Session) bool {
+		if i >= len(s.Messages) {
+			return false
+		}
+		s.Messages[i].Content = content
+		return true
+	})
+}
+
+// annotate 修改会话中一条消息的标记或备注并保存，消息还没有保存到会话时返回false
+func (cs *chatSessionState) annotate(i int, fn func(m *session.Message)) bool {
+	saved := false
+	cs.update(func(s *session.Session) bool {
+		if i >= len(s.Messages) {
+			return false
+		}
+		fn(&s.Messages[i])
+		saved = true
+		return true
+	})
+	return saved
+}
+
+// truncate 只保留会话的前n条消息并保存
+func (cs *chatSessionState) truncate(n int) {
+	cs.update(func(s *session.Session) bool {
+		if n >= len(s.Messages) {
+			return false
+		}
+		s.Messages = s.Messages[:n]
+		return true
+	})
+}
+
+// update 修改已保存的消息，fn 返回true时保存会话。
+// 尚未同步到会话的消息（如未保存的初始对话）只需修改对话历史
+func (cs *chatSessionState) update(fn func(s *session.Session) bool) {
+	if cs == nil {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.current == nil || !fn(cs.current) {
+		return
+	}
+	if err := cs.store.Save(cs.current); err != nil {
+		ui.Warn(i18n.T("session.save_failed"), err)
+	}
+}
+
+// reset 重置对话历史后开始记录新会话
+func (cs *chatSessionState) reset() {
+	if cs == nil {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.current == nil {
+		return
+	}
+	cs.current = cs.store.New(cs.current.Provider, cs.current.Model)
+}
+
+// resumeID 返回可以继续的会话ID，会话还没有内容或已锁定时返回空字符串
+func (cs *chatSessionState) resumeID() string {
+	if cs == nil {
+		return ""
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.current == nil || len(cs.current.Messages) == 0 {
+		return ""
+	}
+	return cs.current.ID
+}
+
+// lock 等待后台生成标题完成后丢弃内存中的会话，返回会话ID以及会话是否已保存
+func (cs *chatSessionState) lock() (string, bool) {
+	cs.titles.Wait()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	id, saved := cs.current.ID, len(cs.current.Messages) > 0
+	if saved {
+		cs.current = nil
+	}
+	return id, saved
+}
+
+// unlock 重新打开会话存储（重新获取会话密钥）并读取锁定前保存的会话
+func (cs *chatSessionState) unlock(id string, saved bool) (*session.Session, error) {
+	store, err := openSessionStore()
+	if err != nil {
+		return nil, err
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if !saved {
+		// 会话还没有内容，只需确认可以获取会话密钥
+		if _, err := sessionKey(false); err != nil {
+			return nil, err
+		}
+		cs.store = store
+		return cs.current, nil
+	}
+	s, err := store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	cs.store, cs.current = store, s
+	return s, nil
+}
+
+// sessionHistory 将会话消息转换为对话历史
+func sessionHistory(s *session.Session) []Message {
+	history := make([]Message, 0, len(s.Messages))
+	for _, m := range s.Messages {
+		history = append(history, Message{Role: m.Role, Content: m.Content, Usage: m.Usage, Stats: m.Stats, Truncated: m.Truncated})
+	}
+	return history
+}
+
+func init() {
+	rootCmd.AddCommand(sessionCmd)
+	sessionCmd.AddCommand(sessionListCmd)
+	sessionCmd.AddCommand(sessionShowCmd)
+	sessionCmd.AddCommand(sessionDeleteCmd)
+	sessionCmd.AddCommand(sessionExportCmd)
+	sessionCmd.AddCommand(sessionTagCmd)
+	sessionCmd.AddCommand(sessionCompactCmd)
+	sessionCmd.AddCommand(sessionShareCmd)
+	sessionCmd.AddCommand(sessionEncryptCmd)
+	sessionCmd.AddCommand(sessionDecryptCmd)
+
+	sessionShowCmd.Flags().BoolVar(&sessionShowPinned, "pinned", false, "只显示标记过或有备注的消息")
+	sessionShowCmd.Flags().BoolVar(&sessionShowTools, "tools", false, "完整显示工具调用的参数和结果")
+	sessionExportCmd.Flags().StringVar(&sessionExportFormat, "format", "markdown", "导出格式: markdown, html, pdf")
+	sessionExportCmd.Flags().StringVarP(&sessionExportOutput, "output", "o", "", "输出文件（默认输出到标准输出）")
+	sessionListCmd.Flags().StringSliceVar(&sessionListTags, "tag", nil, "只列出有指定标签的会话，多个标签用逗号分隔")
+	sessionTagCmd.Flags().BoolVarP(&sessionTagRemove, "remove", "r", false, "移除指定的标签")
+	sessionCompactCmd.Flags().IntVar(&sessionCompactKeep, "keep", 2, "原样保留的最近对话轮数")
+	sessionCompactCmd.Flags().StringVar(&sessionCompactModel, "model", "", "生成摘要使用的模型（默认使用 advanced.title_model 或提供商默认模型）")
+	sessionCompactCmd.Flags().StringVarP(&sessionCompactProvider, "provider", "p", "", "生成摘要使用的提供商（默认使用会话的提供商）")
+	sessionShareCmd.Flags().StringVar(&sessionShareTarget, "target", "", "分享目标: gist、0x0 或 paste（默认使用 share.target，未配置时为 gist）")
+	sessionShareCmd.Flags().BoolVar(&sessionSharePublic, "public", false, "创建公开的 gist（默认为私密）")
+	sessionShareCmd.Flags().BoolVar(&sessionShareAllowSecrets, "allow-secrets", false, "发现疑似密钥时仍然上传")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/keyring"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/session"
+)
+
+const (
+	// sessionKeyEnv 会话密钥的环境变量（base64编码），用于没有钥匙串的服务器和CI
+	sessionKeyEnv = "AI_CHAT_CLI_SESSION_KEY"
+	// sessionKeyService 会话密钥在系统钥匙串中的服务名和账号
+	sessionKeyService = "ai-chat-cli"
+	sessionKeyAccount = "session-key"
+	// sessionKeyFile 系统没有钥匙串时保存会话密钥的文件
+	sessionKeyFile = "session.key"
+)
+
+// openSessionStore 打开默认会话存储，并按 advanced.encrypt_sessions 设置加密。
+// 关闭加密后仍然可以读取之前加密的会话，密钥在第一次读到加密会话时才获取
+func openSessionStore() (*session.Store, error) {
+	store, err := session.OpenDefault()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypt := false
+	if cfg, err := config.LoadConfig(); err == nil {
+		encrypt = cfg.Advanced.EncryptSessions
+	}
+	store.SetKeyFunc(func() ([]byte, error) { return sessionKey(encrypt) }, encrypt)
+	return store, nil
+}
+
+// sessionKey 依次从环境变量、系统钥匙串和密钥文件读取会话密钥，
+// create 为 true 且没有密钥时生成新密钥并保存
+func sessionKey(create bool) ([]byte, error) {
+	if v := os.Getenv(sessionKeyEnv); v != "" {
+		return decodeSessionKey(v, sessionKeyEnv)
+	}
+
+	v, err := keyring.Get(sessionKeyService, sessionKeyAccount)
+	switch {
+	case err == nil:
+		return decodeSessionKey(v, "钥匙串")
+	case errors.Is(err, keyring.ErrUnsupported):
+		return fileSessionKey(create)
+	case !errors.Is(err, keyring.ErrNotFound):
+		return nil, err
+	}
+
+	if !create {
+		return nil, fmt.Errorf("%w（钥匙串中没有会话密钥，可以通过 %s 环境变量提供）", session.ErrEncrypted, sessionKeyEnv)
+	}
+	key, encoded, err := newSessionKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := keyring.Set(sessionKeyService, sessionKeyAccount, encoded); err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("session.key_generated"))
+	return key, nil
+}
+
+// fileSessionKey 系统没有钥匙串时，从 ~/.ai-chat-cli/session.key 读取会话密钥
+func fileSessionKey(create bool) ([]byte, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(home, ".ai-chat-cli", sessionKeyFile)
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return decodeSessionKey(string(data), path)
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取会话密钥失败: %w", err)
+	}
+	if !create {
+		return nil, fmt.Errorf("%w（没有找到 %s，可以通过 %s 环境变量提供）", session.ErrEncrypted, path, sessionKeyEnv)
+	}
+
+	key, encoded, err := newSessionKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	// 使用 O_EXCL 创建，另一个进程同时生成了密钥时使用先保存的密钥
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return fileSessionKey(false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("保存会话密钥失败: %w", err)
+	}
+	_, err = f.WriteString(encoded + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("保存会话密钥失败: %w", err)
+	}
+	ui.Warn(i18n.T("session.key_saved_file"), path)
+	return key, nil
+}
+
+// newSessionKey 生成随机的会话密钥，同时返回base64编码
+func newSessionKey() ([]byte, string, error) {
+	key := make([]byte, session.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, "", fmt.Errorf("生成会话密钥失败: %w", err)
+	}
+	return key, base64.StdEncoding.EncodeToString(key), nil
+}
+
+// decodeSessionKey 解码base64编码的会话密钥
+func decodeSessionKey(v, source string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(v))
+	if err != nil || len(key) != session.KeySize {
+		return nil, fmt.Errorf("%s 中的会话密钥无效，应为base64编码的 %d 字节", source, session.KeySize)
+	}
+	return key, nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"ai-chat-cli/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	shProvider string
+	shYes      bool
+	shPrint    bool
+)
+
+// shCmd represents the sh command
+var shCmd = &cobra.Command{
+	Use:   "sh <描述>",
+	Short: "用自然语言生成Shell命令",
+	Long: `根据自然语言描述生成一条Shell命令，显示后经确认再执行。
+
+会自动识别当前的Shell和操作系统，生成对应语法的命令。
+
+示例:
+  ai-chat-cli sh "查找上周修改过的大文件"
+  ai-chat-cli sh --yes "统计当前目录下Go代码的行数"    # 不确认直接执行
+  ai-chat-cli sh --print "列出监听中的端口"            # 只输出命令，不执行`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runSh,
+}
+
+func runSh(cmd *cobra.Command, args []string) {
+	shell := detectShell()
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), shProvider)
+	if !ok {
+		return
+	}
+
+	system := fmt.Sprintf(`You translate natural-language requests into exactly one shell command.
+Target shell: %s. Operating system: %s (%s).
+Rules:
+- Reply with only the command, on a single line, without explanation or Markdown.
+- Chain steps with pipes or && if several are needed.
+- Prefer safe, non-destructive options; never use sudo unless explicitly asked.`, shell, runtime.GOOS, runtime.GOARCH)
+
+	resp, err := complete(cmd.Context(), provider, system, strings.Join(args, " "), 0.1)
+	if err != nil {
+		fail(errorExitCode(err), "生成命令失败: %v", err)
+		return
+	}
+
+	command := extractCodeBlock(resp.Content)
+	if command == "" {
+		fail(ExitProvider, "AI没有返回命令")
+		return
+	}
+
+	if shPrint {
+		fmt.Println(command)
+		return
+	}
+
+	fmt.Printf("%s%s\n", ui.Symbol("💻 ", "$ "), ui.Colors().Cyan(command))
+
+	if !shYes && !confirm("▶️  执行该命令?") {
+		fmt.Println("已取消")
+		return
+	}
+
+	if err := runShellCommand(shell, command); err != nil {
+		fail(ExitError, "命令执行失败: %v", err)
+	}
+}
+
+// detectShell 识别当前用户使用的Shell
+func detectShell() string {
+	if runtime.GOOS == "windows" {
+		if os.Getenv("PSModulePath") != "" {
+			return "powershell"
+		}
+		return "cmd"
+	}
+
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return filepath.Base(shell)
+	}
+	return "sh"
+}
+
+// runShellCommand 使用指定的Shell执行命令，输入输出直接连接到终端
+func runShellCommand(shell, command string) error {
+	var c *exec.Cmd
+	switch shell {
+	case "powershell", "pwsh":
+		c = exec.Command(shell, "-NoProfile", "-Command", command)
+	case "cmd":
+		c = exec.Command("cmd", "/C", command)
+	default:
+		if _, err := exec.LookPath(shell); err != nil {
+			shell = "sh"
+		}
+		c = exec.Command(shell, "-c", command)
+	}
+
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+func init() {
+	rootCmd.AddCommand(shCmd)
+
+	shCmd.Flags().StringVarP(&shProvider, "provider", "p", "", "指定AI提供商")
+	shCmd.Flags().BoolVarP(&shYes, "yes", "y", false, "不确认直接执行（用于脚本）")
+	shCmd.Flags().BoolVar(&shPrint, "print", false, "只输出命令，不执行")
+}
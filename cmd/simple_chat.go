@@ -1,19 +1,25 @@
 package cmd
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
+	"ai-chat-cli/internal/clipboard"
 	"ai-chat-cli/internal/config"
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/memory"
+	"ai-chat-cli/internal/notify"
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+	"ai-chat-cli/pkg/template"
 
-	"github.com/charmbracelet/glamour"
-	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 )
 
@@ -21,32 +27,116 @@ import (
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
-}
 
-// ChatRequest OpenAI API请求结构
-type ChatRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	MaxTokens   int       `json:"max_tokens"`
-	Temperature float64   `json:"temperature"`
-}
+	// 助手回复的用量和耗时统计
+	Usage *providers.Usage `json:"usage,omitempty"`
+	Stats *providers.Stats `json:"stats,omitempty"`
 
-// ChatResponse OpenAI API响应结构
-type ChatResponse struct {
-	Choices []struct {
-		Message Message `json:"message"`
-	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
+	// Truncated 流式回复被 Ctrl+C 中断，只保存了已收到的部分
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 var (
-	chatProvider string
+	chatProvider        string
+	chatSessionID       string
+	chatSeedFile        string
+	chatAssistantPrefix string
+	chatStats           bool
+	chatNoStats         bool
+	chatVerboseStats    bool
+	chatStdinAs         string
+	chatHistoryTurns    int
+	chatNotify          bool
+	chatContinue        bool
+	chatRoute           string
+	chatPipelineSpec    string
+	chatQueue           bool
+	chatTeePath         string
+	chatStreamJSON      bool
+	chatContinueOutput  bool
+	chatJSON            bool
+	chatSamples         int
+	chatBestOf          string
+	chatIdleTimeout     time.Duration
+	chatNoPager         bool
+	chatFromClipboard   bool
+	chatPreset          string
+	chatCopy            bool
+	chatTemperature     float64
+	chatTopP            float64
+	chatReplyLanguage   string
 )
 
+// chatRuntime 一次 chat 命令运行期间的状态，由 runSimpleChat 根据配置和命令行参数创建后传给各个步骤。
+// 命令行参数只在创建时读取，之后不再修改
+type chatRuntime struct {
+	cfg          *config.Config
+	providerName string
+	provider     providers.Provider
+
+	// session 当前对话记录的会话，未启用历史保存时为nil
+	session *chatSessionState
+	// seed 初始对话，reset 后对话历史恢复为初始对话
+	seed []Message
+	// router 按问题复杂度选择模型
+	router *modelRouter
+	// pipeline 起草和修订两个模型，未指定 --pipeline 时为nil
+	pipeline *draftPipeline
+	// tee 同时写入回答的Markdown文件，未指定 --tee 时为nil
+	tee *answerTee
+
+	// statsMode 回复后用量统计的显示方式
+	statsMode string
+	// historyTurns 每次请求最多发送的历史对话轮数，小于0表示不限制
+	historyTurns int
+	// historyRoles 发送哪些角色的历史消息，为空表示全部发送
+	historyRoles []string
+
+	// paging 超过一屏的回答交给分页程序显示，只用于交互模式
+	paging bool
+	// idleTimeout 和 idleAction 交互模式的空闲超时及超时后的操作
+	idleTimeout time.Duration
+	idleAction  string
+	// lockVerifier 空闲锁定后校验解锁口令，idleAction 为 lock 时设置
+	lockVerifier *lockVerifier
+	// copyReply 回答后复制到剪贴板
+	copyReply bool
+	// model 由 --preset 指定的模型，路由没有选择模型时使用
+	model string
+	// temperature 和 sampling 请求的温度和其他采样参数，已合并提供商配置、预设和命令行参数
+	temperature float64
+	sampling    providers.Sampling
+	// greeting 预设的开场白，进入交互模式时显示
+	greeting string
+	// memory 用 /remember 记住的事实，读取失败时为nil
+	memory *memory.Store
+	// replyLanguage 回答使用的语言，auto 表示与问题相同，为空表示不指定
+	replyLanguage string
+	// interactive 是否为交互模式，回答被截断时据此提示 /continue 或 --continue-output
+	interactive bool
+	// samples 和 judge 由 --n 和 --best-of 指定的回答数和评审模型，samples 不大于1时只请求一个回答
+	samples int
+	judge   string
+	// stream 以流式请求接收回答，被 Ctrl+C 中断时保留已收到的部分。提供商不支持流式响应时为false
+	stream bool
+	// streamJSON 和 printJSON 由 --stream-json 和 --json 指定的JSON输出方式
+	streamJSON bool
+	printJSON  bool
+	// assistantPrefix 预填的回复开头，加入队列的问题同样使用
+	assistantPrefix string
+	// continueOutput 回答被截断时自动请求剩余部分，不再提示 --continue-output
+	continueOutput bool
+	// notify 回答完成或失败后响铃并发送桌面通知
+	notify bool
+	// queue 单次对话时网络不可用则将请求加入队列
+	queue bool
+	// dryRun 演练模式，不创建文件、不复制回答、不发送通知
+	dryRun bool
+	// fromClipboard 和 stdinAs 单次对话的输入来自剪贴板，或按 --stdin-as 使用管道输入
+	fromClipboard bool
+	stdinAs       string
+}
+
 // chatCmd represents the chat command
 var simpleChatCmd = &cobra.Command{
 	Use:   "chat [问题]",
@@ -54,8 +144,33 @@ var simpleChatCmd = &cobra.Command{
 	Long: `与AI模型进行对话交流。可以：
 
 • 直接指定问题：ai-chat-cli chat "你好，介绍一下自己"
+• 管道输入问题：echo "你好" | ai-chat-cli chat
+• 附加上下文：cat main.go | ai-chat-cli chat "这段代码有什么问题"
+• 剪贴板往返：ai-chat-cli chat --from-clipboard --copy "翻译成英文"
 • 进入交互模式：ai-chat-cli chat （然后输入问题）
 • 指定提供商：ai-chat-cli chat --provider free-oai "问题"
+• 继续会话：ai-chat-cli chat --session <id>
+• 继续当前目录的会话：ai-chat-cli chat --continue
+• 预设对话：ai-chat-cli chat --seed convo.yaml
+• 对话预设：ai-chat-cli chat --preset brainstorm
+• 预填回复：ai-chat-cli chat --assistant-prefix "` + "```json" + `" "列出三种水果"
+
+--seed 从YAML文件加载初始对话（系统提示词、few-shot示例、预置的助手回复）：
+  title: "SQL助手"
+  system: "你把自然语言转换为SQL，只输出SQL"
+  messages:
+    - role: user
+      content: "查询所有用户"
+    - role: assistant
+      content: "SELECT * FROM users;"
+
+--preset 使用预设（内置 brainstorm、tutor、critic，或 ~/.ai-chat-cli/presets/<名称>.yaml），
+在初始对话之外还可以指定开场白、提供商、模型和温度：
+  description: "SQL助手"
+  system: "你把自然语言转换为SQL，只输出SQL"
+  greeting: "描述你想查询的数据，我来写SQL。"
+  model: "gpt-4o"
+  temperature: 0.2
 
 支持的提供商：
 • openai (官方API)
@@ -69,178 +184,502 @@ func runSimpleChat(cmd *cobra.Command, args []string) {
 	// 检查配置
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		fmt.Printf("❌ 配置加载失败: %v\n", err)
-		fmt.Println("💡 请先运行 'ai-chat-cli config init' 初始化配置")
+		fail(ExitConfig, i18n.T("config.load_failed"), err)
+		hint("%s", i18n.T("config.init_hint"))
 		return
 	}
 
-	// 如果没有指定提供商，尝试找到第一个可用的
-	if chatProvider == "" {
-		for name, providerCfg := range cfg.Providers {
-			if providerCfg.APIKey != "" {
-				chatProvider = name
-				fmt.Printf("💡 自动选择提供商: %s\n", name)
-				break
-			}
+	env := envFrom(cmd.Context())
+	rt := &chatRuntime{
+		cfg:             cfg,
+		statsMode:       resolveStatsMode(cfg.UI.Stats, chatStatsFlag()),
+		historyTurns:    chatHistoryTurns,
+		idleAction:      config.IdleExit,
+		copyReply:       chatCopy,
+		streamJSON:      chatStreamJSON,
+		printJSON:       chatJSON,
+		assistantPrefix: chatAssistantPrefix,
+		continueOutput:  chatContinueOutput,
+		notify:          chatNotify,
+		queue:           chatQueue,
+		dryRun:          env.dryRun,
+		fromClipboard:   chatFromClipboard,
+		stdinAs:         chatStdinAs,
+	}
+
+	switch rt.stdinAs {
+	case stdinAsContext, stdinAsPrompt, stdinAsIgnore:
+	default:
+		fail(ExitUsage, i18n.T("chat.stdin_as_invalid"), rt.stdinAs)
+		return
+	}
+	if rt.stdinAs == stdinAsPrompt && len(args) > 0 {
+		fail(ExitUsage, "%s", i18n.T("chat.stdin_as_prompt_args"))
+		return
+	}
+
+	// 未指定 --history-turns 时使用配置，配置为0表示不限制
+	if rt.historyTurns < 0 && cfg.Advanced.HistoryTurns > 0 {
+		rt.historyTurns = cfg.Advanced.HistoryTurns
+	}
+	for _, role := range cfg.Advanced.HistoryRoles {
+		if role != "user" && role != "assistant" {
+			ui.Warn(i18n.T("chat.history_role_invalid"), role)
+			continue
+		}
+		rt.historyRoles = append(rt.historyRoles, role)
+	}
+	rt.replyLanguage = cfg.Advanced.ReplyLanguage
+	if chatReplyLanguage != "" {
+		rt.replyLanguage = chatReplyLanguage
+	}
+	if rt.memory, err = memory.Open(memoryProfile(cfg)); err != nil {
+		ui.Warn(i18n.T("chat.memory_unavailable"), err)
+	}
+
+	if chatPipelineSpec != "" {
+		if chatRoute != "" {
+			fail(ExitUsage, "%s", i18n.T("chat.pipeline_with_route"))
+			return
+		}
+		if rt.pipeline, err = parsePipeline(chatPipelineSpec); err != nil {
+			fail(ExitUsage, "%v", err)
+			return
 		}
 	}
 
-	// 获取指定的提供商配置
-	providerCfg, exists := cfg.Providers[chatProvider]
-	if !exists {
-		fmt.Printf("❌ 提供商 '%s' 未找到\n", chatProvider)
-		fmt.Println("📋 可用的提供商:")
-		for name := range cfg.Providers {
-			fmt.Printf("  • %s\n", name)
+	if rt.streamJSON && rt.pipeline != nil {
+		fail(ExitUsage, "%s", i18n.T("chat.stream_json_with_pipeline"))
+		return
+	}
+
+	if chatSamples < 1 || chatSamples > maxSamples {
+		fail(ExitUsage, i18n.T("chat.samples_range"), maxSamples)
+		return
+	}
+	rt.samples = chatSamples
+	if chatBestOf != "" {
+		if rt.samples < 2 {
+			fail(ExitUsage, "%s", i18n.T("chat.best_of_needs_n"))
+			return
+		}
+		if rt.judge, err = parseBestOf(chatBestOf); err != nil {
+			fail(ExitUsage, "%v", err)
+			return
 		}
+	}
+	if rt.samples > 1 && rt.pipeline != nil {
+		fail(ExitUsage, "%s", i18n.T("chat.samples_with_pipeline"))
 		return
 	}
 
-	if providerCfg.APIKey == "" {
-		fmt.Printf("❌ 提供商 '%s' 的API密钥未设置\n", chatProvider)
-		fmt.Printf("💡 请运行以下命令设置API密钥：\n")
-		fmt.Printf("   ai-chat-cli config set providers.%s.api_key YOUR_API_KEY\n", chatProvider)
+	if chatSeedFile != "" && chatSessionID != "" {
+		fail(ExitUsage, "%s", i18n.T("chat.seed_with_session"))
 		return
 	}
 
-	fmt.Printf("🚀 使用提供商: %s\n", chatProvider)
-	if providerCfg.BaseURL != "" && providerCfg.BaseURL != "https://api.openai.com/v1" {
-		fmt.Printf("🌐 API地址: %s\n", providerCfg.BaseURL)
+	var seed *template.Seed
+	var preset *template.Preset
+	if chatSeedFile != "" {
+		if seed, err = template.LoadSeed(chatSeedFile); err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+	} else if chatPreset != "" {
+		if preset, err = template.LoadPreset(chatPreset); err != nil {
+			fail(ExitUsage, "%v", err)
+			return
+		}
+		seed = preset.Seed()
+		rt.model, rt.greeting = preset.Model, preset.Greeting
 	}
-	if providerCfg.Model != "" {
-		fmt.Printf("🤖 使用模型: %s\n", providerCfg.Model)
+	if seed != nil {
+		for _, m := range seed.Conversation() {
+			rt.seed = append(rt.seed, Message{Role: m.Role, Content: m.Content})
+		}
 	}
 
-	// 初始化对话历史
-	var conversationHistory []Message
+	// 会话记录创建时的工作目录，chat --continue 据此找到当前项目的会话
+	workDir, _ := os.Getwd()
 
-	if len(args) > 0 {
-		// 单次对话模式
-		question := args[0]
-		err = askQuestionWithHistory(providerCfg, question, &conversationHistory)
+	// 继续已保存的会话时，默认使用会话原来的提供商
+	var resumed *session.Session
+	if chatSessionID != "" {
+		var ok bool
+		if resumed, ok = loadSession(chatSessionID); !ok {
+			return
+		}
+	}
+	if chatContinue {
+		store, err := openSessionStore()
 		if err != nil {
-			fmt.Printf("❌ 对话失败: %v\n", err)
+			fail(ExitError, "%v", err)
+			return
+		}
+		resumed, err = store.LatestInDir(workDir)
+		if errors.Is(err, session.ErrNotFound) {
+			fmt.Fprintln(os.Stderr, i18n.T("chat.continue_none", workDir))
+		} else if err != nil {
+			fail(ExitError, "%v", err)
+			return
 		}
-	} else {
-		// 交互模式
-		runInteractiveChatWithHistory(providerCfg, &conversationHistory)
 	}
-}
+	requested := chatProvider
+	if resumed != nil && requested == "" {
+		requested = resumed.Provider
+	}
+	if preset != nil && requested == "" {
+		requested = preset.Provider
+	}
 
-func askQuestionWithHistory(providerCfg config.ProviderConfig, question string, history *[]Message) error {
-	fmt.Print("🤖 AI: ")
+	// 选择提供商（未指定时自动选择第一个可用的）
+	name, providerCfg, ok := selectProvider(cfg, requested)
+	if !ok {
+		return
+	}
+	if rt.streamJSON && !requireCapabilities(name, providerCfg, providers.CapStreaming) {
+		return
+	}
+	rt.providerName = name
+	rt.stream = providerCapabilities(name, providerCfg).Has(providers.CapStreaming)
 
-	// 设置默认值
-	model := providerCfg.Model
-	if model == "" {
-		model = "gpt-3.5-turbo"
+	// 生成参数的优先级：命令行参数、预设、提供商配置，其余采样参数由提供商按配置补全
+	rt.temperature = defaultTemperature(providerCfg)
+	if preset != nil && preset.Temperature != nil {
+		rt.temperature = *preset.Temperature
+	}
+	if cmd.Flags().Changed("temperature") {
+		rt.temperature = chatTemperature
+	}
+	if cmd.Flags().Changed("top-p") {
+		rt.sampling.TopP = &chatTopP
 	}
 
-	maxTokens := providerCfg.MaxTokens
-	if maxTokens == 0 {
-		maxTokens = 2000
+	// 状态信息输出到标准错误，标准输出只包含AI的回复，便于脚本使用
+	fmt.Fprintln(os.Stderr, i18n.T("provider.using", name))
+	if providerCfg.BaseURL != "" && providerCfg.BaseURL != "https://api.openai.com/v1" {
+		fmt.Fprintln(os.Stderr, i18n.T("provider.base_url", providerCfg.BaseURL))
+	}
+	if rt.model != "" {
+		fmt.Fprintln(os.Stderr, i18n.T("provider.model", rt.model))
+	} else if providerCfg.Model != "" {
+		fmt.Fprintln(os.Stderr, i18n.T("provider.model", providerCfg.Model))
 	}
 
-	baseURL := providerCfg.BaseURL
-	if baseURL == "" {
-		baseURL = "https://api.openai.com/v1"
+	rt.provider = newProvider(env, cfg, name, providerCfg)
+	if rt.continueOutput {
+		rt.provider = newContinuingProvider(rt.provider)
+	}
+	if rt.pipeline == nil {
+		// 预设指定了模型时不按 routing.enabled 自动选择模型，--route 仍然有效
+		route := chatRoute
+		if rt.model != "" && route == "" {
+			route = routeOff
+		}
+		if rt.router, err = newModelRouter(rt.provider, cfg.Routing, route); err != nil {
+			fail(ExitUsage, "%v", err)
+			return
+		}
 	}
 
-	// 添加用户问题到历史
-	*history = append(*history, Message{Role: "user", Content: question})
+	// 演练模式不发送请求，不创建文件
+	if chatTeePath != "" && !rt.dryRun {
+		if rt.tee, err = openAnswerTee(chatTeePath, name, providerCfg.Model); err != nil {
+			fail(ExitError, i18n.T("chat.tee_failed"), chatTeePath, err)
+			return
+		}
+		defer rt.tee.close()
+		fmt.Fprintln(os.Stderr, i18n.T("chat.tee", chatTeePath))
+	}
 
-	// 构建请求（包含完整历史）
-	reqBody := ChatRequest{
-		Model:       model,
-		Messages:    *history, // 发送完整的对话历史
-		MaxTokens:   maxTokens,
-		Temperature: 0.7,
+	// 初始化对话历史
+	var conversationHistory []Message
+	if resumed != nil {
+		conversationHistory = sessionHistory(resumed)
+		fmt.Fprintln(os.Stderr, i18n.T("chat.resumed", resumed.Title, len(conversationHistory)/2))
+	}
+	if preset != nil {
+		conversationHistory = append(conversationHistory, rt.seed...)
+		fmt.Fprintln(os.Stderr, i18n.T("chat.preset_loaded", preset.Name))
+	} else if seed != nil {
+		conversationHistory = append(conversationHistory, rt.seed...)
+		fmt.Fprintln(os.Stderr, i18n.T("chat.seed_loaded", chatSeedFile, len(rt.seed)))
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("构建请求失败: %w", err)
+	// 启用历史保存或继续会话时记录对话
+	if cfg.Advanced.SaveHistory || resumed != nil || chatContinue {
+		store, err := openSessionStore()
+		if err != nil {
+			fail(ExitError, "%v", err)
+			return
+		}
+		if resumed == nil {
+			resumed = store.New(name, providerCfg.Model)
+			resumed.Dir = workDir
+			if seed != nil {
+				resumed.Title = seed.Title
+			}
+		}
+		rt.session = &chatSessionState{store: store, current: resumed}
+		if cfg.Advanced.TitleModel != config.TitleModelOff {
+			rt.session.provider = rt.provider
+			rt.session.titleModel = cfg.Advanced.TitleModel
+		}
+		defer rt.session.wait()
 	}
 
-	// 创建HTTP请求
-	apiURL := baseURL + "/chat/completions"
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("创建请求失败: %w", err)
+	if len(args) > 0 || rt.fromClipboard || (stdinIsPipe() && rt.stdinAs != stdinAsIgnore) {
+		// 单次对话模式
+		question, ok := rt.chatQuestion(args)
+		if !ok {
+			return
+		}
+		conversationHistory = append(conversationHistory, Message{Role: "user", Content: question})
+		req := rt.chatRequest(cmd.Context(), question, "", conversationHistory)
+		err = rt.sendQuestion(cmd.Context(), question, req, &conversationHistory)
+		if cmd.Context().Err() != nil {
+			fmt.Fprintln(os.Stderr)
+			if n := len(conversationHistory); n > 0 && conversationHistory[n-1].Truncated {
+				rt.session.sync(conversationHistory)
+				fmt.Fprintln(os.Stderr, i18n.T("chat.partial_kept"))
+				setExitCode(ExitInterrupted)
+				return
+			}
+			fail(ExitInterrupted, "%s", i18n.T("chat.interrupted"))
+			return
+		}
+		if err != nil && providers.IsNetworkUnreachable(err) {
+			if rt.queue {
+				rt.queueRequest(req, err)
+				return
+			}
+			rt.notifyAnswer(question, err)
+			fail(errorExitCode(err), i18n.T("chat.failed"), err)
+			hint("%s", i18n.T("chat.offline_hint"))
+			return
+		}
+		rt.notifyAnswer(question, err)
+		if err != nil {
+			fail(errorExitCode(err), i18n.T("chat.failed"), err)
+			return
+		}
+		rt.session.sync(conversationHistory)
+	} else if rt.streamJSON {
+		fail(ExitUsage, "%s", i18n.T("chat.stream_json_interactive"))
+	} else if rt.continueOutput {
+		fail(ExitUsage, "%s", i18n.T("chat.continue_output_repl"))
+	} else if rt.printJSON {
+		fail(ExitUsage, "%s", i18n.T("chat.json_interactive"))
+	} else if rt.samples > 1 {
+		fail(ExitUsage, "%s", i18n.T("chat.samples_interactive"))
+	} else {
+		// 交互模式
+		rt.interactive = true
+		rt.idleTimeout = chatIdleTimeout
+		if !cmd.Flags().Changed("idle-timeout") {
+			rt.idleTimeout = time.Duration(cfg.UI.IdleTimeout) * time.Minute
+		}
+		if rt.idleTimeout > 0 {
+			rt.idleAction = rt.resolveIdleAction()
+		}
+		rt.paging = !chatNoPager
+		rt.runInteractiveChatWithHistory(cmd.Context(), &conversationHistory)
 	}
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+providerCfg.APIKey)
+// askQuestionWithHistory 发送问题并输出回复，route 为本条消息指定的模型档位（fast、smart），为空时按路由设置选择
+func (rt *chatRuntime) askQuestionWithHistory(ctx context.Context, question, route string, history *[]Message) error {
+	*history = append(*history, Message{Role: "user", Content: question})
+	return rt.sendQuestion(ctx, question, rt.chatRequest(ctx, question, route, *history), history)
+}
 
-	// 发送请求
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("请求发送失败: %w", err)
+// chatRequest 构建本轮问题的请求：按路由或预设选择模型，包含按 --history-turns 和 history_roles
+// 选择的历史、程序管理的指令以及温度和采样参数。history 的最后一条为本轮问题
+func (rt *chatRuntime) chatRequest(ctx context.Context, question, route string, history []Message) *providers.ChatRequest {
+	model := rt.router.model(ctx, question, route)
+	if model == "" {
+		model = rt.model
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API返回错误 %d: %s", resp.StatusCode, string(body))
+	var messages []providers.Message
+	for _, m := range rt.requestHistory(history) {
+		messages = append(messages, providers.Message{Role: m.Role, Content: m.Content})
 	}
-
-	// 解析响应
-	var chatResp ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return fmt.Errorf("解析响应失败: %w", err)
+	return &providers.ChatRequest{
+		Messages:        rt.withDirectives(messages),
+		Model:           model,
+		Temperature:     rt.temperature,
+		Sampling:        rt.sampling,
+		AssistantPrefix: rt.assistantPrefix,
 	}
+}
 
-	if len(chatResp.Choices) == 0 {
-		return fmt.Errorf("API返回空响应")
+// sendQuestion 发送已构建的请求并输出回复，history 的最后一条为本轮问题，失败时移除
+func (rt *chatRuntime) sendQuestion(ctx context.Context, question string, req *providers.ChatRequest, history *[]Message) error {
+	terminal := stdoutIsTerminal() && !rt.streamJSON && !rt.printJSON
+	if terminal && !rt.dryRun {
+		fmt.Print(ui.Styled(ui.ElemAI, i18n.T("chat.ai")))
 	}
 
-	// print response
-	response := chatResp.Choices[0].Message.Content
-	out, err := glamour.Render(response, "dark")
+	timer := providers.StartTimer()
+	var chatResp *providers.ChatResponse
+	var samples []*providers.ChatResponse // 未选出最好的回答时显示所有回答
+	var err error
+	if rt.pipeline != nil {
+		chatResp, err = rt.pipeline.chat(ctx, rt.provider, req)
+	} else if rt.samples > 1 {
+		chatResp, samples, err = rt.bestOf(ctx, question, req)
+	} else if rt.streamJSON {
+		chatResp, err = streamJSON(ctx, rt.provider, req, timer)
+	} else if rt.stream {
+		// 边接收边拼接，回复完整后再按下面的方式输出
+		chatResp, err = receiveStream(ctx, rt.provider, req, timer, providers.NewStreamUsage(req), nil)
+		if chatResp != nil && chatResp.Model == "" {
+			chatResp.Model = rt.cfg.Providers[rt.providerName].Model
+		}
+	} else {
+		chatResp, err = rt.provider.Chat(ctx, req)
+	}
 	if err != nil {
-		fmt.Println(aurora.Red(err))
-		return nil
+		if chatResp != nil && ctx.Err() != nil {
+			// 流式回复被中断时保留已收到的部分和对应的用量，不丢弃较长的回答
+			if !rt.streamJSON && !rt.printJSON {
+				fmt.Println(chatResp.Content)
+			}
+			usage := chatResp.Usage
+			*history = append(*history, Message{Role: "assistant", Content: chatResp.Content, Usage: &usage,
+				Stats: timer.Stop(usage.CompletionTokens), Truncated: true})
+			return err
+		}
+		// 请求失败时移除未得到回复的问题，保持历史中的问答成对
+		*history = (*history)[:len(*history)-1]
+		return err
+	}
+	usage := chatResp.Usage
+	stats := timer.Stop(usage.CompletionTokens)
+
+	// 终端中渲染Markdown，输出被重定向时保留原文。显示所有回答时历史中只保存第一个
+	response := chatResp.Content
+	display := response
+	if len(samples) > 0 {
+		display = formatSamples(samples)
+	}
+	if rt.streamJSON {
+		// 回复已按数据块输出
+	} else if rt.printJSON && len(samples) > 0 {
+		for _, s := range samples {
+			printAnswerJSON(s)
+		}
+	} else if rt.printJSON {
+		printAnswerJSON(chatResp)
+	} else if terminal {
+		out, err := ui.Markdown(display)
+		if err != nil {
+			fmt.Println(ui.Colors().Red(err))
+			return nil
+		}
+		if rt.paging {
+			ui.Page(out)
+		} else {
+			fmt.Println(out)
+		}
+	} else {
+		fmt.Println(display)
 	}
-	fmt.Println(out)
 
 	// 添加AI回复到历史
-	*history = append(*history, Message{Role: "assistant", Content: response})
+	*history = append(*history, Message{Role: "assistant", Content: response, Usage: &usage, Stats: stats})
+	rt.warnFinishReason(chatResp)
+	if err := rt.tee.write(question, chatResp); err != nil {
+		ui.Warn(i18n.T("chat.tee_failed"), rt.tee.file.Name(), err)
+	}
+	if rt.copyReply && !rt.dryRun {
+		if err := clipboard.WriteText(response); err != nil {
+			ui.Warn(i18n.T("chat.copy_failed"), err)
+		} else {
+			fmt.Fprintln(os.Stderr, i18n.T("chat.copied"))
+		}
+	}
 
 	// 显示使用统计
-	usage := chatResp.Usage
-	fmt.Printf("\n📊 Token使用: %d (输入: %d, 输出: %d) | 对话轮次: %d\n",
-		usage.TotalTokens, usage.PromptTokens, usage.CompletionTokens, len(*history)/2)
+	switch rt.statsMode {
+	case config.StatsOn:
+		fmt.Fprintf(os.Stderr, "\n%s\n", ui.Styled(ui.ElemStats, i18n.T("chat.usage",
+			usage.TotalTokens, usage.PromptTokens, usage.CompletionTokens, len(*history)/2)))
+	case config.StatsVerbose:
+		fmt.Fprintf(os.Stderr, "\n%s\n", ui.Styled(ui.ElemStats, i18n.T("chat.usage",
+			usage.TotalTokens, usage.PromptTokens, usage.CompletionTokens, len(*history)/2)+" | "+formatStats(stats)))
+	}
 
 	return nil
 }
 
-func runInteractiveChatWithHistory(providerCfg config.ProviderConfig, history *[]Message) {
-	fmt.Println("🤖 AI Chat CLI - 交互模式 (支持上下文记忆)")
-	fmt.Println("💡 输入问题开始对话")
-	fmt.Println("💡 特殊命令:")
-	fmt.Println("   • quit/exit - 退出程序")
-	fmt.Println("   • clear - 清屏")
-	fmt.Println("   • reset - 重置对话历史")
-	fmt.Println("   • history - 显示对话历史")
-	fmt.Println("   • help - 显示帮助")
-	fmt.Println("💡 如果输入出现问题，直接按回车重新输入")
+// runInteractiveChatWithHistory 交互模式。按 Ctrl+C 时取消进行中的请求，
+// 等已完成的对话保存后提示如何继续会话并退出
+func (rt *chatRuntime) runInteractiveChatWithHistory(ctx context.Context, history *[]Message) {
+	fmt.Println(i18n.T("chat.banner"))
+	fmt.Println(i18n.T("chat.start"))
+	fmt.Println(i18n.T("chat.commands"))
+	fmt.Println(i18n.T("chat.cmd_quit"))
+	fmt.Println(i18n.T("chat.cmd_clear"))
+	fmt.Println(i18n.T("chat.cmd_reset"))
+	fmt.Println(i18n.T("chat.cmd_history"))
+	fmt.Println(i18n.T("chat.cmd_undo"))
+	fmt.Println(i18n.T("chat.cmd_drop"))
+	fmt.Println(i18n.T("chat.cmd_redact"))
+	fmt.Println(i18n.T("chat.cmd_find"))
+	fmt.Println(i18n.T("chat.cmd_pin"))
+	fmt.Println(i18n.T("chat.cmd_diff"))
+	fmt.Println(i18n.T("chat.cmd_route"))
+	fmt.Println(i18n.T("chat.cmd_memory"))
+	fmt.Println(i18n.T("chat.cmd_models"))
+	fmt.Println(i18n.T("chat.cmd_continue"))
+	fmt.Println(i18n.T("chat.cmd_help"))
+	fmt.Println(i18n.T("chat.retry_hint"))
 	fmt.Println("---")
+	if rt.greeting != "" {
+		fmt.Printf("%s%s\n\n", ui.Styled(ui.ElemAI, i18n.T("chat.ai")), rt.greeting)
+	}
 
-	scanner := bufio.NewScanner(os.Stdin)
+	// busy 在处理问题期间持有，中断时等待已收到的回复保存完毕再退出
+	var busy sync.Mutex
+	go func() {
+		<-ctx.Done()
+		busy.Lock()
+		fmt.Println()
+		rt.endInteractiveChat()
+		rt.session.wait()
+		os.Exit(ExitInterrupted)
+	}()
+
+	reader := newLineReader(os.Stdin)
 
 	for {
-		fmt.Print("👤 你: ")
+		fmt.Print(ui.Styled(ui.ElemUser, i18n.T("chat.you")))
 
-		if !scanner.Scan() {
+		line, ok, idle := reader.next(rt.idleTimeout)
+		if idle {
+			fmt.Println()
+			if rt.idleAction == config.IdleLock {
+				if rt.lockChat(reader, history) {
+					continue
+				}
+			} else {
+				fmt.Println(i18n.T("chat.idle_exit", rt.idleTimeout))
+			}
+			rt.endInteractiveChat()
+			return
+		}
+		if !ok {
 			// 处理EOF或其他错误
-			if err := scanner.Err(); err != nil {
-				fmt.Printf("\n❌ 输入错误: %v\n", err)
+			if reader.err != nil {
+				fmt.Println()
+				fail(ExitError, i18n.T("chat.input_error"), reader.err)
 			}
 			break
 		}
 
-		input := strings.TrimSpace(scanner.Text())
+		input := strings.TrimSpace(line)
 
 		// 检查是否是空输入
 		if input == "" {
@@ -250,7 +689,7 @@ func runInteractiveChatWithHistory(providerCfg config.ProviderConfig, history *[
 		// 检查是否包含不可见字符或控制字符
 		cleanInput := cleanInput(input)
 		if cleanInput == "" {
-			fmt.Println("⚠️  输入包含无效字符，请重新输入")
+			ui.Warn("%s", i18n.T("chat.invalid_input"))
 			continue
 		}
 
@@ -258,63 +697,278 @@ func runInteractiveChatWithHistory(providerCfg config.ProviderConfig, history *[
 		lowerInput := strings.ToLower(cleanInput)
 		switch lowerInput {
 		case "quit", "exit":
-			fmt.Println("👋 再见！")
+			rt.endInteractiveChat()
 			return
 		case "clear":
-			fmt.Print("\033[2J\033[H") // ANSI清屏命令
-			fmt.Println("🤖 AI Chat CLI - 交互模式 (支持上下文记忆)")
-			fmt.Printf("💡 当前对话历史: %d 轮次\n", len(*history)/2)
-			fmt.Println("💡 输入问题开始对话，输入 'help' 查看命令")
+			ui.ClearScreen()
+			fmt.Println(i18n.T("chat.banner"))
+			fmt.Println(i18n.T("chat.history_count", len(*history)/2))
+			fmt.Println(i18n.T("chat.start_help"))
 			fmt.Println("---")
 			continue
 		case "reset":
-			*history = []Message{} // 清空对话历史
-			fmt.Println("🔄 对话历史已重置")
+			*history = append([]Message{}, rt.seed...) // 清空对话历史，保留初始对话
+			rt.session.reset()
+			fmt.Println(i18n.T("chat.reset_done"))
 			continue
 		case "history":
 			showHistory(*history)
 			continue
 		case "help":
-			fmt.Println("🆘 可用命令:")
-			fmt.Println("   • quit/exit - 退出程序")
-			fmt.Println("   • clear - 清屏")
-			fmt.Println("   • reset - 重置对话历史")
-			fmt.Println("   • history - 显示对话历史")
-			fmt.Println("   • help - 显示此帮助")
-			fmt.Println("   • 直接输入问题开始对话")
+			fmt.Println(i18n.T("chat.help_title"))
+			fmt.Println(i18n.T("chat.cmd_quit"))
+			fmt.Println(i18n.T("chat.cmd_clear"))
+			fmt.Println(i18n.T("chat.cmd_reset"))
+			fmt.Println(i18n.T("chat.help_history"))
+			fmt.Println(i18n.T("chat.help_undo"))
+			fmt.Println(i18n.T("chat.cmd_drop"))
+			fmt.Println(i18n.T("chat.help_redact"))
+			fmt.Println(i18n.T("chat.cmd_find"))
+			fmt.Println(i18n.T("chat.cmd_pin"))
+			fmt.Println(i18n.T("chat.cmd_diff"))
+			fmt.Println(i18n.T("chat.cmd_route"))
+			fmt.Println(i18n.T("chat.cmd_memory"))
+			fmt.Println(i18n.T("chat.cmd_models"))
+			fmt.Println(i18n.T("chat.cmd_continue"))
+			fmt.Println(i18n.T("chat.help_help"))
+			fmt.Println(i18n.T("chat.help_ask"))
 			continue
 		}
+		// /fast 和 /smart 为本条消息指定模型
+		route, question, routed := parseRouteOverride(cleanInput)
+		if !routed {
+			question = cleanInput
+			if strings.HasPrefix(cleanInput, "/") {
+				rt.runChatCommand(ctx, cleanInput, history, reader)
+				continue
+			}
+		}
 
 		// 显示清理后的输入（仅在有差异时）
 		if cleanInput != input {
-			fmt.Printf("📝 已清理输入: %s\n", cleanInput)
+			fmt.Println(i18n.T("chat.cleaned", cleanInput))
 		}
 
-		err := askQuestionWithHistory(providerCfg, cleanInput, history)
-		if err != nil {
-			fmt.Printf("❌ 对话失败: %v\n", err)
-			fmt.Println("💡 请检查网络连接或重试，输入 'help' 查看可用命令")
+		busy.Lock()
+		rt.answerInteractive(ctx, question, route, history)
+		busy.Unlock()
+		if ctx.Err() != nil {
+			// 由上面的goroutine退出
+			select {}
 		}
-		fmt.Println()
+	}
+}
+
+// answerInteractive 交互模式中回答一个问题并保存会话。请求被中断时保存已收到的部分回答，
+// 不再提示错误，由调用方退出
+func (rt *chatRuntime) answerInteractive(ctx context.Context, question, route string, history *[]Message) {
+	err := rt.askQuestionWithHistory(ctx, question, route, history)
+	if ctx.Err() != nil {
+		rt.session.sync(*history)
+		return
+	}
+	rt.notifyAnswer(question, err)
+	if err != nil {
+		fmt.Println(i18n.T("chat.failed_repl", err))
+		fmt.Println(i18n.T("chat.network_hint"))
+	} else {
+		rt.session.sync(*history)
+	}
+	fmt.Println()
+}
+
+// queueRequest 网络不可用时将未能发送的请求原样加入队列，包括选择的模型、指令和采样参数
+func (rt *chatRuntime) queueRequest(req *providers.ChatRequest, cause error) {
+	it, err := enqueueRequest(rt.providerName, req)
+	if err != nil {
+		fail(ExitError, i18n.T("chat.failed"), cause)
+		ui.Error("%v", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("chat.queued", it.ID))
+	setExitCode(ExitProvider)
+}
+
+// endInteractiveChat 退出交互模式前提示如何继续当前会话
+func (rt *chatRuntime) endInteractiveChat() {
+	if id := rt.session.resumeID(); id != "" {
+		fmt.Println(i18n.T("chat.resume_hint", id))
+	}
+	fmt.Println(i18n.T("chat.bye"))
+}
+
+// notifyAnswer 指定 --notify 时在回答完成或失败后响铃并发送桌面通知
+func (rt *chatRuntime) notifyAnswer(question string, err error) {
+	if !rt.notify || rt.dryRun {
+		return
+	}
+	notify.Bell()
+
+	message := i18n.T("chat.notify_done", session.DefaultTitle(question))
+	if err != nil {
+		message = i18n.T("chat.notify_failed", err)
+	}
+	if err := notify.Send("AI Chat CLI", message); err != nil {
+		ui.Warn("%v", err)
 	}
 }
 
 // showHistory 显示对话历史
 func showHistory(history []Message) {
 	if len(history) == 0 {
-		fmt.Println("📝 暂无对话历史")
+		fmt.Println(i18n.T("chat.history_empty"))
 		return
 	}
 
-	fmt.Println("📝 对话历史:")
+	fmt.Println(i18n.T("chat.history_title"))
 	for i, msg := range history {
-		if msg.Role == "user" {
-			fmt.Printf("  %d. 👤 你: %s\n", i/2+1, msg.Content)
-		} else if msg.Role == "assistant" {
-			fmt.Printf("     🤖 AI: %s\n", truncateString(msg.Content, 100))
+		switch msg.Role {
+		case "user":
+			fmt.Printf("  %d. %s%s\n", i+1, ui.Styled(ui.ElemUser, i18n.T("chat.you")), msg.Content)
+		case "assistant":
+			fmt.Printf("  %d. %s%s\n", i+1, ui.Styled(ui.ElemAI, i18n.T("chat.ai")), truncateString(msg.Content, 100))
+		default:
+			fmt.Printf("  %d. %s%s: %s\n", i+1, ui.Symbol("⚙️  ", ""), msg.Role, truncateString(msg.Content, 100))
+		}
+	}
+	fmt.Println(i18n.T("chat.history_total", len(history)/2))
+}
+
+// 管道输入的用法，见 --stdin-as
+const (
+	stdinAsContext = "context" // 问题参数是指令，管道输入作为附加的上下文；没有问题参数时作为问题
+	stdinAsPrompt  = "prompt"  // 管道输入就是问题
+	stdinAsIgnore  = "ignore"  // 不读取管道输入，没有问题参数时进入交互模式
+)
+
+// chatContextFormat 将管道输入作为上下文附加在指令之后
+const chatContextFormat = "%s\n\n<context>\n%s\n</context>"
+
+// chatQuestion 根据问题参数和管道输入确定单次对话的问题，失败时打印错误并返回false
+func (rt *chatRuntime) chatQuestion(args []string) (string, bool) {
+	var instruction, input string
+	if len(args) > 0 {
+		instruction = strings.TrimSpace(args[0])
+	}
+	if rt.fromClipboard {
+		// 剪贴板与管道输入的用法相同，此时不读取管道输入
+		text, err := clipboard.ReadText()
+		if err != nil {
+			fail(ExitError, i18n.T("chat.clipboard_failed"), err)
+			return "", false
+		}
+		if input = strings.TrimSpace(text); input == "" {
+			fail(ExitUsage, "%s", i18n.T("chat.clipboard_empty"))
+			return "", false
 		}
+	} else if stdinIsPipe() && rt.stdinAs != stdinAsIgnore {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fail(ExitError, i18n.T("chat.stdin_failed"), err)
+			return "", false
+		}
+		input = strings.TrimSpace(string(data))
+	}
+
+	question := instruction
+	switch {
+	case input == "":
+	case instruction == "":
+		question = input
+	default:
+		question = fmt.Sprintf(chatContextFormat, instruction, input)
+	}
+
+	if question == "" {
+		fail(ExitUsage, "%s", i18n.T("chat.empty_input"))
+		return "", false
+	}
+	return question, true
+}
+
+// requestHistory 选择发送给模型的消息：最近 historyTurns 轮对话中 historyRoles 角色的消息，
+// 以及所有 system 消息和最后一条（当前问题）
+func (rt *chatRuntime) requestHistory(history []Message) []Message {
+	previous := history[:len(history)-1]
+
+	// 从后往前数用户消息，确定保留的最早一轮对话的起点
+	start := 0
+	if rt.historyTurns >= 0 {
+		start = len(previous)
+		turns := 0
+		for i := len(previous) - 1; i >= 0; i-- {
+			if previous[i].Role != "user" {
+				continue
+			}
+			if turns++; turns > rt.historyTurns {
+				break
+			}
+			start = i
+		}
+	}
+
+	selected := make([]Message, 0, len(history))
+	for i, m := range previous {
+		if m.Role == "system" || (i >= start && rt.historyRoleAllowed(m.Role)) {
+			selected = append(selected, m)
+		}
+	}
+	return append(selected, history[len(history)-1])
+}
+
+// historyRoleAllowed 判断该角色的历史消息是否需要发送
+func (rt *chatRuntime) historyRoleAllowed(role string) bool {
+	if len(rt.historyRoles) == 0 {
+		return true
+	}
+	for _, r := range rt.historyRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// chatStatsFlag --verbose-stats、--no-stats 和 --stats 指定的用量统计显示方式，都未指定时为空
+func chatStatsFlag() string {
+	switch {
+	case chatVerboseStats:
+		return config.StatsVerbose
+	case chatNoStats:
+		return config.StatsOff
+	case chatStats:
+		return config.StatsOn
+	}
+	return ""
+}
+
+// resolveStatsMode 确定用量统计的显示方式，命令行参数 requested 优先于配置文件中的 ui.stats
+func resolveStatsMode(configured, requested string) string {
+	if requested != "" {
+		return requested
+	}
+
+	switch configured {
+	case "", config.StatsOn:
+		return config.StatsOn
+	case config.StatsOff, config.StatsVerbose:
+		return configured
+	default:
+		ui.Warn(i18n.T("stats.unknown_mode"), configured)
+		return config.StatsOn
+	}
+}
+
+// formatStats 格式化耗时和输出速度，用于显示在用量统计之后
+func formatStats(stats *providers.Stats) string {
+	parts := []string{i18n.T("stats.latency", float64(stats.LatencyMs)/1000)}
+	if stats.FirstTokenMs > 0 {
+		parts = append(parts, i18n.T("stats.first_token", float64(stats.FirstTokenMs)/1000))
+	}
+	if stats.TokensPerSecond > 0 {
+		parts = append(parts, i18n.T("stats.speed", stats.TokensPerSecond))
 	}
-	fmt.Printf("📊 总计 %d 轮对话\n", len(history)/2)
+	return strings.Join(parts, " | ")
 }
 
 // truncateString 截断长字符串用于显示
@@ -359,4 +1013,35 @@ func init() {
 
 	// 添加提供商选择参数
 	simpleChatCmd.Flags().StringVarP(&chatProvider, "provider", "p", "", "指定AI提供商 (如: openai, free-oai)")
+	simpleChatCmd.Flags().StringVarP(&chatSessionID, "session", "s", "", "继续指定ID的已保存会话")
+	simpleChatCmd.Flags().BoolVarP(&chatContinue, "continue", "c", false, "继续在当前目录中最近的会话，没有时新建一个与当前目录关联的会话")
+	simpleChatCmd.Flags().StringVar(&chatAssistantPrefix, "assistant-prefix", "", "预填的回复开头，模型从这里继续生成（如 ```json）")
+	simpleChatCmd.Flags().StringVar(&chatSeedFile, "seed", "", "从YAML文件加载初始对话（系统提示词和few-shot示例）")
+	simpleChatCmd.Flags().StringVar(&chatPreset, "preset", "", "使用对话预设（系统提示词、开场白、模型和温度），内置 brainstorm、tutor、critic")
+	simpleChatCmd.MarkFlagsMutuallyExclusive("continue", "session", "seed", "preset")
+	simpleChatCmd.Flags().BoolVar(&chatStats, "stats", false, "回复后显示Token用量（覆盖配置中的 ui.stats）")
+	simpleChatCmd.Flags().BoolVar(&chatNoStats, "no-stats", false, "不显示Token用量，便于脚本使用")
+	simpleChatCmd.Flags().BoolVar(&chatVerboseStats, "verbose-stats", false, "显示Token用量以及耗时、首字时间和输出速度")
+	simpleChatCmd.MarkFlagsMutuallyExclusive("stats", "no-stats", "verbose-stats")
+	simpleChatCmd.Flags().IntVar(&chatHistoryTurns, "history-turns", -1, "每次请求最多发送的历史对话轮数，0 表示不发送历史（覆盖 advanced.history_turns）")
+	simpleChatCmd.Flags().StringVar(&chatRoute, "route", "", "按问题复杂度选择模型: auto（自动）、fast、smart、off（使用默认模型），默认按 routing.enabled")
+	simpleChatCmd.Flags().StringVar(&chatPipelineSpec, "pipeline", "", "先起草再修订: draft=<便宜的模型>,refine=<更强的模型>，适合较长的回答")
+	simpleChatCmd.Flags().BoolVar(&chatQueue, "queue", false, "网络不可用时将问题加入队列，联网后由 queue flush 发送（仅单次对话）")
+	simpleChatCmd.Flags().StringVar(&chatTeePath, "tee", "", "同时将回答的Markdown原文和元信息（提供商、模型、时间、用量）写入文件，交互模式中依次追加")
+	simpleChatCmd.Flags().BoolVar(&chatStreamJSON, "stream-json", false, `流式输出JSON Lines: 每段内容一行 {"type":"delta","content":"..."}，结束时输出用量 {"type":"usage",...}（仅单次对话）`)
+	simpleChatCmd.Flags().BoolVar(&chatContinueOutput, "continue-output", false, "回答因达到 max_tokens 上限被截断时自动请求剩余部分并拼接（仅单次对话）")
+	simpleChatCmd.Flags().BoolVar(&chatJSON, "json", false, "以一行JSON输出回答（content、model、usage、finish_reason），便于脚本按结束原因处理（仅单次对话）")
+	simpleChatCmd.MarkFlagsMutuallyExclusive("json", "stream-json")
+	simpleChatCmd.Flags().IntVar(&chatSamples, "n", 1, "请求多个回答（服务不支持 n 参数时并发发送多个请求），依次显示或由 --best-of 选出最好的一个（仅单次对话）")
+	simpleChatCmd.Flags().StringVar(&chatBestOf, "best-of", "", "由评审模型从 --n 的回答中选出最好的一个，格式为 judge=<模型>，如 judge=gpt-4o-mini")
+	simpleChatCmd.MarkFlagsMutuallyExclusive("n", "stream-json")
+	simpleChatCmd.Flags().DurationVar(&chatIdleTimeout, "idle-timeout", 0, "交互模式空闲超过该时间后保存会话并退出（或按 ui.idle_action 锁定），0 表示不限制（覆盖 ui.idle_timeout）")
+	simpleChatCmd.Flags().BoolVar(&chatNoPager, "no-pager", false, "交互模式中超过一屏的回答不交给 $PAGER（默认 less -R）分页显示")
+	simpleChatCmd.Flags().BoolVar(&chatNotify, "notify", false, "回答完成或失败时响铃并发送桌面通知，便于在其他窗口等待较长的回答")
+	simpleChatCmd.Flags().BoolVar(&chatFromClipboard, "from-clipboard", false, "读取剪贴板中的文本，与管道输入一样作为问题的上下文（没有问题参数时作为问题）")
+	simpleChatCmd.Flags().BoolVar(&chatCopy, "copy", false, "将回答的原文复制到剪贴板，交互模式中每次回答后复制")
+	simpleChatCmd.Flags().Float64Var(&chatTemperature, "temperature", 0.7, "温度参数（覆盖预设和提供商配置的 temperature）")
+	simpleChatCmd.Flags().Float64Var(&chatTopP, "top-p", 1, "核采样参数 top_p（覆盖提供商配置的 top_p）")
+	simpleChatCmd.Flags().StringVar(&chatReplyLanguage, "reply-language", "", "回答使用的语言（如 zh、en、ja），auto 表示与问题相同，off 表示不指定（覆盖 advanced.reply_language）")
+	simpleChatCmd.Flags().StringVar(&chatStdinAs, "stdin-as", stdinAsContext, "管道输入的用法: context（作为问题的上下文）、prompt（作为问题）、ignore（不读取）")
 }
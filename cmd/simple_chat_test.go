@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"ai-chat-cli/internal/config"
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+)
+
+// stallingProvider 流式回复发送一段内容后停住，直到请求被取消
+type stallingProvider struct {
+	providers.Provider
+	sent chan struct{}
+}
+
+func (p *stallingProvider) GetName() string { return "stalling" }
+
+func (p *stallingProvider) ChatStream(ctx context.Context, req *providers.ChatRequest) (<-chan providers.StreamChunk, error) {
+	out := make(chan providers.StreamChunk)
+	go func() {
+		defer close(out)
+		out <- providers.StreamChunk{Content: "一个很长的回答的前半部分"}
+		close(p.sent)
+		<-ctx.Done()
+		out <- providers.StreamChunk{Error: ctx.Err()}
+	}()
+	return out, nil
+}
+
+func TestAnswerInteractiveKeepsPartialReplyOnCancel(t *testing.T) {
+	store := session.NewStore(t.TempDir())
+	current := store.New("stalling", "")
+	provider := &stallingProvider{sent: make(chan struct{})}
+	rt := &chatRuntime{
+		cfg:          &config.Config{},
+		providerName: "stalling",
+		provider:     provider,
+		session:      &chatSessionState{store: store, current: current},
+		historyTurns: -1,
+		stream:       true,
+		interactive:  true,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-provider.sent
+		cancel()
+	}()
+
+	var history []Message
+	rt.answerInteractive(ctx, "写一篇长文", "", &history)
+
+	saved, err := store.Get(current.ID)
+	if err != nil {
+		t.Fatalf("读取会话失败: %v", err)
+	}
+	if len(saved.Messages) != 2 {
+		t.Fatalf("会话有 %d 条消息，应为问题和部分回答共2条", len(saved.Messages))
+	}
+	reply := saved.Messages[1]
+	if reply.Role != "assistant" || reply.Content != "一个很长的回答的前半部分" {
+		t.Errorf("保存的回答为 %s %q", reply.Role, reply.Content)
+	}
+	if !reply.Truncated {
+		t.Error("部分回答没有标记为 truncated")
+	}
+	if reply.Usage == nil || reply.Usage.CompletionTokens == 0 {
+		t.Errorf("部分回答没有记录用量: %+v", reply.Usage)
+	}
+}
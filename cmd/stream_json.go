@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// streamEvent --stream-json 输出的一行JSON，type 为 delta（增量内容）、usage（结束时的用量和结束原因）或 error
+type streamEvent struct {
+	Type             string `json:"type"`
+	Content          string `json:"content,omitempty"`
+	Model            string `json:"model,omitempty"`
+	PromptTokens     int    `json:"prompt_tokens,omitempty"`
+	CompletionTokens int    `json:"completion_tokens,omitempty"`
+	TotalTokens      int    `json:"total_tokens,omitempty"`
+	Estimated        bool   `json:"estimated,omitempty"` // 提供商没有返回用量，按内容估算
+	FinishReason     string `json:"finish_reason,omitempty"`
+	Code             string `json:"code,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// streamJSON 发送流式请求，每收到一段内容向标准输出写一行 delta 事件，结束时写 usage 事件，失败时写 error 事件。
+// 返回拼接后的完整回复，用于保存对话历史。被中断时同时返回已收到的部分回复和错误
+func streamJSON(ctx context.Context, provider providers.Provider, req *providers.ChatRequest, timer *providers.Timer) (*providers.ChatResponse, error) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+
+	usage := providers.NewStreamUsage(req)
+	resp, err := receiveStream(ctx, provider, req, timer, usage, func(content string) {
+		enc.Encode(streamEvent{Type: "delta", Content: content})
+	})
+	if err != nil {
+		enc.Encode(errorEvent(err))
+		return resp, err
+	}
+
+	enc.Encode(streamEvent{
+		Type:             "usage",
+		Model:            req.Model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      resp.Usage.TotalTokens,
+		Estimated:        usage.Estimated(),
+		FinishReason:     resp.FinishReason,
+	})
+	return resp, nil
+}
+
+// receiveStream 发送流式请求并拼接收到的内容，每收到一段内容调用一次 onContent（可以为nil），用量累计在 usage 中。
+// 返回完整的回复；被中断时同时返回已收到的部分回复（没有收到内容时为nil）和错误
+func receiveStream(ctx context.Context, provider providers.Provider, req *providers.ChatRequest, timer *providers.Timer,
+	usage *providers.StreamUsage, onContent func(string)) (*providers.ChatResponse, error) {
+	chunks, err := provider.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	var streamErr error
+	var finishReason string
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			streamErr = chunk.Error
+			continue
+		}
+		if streamErr != nil {
+			continue
+		}
+		usage.Add(chunk)
+		if chunk.Done {
+			finishReason = chunk.FinishReason
+		}
+		if chunk.Content == "" {
+			continue
+		}
+		timer.FirstToken()
+		content.WriteString(chunk.Content)
+		if onContent != nil {
+			onContent(chunk.Content)
+		}
+	}
+	if streamErr == nil {
+		streamErr = ctx.Err()
+	}
+	if streamErr != nil {
+		if ctx.Err() != nil && content.Len() > 0 {
+			return &providers.ChatResponse{Content: content.String(), Model: req.Model, Usage: usage.Usage()}, streamErr
+		}
+		return nil, streamErr
+	}
+	return &providers.ChatResponse{Content: content.String(), Model: req.Model, Usage: usage.Usage(), FinishReason: finishReason}, nil
+}
+
+// errorEvent 根据错误生成 error 事件，提供商错误带上错误码
+func errorEvent(err error) streamEvent {
+	ev := streamEvent{Type: "error", Error: err.Error()}
+	var pe *providers.ProviderError
+	if errors.As(err, &pe) {
+		ev.Code = pe.Code
+	}
+	return ev
+}
+
+// printAnswerJSON chat --json 输出一行完整的回答：内容、模型、用量和结束原因（stop、length、content_filter、refusal 等）
+func printAnswerJSON(resp *providers.ChatResponse) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.Encode(resp)
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/pkg/providers"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/html"
+)
+
+var (
+	summarizeProvider  string
+	summarizeFile      string
+	summarizeURL       string
+	summarizeLength    string
+	summarizeFormat    string
+	summarizeChunkSize int
+)
+
+// summaryLengths 摘要长度对应的提示
+var summaryLengths = map[string]string{
+	"short":  "very concise, at most 5 sentences or bullet points",
+	"medium": "moderately detailed, roughly 150-300 words",
+	"long":   "detailed, covering every major section, roughly 500-800 words",
+}
+
+// summaryFormats 摘要格式对应的提示
+var summaryFormats = map[string]string{
+	"bullets":   "a Markdown bullet list",
+	"paragraph": "plain prose paragraphs",
+	"outline":   "a hierarchical Markdown outline with headings",
+}
+
+// summarizeCmd represents the summarize command
+var summarizeCmd = &cobra.Command{
+	Use:   "summarize [文本]",
+	Short: "总结文件、网页或文本",
+	Long: `使用AI总结文件、网页或文本内容。
+
+超过上下文窗口的长文档会自动切分为多个块，先分别总结再合并（map-reduce）。
+
+示例:
+  ai-chat-cli summarize --file report.pdf --length short --format bullets
+  ai-chat-cli summarize --url https://example.com/article
+  cat notes.txt | ai-chat-cli summarize --format paragraph
+
+PDF文件需要系统中安装 pdftotext（poppler-utils）。`,
+	Run: runSummarize,
+}
+
+func runSummarize(cmd *cobra.Command, args []string) {
+	lengthHint, ok := summaryLengths[summarizeLength]
+	if !ok {
+		fail(ExitUsage, i18n.T("summarize.length_invalid"), summarizeLength)
+		return
+	}
+	formatHint, ok := summaryFormats[summarizeFormat]
+	if !ok {
+		fail(ExitUsage, i18n.T("summarize.format_invalid"), summarizeFormat)
+		return
+	}
+	if summarizeChunkSize <= 0 {
+		fail(ExitUsage, "%s", i18n.T("chunk.size_invalid"))
+		return
+	}
+
+	var text string
+	var err error
+	switch {
+	case summarizeURL != "":
+		text, err = fetchURLText(summarizeURL)
+	case strings.EqualFold(filepath.Ext(summarizeFile), ".pdf"):
+		text, err = readPDFText(summarizeFile)
+	default:
+		text, err = readInput(summarizeFile, args)
+	}
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+	if strings.TrimSpace(text) == "" {
+		fail(ExitError, "%s", i18n.T("summarize.empty"))
+		return
+	}
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), summarizeProvider)
+	if !ok {
+		return
+	}
+
+	summary, err := mapReduceSummarize(cmd.Context(), provider, text, summarizeChunkSize, lengthHint, formatHint)
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("summarize.failed"), err)
+		return
+	}
+
+	fmt.Println(strings.TrimSpace(summary))
+}
+
+// mapReduceSummarize 对超长文本分块总结后再合并，直到结果能放入单个块
+func mapReduceSummarize(ctx context.Context, provider providers.Provider, text string, chunkSize int, lengthHint, formatHint string) (string, error) {
+	chunks := splitChunks(text, chunkSize)
+	if len(chunks) == 1 {
+		resp, err := complete(ctx, provider, buildSummarizePrompt(lengthHint, formatHint, false), text, 0.3)
+		if err != nil {
+			return "", err
+		}
+		return resp.Content, nil
+	}
+
+	// map：分别总结每个块
+	partials := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		fmt.Fprintln(os.Stderr, i18n.T("summarize.chunk", i+1, len(chunks)))
+		resp, err := complete(ctx, provider, buildSummarizePrompt(summaryLengths["medium"], summaryFormats["bullets"], true), chunk, 0.3)
+		if err != nil {
+			return "", fmt.Errorf(i18n.T("summarize.chunk_failed"), i+1, err)
+		}
+		partials = append(partials, fmt.Sprintf("Part %d:\n%s", i+1, resp.Content))
+	}
+
+	// reduce：合并部分摘要，合并结果仍然过长时继续递归
+	merged := strings.Join(partials, "\n\n")
+	if len(merged) >= len(text) {
+		return "", errors.New(i18n.T("summarize.not_shorter"))
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("summarize.merging"))
+	return mapReduceSummarize(ctx, provider, merged, chunkSize, lengthHint, formatHint)
+}
+
+// buildSummarizePrompt 构建总结用的系统提示词
+func buildSummarizePrompt(lengthHint, formatHint string, partial bool) string {
+	var b strings.Builder
+	if partial {
+		b.WriteString("You are summarizing one part of a longer document. ")
+		b.WriteString("Capture the key facts, figures, decisions and conclusions of this part so they can be merged later.\n")
+	} else {
+		b.WriteString("You are an expert at summarizing documents. Summarize the user's text.\n")
+	}
+	fmt.Fprintf(&b, "The summary should be %s, formatted as %s.\n", lengthHint, formatHint)
+	b.WriteString("Write the summary in the same language as the source text. Output only the summary.")
+	return b.String()
+}
+
+// readPDFText 使用pdftotext提取PDF文件的文本
+func readPDFText(filename string) (string, error) {
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		return "", errors.New(i18n.T("summarize.pdftotext"))
+	}
+
+	out, err := exec.Command("pdftotext", "-layout", filename, "-").Output()
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("summarize.pdf_failed"), err)
+	}
+	return string(out), nil
+}
+
+// fetchURLText 下载网页并提取正文文本
+func fetchURLText(url string) (string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("summarize.download_failed"), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(i18n.T("summarize.download_status"), resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("summarize.read_failed"), err)
+	}
+
+	if !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return string(body), nil
+	}
+	return extractHTMLText(body), nil
+}
+
+// extractHTMLText 提取HTML中的可见文本，忽略脚本、样式等标签
+func extractHTMLText(body []byte) string {
+	var b strings.Builder
+	skip := 0
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(b.String())
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "script", "style", "noscript", "svg", "head":
+				skip++
+			case "p", "div", "br", "li", "h1", "h2", "h3", "h4", "h5", "h6", "tr", "section", "article":
+				b.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "script", "style", "noscript", "svg", "head":
+				if skip > 0 {
+					skip--
+				}
+			}
+		case html.TextToken:
+			if skip == 0 {
+				if text := strings.TrimSpace(string(tokenizer.Text())); text != "" {
+					b.WriteString(text)
+					b.WriteString(" ")
+				}
+			}
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(summarizeCmd)
+
+	summarizeCmd.Flags().StringVarP(&summarizeProvider, "provider", "p", "", "指定AI提供商")
+	summarizeCmd.Flags().StringVarP(&summarizeFile, "file", "f", "", "要总结的文件（支持文本和PDF）")
+	summarizeCmd.Flags().StringVarP(&summarizeURL, "url", "u", "", "要总结的网页地址")
+	summarizeCmd.Flags().StringVarP(&summarizeLength, "length", "l", "medium", "摘要长度: short, medium, long")
+	summarizeCmd.Flags().StringVar(&summarizeFormat, "format", "bullets", "摘要格式: bullets, paragraph, outline")
+	summarizeCmd.Flags().IntVar(&summarizeChunkSize, "chunk-size", 12000, "每个分块的最大字符数")
+}
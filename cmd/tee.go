@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+)
+
+// answerTee 将回答的Markdown原文连同元信息写入文件，终端照常显示回答。
+// 交互模式中每个回答依次追加到同一个文件
+type answerTee struct {
+	file     *os.File
+	provider string
+	model    string
+	entries  int
+}
+
+// openAnswerTee 创建（或清空）回答记录文件，不存在的目录会自动创建
+func openAnswerTee(path, provider, model string) (*answerTee, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("创建目录失败: %w", err)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("创建文件失败: %w", err)
+	}
+	return &answerTee{file: f, provider: provider, model: model}, nil
+}
+
+// write 写入一个回答，标题取自问题，元信息包括提供商、模型、时间和Token用量
+func (t *answerTee) write(question string, resp *providers.ChatResponse) error {
+	if t == nil {
+		return nil
+	}
+
+	var b strings.Builder
+	if t.entries > 0 {
+		b.WriteString("\n---\n\n")
+	}
+	fmt.Fprintf(&b, "# %s\n\n", session.DefaultTitle(question))
+	fmt.Fprintf(&b, "- 提供商: %s\n", t.provider)
+	model := resp.Model
+	if model == "" {
+		model = t.model
+	}
+	if model != "" {
+		fmt.Fprintf(&b, "- 模型: %s\n", model)
+	}
+	fmt.Fprintf(&b, "- 时间: %s\n", time.Now().Format("2006-01-02 15:04"))
+	if resp.Usage.TotalTokens > 0 {
+		fmt.Fprintf(&b, "- Token: %d（输入 %d，输出 %d）\n",
+			resp.Usage.TotalTokens, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	}
+	fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(resp.Content))
+
+	t.entries++
+	_, err := t.file.WriteString(b.String())
+	return err
+}
+
+// close 关闭回答记录文件
+func (t *answerTee) close() error {
+	if t == nil {
+		return nil
+	}
+	return t.file.Close()
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"ai-chat-cli/internal/i18n"
+	"ai-chat-cli/internal/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	translateProvider string
+	translateTo       string
+	translateFrom     string
+	translateGlossary string
+	translateFile     string
+)
+
+// translateCmd represents the translate command
+var translateCmd = &cobra.Command{
+	Use:   "translate [文本]",
+	Short: "翻译文本或文件",
+	Long: `使用AI翻译文本，自动识别源语言，并保留Markdown格式和代码块。
+
+输入来源（按优先级）：
+• --file 指定的文件
+• 标准输入：ai-chat-cli translate --to en < file.md
+• 命令行参数：ai-chat-cli translate --to en "你好，世界"
+
+术语表文件每行一条，格式为 "原文 = 译文"，以 # 开头的行为注释。`,
+	Run: runTranslate,
+}
+
+// fencedCodeBlockRe 匹配Markdown围栏代码块
+var fencedCodeBlockRe = regexp.MustCompile("(?ms)^[ \t]*```.*?^[ \t]*```[ \t]*$")
+
+func runTranslate(cmd *cobra.Command, args []string) {
+	text, err := readInput(translateFile, args)
+	if err != nil {
+		fail(ExitError, "%v", err)
+		return
+	}
+
+	glossary, err := loadGlossary(translateGlossary)
+	if err != nil {
+		fail(ExitError, i18n.T("translate.glossary_failed"), err)
+		return
+	}
+
+	provider, ok := loadProvider(envFrom(cmd.Context()), translateProvider)
+	if !ok {
+		return
+	}
+
+	from := translateFrom
+	if from == "" {
+		if from = detectLanguage(fencedCodeBlockRe.ReplaceAllString(text, "")); from != "" {
+			fmt.Fprintln(os.Stderr, i18n.T("translate.detected", from))
+		}
+	}
+
+	// 代码块不参与翻译，先替换为占位符
+	masked, blocks := maskCodeBlocks(text)
+
+	resp, err := complete(cmd.Context(), provider, buildTranslatePrompt(from, translateTo, glossary), masked, 0.3)
+	if err != nil {
+		fail(errorExitCode(err), i18n.T("translate.failed"), err)
+		return
+	}
+
+	result, missing := unmaskCodeBlocks(resp.Content, blocks)
+	if missing > 0 {
+		ui.Warn(i18n.T("translate.blocks_missing"), missing)
+	}
+
+	fmt.Print(result)
+	if !strings.HasSuffix(result, "\n") {
+		fmt.Println()
+	}
+}
+
+// buildTranslatePrompt 构建翻译用的系统提示词
+func buildTranslatePrompt(from, to string, glossary [][2]string) string {
+	var b strings.Builder
+	b.WriteString("You are a professional translator. ")
+	if from != "" {
+		fmt.Fprintf(&b, "Translate the user's text from %s to %s.\n", from, to)
+	} else {
+		fmt.Fprintf(&b, "Detect the language of the user's text and translate it to %s.\n", to)
+	}
+	b.WriteString("Rules:\n")
+	b.WriteString("- Output only the translation, without explanations or surrounding quotes.\n")
+	b.WriteString("- Preserve the Markdown structure exactly: headings, lists, tables, links, emphasis and line breaks.\n")
+	b.WriteString("- Do not translate inline code, URLs or file paths.\n")
+	b.WriteString("- Keep placeholders like @@CODE_BLOCK_0@@ unchanged and on their own lines.\n")
+
+	if len(glossary) > 0 {
+		b.WriteString("- Use the following glossary for these terms:\n")
+		for _, entry := range glossary {
+			fmt.Fprintf(&b, "  %s => %s\n", entry[0], entry[1])
+		}
+	}
+
+	return b.String()
+}
+
+// loadGlossary 加载术语表文件，每行格式为 "原文 = 译文"
+func loadGlossary(filename string) ([][2]string, error) {
+	if filename == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var glossary [][2]string
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		source, target, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf(i18n.T("translate.glossary_line"), lineNo)
+		}
+		glossary = append(glossary, [2]string{strings.TrimSpace(source), strings.TrimSpace(target)})
+	}
+
+	return glossary, scanner.Err()
+}
+
+// maskCodeBlocks 将围栏代码块替换为占位符，返回替换后的文本和原始代码块
+func maskCodeBlocks(text string) (string, []string) {
+	var blocks []string
+	masked := fencedCodeBlockRe.ReplaceAllStringFunc(text, func(block string) string {
+		placeholder := fmt.Sprintf("@@CODE_BLOCK_%d@@", len(blocks))
+		blocks = append(blocks, block)
+		return placeholder
+	})
+	return masked, blocks
+}
+
+// unmaskCodeBlocks 将占位符还原为原始代码块，返回还原后的文本和未找到的占位符数量
+func unmaskCodeBlocks(text string, blocks []string) (string, int) {
+	missing := 0
+	for i, block := range blocks {
+		placeholder := fmt.Sprintf("@@CODE_BLOCK_%d@@", i)
+		if !strings.Contains(text, placeholder) {
+			missing++
+			continue
+		}
+		text = strings.Replace(text, placeholder, block, 1)
+	}
+	return text, missing
+}
+
+// detectLanguage 根据文字的Unicode脚本粗略识别语言，无法确定时返回空字符串
+func detectLanguage(text string) string {
+	counts := map[string]int{}
+	total := 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			counts["Japanese"]++
+		case unicode.Is(unicode.Hangul, r):
+			counts["Korean"]++
+		case unicode.Is(unicode.Han, r):
+			counts["Chinese"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["Russian"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["Arabic"]++
+		case unicode.Is(unicode.Latin, r):
+			counts["Latin"]++
+		default:
+			continue
+		}
+		total++
+	}
+
+	if total == 0 {
+		return ""
+	}
+
+	// 日文中常夹杂汉字，出现假名即视为日文
+	if counts["Japanese"] > 0 && counts["Japanese"]*10 >= counts["Chinese"] {
+		return "Japanese"
+	}
+
+	best, bestCount := "", 0
+	for lang, count := range counts {
+		if count > bestCount {
+			best, bestCount = lang, count
+		}
+	}
+
+	// 拉丁字母无法区分具体语言，交给模型识别
+	if best == "Latin" || bestCount*2 < total {
+		return ""
+	}
+	return best
+}
+
+func init() {
+	rootCmd.AddCommand(translateCmd)
+
+	translateCmd.Flags().StringVarP(&translateProvider, "provider", "p", "", "指定AI提供商")
+	translateCmd.Flags().StringVar(&translateTo, "to", "en", "目标语言 (如: en, zh, ja)")
+	translateCmd.Flags().StringVar(&translateFrom, "from", "", "源语言，默认自动识别")
+	translateCmd.Flags().StringVarP(&translateGlossary, "glossary", "g", "", "术语表文件路径")
+	translateCmd.Flags().StringVarP(&translateFile, "file", "f", "", "要翻译的文件")
+}
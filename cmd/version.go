@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"time"
+
+	"ai-chat-cli/internal/ui"
+	"ai-chat-cli/internal/update"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // Version 应用程序版本
@@ -15,6 +21,19 @@ var BuildDate = "2024-01-XX"
 // GitCommit Git提交哈希
 var GitCommit = "dev"
 
+// UpdateRepo 检查新版本使用的 GitHub 仓库
+var UpdateRepo = "chenweil/AI-Chat-CLI"
+
+// updateWait 命令结束后等待后台版本检查完成的最长时间
+const updateWait = 300 * time.Millisecond
+
+var (
+	// updateChecker 本次运行的版本检查器，不检查新版本时为nil
+	updateChecker *update.Checker
+	// updateDone 后台检查完成时关闭，本次运行没有发起检查时为nil
+	updateDone chan struct{}
+)
+
 // versionCmd represents the version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
@@ -30,3 +49,47 @@ var versionCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
+
+// initUpdateCheck 距离上次检查超过一天时在后台检查新版本。
+// 只在终端中运行时检查，演练或回放（offline 为true）时不检查，配置 advanced.check_updates 为 false 时关闭
+func initUpdateCheck(offline bool) {
+	if !ui.StderrIsTerminal() || offline {
+		return
+	}
+	if viper.IsSet("advanced.check_updates") && !viper.GetBool("advanced.check_updates") {
+		return
+	}
+
+	checker, err := update.NewChecker(UpdateRepo, Version)
+	if err != nil {
+		return
+	}
+	updateChecker = checker
+	if !checker.Due() {
+		return
+	}
+
+	updateDone = make(chan struct{})
+	go func() {
+		defer close(updateDone)
+		checker.Check(context.Background())
+	}()
+}
+
+// printUpdateHint 命令结束后有新版本时打印一行提示，最多短暂等待后台检查完成
+func printUpdateHint() {
+	if updateChecker == nil {
+		return
+	}
+	if updateDone != nil {
+		select {
+		case <-updateDone:
+		case <-time.After(updateWait):
+		}
+	}
+
+	latest := updateChecker.Cached().Latest
+	if updateChecker.Newer(latest) {
+		ui.Hint("发现新版本 %s（当前 %s）: %s", latest, Version, updateChecker.ReleaseURL())
+	}
+}
@@ -0,0 +1,217 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"ai-chat-cli/internal/filelock"
+	"ai-chat-cli/pkg/providers"
+)
+
+// 记录内容的方式
+const (
+	ContentHash = "hash" // 只记录提示词和回复的SHA-256（默认）
+	ContentFull = "full" // 记录完整的提示词和回复
+)
+
+// genesis 第一条记录的 prev 值
+var genesis = strings.Repeat("0", 64)
+
+// maxLineSize 校验时单条记录的最大长度
+const maxLineSize = 64 << 20
+
+// Record 一条审计记录，每条记录包含上一条记录的哈希，组成哈希链
+type Record struct {
+	Seq            int                 `json:"seq"`
+	Time           time.Time           `json:"time"`
+	User           string              `json:"user"`
+	Host           string              `json:"host,omitempty"`
+	Client         string              `json:"client,omitempty"` // serve 的客户端名称
+	Provider       string              `json:"provider"`
+	Model          string              `json:"model,omitempty"`
+	PromptSHA256   string              `json:"prompt_sha256"`
+	Prompt         []providers.Message `json:"prompt,omitempty"`
+	ResponseSHA256 string              `json:"response_sha256,omitempty"`
+	Response       string              `json:"response,omitempty"`
+	Tokens         int                 `json:"tokens,omitempty"`
+	Error          string              `json:"error,omitempty"`
+	Prev           string              `json:"prev"`
+	Hash           string              `json:"hash"`
+}
+
+// Log 只追加的审计日志文件，每行一条JSON记录
+type Log struct {
+	path string
+	full bool
+}
+
+// Open 创建审计日志，content 为 hash 或 full，为空时使用 hash
+func Open(path, content string) (*Log, error) {
+	switch content {
+	case "", ContentHash:
+		return &Log{path: path}, nil
+	case ContentFull:
+		return &Log{path: path, full: true}, nil
+	default:
+		return nil, fmt.Errorf("无效的审计内容设置: %s（可选 hash、full）", content)
+	}
+}
+
+// Append 追加一条对话记录，自动填写序号、时间、哈希和提示词、回复的内容。
+// 使用文件锁保证多个进程同时写入时哈希链保持连续
+func (l *Log) Append(rec Record, prompt []providers.Message, response string) error {
+	promptJSON, err := json.Marshal(prompt)
+	if err != nil {
+		return err
+	}
+	rec.PromptSHA256 = sha256Hex(promptJSON)
+	if response != "" {
+		rec.ResponseSHA256 = sha256Hex([]byte(response))
+	}
+	if l.full {
+		rec.Prompt = prompt
+		rec.Response = response
+	}
+	if rec.Time.IsZero() {
+		rec.Time = time.Now()
+	}
+	rec.Time = rec.Time.UTC().Truncate(time.Millisecond)
+
+	lock, err := filelock.Acquire(l.path)
+	if err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	defer lock.Release()
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	defer f.Close()
+
+	last, err := lastRecord(f)
+	if err != nil {
+		return fmt.Errorf("读取审计日志失败: %w", err)
+	}
+	rec.Seq, rec.Prev = 1, genesis
+	if last != nil {
+		rec.Seq, rec.Prev = last.Seq+1, last.Hash
+	}
+	if rec.Hash, err = recordHash(rec); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return f.Sync()
+}
+
+// Verify 校验审计日志的哈希链，返回记录数。
+// 记录被修改、删除、插入或调换顺序时返回出错的行号；无法发现从末尾截断的记录
+func Verify(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	prev, n := genesis, 0
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return n, fmt.Errorf("第 %d 行不是有效的审计记录: %w", line, err)
+		}
+		if rec.Seq != n+1 {
+			return n, fmt.Errorf("第 %d 行的序号为 %d，应为 %d，记录可能被删除或插入", line, rec.Seq, n+1)
+		}
+		if rec.Prev != prev {
+			return n, fmt.Errorf("第 %d 行（序号 %d）与上一条记录的哈希不一致", line, rec.Seq)
+		}
+		hash, err := recordHash(rec)
+		if err != nil {
+			return n, err
+		}
+		if rec.Hash != hash {
+			return n, fmt.Errorf("第 %d 行（序号 %d）的内容已被修改", line, rec.Seq)
+		}
+		prev = rec.Hash
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		return n, fmt.Errorf("读取审计日志失败: %w", err)
+	}
+	return n, nil
+}
+
+// recordHash 计算记录的哈希，哈希字段本身不参与计算
+func recordHash(rec Record) (string, error) {
+	rec.Hash = ""
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	return sha256Hex(data), nil
+}
+
+// lastRecord 从文件末尾向前读取最后一条记录，文件为空时返回nil
+func lastRecord(f *os.File) (*Record, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	end := info.Size()
+
+	var tail []byte
+	const chunk = 64 * 1024
+	for end > 0 {
+		size := int64(chunk)
+		if size > end {
+			size = end
+		}
+		buf := make([]byte, size)
+		if _, err := f.ReadAt(buf, end-size); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		tail = append(buf, tail...)
+		end -= size
+
+		trimmed := bytes.TrimRight(tail, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 || end == 0 {
+			line := trimmed[i+1:]
+			if len(line) == 0 {
+				return nil, nil
+			}
+			var rec Record
+			if err := json.Unmarshal(line, &rec); err != nil {
+				return nil, fmt.Errorf("最后一条记录已损坏: %w", err)
+			}
+			return &rec, nil
+		}
+	}
+	return nil, nil
+}
+
+// sha256Hex 计算SHA-256并转换为十六进制
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// writeLog 写入 n 条记录，返回日志路径和每行内容
+func writeLog(t *testing.T, n int) (string, []string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(path, ContentFull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		prompt := []providers.Message{{Role: "user", Content: strings.Repeat("问", i+1)}}
+		if err := log.Append(Record{User: "alice", Provider: "mock"}, prompt, "答"); err != nil {
+			t.Fatalf("追加第 %d 条记录失败: %v", i+1, err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(lines []string) []string
+		want    int
+		wantErr string
+	}{
+		{"完整的日志", func(lines []string) []string { return lines }, 3, ""},
+		{"空行被忽略", func(lines []string) []string {
+			return []string{lines[0], "", lines[1], "  ", lines[2]}
+		}, 3, ""},
+		{"从末尾截断无法发现", func(lines []string) []string { return lines[:2] }, 2, ""},
+		{"修改内容", func(lines []string) []string {
+			lines[1] = strings.Replace(lines[1], `"user":"alice"`, `"user":"mallory"`, 1)
+			return lines
+		}, 1, "第 2 行（序号 2）的内容已被修改"},
+		{"删除记录", func(lines []string) []string {
+			return []string{lines[0], lines[2]}
+		}, 1, "第 2 行的序号为 3，应为 2"},
+		{"调换顺序", func(lines []string) []string {
+			return []string{lines[1], lines[0], lines[2]}
+		}, 0, "第 1 行的序号为 2，应为 1"},
+		{"不是JSON", func(lines []string) []string {
+			lines[2] = "not json"
+			return lines
+		}, 2, "第 3 行不是有效的审计记录"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, lines := writeLog(t, 3)
+			content := strings.Join(tt.tamper(lines), "\n") + "\n"
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			n, err := Verify(path)
+			if n != tt.want {
+				t.Errorf("校验通过 %d 条记录，应为 %d 条", n, tt.want)
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("校验失败: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("错误为 %v，应包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyForgedChain(t *testing.T) {
+	// 修改记录后重新计算本条的哈希，下一条记录的 prev 仍指向原来的哈希
+	path, lines := writeLog(t, 3)
+	log, err := Open(filepath.Join(t.TempDir(), "other.jsonl"), ContentFull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Append(Record{User: "mallory", Provider: "mock"}, []providers.Message{{Role: "user", Content: "问"}}, "答"); err != nil {
+		t.Fatal(err)
+	}
+	forged, err := os.ReadFile(log.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines[0] = strings.TrimSuffix(string(forged), "\n")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := Verify(path)
+	if n != 1 || err == nil || !strings.Contains(err.Error(), "第 2 行（序号 2）与上一条记录的哈希不一致") {
+		t.Errorf("Verify = %d, %v，应在第2行发现哈希链断开", n, err)
+	}
+}
+
+func TestAppendHashContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Append(Record{Provider: "mock"}, []providers.Message{{Role: "user", Content: "机密问题"}}, "机密回答"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "机密") {
+		t.Errorf("hash 模式下记录了提示词或回复的原文: %s", data)
+	}
+	if n, err := Verify(path); n != 1 || err != nil {
+		t.Errorf("Verify = %d, %v", n, err)
+	}
+}
+
+func TestOpenInvalidContent(t *testing.T) {
+	if _, err := Open("audit.jsonl", "everything"); err == nil {
+		t.Error("无效的 content 设置应返回错误")
+	}
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// DefaultTTL 未配置有效期时缓存的回复保留的时间
+const DefaultTTL = 24 * time.Hour
+
+// Entry 缓存的一条回复
+type Entry struct {
+	Key      string                 `json:"key"`
+	Created  time.Time              `json:"created"`
+	Provider string                 `json:"provider"`
+	Response providers.ChatResponse `json:"response"`
+}
+
+// Cache 保存在目录中的回复缓存，每条回复一个文件，文件名为请求的哈希
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// Open 打开缓存目录，ttl 不大于0时使用 DefaultTTL
+func Open(dir string, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// OpenDefault 打开默认的缓存目录 ~/.ai-chat-cli/cache
+func OpenDefault(ttl time.Duration) (*Cache, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return Open(filepath.Join(home, ".ai-chat-cli", "cache"), ttl), nil
+}
+
+// Defaults 提供商配置中的默认值，发送时补全到请求中。参与计算缓存键，修改配置后不再使用按旧配置缓存的回复
+type Defaults struct {
+	Model     string
+	MaxTokens int
+	Sampling  providers.Sampling
+	User      string
+	Metadata  map[string]string
+	Compat    providers.Compat
+	Extra     map[string]string
+}
+
+// keyRequest 参与计算缓存键的请求内容
+type keyRequest struct {
+	Provider        string              `json:"provider"`
+	Model           string              `json:"model"`
+	Messages        []providers.Message `json:"messages"`
+	MaxTokens       int                 `json:"max_tokens"`
+	Temperature     float64             `json:"temperature"`
+	Sampling        providers.Sampling  `json:"sampling"`
+	AssistantPrefix string              `json:"assistant_prefix"`
+	N               int                 `json:"n,omitempty"`
+
+	DefaultSampling providers.Sampling `json:"default_sampling"`
+	User            string             `json:"user,omitempty"`
+	Metadata        map[string]string  `json:"metadata,omitempty"`
+	Compat          providers.Compat   `json:"compat"`
+	Extra           map[string]string  `json:"extra,omitempty"`
+}
+
+// Key 根据提供商、模型、消息、参数和提供商配置的默认值计算缓存键。请求未指定模型和最大token数时使用默认值，
+// 消息内容去掉首尾空白，只有空白不同的请求使用同一条缓存
+func Key(provider string, defaults Defaults, req *providers.ChatRequest) string {
+	k := keyRequest{
+		Provider:        provider,
+		Model:           req.Model,
+		MaxTokens:       req.MaxTokens,
+		Temperature:     req.Temperature,
+		Sampling:        req.Sampling,
+		AssistantPrefix: req.AssistantPrefix,
+		N:               req.N,
+		DefaultSampling: defaults.Sampling,
+		User:            defaults.User,
+		Metadata:        defaults.Metadata,
+		Compat:          defaults.Compat,
+		Extra:           defaults.Extra,
+	}
+	if k.Model == "" {
+		k.Model = defaults.Model
+	}
+	if k.MaxTokens == 0 {
+		k.MaxTokens = defaults.MaxTokens
+	}
+	for _, m := range req.Messages {
+		k.Messages = append(k.Messages, providers.Message{Role: m.Role, Content: strings.TrimSpace(m.Content), Images: m.Images})
+	}
+
+	data, _ := json.Marshal(k)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Get 获取未过期的缓存回复，过期的缓存会被删除
+func (c *Cache) Get(key string) (*providers.ChatResponse, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return nil, false
+	}
+	if time.Since(e.Created) > c.ttl {
+		os.Remove(c.path(key))
+		return nil, false
+	}
+	return &e.Response, true
+}
+
+// Put 保存回复，先写入临时文件再重命名，同时运行的命令不会读到不完整的文件
+func (c *Cache) Put(key, provider string, resp *providers.ChatResponse) error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	data, err := json.Marshal(Entry{Key: key, Created: time.Now(), Provider: provider, Response: *resp})
+	if err != nil {
+		return err
+	}
+	path := c.path(key)
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("保存缓存失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("保存缓存失败: %w", err)
+	}
+	return nil
+}
+
+// Clear 删除缓存，expiredOnly 为 true 时只删除过期的缓存，返回删除的条数
+func (c *Cache) Clear(expiredOnly bool) (int, error) {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("读取缓存目录失败: %w", err)
+	}
+
+	removed := 0
+	for _, de := range entries {
+		if de.IsDir() || filepath.Ext(de.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(c.dir, de.Name())
+		if expiredOnly {
+			info, err := de.Info()
+			if err != nil || time.Since(info.ModTime()) <= c.ttl {
+				continue
+			}
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("删除缓存失败: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// path 缓存文件的路径
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -0,0 +1,143 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNoImage 剪贴板中没有图片
+var ErrNoImage = errors.New("剪贴板中没有图片")
+
+// windowsImageScript 将剪贴板中的图片以PNG格式输出为base64，没有图片时不输出
+const windowsImageScript = `Add-Type -AssemblyName System.Windows.Forms, System.Drawing
+$img = [System.Windows.Forms.Clipboard]::GetImage()
+if ($img -ne $null) {
+  $ms = New-Object System.IO.MemoryStream
+  $img.Save($ms, [System.Drawing.Imaging.ImageFormat]::Png)
+  [Convert]::ToBase64String($ms.ToArray())
+}`
+
+// ReadImage 读取剪贴板中的图片（PNG格式）。macOS 使用 osascript，Windows 使用 PowerShell，
+// Linux 在 Wayland 下使用 wl-paste，否则使用 xclip
+func ReadImage() ([]byte, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		out, err := run("osascript", "-e", "the clipboard as «class PNGf»")
+		if err != nil {
+			return nil, ErrNoImage
+		}
+		// 输出形如 «data PNGf89504E47...»
+		s := strings.TrimSpace(string(out))
+		if !strings.HasPrefix(s, "«data PNGf") {
+			return nil, ErrNoImage
+		}
+		return hex.DecodeString(strings.TrimSuffix(strings.TrimPrefix(s, "«data PNGf"), "»"))
+	case "windows":
+		out, err := run("powershell", "-NoProfile", "-STA", "-Command", windowsImageScript)
+		if err != nil {
+			return nil, err
+		}
+		encoded := strings.TrimSpace(string(out))
+		if encoded == "" {
+			return nil, ErrNoImage
+		}
+		return base64.StdEncoding.DecodeString(encoded)
+	default:
+		var out []byte
+		var err error
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			out, err = run("wl-paste", "--no-newline", "--type", "image/png")
+		} else {
+			out, err = run("xclip", "-selection", "clipboard", "-target", "image/png", "-out")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(out) == 0 {
+			return nil, ErrNoImage
+		}
+		return out, nil
+	}
+}
+
+// ReadText 读取剪贴板中的文本。macOS 使用 pbpaste，Windows 使用 PowerShell，
+// Linux 在 Wayland 下使用 wl-paste，否则使用 xclip
+func ReadText() (string, error) {
+	var out []byte
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		out, err = run("pbpaste")
+	case "windows":
+		out, err = run("powershell", "-NoProfile", "-Command",
+			"[Console]::OutputEncoding = [Text.Encoding]::UTF8; Get-Clipboard -Raw")
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			out, err = run("wl-paste", "--no-newline", "--type", "text/plain")
+		} else {
+			out, err = run("xclip", "-selection", "clipboard", "-out")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// WriteText 将文本写入剪贴板，使用的命令与 ReadText 对应（macOS 为 pbcopy，Linux 为 wl-copy 或 xclip）
+func WriteText(text string) error {
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		err = runInput(text, "pbcopy")
+	case "windows":
+		err = runInput(text, "powershell", "-NoProfile", "-Command",
+			"[Console]::InputEncoding = [Text.Encoding]::UTF8; Set-Clipboard -Value ([Console]::In.ReadToEnd())")
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			err = runInput(text, "wl-copy")
+		} else {
+			err = runInput(text, "xclip", "-selection", "clipboard", "-in")
+		}
+	}
+	return err
+}
+
+// run 执行读取剪贴板的命令，命令不存在时提示需要安装
+func run(name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, fmt.Errorf("使用剪贴板需要 %s，请先安装", name)
+	}
+	var stderr bytes.Buffer
+	c := exec.Command(name, args...)
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("读取剪贴板失败: %s", msg)
+		}
+		return nil, fmt.Errorf("读取剪贴板失败: %w", err)
+	}
+	return out, nil
+}
+
+// runInput 执行写入剪贴板的命令。xclip 和 wl-copy 会在后台继续运行以提供剪贴板内容，
+// 因此不连接其输出，否则会一直等到剪贴板被其他程序占用
+func runInput(input, name string, args ...string) error {
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("使用剪贴板需要 %s，请先安装", name)
+	}
+	c := exec.Command(name, args...)
+	c.Stdin = strings.NewReader(input)
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("写入剪贴板失败: %w", err)
+	}
+	return nil
+}
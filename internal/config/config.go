@@ -1,11 +1,16 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+
+	"ai-chat-cli/internal/filelock"
 
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 // Config 应用程序配置结构
@@ -21,15 +26,105 @@ type Config struct {
 
 	// 日志设置
 	Logging LoggingConfig `mapstructure:"logging" yaml:"logging" json:"logging"`
+
+	// 网关服务设置
+	Serve ServeConfig `mapstructure:"serve" yaml:"serve" json:"serve"`
+
+	// 界面设置
+	UI UIConfig `mapstructure:"ui" yaml:"ui" json:"ui"`
+
+	// 会话分享设置
+	Share ShareConfig `mapstructure:"share" yaml:"share" json:"share"`
+
+	// issue 命令提交问题的设置
+	Issues IssuesConfig `mapstructure:"issues" yaml:"issues,omitempty" json:"issues,omitempty"`
+
+	// 审计日志设置
+	Audit AuditConfig `mapstructure:"audit" yaml:"audit" json:"audit"`
+
+	// 模型路由设置
+	Routing RoutingConfig `mapstructure:"routing" yaml:"routing" json:"routing"`
+
+	// 发送前检查提示词的过滤规则
+	Filters []FilterRule `mapstructure:"filters" yaml:"filters,omitempty" json:"filters,omitempty"`
 }
 
 // ProviderConfig AI提供商配置
 type ProviderConfig struct {
-	APIKey    string            `mapstructure:"api_key" yaml:"api_key" json:"api_key"`
-	BaseURL   string            `mapstructure:"base_url" yaml:"base_url" json:"base_url"`
-	Model     string            `mapstructure:"model" yaml:"model" json:"model"`
-	MaxTokens int               `mapstructure:"max_tokens" yaml:"max_tokens" json:"max_tokens"`
-	Extra     map[string]string `mapstructure:"extra" yaml:"extra" json:"extra"`
+	APIKey    string `mapstructure:"api_key" yaml:"api_key" json:"api_key"`
+	BaseURL   string `mapstructure:"base_url" yaml:"base_url" json:"base_url"`
+	Model     string `mapstructure:"model" yaml:"model" json:"model"`
+	MaxTokens int    `mapstructure:"max_tokens" yaml:"max_tokens" json:"max_tokens"`
+	RateLimit int    `mapstructure:"rate_limit" yaml:"rate_limit" json:"rate_limit"` // 每分钟最大请求数，0表示不限制
+	// 每百万输入token的价格（美元），用于在发送大量输入前估算成本
+	InputPrice float64 `mapstructure:"input_price" yaml:"input_price" json:"input_price"`
+	// 每百万输出token的价格（美元），与 input_price 一起用于 history stats 和 estimate 估算成本
+	OutputPrice float64           `mapstructure:"output_price" yaml:"output_price,omitempty" json:"output_price,omitempty"`
+	Extra       map[string]string `mapstructure:"extra" yaml:"extra" json:"extra"`
+	// 不使用API密钥时的认证方式
+	Auth AuthConfig `mapstructure:"auth" yaml:"auth,omitempty" json:"auth,omitempty"`
+	// 支持的功能（streaming、tools、vision、embeddings），为空时使用提供商类型的默认值。
+	// 兼容OpenAI的本地服务不一定支持所有功能，如 Ollama 的部分模型不支持图片
+	Capabilities []string `mapstructure:"capabilities" yaml:"capabilities,omitempty" json:"capabilities,omitempty"`
+	// 请求格式的兼容设置，用于不接受标准字段的网关
+	Compat CompatConfig `mapstructure:"compat" yaml:"compat,omitempty" json:"compat,omitempty"`
+	// 对话请求默认的生成参数，未设置时 temperature 为0.7，其他参数使用API的默认值。
+	// 命令行参数、预设和模板中指定的值优先
+	Temperature      *float64 `mapstructure:"temperature" yaml:"temperature,omitempty" json:"temperature,omitempty"`
+	TopP             *float64 `mapstructure:"top_p" yaml:"top_p,omitempty" json:"top_p,omitempty"`
+	PresencePenalty  *float64 `mapstructure:"presence_penalty" yaml:"presence_penalty,omitempty" json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64 `mapstructure:"frequency_penalty" yaml:"frequency_penalty,omitempty" json:"frequency_penalty,omitempty"`
+	Stop             []string `mapstructure:"stop" yaml:"stop,omitempty" json:"stop,omitempty"`
+	// 随每次请求发送的 user 字段和 metadata，企业网关用于归属统计和滥用追踪，--metadata 可以追加或覆盖
+	UserID   string            `mapstructure:"user_id" yaml:"user_id,omitempty" json:"user_id,omitempty"`
+	Metadata map[string]string `mapstructure:"metadata" yaml:"metadata,omitempty" json:"metadata,omitempty"`
+}
+
+// CompatConfig 兼容OpenAI的服务之间请求格式的差异
+type CompatConfig struct {
+	// 最大输出token数的字段名: max_tokens（默认）、max_completion_tokens 或 none（不发送）
+	MaxTokensField string `mapstructure:"max_tokens_field" yaml:"max_tokens_field,omitempty" json:"max_tokens_field,omitempty"`
+	// 不发送 temperature，用于只接受默认温度的模型
+	OmitTemperature bool `mapstructure:"omit_temperature" yaml:"omit_temperature,omitempty" json:"omit_temperature,omitempty"`
+	// 流式请求带上 stream_options.include_usage，让服务返回用量
+	StreamUsage bool `mapstructure:"stream_usage" yaml:"stream_usage,omitempty" json:"stream_usage,omitempty"`
+	// 服务支持 n 参数，chat --n 一次请求返回多个回答，否则并发发送多个请求
+	MultipleChoices bool `mapstructure:"multiple_choices" yaml:"multiple_choices,omitempty" json:"multiple_choices,omitempty"`
+}
+
+// 提供商的认证方式
+const (
+	AuthAPIKey    = "api_key"    // 使用 api_key（默认）
+	AuthAzureAD   = "azure_ad"   // Azure AD 服务主体，用于 Azure OpenAI
+	AuthOAuth     = "oauth"      // 通用的 OAuth 2.0 客户端凭据流程
+	AuthGoogleADC = "google_adc" // Google 应用默认凭据，用于 Vertex AI
+)
+
+// AuthConfig 通过 OAuth 获取访问令牌的认证配置，令牌过期前自动刷新
+type AuthConfig struct {
+	// 认证方式: api_key（默认）、azure_ad、oauth 或 google_adc
+	Type string `mapstructure:"type" yaml:"type,omitempty" json:"type,omitempty"`
+	// Azure AD 租户ID
+	TenantID string `mapstructure:"tenant_id" yaml:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	// azure_ad 和 oauth 的客户端ID和密钥
+	ClientID     string `mapstructure:"client_id" yaml:"client_id,omitempty" json:"client_id,omitempty"`
+	ClientSecret string `mapstructure:"client_secret" yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+	// oauth 的令牌地址
+	TokenURL string `mapstructure:"token_url" yaml:"token_url,omitempty" json:"token_url,omitempty"`
+	// 权限范围，azure_ad 和 google_adc 为空时使用默认值
+	Scope string `mapstructure:"scope" yaml:"scope,omitempty" json:"scope,omitempty"`
+	// google_adc 的凭据文件，为空时依次使用 GOOGLE_APPLICATION_CREDENTIALS、gcloud 的默认凭据和元数据服务器
+	CredentialsFile string `mapstructure:"credentials_file" yaml:"credentials_file,omitempty" json:"credentials_file,omitempty"`
+}
+
+// UsesToken 判断提供商是否通过 OAuth 令牌认证
+func (p ProviderConfig) UsesToken() bool {
+	return p.Auth.Type != "" && p.Auth.Type != AuthAPIKey
+}
+
+// HasCredentials 判断提供商是否配置了API密钥或令牌认证
+func (p ProviderConfig) HasCredentials() bool {
+	return p.APIKey != "" || p.UsesToken()
 }
 
 // DefaultConfig 默认配置
@@ -46,20 +141,167 @@ type AdvancedConfig struct {
 	CostLimit     float64 `mapstructure:"cost_limit" yaml:"cost_limit" json:"cost_limit"`
 	SaveHistory   bool    `mapstructure:"save_history" yaml:"save_history" json:"save_history"`
 	HistoryLength int     `mapstructure:"history_length" yaml:"history_length" json:"history_length"`
+	// 发送前审核用户输入: warn（警告后继续）或 block（拒绝发送），为空表示不审核
+	ModerateInputs string `mapstructure:"moderate_inputs" yaml:"moderate_inputs" json:"moderate_inputs"`
+	// 自动生成会话标题使用的模型，为空时使用当前模型，设置为 off 关闭自动标题
+	TitleModel string `mapstructure:"title_model" yaml:"title_model" json:"title_model"`
+	// 每次请求最多发送的历史对话轮数，0表示不限制
+	HistoryTurns int `mapstructure:"history_turns" yaml:"history_turns" json:"history_turns"`
+	// 发送哪些角色的历史消息（user、assistant），为空表示全部发送，system 消息始终发送
+	HistoryRoles []string `mapstructure:"history_roles" yaml:"history_roles" json:"history_roles"`
+	// 输入超过该token数时发送前需要确认，0使用默认值20000，-1表示不检查
+	ConfirmInputTokens int `mapstructure:"confirm_input_tokens" yaml:"confirm_input_tokens" json:"confirm_input_tokens"`
+	// 是否加密保存的会话，密钥保存在系统钥匙串中
+	EncryptSessions bool `mapstructure:"encrypt_sessions" yaml:"encrypt_sessions" json:"encrypt_sessions"`
+	// 发送前将邮箱、电话、证件号码和密钥替换为占位符，并在回复中恢复
+	MaskPII bool `mapstructure:"mask_pii" yaml:"mask_pii" json:"mask_pii"`
+	// 是否每天在后台检查一次新版本，未设置时检查
+	CheckUpdates *bool `mapstructure:"check_updates" yaml:"check_updates,omitempty" json:"check_updates,omitempty"`
+	// 是否缓存回复，相同的请求直接返回缓存的回复
+	Cache bool `mapstructure:"cache" yaml:"cache" json:"cache"`
+	// 缓存的有效期（小时），0使用默认值24
+	CacheTTL int `mapstructure:"cache_ttl" yaml:"cache_ttl" json:"cache_ttl"`
+	// 回答使用的语言（如 zh、en），auto 表示与问题的语言相同，为空表示不指定，作为系统指令随每次请求发送
+	ReplyLanguage string `mapstructure:"reply_language" yaml:"reply_language,omitempty" json:"reply_language,omitempty"`
+	// 交互模式中 /remember 记住的事实属于哪个配置档，为空时按配置文件名区分，默认配置文件为 default
+	MemoryProfile string `mapstructure:"memory_profile" yaml:"memory_profile,omitempty" json:"memory_profile,omitempty"`
+	// 流式回复在完成前中断时，以已收到的内容为开头请求续写的最多次数，0使用默认值2，-1表示不续写
+	StreamResumes int `mapstructure:"stream_resumes" yaml:"stream_resumes" json:"stream_resumes"`
 }
 
+// TitleModelOff 关闭自动生成会话标题
+const TitleModelOff = "off"
+
 // LoggingConfig 日志配置
 type LoggingConfig struct {
 	Level    string `mapstructure:"level" yaml:"level" json:"level"`
 	File     string `mapstructure:"file" yaml:"file" json:"file"`
 	Requests bool   `mapstructure:"requests" yaml:"requests" json:"requests"`
+
+	// 日志文件轮转
+	MaxSize    int `mapstructure:"max_size" yaml:"max_size" json:"max_size"`          // 单个文件最大大小（MB），默认10
+	MaxAge     int `mapstructure:"max_age" yaml:"max_age" json:"max_age"`             // 轮转文件保留天数，0表示不按时间清理
+	MaxBackups int `mapstructure:"max_backups" yaml:"max_backups" json:"max_backups"` // 最多保留的轮转文件数，默认5
+}
+
+// UIConfig 界面配置
+type UIConfig struct {
+	// 界面语言: zh-CN 或 en-US，为空时根据 LANG 环境变量选择
+	Language string `mapstructure:"language" yaml:"language" json:"language"`
+	// 回复后的用量统计: on（默认）、off 或 verbose（附加耗时和输出速度）
+	Stats string `mapstructure:"stats" yaml:"stats" json:"stats"`
+	// 界面主题: default、vivid（着色）或 plain（不使用表情符号）
+	Theme string `mapstructure:"theme" yaml:"theme" json:"theme"`
+	// 覆盖主题中界面元素（user、ai、stats、error、warn、hint、success）的符号和颜色
+	Symbols map[string]string `mapstructure:"symbols" yaml:"symbols,omitempty" json:"symbols,omitempty"`
+	Colors  map[string]string `mapstructure:"colors" yaml:"colors,omitempty" json:"colors,omitempty"`
+	// 交互模式空闲多少分钟后保存会话并退出或锁定，0表示不限制
+	IdleTimeout int `mapstructure:"idle_timeout" yaml:"idle_timeout" json:"idle_timeout"`
+	// 空闲超时后的操作: exit（默认）或 lock（清屏并清空内存中的对话，需要加密会话）
+	IdleAction string `mapstructure:"idle_action" yaml:"idle_action" json:"idle_action"`
+}
+
+// 交互模式空闲超时后的操作
+const (
+	IdleExit = "exit"
+	IdleLock = "lock"
+)
+
+// 用量统计的显示方式
+const (
+	StatsOn      = "on"
+	StatsOff     = "off"
+	StatsVerbose = "verbose"
+)
+
+// ShareConfig 会话分享配置
+type ShareConfig struct {
+	// 分享目标: gist、0x0 或 paste，默认为 gist
+	Target string `mapstructure:"target" yaml:"target" json:"target"`
+	// 创建 gist 使用的 GitHub token，为空时使用 GITHUB_TOKEN 环境变量
+	GistToken string `mapstructure:"gist_token" yaml:"gist_token" json:"gist_token"`
+	// 创建公开的 gist，默认为私密
+	Public bool `mapstructure:"public" yaml:"public" json:"public"`
+	// 自定义粘贴服务的地址，以POST请求发送Markdown原文，响应内容为链接
+	PasteURL string `mapstructure:"paste_url" yaml:"paste_url" json:"paste_url"`
+}
+
+// IssuesConfig issue 命令提交问题的配置
+type IssuesConfig struct {
+	// 问题跟踪系统: github 或 jira，默认为 github
+	Tracker string `mapstructure:"tracker" yaml:"tracker,omitempty" json:"tracker,omitempty"`
+	// 默认的 GitHub 仓库（owner/name），可以用 --repo 覆盖
+	Repo string `mapstructure:"repo" yaml:"repo,omitempty" json:"repo,omitempty"`
+	// 创建 issue 使用的 GitHub token，为空时使用 GITHUB_TOKEN 环境变量
+	GitHubToken string `mapstructure:"github_token" yaml:"github_token,omitempty" json:"github_token,omitempty"`
+	// Jira 站点地址、账号邮箱和 API token（为空时使用 JIRA_API_TOKEN 环境变量）
+	JiraURL   string `mapstructure:"jira_url" yaml:"jira_url,omitempty" json:"jira_url,omitempty"`
+	JiraEmail string `mapstructure:"jira_email" yaml:"jira_email,omitempty" json:"jira_email,omitempty"`
+	JiraToken string `mapstructure:"jira_token" yaml:"jira_token,omitempty" json:"jira_token,omitempty"`
+	// 默认的 Jira 项目键和问题类型（默认为 Bug）
+	JiraProject   string `mapstructure:"jira_project" yaml:"jira_project,omitempty" json:"jira_project,omitempty"`
+	JiraIssueType string `mapstructure:"jira_issue_type" yaml:"jira_issue_type,omitempty" json:"jira_issue_type,omitempty"`
+}
+
+// AuditConfig 审计日志配置
+type AuditConfig struct {
+	// 是否记录每次对话请求的审计日志
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	// 审计日志文件，默认为 ~/.ai-chat-cli/audit.jsonl
+	File string `mapstructure:"file" yaml:"file" json:"file"`
+	// 记录内容: hash（默认，只记录SHA-256）或 full（记录完整的提示词和回复）
+	Content string `mapstructure:"content" yaml:"content" json:"content"`
+}
+
+// FilterRule 发送前检查提示词的过滤规则，防止内部主机名、客户编号等敏感内容发送到外部API
+type FilterRule struct {
+	// 规则名称，显示在提示中
+	Name string `mapstructure:"name" yaml:"name" json:"name"`
+	// 正则表达式
+	Pattern string `mapstructure:"pattern" yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	// 不区分大小写的关键词
+	Keywords []string `mapstructure:"keywords" yaml:"keywords,omitempty" json:"keywords,omitempty"`
+	// 匹配后的处理方式: block（默认，拒绝发送）、warn（警告后发送）或 confirm（确认后发送）
+	Action string `mapstructure:"action" yaml:"action,omitempty" json:"action,omitempty"`
 }
 
-// GlobalConfig 全局配置实例
-var GlobalConfig *Config
+// RoutingConfig 按问题复杂度选择模型的配置，模型属于当前使用的提供商
+type RoutingConfig struct {
+	// 是否自动选择模型，可用 chat --route 临时覆盖
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	// 简单问题使用的快速模型
+	Fast string `mapstructure:"fast" yaml:"fast" json:"fast"`
+	// 编程、推理等复杂问题使用的模型
+	Smart string `mapstructure:"smart" yaml:"smart" json:"smart"`
+	// 判断复杂度的方式: heuristic（默认，按长度、代码和关键词判断）或 model（请快速模型判断）
+	Classifier string `mapstructure:"classifier" yaml:"classifier" json:"classifier"`
+}
+
+// ServeConfig 网关服务配置
+type ServeConfig struct {
+	// 客户端访问密钥，为空时不需要认证
+	Keys []ClientKeyConfig `mapstructure:"keys" yaml:"keys" json:"keys"`
+}
+
+// ClientKeyConfig 网关客户端访问密钥
+type ClientKeyConfig struct {
+	Name        string   `mapstructure:"name" yaml:"name" json:"name"`
+	Key         string   `mapstructure:"key" yaml:"key" json:"key"`
+	RateLimit   int      `mapstructure:"rate_limit" yaml:"rate_limit" json:"rate_limit"`       // 每分钟最大请求数，0表示不限制
+	Models      []string `mapstructure:"models" yaml:"models" json:"models"`                   // 允许的模型，如 "free-oai/*"，为空表示不限制
+	DailyTokens int      `mapstructure:"daily_tokens" yaml:"daily_tokens" json:"daily_tokens"` // 每日token配额，0表示不限制
+	DailyCost   float64  `mapstructure:"daily_cost" yaml:"daily_cost" json:"daily_cost"`       // 每日成本上限（美元），0表示不限制
+}
 
-// Load 加载配置
+// viperMu 保护全局的viper实例。viper 不是并发安全的，serve 的请求、后台任务（如生成会话标题）
+// 可能同时读取配置，所有读写全局viper的地方都需要持有该锁
+var viperMu sync.Mutex
+
+// Load 加载配置并设置默认值和验证
 func Load() (*Config, error) {
+	viperMu.Lock()
+	defer viperMu.Unlock()
+
 	cfg := &Config{}
 
 	// 设置默认值
@@ -75,7 +317,6 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("配置验证失败: %w", err)
 	}
 
-	GlobalConfig = cfg
 	return cfg, nil
 }
 
@@ -90,7 +331,7 @@ func (c *Config) Validate() error {
 
 	// 验证API密钥
 	for name, provider := range c.Providers {
-		if provider.APIKey == "" {
+		if !provider.HasCredentials() {
 			// 尝试从环境变量获取
 			envKey := fmt.Sprintf("%s_API_KEY", name)
 			if os.Getenv(envKey) == "" {
@@ -102,20 +343,48 @@ func (c *Config) Validate() error {
 	return nil
 }
 
-// Save 保存配置到文件
+// Save 保存配置到文件，写入期间持有文件锁，先写入临时文件再重命名，避免同时运行的命令损坏配置文件
 func (c *Config) Save(filename string) error {
-	// 确保目录存在
-	dir := filepath.Dir(filename)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("创建配置目录失败: %w", err)
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %w", err)
 	}
 
-	// 写入配置文件
-	viper.SetConfigFile(filename)
-	if err := viper.WriteConfig(); err != nil {
+	lock, err := filelock.Acquire(filename)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if err := filelock.WriteFile(filename, data, 0600); err != nil {
 		return fmt.Errorf("保存配置文件失败: %w", err)
 	}
+	return nil
+}
+
+// Set 修改配置文件中的一项并保存。持有文件锁期间重新读取配置文件后再修改，
+// 同时运行的多个 config set 不会互相覆盖对方的修改
+func Set(filename, key string, value interface{}) error {
+	lock, err := filelock.Acquire(filename)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	v := viper.New()
+	v.SetConfigFile(filename)
+	if err := v.ReadInConfig(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	v.Set(key, value)
 
+	data, err := yaml.Marshal(v.AllSettings())
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %w", err)
+	}
+	if err := filelock.WriteFile(filename, data, 0600); err != nil {
+		return fmt.Errorf("保存配置文件失败: %w", err)
+	}
 	return nil
 }
 
@@ -174,8 +443,11 @@ func GetDefaultConfigPath() (string, error) {
 	return filepath.Join(home, ".ai-chat-cli", "config.yaml"), nil
 }
 
-// LoadConfig 加载配置文件
+// LoadConfig 加载配置文件，每次调用都重新读取并返回新的配置，调用方可以随意修改，可以并发调用
 func LoadConfig() (*Config, error) {
+	viperMu.Lock()
+	defer viperMu.Unlock()
+
 	cfg := &Config{}
 
 	// 读取配置文件
@@ -190,3 +462,20 @@ func LoadConfig() (*Config, error) {
 
 	return cfg, nil
 }
+
+// LoadFile 从指定文件加载配置，不影响全局的viper实例
+func LoadFile(filename string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(filename)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	cfg := &Config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch 监听配置文件的修改，每次修改后从文件重新加载配置并调用 onChange（加载失败时 cfg 为nil）。
+// 监听配置文件所在的目录，编辑器先写临时文件再重命名的保存方式也能检测到。
+// 与 viper.WatchConfig 不同，重新加载时不修改全局的viper实例，不会与同时读取配置的请求冲突
+func Watch(filename string, onChange func(cfg *Config, err error)) (func(), error) {
+	filename = filepath.Clean(filename)
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Add(filepath.Dir(filename)); err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	go func() {
+		for {
+			select {
+			case e, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(e.Name) != filename || !(e.Has(fsnotify.Write) || e.Has(fsnotify.Create)) {
+					continue
+				}
+				onChange(LoadFile(filename))
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+	return func() { w.Close() }, nil
+}
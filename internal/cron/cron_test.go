@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{"* * * * *", false},
+		{"0 9 * * 1-5", false},
+		{"*/15 0-6/2 1,15 * 0", false},
+		{"30 8 * * 7", false},
+		{"@daily", false},
+		{" @hourly ", false},
+		{"0 9 * *", true},
+		{"0 9 * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"5-1 * * * *", true},
+		{"*/0 * * * *", true},
+		{"a * * * *", true},
+		{"@sometimes", true},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) 错误 = %v，期望出错: %v", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	// 2024-01-01 是星期一
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{"* * * * *", at(1, 0, 0), true},
+		{"0 9 * * *", at(1, 9, 0), true},
+		{"0 9 * * *", at(1, 9, 1), false},
+		{"*/15 * * * *", at(1, 3, 45), true},
+		{"*/15 * * * *", at(1, 3, 40), false},
+		{"0 9 * * 1-5", at(1, 9, 0), true},
+		{"0 9 * * 1-5", at(6, 9, 0), false},
+		{"0 0 * * 7", at(7, 0, 0), true},
+		{"0 0 * * 0", at(7, 0, 0), true},
+		// 日期和星期都指定时满足任一条件即可
+		{"0 0 15 * 1", at(15, 0, 0), true},
+		{"0 0 15 * 1", at(8, 0, 0), true},
+		{"0 0 15 * 1", at(9, 0, 0), false},
+		// 只指定日期时星期不限制
+		{"0 0 15 * *", at(15, 0, 0), true},
+		{"0 0 1 2 *", at(1, 0, 0), false},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := s.Matches(tt.t); got != tt.want {
+			t.Errorf("%q Matches(%s) = %v，期望 %v", tt.expr, tt.t.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	kolkata := time.FixedZone("IST", 5*3600+30*60)
+	kathmandu := time.FixedZone("NPT", 5*3600+45*60)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("没有时区数据: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"下一分钟", "* * * * *",
+			time.Date(2024, 1, 1, 9, 0, 30, 0, time.UTC), time.Date(2024, 1, 1, 9, 1, 0, 0, time.UTC)},
+		{"不含当前分钟", "0 9 * * *",
+			time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{"同一天稍后", "30 14 * * *",
+			time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)},
+		{"跨月", "0 0 1 * *",
+			time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"闰日", "0 0 29 2 *",
+			time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"工作日", "0 9 * * 1-5",
+			time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC), time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)},
+		{"半小时时区", "0 9 * * *",
+			time.Date(2024, 1, 1, 7, 10, 0, 0, kolkata), time.Date(2024, 1, 1, 9, 0, 0, 0, kolkata)},
+		{"45分钟时区", "0 9 * * *",
+			time.Date(2024, 1, 1, 7, 10, 0, 0, kathmandu), time.Date(2024, 1, 1, 9, 0, 0, 0, kathmandu)},
+		{"夏令时开始", "30 2 * * *",
+			time.Date(2024, 3, 10, 0, 0, 0, 0, newYork), time.Date(2024, 3, 11, 2, 30, 0, 0, newYork)},
+		{"夏令时结束", "0 3 * * *",
+			time.Date(2024, 11, 3, 0, 0, 0, 0, newYork), time.Date(2024, 11, 3, 3, 0, 0, 0, newYork)},
+		{"没有触发时间", "0 0 31 2 *",
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("%q Next(%s) = %s，期望 %s", tt.expr, tt.from, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OpKind 差异操作类型
+type OpKind int
+
+const (
+	// Equal 两边相同的行
+	Equal OpKind = iota
+	// Delete 仅存在于原文的行
+	Delete
+	// Insert 仅存在于新文本的行
+	Insert
+)
+
+// Op 一行差异
+type Op struct {
+	Kind OpKind // 操作类型
+	Text string // 行内容（不含换行符）
+}
+
+// Lines 使用Myers算法计算两组行之间的最短编辑序列
+func Lines(a, b []string) []Op {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, offset)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack 根据每一步的搜索状态回溯出编辑序列
+func backtrack(a, b []string, trace [][]int, offset int) []Op {
+	var ops []Op
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, Op{Kind: Equal, Text: a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, Op{Kind: Insert, Text: b[y-1]})
+			} else {
+				ops = append(ops, Op{Kind: Delete, Text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	// 回溯得到的是逆序
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// Unified 生成统一格式（unified diff）的差异文本，context为每个变更块前后保留的上下文行数。
+// 两段文本相同时返回空字符串。
+func Unified(oldName, newName, oldText, newText string, context int) string {
+	ops := Lines(splitLines(oldText), splitLines(newText))
+
+	changed := false
+	for _, op := range ops {
+		if op.Kind != Equal {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(ops); {
+		// 找到下一个变更
+		first := start
+		for first < len(ops) && ops[first].Kind == Equal {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// 扩展变更块，直到连续相同的行超过两倍上下文
+		hunkStart := first - context
+		if hunkStart < start {
+			hunkStart = start
+		}
+		hunkEnd := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].Kind != Equal {
+				hunkEnd = i + 1
+				continue
+			}
+			if i-hunkEnd >= 2*context {
+				break
+			}
+		}
+		hunkEnd += context
+		if hunkEnd > len(ops) {
+			hunkEnd = len(ops)
+		}
+
+		oldLine, newLine := lineNumbers(ops, hunkStart)
+		oldCount, newCount := 0, 0
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.Kind != Insert {
+				oldCount++
+			}
+			if op.Kind != Delete {
+				newCount++
+			}
+		}
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, op := range ops[hunkStart:hunkEnd] {
+			switch op.Kind {
+			case Equal:
+				b.WriteString(" ")
+			case Delete:
+				b.WriteString("-")
+			case Insert:
+				b.WriteString("+")
+			}
+			b.WriteString(op.Text)
+			b.WriteString("\n")
+		}
+
+		start = hunkEnd
+	}
+
+	return b.String()
+}
+
+// lineNumbers 计算编辑序列中第idx个操作对应的原文和新文本行号（从1开始）
+func lineNumbers(ops []Op, idx int) (int, int) {
+	oldLine, newLine := 1, 1
+	for _, op := range ops[:idx] {
+		if op.Kind != Insert {
+			oldLine++
+		}
+		if op.Kind != Delete {
+			newLine++
+		}
+	}
+	return oldLine, newLine
+}
+
+// splitLines 将文本按行切分，忽略末尾的换行符
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
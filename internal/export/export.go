@@ -0,0 +1,154 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"ai-chat-cli/pkg/session"
+)
+
+// roleNames 消息角色的显示名称
+var roleNames = map[string]string{
+	"system":    "⚙️ 系统",
+	"user":      "👤 你",
+	"assistant": "🤖 AI",
+}
+
+// roleName 获取角色的显示名称
+func roleName(role string) string {
+	if name, ok := roleNames[role]; ok {
+		return name
+	}
+	return role
+}
+
+// Markdown 将会话导出为Markdown
+func Markdown(w io.Writer, s *session.Session) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", title(s))
+	for _, line := range metadata(s) {
+		fmt.Fprintf(&b, "- %s\n", line)
+	}
+	b.WriteString("\n---\n\n")
+
+	for _, m := range s.Messages {
+		fmt.Fprintf(&b, "### %s%s\n\n", roleName(m.Role), pinMark(m))
+		if m.Note != "" {
+			fmt.Fprintf(&b, "> 📝 %s\n\n", strings.ReplaceAll(strings.TrimSpace(m.Note), "\n", "\n> "))
+		}
+		if content := strings.TrimSpace(m.Content); content != "" {
+			fmt.Fprintf(&b, "%s\n\n", content)
+		}
+		for _, c := range m.ToolCalls {
+			markdownToolCall(&b, c)
+		}
+		if m.Truncated {
+			b.WriteString("*（回答被中断，内容不完整）*\n\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownToolCall 工具调用导出为可折叠的块（GitHub 等支持 <details> 的查看器中默认折叠）
+func markdownToolCall(b *strings.Builder, c session.ToolCall) {
+	fmt.Fprintf(b, "<details>\n<summary>🔧 %s</summary>\n\n", ToolCallLabel(c))
+	if c.Arguments != "" {
+		fmt.Fprintf(b, "**参数**\n\n%s\n\n", fenced(c.Arguments))
+	}
+	if c.Result != "" {
+		fmt.Fprintf(b, "**结果**\n\n%s\n\n", fenced(c.Result))
+	}
+	b.WriteString("</details>\n\n")
+}
+
+// ToolCallLabel 工具调用的标题：工具名称、耗时和是否出错
+func ToolCallLabel(c session.ToolCall) string {
+	label := "工具调用: " + c.Name
+	if c.DurationMs > 0 {
+		label += "（" + formatDuration(c.DurationMs) + "）"
+	}
+	if c.Error {
+		label += " ❌ 出错"
+	}
+	return label
+}
+
+// formatDuration 以毫秒或秒显示耗时
+func formatDuration(ms int64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
+
+// fenced 将文本放入围栏代码块，围栏比文本中最长的连续反引号更长
+func fenced(text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + toolLanguage(text) + "\n" + strings.TrimRight(text, "\n") + "\n" + fence
+}
+
+// toolLanguage 工具参数和结果的代码高亮语言：看起来是JSON时为 json，否则由高亮程序自动识别
+func toolLanguage(text string) string {
+	if trimmed := strings.TrimSpace(text); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return "json"
+	}
+	return ""
+}
+
+// pinMark 被标记的消息在角色名称后显示的符号
+func pinMark(m session.Message) string {
+	if m.Pinned {
+		return " 📌"
+	}
+	return ""
+}
+
+// title 获取会话标题
+func title(s *session.Session) string {
+	if s.Title != "" {
+		return s.Title
+	}
+	return s.ID
+}
+
+// metadata 获取会话的元信息
+func metadata(s *session.Session) []string {
+	lines := []string{"会话: " + s.ID}
+	if s.Provider != "" {
+		lines = append(lines, "提供商: "+s.Provider)
+	}
+	if s.Model != "" {
+		lines = append(lines, "模型: "+s.Model)
+	}
+	if len(s.Tags) > 0 {
+		lines = append(lines, "标签: "+strings.Join(s.Tags, ", "))
+	}
+	lines = append(lines, "创建时间: "+s.CreatedAt.Format("2006-01-02 15:04"))
+	lines = append(lines, fmt.Sprintf("消息数: %d", len(s.Messages)))
+	if marked := s.Marked(); len(marked) > 0 {
+		lines = append(lines, fmt.Sprintf("标记的消息: %d", len(marked)))
+	}
+	calls := 0
+	for _, m := range s.Messages {
+		calls += len(m.ToolCalls)
+	}
+	if calls > 0 {
+		lines = append(lines, fmt.Sprintf("工具调用: %d", calls))
+	}
+	return lines
+}
@@ -0,0 +1,241 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"ai-chat-cli/pkg/session"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	// collapseRunes 超过该字数的消息默认折叠
+	collapseRunes = 3000
+	// collapseLines 超过该行数的消息默认折叠
+	collapseLines = 60
+)
+
+// markdown Markdown渲染器，代码块使用chroma以内联样式高亮，原始HTML作为文本显示
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(
+		renderer.WithNodeRenderers(util.Prioritized(&transcriptRenderer{}, 100)),
+	),
+)
+
+// HTML 将会话导出为单个自包含的HTML文件，样式内联，无需外部资源
+func HTML(w io.Writer, s *session.Session) error {
+	var b strings.Builder
+
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"zh-CN\">\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n", html.EscapeString(title(s)), htmlStyle)
+
+	fmt.Fprintf(&b, "<header>\n<h1>%s</h1>\n<ul class=\"meta\">\n", html.EscapeString(title(s)))
+	for _, line := range metadata(s) {
+		fmt.Fprintf(&b, "<li>%s</li>\n", html.EscapeString(line))
+	}
+	b.WriteString("</ul>\n</header>\n<main>\n")
+
+	for _, m := range s.Messages {
+		var body bytes.Buffer
+		if err := markdown.Convert([]byte(m.Content), &body); err != nil {
+			return fmt.Errorf("渲染消息失败: %w", err)
+		}
+
+		class := m.Role
+		if m.Pinned {
+			class += " pinned"
+		}
+		fmt.Fprintf(&b, "<section class=\"message %s\">\n<div class=\"role\">%s%s", html.EscapeString(class), html.EscapeString(roleName(m.Role)), pinMark(m))
+		if !m.CreatedAt.IsZero() {
+			fmt.Fprintf(&b, " <time>%s</time>", m.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		b.WriteString("</div>\n")
+		if m.Note != "" {
+			fmt.Fprintf(&b, "<div class=\"note\">📝 %s</div>\n", html.EscapeString(strings.TrimSpace(m.Note)))
+		}
+
+		if strings.TrimSpace(m.Content) == "" {
+			// 只有工具调用的消息
+		} else if isLong(m.Content) {
+			fmt.Fprintf(&b, "<details>\n<summary>%s <span class=\"more\">（共 %d 字，点击展开）</span></summary>\n<div class=\"body\">%s</div>\n</details>\n",
+				html.EscapeString(preview(m.Content)), utf8.RuneCountInString(m.Content), body.String())
+		} else {
+			fmt.Fprintf(&b, "<div class=\"body\">%s</div>\n", body.String())
+		}
+		for _, c := range m.ToolCalls {
+			if err := htmlToolCall(&b, c); err != nil {
+				return err
+			}
+		}
+		if m.Truncated {
+			b.WriteString("<div class=\"truncated\">回答被中断，内容不完整</div>\n")
+		}
+		b.WriteString("</section>\n")
+	}
+
+	b.WriteString("</main>\n</body>\n</html>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// htmlToolCall 工具调用显示为默认折叠的块，参数和结果按代码块高亮
+func htmlToolCall(b *strings.Builder, c session.ToolCall) error {
+	class := "tool"
+	if c.Error {
+		class += " error"
+	}
+	fmt.Fprintf(b, "<details class=\"%s\">\n<summary>🔧 %s</summary>\n", class, html.EscapeString(ToolCallLabel(c)))
+	for _, part := range []struct{ label, text string }{{"参数", c.Arguments}, {"结果", c.Result}} {
+		if part.text == "" {
+			continue
+		}
+		var code bytes.Buffer
+		if err := markdown.Convert([]byte(fenced(part.text)), &code); err != nil {
+			return fmt.Errorf("渲染工具调用失败: %w", err)
+		}
+		fmt.Fprintf(b, "<div class=\"tool-label\">%s</div>\n%s", part.label, code.String())
+	}
+	b.WriteString("</details>\n")
+	return nil
+}
+
+// isLong 判断消息是否需要折叠
+func isLong(content string) bool {
+	return utf8.RuneCountInString(content) > collapseRunes || strings.Count(content, "\n") > collapseLines
+}
+
+// preview 获取消息第一行的预览
+func preview(content string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	runes := []rune(line)
+	if len(runes) > 80 {
+		return string(runes[:80]) + "..."
+	}
+	return line
+}
+
+// transcriptRenderer 对话记录的渲染规则：围栏代码块使用chroma高亮；
+// 消息中的HTML标签原样显示为文本，既避免注入脚本，也不会丢失用户输入的内容
+type transcriptRenderer struct{}
+
+// RegisterFuncs 实现renderer.NodeRenderer
+func (r *transcriptRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindHTMLBlock, r.renderHTMLBlock)
+	reg.Register(ast.KindRawHTML, r.renderRawHTML)
+}
+
+func (r *transcriptRenderer) renderHTMLBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*ast.HTMLBlock)
+	w.WriteString("<p class=\"raw\">")
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		w.WriteString(html.EscapeString(string(seg.Value(source))))
+	}
+	if n.HasClosure() {
+		w.WriteString(html.EscapeString(string(n.ClosureLine.Value(source))))
+	}
+	w.WriteString("</p>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *transcriptRenderer) renderRawHTML(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*ast.RawHTML)
+	for i := 0; i < n.Segments.Len(); i++ {
+		seg := n.Segments.At(i)
+		w.WriteString(html.EscapeString(string(seg.Value(source))))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *transcriptRenderer) renderCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*ast.FencedCodeBlock)
+	var code strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		code.Write(seg.Value(source))
+	}
+
+	lexer := lexers.Get(string(n.Language(source)))
+	if lexer == nil {
+		lexer = lexers.Analyse(code.String())
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, code.String())
+	if err != nil {
+		fmt.Fprintf(w, "<pre><code>%s</code></pre>\n", html.EscapeString(code.String()))
+		return ast.WalkSkipChildren, nil
+	}
+
+	formatter := chromahtml.New(chromahtml.TabWidth(4))
+	if err := formatter.Format(w, styles.Get("github"), iterator); err != nil {
+		return ast.WalkStop, err
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// htmlStyle 导出页面的样式
+const htmlStyle = `
+body { margin: 0; background: #f6f7f9; color: #1f2328; font: 15px/1.65 -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; }
+header, main { max-width: 860px; margin: 0 auto; padding: 0 20px; }
+header { padding-top: 32px; }
+h1 { font-size: 24px; margin: 0 0 8px; }
+.meta { list-style: none; padding: 0; margin: 0 0 24px; color: #656d76; font-size: 13px; display: flex; flex-wrap: wrap; gap: 4px 16px; }
+.message { background: #fff; border: 1px solid #d0d7de; border-radius: 8px; padding: 12px 18px; margin-bottom: 16px; }
+.message.user { background: #eef4ff; border-color: #c8dafc; }
+.message.system { background: #fff8e6; border-color: #f0dca8; }
+.role { font-weight: 600; font-size: 13px; color: #57606a; margin-bottom: 4px; }
+.role time { font-weight: normal; margin-left: 8px; color: #8c959f; }
+.message.pinned { border-left: 4px solid #d4a72c; }
+.truncated { color: #9a6700; font-size: 13px; font-style: italic; }
+.note { background: #fff8c5; border-radius: 4px; padding: 4px 10px; margin-bottom: 8px; font-size: 14px; white-space: pre-wrap; }
+.body > :first-child { margin-top: 0; }
+.body > :last-child { margin-bottom: 0; }
+pre { padding: 12px; border-radius: 6px; overflow-x: auto; font-size: 13px; border: 1px solid #e1e4e8; }
+code { font-family: "SF Mono", Consolas, "Liberation Mono", monospace; }
+:not(pre) > code { background: #eff1f3; padding: 1px 5px; border-radius: 4px; font-size: 90%; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; }
+.raw { white-space: pre-wrap; }
+blockquote { margin: 0; padding-left: 12px; border-left: 3px solid #d0d7de; color: #57606a; }
+details > summary { cursor: pointer; color: #57606a; }
+.tool { border: 1px solid #d0d7de; border-radius: 6px; padding: 6px 12px; margin-top: 8px; background: #f6f8fa; font-size: 14px; }
+.tool.error { border-color: #ff818266; background: #fff5f5; }
+.tool-label { font-size: 12px; font-weight: 600; color: #57606a; margin: 6px 0 2px; }
+.tool pre { margin: 0; }
+details > summary .more { color: #0969da; font-size: 13px; }
+details[open] > summary { margin-bottom: 8px; }
+`
@@ -0,0 +1,541 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"ai-chat-cli/pkg/session"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// 页面布局，单位为点
+const (
+	pdfMargin     = 56 // 页边距
+	pdfFooter     = 24 // 页脚（页码）占用的高度
+	pdfBodySize   = 10.5
+	pdfCodeSize   = 8.5
+	pdfLineHeight = 1.5 // 行高与字号的比例
+	pdfIndentStep = 16  // 列表和引用每层的缩进
+)
+
+// 导出PDF使用的颜色
+var (
+	pdfText      = rgb(0x1f, 0x23, 0x28)
+	pdfMuted     = rgb(0x65, 0x6d, 0x76)
+	pdfLink      = rgb(0x09, 0x69, 0xda)
+	pdfUser      = rgb(0x09, 0x69, 0xda)
+	pdfAssistant = rgb(0x1a, 0x7f, 0x37)
+	pdfCodeText  = rgb(0x95, 0x38, 0x00)
+	pdfCodeBg    = rgb(0xf6, 0xf8, 0xfa)
+	pdfRule      = rgb(0xd0, 0xd7, 0xde)
+	pdfNote      = rgb(0x9a, 0x67, 0x00)
+	pdfError     = rgb(0xcf, 0x22, 0x2e)
+)
+
+// pdfSpan 一段使用同一字体（中文字符除外）和颜色的文字
+type pdfSpan struct {
+	text  string
+	font  pdfFont
+	color pdfColor
+}
+
+// pdfAtom 换行的最小单位：一个拉丁单词、一个空格或一个中文字符，等宽模式下为单个字符
+type pdfAtom struct {
+	text  string
+	font  pdfFont
+	color pdfColor
+	width float64
+	size  float64
+	space bool
+	br    bool // 强制换行
+}
+
+// pdfLayout 从上到下排版会话内容，空间不够时换页
+type pdfLayout struct {
+	doc    *pdfDoc
+	y      float64 // 下一行顶部到页面顶部的距离
+	indent float64 // 当前的左缩进
+	quote  int     // 引用的层数，每层在左侧画一条竖线
+	muted  bool    // 引用中的文字使用灰色
+}
+
+// PDF 将会话导出为PDF：标题和元信息、渲染后的Markdown和高亮的代码块，每页底部有页码。
+// 不嵌入字体，中文需要PDF阅读器支持 Adobe 中文字体包（Acrobat、macOS 预览、浏览器等都支持），
+// 表情符号无法显示，会被去掉
+func PDF(w io.Writer, s *session.Session) error {
+	l := &pdfLayout{doc: &pdfDoc{}}
+	l.newPage()
+
+	l.paragraph([]pdfSpan{{text: title(s), font: fontBold, color: pdfText}}, 18)
+	l.space(4)
+	for _, line := range metadata(s) {
+		l.paragraph([]pdfSpan{{text: line, font: fontRegular, color: pdfMuted}}, 9)
+	}
+	l.space(8)
+	l.rule()
+
+	for _, m := range s.Messages {
+		l.message(m)
+	}
+
+	// 所有页面排版完成后才知道总页数
+	for i := range l.doc.pages {
+		l.doc.current = i
+		number := fmt.Sprintf("%d / %d", i+1, len(l.doc.pages))
+		x := pdfPageWidth - pdfMargin - textWidth(number, fontRegular, 8)
+		l.doc.text(x, pdfPageHeight-pdfMargin/2, fontRegular, 8, pdfMuted, number)
+	}
+
+	return l.doc.write(w, title(s))
+}
+
+// message 排版一条消息：角色、时间、标记和备注，之后是渲染后的内容
+func (l *pdfLayout) message(m session.Message) {
+	color := pdfText
+	switch m.Role {
+	case "user":
+		color = pdfUser
+	case "assistant":
+		color = pdfAssistant
+	}
+	header := []pdfSpan{{text: strings.TrimSpace(stripUnsupported(roleName(m.Role))), font: fontBold, color: color}}
+	if !m.CreatedAt.IsZero() {
+		header = append(header, pdfSpan{text: "  " + m.CreatedAt.Format("2006-01-02 15:04"), font: fontRegular, color: pdfMuted})
+	}
+	if m.Pinned {
+		header = append(header, pdfSpan{text: "  [已标记]", font: fontBold, color: pdfNote})
+	}
+	l.space(6)
+	l.paragraph(header, 11.5)
+	if m.Note != "" {
+		l.paragraph([]pdfSpan{{text: "备注: " + strings.TrimSpace(m.Note), font: fontItalic, color: pdfNote}}, 9.5)
+	}
+	l.space(2)
+
+	source := []byte(m.Content)
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	l.block(doc, source)
+	for _, c := range m.ToolCalls {
+		l.toolCall(c)
+	}
+
+	if m.Truncated {
+		l.paragraph([]pdfSpan{{text: "（回答被中断，内容不完整）", font: fontItalic, color: pdfMuted}}, 9.5)
+	}
+	l.space(4)
+	l.rule()
+}
+
+// toolCall 排版一次工具调用：缩进的标题，之后是参数和结果的代码块。PDF中无法折叠，全部展开
+func (l *pdfLayout) toolCall(c session.ToolCall) {
+	color := pdfMuted
+	if c.Error {
+		color = pdfError
+	}
+	l.indent += pdfIndentStep
+	l.space(4)
+	l.paragraph([]pdfSpan{{text: strings.TrimSpace(stripUnsupported(ToolCallLabel(c))), font: fontBold, color: color}}, 9.5)
+	for _, part := range []struct{ label, text string }{{"参数", c.Arguments}, {"结果", c.Result}} {
+		if part.text == "" {
+			continue
+		}
+		l.paragraph([]pdfSpan{{text: part.label, font: fontItalic, color: pdfMuted}}, 9)
+		l.code(part.text, toolLanguage(part.text))
+	}
+	l.indent -= pdfIndentStep
+}
+
+// block 排版块级节点
+func (l *pdfLayout) block(node ast.Node, source []byte) {
+	switch n := node.(type) {
+	case *ast.Heading:
+		sizes := map[int]float64{1: 15, 2: 13.5, 3: 12}
+		size, ok := sizes[n.Level]
+		if !ok {
+			size = 11
+		}
+		l.space(4)
+		l.paragraph(l.inline(n, source, fontBold, pdfText), size)
+		l.space(2)
+	case *ast.Paragraph:
+		l.paragraph(l.inline(n, source, fontRegular, pdfText), pdfBodySize)
+		l.space(4)
+	case *ast.TextBlock:
+		l.paragraph(l.inline(n, source, fontRegular, pdfText), pdfBodySize)
+	case *ast.List:
+		number := n.Start
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			marker := "•"
+			if n.IsOrdered() {
+				marker = fmt.Sprintf("%d.", number)
+				number++
+			}
+			l.ensure(pdfBodySize * pdfLineHeight)
+			l.doc.text(pdfMargin+l.indent+4, l.y+pdfBodySize, fontRegular, pdfBodySize, l.color(pdfText), marker)
+			l.indent += pdfIndentStep
+			l.children(item, source)
+			l.indent -= pdfIndentStep
+		}
+		l.space(4)
+	case *ast.Blockquote:
+		l.indent += pdfIndentStep
+		l.quote++
+		muted := l.muted
+		l.muted = true
+		l.children(n, source)
+		l.indent -= pdfIndentStep
+		l.quote--
+		l.muted = muted
+	case *ast.FencedCodeBlock:
+		l.code(blockText(n, source), string(n.Language(source)))
+	case *ast.CodeBlock:
+		l.code(blockText(n, source), "")
+	case *ast.HTMLBlock:
+		l.paragraph([]pdfSpan{{text: blockText(n, source), font: fontMono, color: pdfText}}, pdfCodeSize)
+		l.space(4)
+	case *ast.ThematicBreak:
+		l.space(4)
+		l.rule()
+	case *east.Table:
+		l.table(n, source)
+	default:
+		l.children(n, source)
+	}
+}
+
+// children 依次排版子节点
+func (l *pdfLayout) children(node ast.Node, source []byte) {
+	for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+		l.block(c, source)
+	}
+}
+
+// table 表格的每行排为一段，单元格之间用竖线分隔，表头加粗
+func (l *pdfLayout) table(n *east.Table, source []byte) {
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		font := fontRegular
+		if _, ok := row.(*east.TableHeader); ok {
+			font = fontBold
+		}
+		var spans []pdfSpan
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			if len(spans) > 0 {
+				spans = append(spans, pdfSpan{text: "  |  ", font: fontRegular, color: pdfRule})
+			}
+			spans = append(spans, l.inline(cell, source, font, pdfText)...)
+		}
+		l.paragraph(spans, pdfBodySize-1)
+	}
+	l.space(4)
+}
+
+// inline 将行内节点转换为文字片段
+func (l *pdfLayout) inline(node ast.Node, source []byte, font pdfFont, color pdfColor) []pdfSpan {
+	var spans []pdfSpan
+	for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+		switch n := c.(type) {
+		case *ast.Text:
+			spans = append(spans, pdfSpan{text: string(n.Segment.Value(source)), font: font, color: color})
+			if n.HardLineBreak() {
+				spans = append(spans, pdfSpan{text: "\n", font: font, color: color})
+			} else if n.SoftLineBreak() {
+				spans = append(spans, pdfSpan{text: " ", font: font, color: color})
+			}
+		case *ast.String:
+			spans = append(spans, pdfSpan{text: string(n.Value), font: font, color: color})
+		case *ast.CodeSpan:
+			spans = append(spans, pdfSpan{text: inlineText(n, source), font: fontMono, color: pdfCodeText})
+		case *ast.Emphasis:
+			emphasis := fontItalic
+			if n.Level >= 2 {
+				emphasis = fontBold
+			}
+			spans = append(spans, l.inline(n, source, emphasis, color)...)
+		case *ast.Link:
+			spans = append(spans, l.inline(n, source, font, pdfLink)...)
+			if dest := string(n.Destination); dest != "" && dest != inlineText(n, source) {
+				spans = append(spans, pdfSpan{text: " (" + dest + ")", font: fontRegular, color: pdfMuted})
+			}
+		case *ast.AutoLink:
+			spans = append(spans, pdfSpan{text: string(n.URL(source)), font: font, color: pdfLink})
+		case *ast.Image:
+			spans = append(spans, pdfSpan{text: "[图片: " + inlineText(n, source) + "]", font: fontItalic, color: pdfMuted})
+		case *ast.RawHTML:
+			for i := 0; i < n.Segments.Len(); i++ {
+				seg := n.Segments.At(i)
+				spans = append(spans, pdfSpan{text: string(seg.Value(source)), font: font, color: color})
+			}
+		case *east.TaskCheckBox:
+			box := "[ ] "
+			if n.IsChecked {
+				box = "[x] "
+			}
+			spans = append(spans, pdfSpan{text: box, font: fontMono, color: color})
+		default:
+			spans = append(spans, l.inline(n, source, font, color)...)
+		}
+	}
+	return spans
+}
+
+// code 排版代码块：浅灰色背景、等宽字体、按语言高亮，超过一行的宽度时折行
+func (l *pdfLayout) code(code, language string) {
+	code = strings.ReplaceAll(strings.TrimRight(code, "\n"), "\t", "    ")
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		lexer = lexers.Analyse(code)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+
+	var spans []pdfSpan
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		spans = []pdfSpan{{text: code, font: fontMono, color: pdfText}}
+	} else {
+		style := styles.Get("github")
+		for _, token := range iterator.Tokens() {
+			color := pdfText
+			if c := style.Get(token.Type).Colour; c.IsSet() {
+				color = rgb(c.Red(), c.Green(), c.Blue())
+			}
+			spans = append(spans, pdfSpan{text: token.Value, font: fontMono, color: color})
+		}
+	}
+
+	const padding = 6
+	width := l.width() - 2*padding
+	lineHeight := pdfCodeSize * pdfLineHeight
+	l.space(2)
+	for i, line := range wrapAtoms(atomize(spans, pdfCodeSize, true), width) {
+		height := lineHeight
+		if i == 0 {
+			height += padding / 2
+		}
+		l.ensure(height)
+		l.doc.rect(pdfMargin+l.indent, l.y, l.width(), height, pdfCodeBg)
+		l.drawLine(line, pdfMargin+l.indent+padding, l.y+height-lineHeight+pdfCodeSize, pdfCodeSize)
+		l.y += height
+	}
+	l.ensure(padding / 2)
+	l.doc.rect(pdfMargin+l.indent, l.y, l.width(), padding/2, pdfCodeBg)
+	l.y += padding / 2
+	l.space(6)
+}
+
+// paragraph 排版一段文字，按可用宽度自动换行
+func (l *pdfLayout) paragraph(spans []pdfSpan, size float64) {
+	lineHeight := size * pdfLineHeight
+	for _, line := range wrapAtoms(atomize(spans, size, false), l.width()) {
+		l.ensure(lineHeight)
+		l.drawLine(line, pdfMargin+l.indent, l.y+size, size)
+		l.y += lineHeight
+	}
+}
+
+// drawLine 绘制一行，相邻且字体和颜色相同的部分合并为一次绘制
+func (l *pdfLayout) drawLine(line []pdfAtom, x, baseline, size float64) {
+	for level := 0; level < l.quote; level++ {
+		l.doc.rect(pdfMargin+float64(level)*pdfIndentStep+4, baseline-size, 2, size*pdfLineHeight, pdfRule)
+	}
+	for i := 0; i < len(line); {
+		j := i
+		var s strings.Builder
+		width := 0.0
+		for ; j < len(line) && line[j].font == line[i].font && line[j].color == line[i].color; j++ {
+			s.WriteString(line[j].text)
+			width += line[j].width
+		}
+		if strings.TrimSpace(s.String()) != "" {
+			l.doc.text(x, baseline, line[i].font, size, l.color(line[i].color), s.String())
+		}
+		x += width
+		i = j
+	}
+}
+
+// color 引用中的正文使用灰色
+func (l *pdfLayout) color(c pdfColor) pdfColor {
+	if l.muted && c == pdfText {
+		return pdfMuted
+	}
+	return c
+}
+
+// width 当前缩进下一行的可用宽度
+func (l *pdfLayout) width() float64 {
+	return pdfPageWidth - 2*pdfMargin - l.indent
+}
+
+// ensure 当前页剩余的高度不够时换页
+func (l *pdfLayout) ensure(height float64) {
+	if l.y+height > pdfPageHeight-pdfMargin-pdfFooter {
+		l.newPage()
+	}
+}
+
+// newPage 开始新的一页
+func (l *pdfLayout) newPage() {
+	l.doc.addPage()
+	l.y = pdfMargin
+}
+
+// space 添加垂直间距，在页首时忽略
+func (l *pdfLayout) space(height float64) {
+	if l.y > pdfMargin {
+		l.y += height
+	}
+}
+
+// rule 绘制分隔线
+func (l *pdfLayout) rule() {
+	l.ensure(8)
+	l.doc.hline(pdfMargin, pdfPageWidth-pdfMargin, l.y+4, 0.5, pdfRule)
+	l.y += 8
+}
+
+// atomize 将文字片段拆分为换行的最小单位，mono 为 true 时可以在任意字符处换行，并保留行首的空格
+func atomize(spans []pdfSpan, size float64, mono bool) []pdfAtom {
+	var atoms []pdfAtom
+	for _, span := range spans {
+		var word []rune
+		flush := func() {
+			if len(word) > 0 {
+				atoms = append(atoms, newAtom(string(word), span.font, span.color, size))
+				word = word[:0]
+			}
+		}
+		for _, r := range span.text {
+			switch {
+			case r == '\n':
+				flush()
+				atoms = append(atoms, pdfAtom{br: true})
+			case !pdfSupported(r):
+			case unicode.IsSpace(r):
+				flush()
+				if !mono && len(atoms) > 0 && atoms[len(atoms)-1].space {
+					// 连续的空格（包括去掉表情符号后留下的）只保留一个
+					continue
+				}
+				a := newAtom(" ", span.font, span.color, size)
+				a.space = !mono
+				atoms = append(atoms, a)
+			case mono || fontFor(span.font, r) == fontCJK:
+				flush()
+				atoms = append(atoms, newAtom(string(r), span.font, span.color, size))
+			default:
+				word = append(word, r)
+			}
+		}
+		flush()
+	}
+	return atoms
+}
+
+// newAtom 创建换行单位并计算宽度，中文字符使用中文字体
+func newAtom(s string, font pdfFont, color pdfColor, size float64) pdfAtom {
+	r := []rune(s)[0]
+	a := pdfAtom{text: s, font: fontFor(font, r), color: color, size: size}
+	a.width = textWidth(s, a.font, size)
+	return a
+}
+
+// textWidth 使用同一字体的文字的宽度
+func textWidth(s string, font pdfFont, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		units += runeWidth(font, r)
+	}
+	return float64(units) * size / 1000
+}
+
+// wrapAtoms 按宽度将换行单位分成多行，行首和行尾的空格被去掉，超过一行的单词按字符拆开
+func wrapAtoms(atoms []pdfAtom, width float64) [][]pdfAtom {
+	var lines [][]pdfAtom
+	var line []pdfAtom
+	used := 0.0
+	flush := func() {
+		for len(line) > 0 && line[len(line)-1].space {
+			line = line[:len(line)-1]
+		}
+		lines = append(lines, line)
+		line, used = nil, 0
+	}
+
+	for _, a := range atoms {
+		switch {
+		case a.br:
+			flush()
+		case a.space && len(line) == 0:
+		case used+a.width <= width || len(line) == 0 && a.width <= width:
+			line = append(line, a)
+			used += a.width
+		case a.space:
+			flush()
+		case a.width > width:
+			// 长单词（如URL）按字符拆开
+			for _, r := range a.text {
+				part := pdfAtom{text: string(r), font: a.font, color: a.color, size: a.size, width: textWidth(string(r), a.font, a.size)}
+				if used+part.width > width && len(line) > 0 {
+					flush()
+				}
+				line = append(line, part)
+				used += part.width
+			}
+		default:
+			flush()
+			line = append(line, a)
+			used = a.width
+		}
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		flush()
+	}
+	return lines
+}
+
+// blockText 获取块级节点的原文
+func blockText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		b.Write(seg.Value(source))
+	}
+	return b.String()
+}
+
+// inlineText 获取行内节点的纯文本
+func inlineText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(source))
+		case *ast.String:
+			b.Write(t.Value)
+		default:
+			b.WriteString(inlineText(c, source))
+		}
+	}
+	return b.String()
+}
+
+// stripUnsupported 去掉PDF中无法显示的字符
+func stripUnsupported(s string) string {
+	return strings.Map(func(r rune) rune {
+		if !pdfSupported(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
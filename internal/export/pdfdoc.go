@@ -0,0 +1,254 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// A4纸的尺寸，单位为点（1/72英寸）
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+)
+
+// pdfFont 导出PDF使用的字体。拉丁字母使用PDF阅读器内置的14种标准字体，
+// 中文使用 Adobe 中文字体包的 STSong-Light，都不嵌入字体文件，生成的文件很小
+type pdfFont int
+
+const (
+	fontRegular pdfFont = iota // Helvetica
+	fontBold                   // Helvetica-Bold
+	fontItalic                 // Helvetica-Oblique
+	fontMono                   // Courier
+	fontCJK                    // STSong-Light，拉丁字体不包含的字符都使用该字体
+)
+
+// pdfLatinFonts 拉丁字体的名称，顺序与 pdfFont 相同
+var pdfLatinFonts = [...]string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique", "Courier"}
+
+// helveticaWidths Helvetica 中可打印ASCII字符（32~126）的宽度，单位为字号的千分之一，Helvetica-Oblique 相同
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// helveticaBoldWidths Helvetica-Bold 中可打印ASCII字符的宽度
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// winAnsiExtra WinAnsiEncoding 中常用的非ASCII标点，使用拉丁字体比中文字体的全角字形更紧凑
+type winAnsiExtra struct {
+	code        byte
+	width, bold int
+}
+
+var winAnsiExtras = map[rune]winAnsiExtra{
+	'•': {0x95, 350, 350},
+	'–': {0x96, 556, 556},
+	'—': {0x97, 1000, 1000},
+	'‘': {0x91, 222, 278},
+	'’': {0x92, 222, 278},
+	'“': {0x93, 333, 500},
+	'”': {0x94, 333, 500},
+	'…': {0x85, 1000, 1000},
+}
+
+// pdfColor RGB颜色，各分量取值0~1
+type pdfColor struct{ r, g, b float64 }
+
+// rgb 根据0~255的分量创建颜色
+func rgb(r, g, b uint8) pdfColor {
+	return pdfColor{float64(r) / 255, float64(g) / 255, float64(b) / 255}
+}
+
+// fontFor 获取字符实际使用的字体：等宽字体只用于ASCII，其他拉丁字体还包括 winAnsiExtras 中的标点
+func fontFor(font pdfFont, r rune) pdfFont {
+	if r < 0x80 {
+		return font
+	}
+	if _, ok := winAnsiExtras[r]; ok && font != fontMono {
+		return font
+	}
+	return fontCJK
+}
+
+// runeWidth 字符在 font 中的宽度，单位为字号的千分之一。font 需要是 fontFor 返回的字体
+func runeWidth(font pdfFont, r rune) int {
+	switch {
+	case font == fontCJK:
+		return 1000
+	case font == fontMono:
+		return 600
+	case r < 0x20 || r > 0x7e:
+		extra := winAnsiExtras[r]
+		if font == fontBold {
+			return extra.bold
+		}
+		return extra.width
+	case font == fontBold:
+		return helveticaBoldWidths[r-0x20]
+	default:
+		return helveticaWidths[r-0x20]
+	}
+}
+
+// pdfSupported 判断字符能否显示：不嵌入字体时无法显示表情符号等基本多文种平面以外的字符，
+// 变体选择符、零宽字符和控制字符也去掉
+func pdfSupported(r rune) bool {
+	switch {
+	case r > 0xffff:
+		return false
+	case r < 0x20:
+		return false
+	case r >= 0x200b && r <= 0x200f, r >= 0xfe00 && r <= 0xfe0f:
+		return false
+	case r >= 0x2600 && r <= 0x27bf, r >= 0x2b00 && r <= 0x2bff:
+		// 杂项符号和装饰符号，大多是表情符号，中文字体中没有对应的字形
+		return false
+	}
+	return true
+}
+
+// encodeText 将使用同一字体的文字编码为PDF字符串：拉丁字体使用 WinAnsiEncoding，
+// 中文字体使用 UniGB-UCS2-H，即UTF-16BE编码的十六进制字符串
+func encodeText(font pdfFont, s string) string {
+	var b strings.Builder
+	if font == fontCJK {
+		b.WriteByte('<')
+		for _, r := range s {
+			fmt.Fprintf(&b, "%04X", r)
+		}
+		b.WriteByte('>')
+		return b.String()
+	}
+
+	b.WriteByte('(')
+	for _, r := range s {
+		c := byte(r)
+		if r >= 0x80 {
+			c = winAnsiExtras[r].code
+		}
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// pdfDoc 正在生成的PDF文档，每页的内容流单独保存，最后一起写入文件
+type pdfDoc struct {
+	pages   []*bytes.Buffer
+	current int // 绘制到哪一页
+}
+
+// addPage 添加一页，之后的绘制都在该页上
+func (d *pdfDoc) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.current = len(d.pages) - 1
+}
+
+// page 当前页的内容流
+func (d *pdfDoc) page() *bytes.Buffer {
+	return d.pages[d.current]
+}
+
+// text 在当前页绘制使用同一字体的文字，x 为左边距离，y 为基线到页面顶部的距离
+func (d *pdfDoc) text(x, y float64, font pdfFont, size float64, c pdfColor, s string) {
+	fmt.Fprintf(d.page(), "BT /F%d %.2f Tf %.3f %.3f %.3f rg %.2f %.2f Td %s Tj ET\n",
+		font+1, size, c.r, c.g, c.b, x, pdfPageHeight-y, encodeText(font, s))
+}
+
+// rect 填充矩形，y 为矩形顶部到页面顶部的距离
+func (d *pdfDoc) rect(x, y, w, h float64, c pdfColor) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", c.r, c.g, c.b, x, pdfPageHeight-y-h, w, h)
+}
+
+// hline 绘制水平线
+func (d *pdfDoc) hline(x1, x2, y, width float64, c pdfColor) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S\n", c.r, c.g, c.b, width, x1, pdfPageHeight-y, x2, pdfPageHeight-y)
+}
+
+// write 写入完整的PDF文件。对象依次为目录、页面树、文档信息、5个字体对象，之后每页一个页面对象和一个内容流
+func (d *pdfDoc) write(w io.Writer, title string) error {
+	var b bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// 第一页的页面对象编号
+	const firstPage = 11
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj(fmt.Sprintf("<< /Title %s /Producer (ai-chat-cli) /CreationDate (D:%s) >>", pdfTextString(title), time.Now().Format("20060102150405")))
+	for _, name := range pdfLatinFonts {
+		obj(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	obj("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [9 0 R] >>")
+	obj("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light " +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> /FontDescriptor 10 0 R /DW 1000 >>")
+	obj("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] " +
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+
+	fonts := "/F1 4 0 R /F2 5 0 R /F3 6 0 R /F4 7 0 R /F5 8 0 R"
+	for i, content := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, fonts, firstPage+2*i+1))
+
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(content.Bytes())
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", len(offsets), z.Len())
+		b.Write(z.Bytes())
+		b.WriteString("\nendstream\nendobj\n")
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pdfTextString 文档信息中使用的文字，以带BOM的UTF-16BE编码，可以包含中文
+func pdfTextString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
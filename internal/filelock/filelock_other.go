@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly || windows)
+
+package filelock
+
+import "os"
+
+// lock 当前平台不支持文件锁，只依靠原子写入保证文件完整
+func lock(f *os.File) error {
+	return nil
+}
+
+// unlock 当前平台不支持文件锁
+func unlock(f *os.File) error {
+	return nil
+}
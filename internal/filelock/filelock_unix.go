@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+
+package filelock
+
+import (
+	"os"
+	"syscall"
+)
+
+// lock 使用 flock 获取排他锁
+func lock(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlock 释放 flock 锁
+func unlock(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,114 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// 匹配后的处理方式，按严格程度从低到高排列
+const (
+	ActionWarn    = "warn"    // 发出警告并继续发送
+	ActionConfirm = "confirm" // 请用户确认后发送
+	ActionBlock   = "block"   // 拒绝发送（默认）
+)
+
+// maxMatchRunes 报告中保留的匹配内容长度
+const maxMatchRunes = 40
+
+// Rule 一条过滤规则
+type Rule struct {
+	Name   string
+	Action string
+	re     *regexp.Regexp
+}
+
+// Match 文本中一处匹配
+type Match struct {
+	Rule   string // 规则名称
+	Action string
+	Text   string // 匹配的内容，过长时截断
+}
+
+// Filter 一组按顺序检查的过滤规则
+type Filter struct {
+	rules []*Rule
+}
+
+// Add 添加一条规则，pattern 为正则表达式，keywords 为不区分大小写的关键词，两者至少指定一个
+func (f *Filter) Add(name, pattern string, keywords []string, action string) error {
+	switch action {
+	case "":
+		action = ActionBlock
+	case ActionWarn, ActionConfirm, ActionBlock:
+	default:
+		return fmt.Errorf("过滤规则 %s 的 action 无效: %s（可选 block、warn、confirm）", name, action)
+	}
+
+	var parts []string
+	if pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("过滤规则 %s 的 pattern 无效: %w", name, err)
+		}
+		parts = append(parts, "(?:"+pattern+")")
+	}
+	for _, k := range keywords {
+		if k = strings.TrimSpace(k); k != "" {
+			parts = append(parts, "(?i:"+regexp.QuoteMeta(k)+")")
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("过滤规则 %s 需要 pattern 或 keywords", name)
+	}
+
+	f.rules = append(f.rules, &Rule{Name: name, Action: action, re: regexp.MustCompile(strings.Join(parts, "|"))})
+	return nil
+}
+
+// Len 返回规则数量
+func (f *Filter) Len() int {
+	if f == nil {
+		return 0
+	}
+	return len(f.rules)
+}
+
+// Check 返回文本中所有匹配，同一规则的相同内容只报告一次
+func (f *Filter) Check(text string) []Match {
+	if f == nil {
+		return nil
+	}
+	var matches []Match
+	for _, r := range f.rules {
+		seen := map[string]bool{}
+		for _, m := range r.re.FindAllString(text, -1) {
+			if seen[m] {
+				continue
+			}
+			seen[m] = true
+			matches = append(matches, Match{Rule: r.Name, Action: r.Action, Text: truncate(m)})
+		}
+	}
+	return matches
+}
+
+// Strictest 返回匹配中最严格的处理方式，没有匹配时返回空字符串
+func Strictest(matches []Match) string {
+	rank := map[string]int{ActionWarn: 1, ActionConfirm: 2, ActionBlock: 3}
+	action := ""
+	for _, m := range matches {
+		if rank[m.Action] > rank[action] {
+			action = m.Action
+		}
+	}
+	return action
+}
+
+// truncate 截断过长的匹配内容
+func truncate(s string) string {
+	if utf8.RuneCountInString(s) <= maxMatchRunes {
+		return s
+	}
+	return string([]rune(s)[:maxMatchRunes]) + "…"
+}
@@ -0,0 +1,597 @@
+package i18n
+
+// enUS 英文消息目录
+var enUS = map[string]string{
+	// 通用
+	"config.using":               "Using config file: %s",
+	"config.load_failed":         "Failed to load config: %v",
+	"config.init_hint":           "Run 'ai-chat-cli config init' to create a config file",
+	"provider.auto":              "💡 Using provider: %s (auto-selected)",
+	"provider.not_found":         "Provider '%s' not found",
+	"provider.available":         "📋 Available providers:",
+	"provider.no_key":            "API key for provider '%s' is not set",
+	"provider.key_hint":          "Set the API key with:\n   ai-chat-cli config set providers.%s.api_key YOUR_API_KEY",
+	"provider.using":             "🚀 Provider: %s",
+	"provider.base_url":          "🌐 API URL: %s",
+	"provider.model":             "🤖 Model: %s",
+	"provider.cached":            "⚡ Using cached response (run ai-chat-cli cache clear to clear the cache)",
+	"provider.unsupported":       "Provider '%s' doesn't support %s",
+	"provider.capabilities_hint": "If the provider does support it, list its capabilities in providers.%s.capabilities in the config file",
+	"moderation.unknown":         "Unknown advanced.moderate_inputs value '%s' (expected warn or block), input moderation skipped",
+	"moderation.failed":          "Moderation failed: %v",
+	"moderation.flagged":         "Input was flagged: %s",
+	"session.save_failed":        "Failed to save session: %v",
+	"session.title_failed":       "Failed to save session title: %v",
+	"stats.latency":              "Latency: %.2fs",
+	"stats.first_token":          "First token: %.2fs",
+	"stats.speed":                "Speed: %.1f tokens/s",
+	"stats.unknown_mode":         "Unknown ui.stats value '%s' (expected on, off or verbose), using on",
+
+	// chat 命令
+	"chat.seed_with_session":         "--seed and --session cannot be used together",
+	"chat.resumed":                   "📂 Resuming session: %s (%d exchanges)",
+	"chat.continue_none":             "📂 No saved session for %s yet, starting a new one",
+	"chat.seed_loaded":               "🌱 Loaded seed conversation: %s (%d messages)",
+	"chat.preset_loaded":             "🧩 Using preset: %s",
+	"chat.empty_input":               "Input is empty",
+	"chat.stdin_as_invalid":          "Unsupported --stdin-as value: %s (expected context, prompt or ignore)",
+	"chat.stdin_as_prompt_args":      "--stdin-as prompt uses piped input as the question, do not pass a question argument",
+	"chat.history_role_invalid":      "Invalid role '%s' in advanced.history_roles (expected user or assistant), ignored",
+	"chat.failed":                    "Chat failed: %v",
+	"chat.notify_done":               "✅ Answer ready: %s",
+	"chat.notify_failed":             "❌ Chat failed: %v",
+	"chat.interrupted":               "Interrupted before a reply was received",
+	"chat.partial_kept":              "✂️  Kept the partial answer received before the interruption, marked as truncated in the session",
+	"chat.route":                     "🧭 Route: %s → %s (%s)",
+	"chat.route_reason_short":        "short conversation",
+	"chat.route_reason_long":         "long input",
+	"chat.route_reason_code":         "contains code",
+	"chat.route_reason_reasoning":    "needs reasoning or analysis",
+	"chat.route_reason_classifier":   "decided by classifier model",
+	"chat.route_reason_override":     "chosen manually",
+	"chat.route_invalid":             "Unsupported --route value: %s (expected auto, fast, smart or off)",
+	"chat.route_unconfigured":        "No routing.%s model is configured, using the provider's default model",
+	"chat.route_classifier_invalid":  "Unknown routing.classifier value '%s' (expected heuristic or model), using heuristic",
+	"chat.route_classifier_failed":   "Classifier model failed, falling back to heuristics: %v",
+	"chat.pipeline_invalid":          "Invalid --pipeline value: %s (expected draft=<model>,refine=<model>)",
+	"chat.pipeline_with_route":       "--pipeline cannot be used together with --route",
+	"chat.pipeline_draft":            "✏️  Drafting: %s",
+	"chat.pipeline_refine":           "🔍 Refining: %s",
+	"chat.offline_hint":              "Use --queue to queue the question and run 'ai-chat-cli queue flush' once you are back online",
+	"chat.queued":                    "📥 Network unavailable, question queued (%s); run 'ai-chat-cli queue flush' once you are back online",
+	"chat.tee":                       "📝 Also writing answers to: %s",
+	"chat.clipboard_failed":          "Failed to read the clipboard: %v",
+	"chat.clipboard_empty":           "The clipboard has no text",
+	"chat.copied":                    "📋 Answer copied to the clipboard",
+	"chat.copy_failed":               "Failed to copy to the clipboard: %v",
+	"chat.tee_failed":                "Cannot write %s: %v",
+	"chat.stream_json_with_pipeline": "--stream-json cannot be used together with --pipeline",
+	"chat.stream_json_interactive":   "--stream-json only works for one-shot questions; pass the question as an argument or on stdin",
+	"chat.length_truncated":          "The answer hit the max_tokens limit and is incomplete",
+	"chat.continue_hint_repl":        "Type /continue to fetch the rest",
+	"chat.continue_hint_once":        "Use --continue-output to fetch the rest automatically and append it",
+	"chat.continue_output_repl":      "--continue-output only works for one-shot questions; use /continue in interactive mode",
+	"chat.content_filtered":          "The answer was filtered by the provider's content policy and may be empty or incomplete",
+	"chat.refused":                   "The model refused to answer this question",
+	"chat.json_interactive":          "--json only works for one-shot questions; pass the question as an argument or on stdin",
+	"chat.samples_interactive":       "--n only works for one-shot questions; pass the question as an argument or on stdin",
+	"chat.samples_range":             "--n must be between 1 and %d",
+	"chat.best_of_needs_n":           "--best-of requires --n greater than 1",
+	"chat.samples_with_pipeline":     "--n cannot be used with --pipeline",
+	"chat.samples_failed":            "%d of %d answer requests failed: %v",
+	"chat.judge_failed":              "Judging failed, showing all answers: %v",
+	"chat.judge_chose":               "🏆 Judge model %s picked answer %d of %d",
+	"chat.sample_heading":            "Answer %d/%d",
+	"chat.idle_exit":                 "⏰ Idle for more than %s, leaving chat",
+	"chat.idle_locked":               "🔒 Idle timeout: conversation locked and cleared from memory. Enter the unlock passphrase to restore it (the session key is read again)",
+	"chat.idle_unlocked":             "🔓 Unlocked, %d turns restored",
+	"chat.idle_unlock_failed":        "Unlock failed: %v",
+	"chat.idle_lock_unavailable":     "ui.idle_action lock requires advanced.encrypt_sessions with saved sessions; the chat will exit when idle instead",
+	"chat.idle_lock_no_passphrase":   "ui.idle_action lock requires an unlock passphrase, run 'ai-chat-cli session passphrase' first; the chat will exit when idle instead",
+	"chat.idle_lock_bad_passphrase":  "Cannot read the unlock passphrase (%v); the chat will exit when idle instead",
+	"chat.idle_passphrase":           "🔑 Unlock passphrase: ",
+	"chat.idle_passphrase_wrong":     "❌ Wrong passphrase",
+	"chat.idle_action_invalid":       "Unknown ui.idle_action: %s (expected exit or lock); the chat will exit when idle",
+	"chat.ai":                        "AI: ",
+	"chat.you":                       "You: ",
+	"chat.usage":                     "Tokens: %d (prompt: %d, completion: %d) | Exchanges: %d",
+	"chat.banner":                    "🤖 AI Chat CLI - interactive mode (with conversation memory)",
+	"chat.start":                     "💡 Type a question to start",
+	"chat.commands":                  "💡 Commands:",
+	"chat.cmd_quit":                  "   • quit/exit - exit",
+	"chat.cmd_clear":                 "   • clear - clear the screen",
+	"chat.cmd_reset":                 "   • reset - reset the conversation",
+	"chat.cmd_history":               "   • history - show the conversation",
+	"chat.cmd_undo":                  "   • /undo - undo the last exchange",
+	"chat.cmd_drop":                  "   • /drop <n> - delete message n",
+	"chat.cmd_redact":                "   • /redact <n> [text] - hide message n or the given text in it",
+	"chat.cmd_find":                  "   • /find <text> - find messages containing the text",
+	"chat.cmd_pin":                   "   • /pin <n>, /note <n> <note> - pin message n or add a note to it, saved in the session",
+	"chat.cmd_diff":                  "   • /diff [n m] - diff the last two AI replies (or messages n and m)",
+	"chat.cmd_route":                 "   • /fast <question>, /smart <question> - use the fast or smart model for this message",
+	"chat.cmd_memory":                "   • /remember key=value - remember a fact for later turns and sessions; /memory list, /memory forget <key> to view or remove",
+	"chat.cmd_models":                "   • /models [filter] - pick the model for this chat from the provider's model list",
+	"chat.cmd_continue":              "   • /continue - fetch the rest of an answer cut off by the length limit and append it",
+	"chat.cmd_help":                  "   • help - show help",
+	"chat.retry_hint":                "💡 If your input gets garbled, press Enter and type it again",
+	"chat.input_error":               "Input error: %v",
+	"chat.invalid_input":             "Input contains invalid characters, please try again",
+	"chat.resume_hint":               "💡 Continue later with: ai-chat-cli chat --session %s",
+	"chat.bye":                       "👋 Bye!",
+	"chat.history_count":             "💡 Conversation so far: %d exchanges",
+	"chat.start_help":                "💡 Type a question to start, or 'help' for commands",
+	"chat.reset_done":                "🔄 Conversation reset",
+	"chat.help_title":                "🆘 Commands:",
+	"chat.help_history":              "   • history - show the conversation with message numbers",
+	"chat.help_undo":                 "   • /undo - undo the last exchange so it is no longer sent as context",
+	"chat.help_redact":               "   • /redact <n> [text] - hide message n, or only the given text in it",
+	"chat.help_help":                 "   • help - show this help",
+	"chat.help_ask":                  "   • type anything else to ask a question",
+	"chat.cleaned":                   "📝 Cleaned input: %s",
+	"chat.failed_repl":               "❌ Chat failed: %v",
+	"chat.network_hint":              "💡 Check your network connection and try again, or type 'help' for commands",
+	"chat.history_empty":             "📝 No messages yet",
+	"chat.history_title":             "📝 Conversation:",
+	"chat.history_total":             "📊 %d exchanges in total",
+
+	// 交互模式中的 / 命令
+	"chat.dropped":            "🗑️  Deleted message %d",
+	"chat.redact_not_found":   "❌ Message %d does not contain the given text",
+	"chat.redacted":           "🙈 Hid the content of message %d",
+	"chat.redacted_content":   "[redacted]",
+	"chat.pinned":             "📌 Pinned message %d, see pinned messages with session show --pinned",
+	"chat.unpinned":           "📌 Unpinned message %d",
+	"chat.noted":              "📝 Added a note to message %d",
+	"chat.note_removed":       "📝 Removed the note from message %d",
+	"chat.mark_unsaved":       "❌ Message %d is not saved in a session and cannot be marked (enable advanced.save_history)",
+	"chat.find_usage":         "❌ Usage: /find <text>",
+	"chat.find_title":         "🔍 Messages containing \"%s\":",
+	"chat.find_none":          "🔍 No messages contain \"%s\"",
+	"chat.find_total":         "📊 %d messages in total",
+	"chat.diff_none":          "💡 At least two AI replies are needed to diff",
+	"chat.diff_usage":         "💡 Usage: /diff compares the last two AI replies, /diff <n> <m> compares messages n and m",
+	"chat.diff_label":         "message %d",
+	"chat.diff_same":          "✓ Messages %d and %d are identical",
+	"chat.undo_none":          "📝 Nothing to undo",
+	"chat.undone":             "↩️  Undid the last exchange",
+	"chat.unknown_command":    "Unknown command: %s, type 'help' for available commands",
+	"chat.index_usage":        "❌ Usage: %s <message number>, type 'history' to see message numbers",
+	"chat.index_invalid":      "❌ Invalid message number: %s, there are %d messages",
+	"chat.remember_usage":     "❌ Usage: /remember key=value, e.g. /remember stack=Go + PostgreSQL",
+	"chat.remembered":         "🧠 Remembered %s = %s",
+	"chat.memory_empty":       "🧠 Nothing remembered yet, use /remember key=value to add a fact",
+	"chat.memory_title":       "🧠 Remembered facts (profile %s):",
+	"chat.forgotten":          "🧠 Forgot %s",
+	"chat.forget_not_found":   "❌ Nothing remembered as %s, type /memory list to see facts",
+	"chat.memory_usage":       "❌ Usage: /memory list or /memory forget <key>",
+	"chat.memory_failed":      "Failed to save memory: %v",
+	"chat.memory_unavailable": "Cannot read memory, this chat runs without it: %v",
+	"chat.models_failed":      "Failed to fetch the model list: %v",
+	"chat.model_unchanged":    "🤖 Model unchanged",
+	"chat.model_switched":     "🤖 This chat now uses model: %s",
+	"chat.continue_no_answer": "💬 No answer to continue",
+	"chat.continue_failed":    "❌ Failed to fetch the rest of the answer: %v",
+
+	// 提供商、缓存、过滤和其他命令
+	"provider.metadata_invalid":  "Invalid metadata in the provider config, ignored: %v",
+	"provider.compat_invalid":    "providers.%s.compat: %v, using max_tokens",
+	"provider.capabilities_none": "none",
+	"provider.auth_unsupported":  "Unsupported auth type: %s (options: api_key, azure_ad, oauth, google_adc)",
+	"ui.theme_invalid":           "%v, using the default theme",
+	"list.separator":             ", ",
+	"cache.expired_cleared":      "Deleted %d expired cache entries",
+	"cache.cleared":              "Deleted %d cache entries",
+	"cache.unavailable":          "Reply cache unavailable: %v",
+	"chat.stdin_failed":          "Failed to read standard input: %v",
+	"chat.memory_off":            "memory is unavailable",
+	"chat.best_of_invalid":       "--best-of should be judge=<model>, e.g. judge=gpt-4o-mini",
+	"chat.judge_unparsed":        "Cannot find a candidate number in the judge's reply: %q",
+	"pii.masked":                 "🛡️  Replaced %d pieces of sensitive information before sending: %s",
+	"filter.invalid":             "The filter rules are invalid, refused to send",
+	"filter.blocked":             "The prompt contains restricted content, refused to send (%s)",
+	"filter.separator":           "; ",
+	"filter.matched":             "The prompt matches filter rule %s: %s",
+	"filter.confirm_required":    "The prompt contains restricted content that needs confirmation, refused to send",
+	"filter.confirm":             "Send anyway?",
+	"filter.cancelled":           "The prompt contains restricted content, sending cancelled",
+	"guard.too_large":            "The input is too large, sending cancelled",
+	"guard.estimate":             "about %d tokens",
+	"guard.estimate_cost":        ", about $%.2f",
+	"guard.over_limit":           "The input of this request is %s, above the confirmation threshold of %d tokens",
+	"guard.confirm":              "Send anyway?",
+	"guard.hint":                 "Adjust advanced.confirm_input_tokens, or set it to -1 to turn the check off",
+	"guard.non_interactive":      "%w (%s, cannot confirm in non-interactive mode)",
+	"audit.disabled":             "Audit log disabled: %v",
+	"dryrun.request":             "🧪 Dry run, request not sent: POST %s",
+	"dryrun.build_failed":        "Failed to build the request: %v",
+	"dryrun.estimate":            "📏 Estimated input: about %d tokens (%d messages)",
+	"session.key_generated":      "🔑 Generated a session encryption key and saved it to the system keychain",
+	"session.key_saved_file":     "No system keychain available, the session encryption key was saved to %s, keep it safe",
+	"models.save_needs_pick":     "--save requires --pick",
+	"models.pick_needs_terminal": "--pick must run in a terminal",
+	"models.none_matched":        "📭 No models match %q",
+	"models.matched":             "🔍 Models matching %q (%d in total):",
+	"models.available":           "📋 Available models (%d in total):",
+	"models.more":                "  … %d more, type text to narrow the list",
+	"models.prompt":              "Type a number to choose, text to filter, or press Enter to cancel: ",
+	"models.invalid":             "❌ Invalid number: %d",
+	"models.current":             "(current)",
+	"models.cancelled":           "Cancelled",
+	"models.save_hint":           "Use --save to make %s the default model of provider %s",
+	"models.no_config":           "No config file found",
+	"models.save_failed":         "Failed to save the config: %v",
+	"models.saved":               "Set the default model of provider %s to %s",
+	"lock.passphrase_terminal":   "session passphrase must run in a terminal",
+	"lock.passphrase_new":        "🔑 New unlock passphrase: ",
+	"lock.passphrase_again":      "🔑 Repeat: ",
+	"lock.passphrase_empty":      "The passphrase cannot be empty",
+	"lock.passphrase_mismatch":   "The passphrases do not match",
+	"lock.passphrase_failed":     "Failed to save the unlock passphrase: %v",
+	"lock.passphrase_saved":      "Unlock passphrase set (verifier saved to %s)",
+
+	// config 命令
+	"config.path_failed":      "Cannot determine the config file path: %v",
+	"config.exists":           "Config file already exists: %s",
+	"config.exists_hint":      "To re-initialize, delete the existing config file first.",
+	"config.create_failed":    "Failed to create config file: %v",
+	"config.created":          "Config file created: %s",
+	"config.edit_keys":        "Edit the config file to set your API keys:",
+	"config.openai_key":       "- OpenAI API key: set the OPENAI_API_KEY environment variable",
+	"config.anthropic_key":    "- Anthropic API key: set the ANTHROPIC_API_KEY environment variable",
+	"config.key_field":        "Or set the api_key field directly in the config file.",
+	"config.current":          "Current configuration:",
+	"config.default_provider": "Default provider: %s",
+	"config.stream":           "Streaming output: %t",
+	"config.max_retries":      "Max retries: %d",
+	"config.timeout":          "Timeout: %ds",
+	"config.cost_limit":       "Cost limit: $%.2f",
+	"config.providers":        "Configured providers:",
+	"config.key_unset":        "not set",
+	"config.key_token":        "not used (%s auth)",
+	"config.key_set":          "set",
+	"config.key_env":          "environment variable",
+	"config.model":            "Model: %s",
+	"config.api_key":          "API key: %s",
+	"config.max_tokens":       "Max tokens: %d",
+	"config.not_found":        "No config file found",
+	"config.save_failed":      "Failed to save config: %v",
+	"config.set":              "Set %s = %s",
+
+	// session 命令
+	"session.none_tagged":          "📝 No sessions tagged %s",
+	"session.none":                 "📝 No saved sessions",
+	"session.list_title":           "📝 Saved sessions:",
+	"session.list_row":             "%s  %s  %-12s %3d msgs  %s",
+	"session.no_tags":              "📝 The session has no tags",
+	"session.tags_required":        "Specify tags, separated by commas",
+	"session.tags_cleared":         "Updated tags of session %s, it now has no tags",
+	"session.tags_updated":         "Updated tags of session %s: %s",
+	"session.provider":             "Provider: %s",
+	"session.model":                "Model: %s",
+	"session.tags":                 "Tags: %s",
+	"session.dir":                  "Directory: %s",
+	"session.created":              "Created: %s",
+	"session.marked":               "Marked messages: %s",
+	"session.no_marked":            "📌 No marked messages in the session, mark them in interactive mode with /pin <n> or /note <n> <note>",
+	"session.truncated":            "✂️  The answer was interrupted and is incomplete",
+	"session.tool_result":          "Result",
+	"session.tool_args":            "Arguments",
+	"session.tool_folded":          "… (%d more lines, --tools shows everything)",
+	"session.deleted":              "Session deleted: %s",
+	"session.pdf_terminal":         "PDF cannot be written to the terminal, use -o to choose an output file",
+	"session.format_invalid":       "Unsupported export format: %s (choose markdown, html or pdf)",
+	"session.export_failed":        "Export failed: %v",
+	"session.create_failed":        "Failed to create file: %v",
+	"session.exported":             "Exported to %s",
+	"session.keep_negative":        "--keep cannot be negative",
+	"session.compact_none":         "📝 The session only has the last %d rounds, nothing to compact",
+	"session.compacting":           "🗜️  Summarizing %d earlier messages...",
+	"session.summary_failed":       "Failed to generate the summary: %v",
+	"session.compacted":            "Compacted session %s: %d messages → %d",
+	"session.archive":              "📦 Full copy: %s",
+	"session.gist_token":           "Sharing to gist needs a GitHub token",
+	"session.gist_token_hint":      "Use 'ai-chat-cli config set share.gist_token <token>' or set the GITHUB_TOKEN environment variable",
+	"session.paste_url":            "Sharing to paste needs share.paste_url to be set",
+	"session.share_target_invalid": "Unsupported share target: %s (choose gist, 0x0 or paste)",
+	"session.secrets_found":        "Found %d likely secrets in the session:",
+	"session.secret_line":          "• line %d %s: %s",
+	"session.share_cancelled":      "Upload cancelled, redact these first",
+	"session.share_redact_hint":    "Redact them with /redact in interactive mode and retry, or use --allow-secrets to upload anyway once you have checked them",
+	"session.uploading":            "📤 Uploading to %s...",
+	"session.share_failed":         "Share failed: %v",
+	"session.encrypted":            "Encrypted %d sessions",
+	"session.encrypt_hint":         "Use 'ai-chat-cli config set advanced.encrypt_sessions true' to encrypt sessions saved from now on",
+	"session.decrypted":            "Decrypted %d sessions",
+	"session.not_found":            "Session not found: %s",
+	"session.list_hint":            "Use 'ai-chat-cli session list' to see saved sessions",
+
+	// serve 命令
+	"serve.dry_run":              "serve does not support --dry-run",
+	"serve.log_file":             "📝 Logging to: %s",
+	"serve.started":              "🚀 Gateway started: http://%s/v1",
+	"serve.default_provider":     "🤖 Default provider: %s",
+	"serve.keys_enabled":         "🔑 Access key authentication enabled (%d keys)",
+	"serve.no_keys":              "No access keys configured (serve.keys), anyone on the local network can use your providers",
+	"serve.stop_hint":            "Press Ctrl+C to stop the server",
+	"serve.failed":               "Server exited unexpectedly: %v",
+	"serve.metrics":              "%d upstream requests, %d failed, average latency %s",
+	"serve.merged":               "♻️  %d requests merged with identical in-flight requests",
+	"serve.stopped":              "👋 Server stopped",
+	"serve.no_providers":         "No provider has an API key set",
+	"serve.provider_unavailable": "Provider '%s' not found or has no API key set",
+	"serve.unpriced":             "Provider '%s' has no price for its default model, its usage is not counted towards daily_cost",
+	"provider.price_hint":        "Set input_price and output_price in the provider config (USD per million tokens)",
+	"path.home_failed":           "Failed to get the home directory: %w",
+	"serve.key_empty":            "Access key '%s' has no key set, ignored",
+	"serve.key_duplicate":        "Access keys '%s' and '%s' have the same key",
+	"serve.reload_invalid":       "%s config file is invalid, keeping the previous config: %v",
+	"serve.reloaded":             "🔄 %s config reloaded: %d providers, default %s, %d access keys",
+	"serve.watch_failed":         "Cannot watch the config file for changes: %v",
+
+	// schedule 命令
+	"schedule.prompt_required": "Specify a template with --template or give the prompt directly",
+	"schedule.var_invalid":     "Invalid variable: %s, expected key=value",
+	"schedule.added":           "Scheduled job %s added",
+	"schedule.next":            "Next run: %s",
+	"schedule.run_hint":        "Use 'ai-chat-cli schedule run' to start the daemon",
+	"schedule.none":            "📝 No scheduled jobs yet",
+	"schedule.add_hint":        "Use 'ai-chat-cli schedule add' to add a job",
+	"schedule.template":        "template %s",
+	"schedule.cron_invalid":    "invalid expression",
+	"schedule.stdout":          "standard output",
+	"schedule.next_output":     "Next run: %s | Output: %s",
+	"schedule.succeeded":       "succeeded",
+	"schedule.failed_status":   "failed: %s",
+	"schedule.last_run":        "Last run: %s %s",
+	"schedule.removed":         "Scheduled job %s removed",
+	"schedule.not_found":       "Scheduled job %s does not exist",
+	"schedule.daemon_started":  "🚀 Scheduler daemon started, press Ctrl+C to exit",
+	"schedule.daemon_stopped":  "👋 Daemon stopped",
+	"schedule.job_invalid":     "Job %s: %v",
+	"schedule.running":         "[%s] ▶ Running job %s",
+	"schedule.job_failed":      "Job %s failed: %v",
+	"schedule.job_done":        "Job %s done, result written to %s",
+	"schedule.record_failed":   "Failed to save the run record: %v",
+	"schedule.provider_failed": "Failed to load the AI provider",
+	"schedule.mkdir_failed":    "Failed to create the output directory: %w",
+	"schedule.write_failed":    "Failed to write the result: %w",
+	"schedule.path_failed":     "Failed to get the job file path: %v",
+
+	// batch 命令
+	"batch.output_required":     "Specify the result file with --output",
+	"batch.resuming":            "♻️  %d jobs already done, continuing with the remaining %d",
+	"batch.all_done":            "All jobs are already done",
+	"batch.open_failed":         "Failed to open the result file: %v",
+	"batch.concurrency_rpm":     "🚦 Concurrency: %d, rate limit: %d requests per minute",
+	"batch.concurrency":         "🚦 Concurrency: %d",
+	"batch.write_failed":        "Failed to write the result: %v",
+	"batch.interrupted":         "⏹️  Interrupted: %d done, %d failed, results written to %s, run the same command again to continue with the remaining jobs",
+	"batch.done":                "%d done, %d failed, results written to %s",
+	"batch.merged":              "♻️  %d jobs merged with identical jobs in progress, no duplicate requests were sent",
+	"batch.progress":            "⏳ Progress %d/%d | failed %d | elapsed %s | remaining %s",
+	"batch.dry_run":             "🧪 Dry run, no requests sent: %d jobs, about %d input tokens",
+	"batch.empty_prompt":        "Empty prompt",
+	"batch.read_failed":         "Failed to read the job file: %w",
+	"batch.line_invalid":        "Line %d is malformed: %w",
+	"batch.duplicate_id":        "Line %d: duplicate ID '%s'",
+	"batch.read_results_failed": "Failed to read the result file: %w",
+
+	// batch submit/status/fetch 命令
+	"batch.unsupported_option": "Async batch results are downloaded later, so redaction cannot be reverted and audit logs cannot be recorded; disable %s before submitting",
+	"batch.job_error":          "Job %s: %v",
+	"batch.job_rejected":       "Job %s: %w",
+	"batch.uploading":          "📤 Uploading %d requests...",
+	"batch.submit_failed":      "Failed to submit the batch: %v",
+	"batch.created":            "Batch created: %s",
+	"batch.status_hint":        "Check progress: ai-chat-cli batch status %s",
+	"batch.fetch_hint":         "Download results: ai-chat-cli batch fetch %s --output results.jsonl",
+	"batch.interval_min":       "--interval cannot be less than %s",
+	"batch.get_failed":         "Failed to query the batch: %v",
+	"batch.unfinished_hint":    "The batch has not finished yet, use --wait to wait and download automatically",
+	"batch.download_failed":    "Failed to download the results: %v",
+	"batch.create_failed":      "Failed to create the result file: %v",
+	"batch.downloaded":         "Downloaded %d results (%d failed) to %s",
+	"batch.unsupported":        "Provider '%s' does not support async batches",
+	"batch.info":               "📦 Batch: %s",
+	"batch.status":             "Status: %s",
+	"batch.counts":             "Progress: %d completed, %d failed, %d total",
+	"batch.created_at":         "Created: %s",
+	"batch.completed_at":       "Completed: %s",
+
+	// summarize 命令
+	"summarize.length_invalid":  "Unsupported summary length: %s (choose short, medium or long)",
+	"summarize.format_invalid":  "Unsupported summary format: %s (choose bullets, paragraph or outline)",
+	"chunk.size_invalid":        "--chunk-size must be greater than 0",
+	"summarize.empty":           "Nothing to summarize",
+	"summarize.failed":          "Summarization failed: %v",
+	"summarize.chunk":           "📄 Summarizing part %d/%d...",
+	"summarize.chunk_failed":    "Failed to summarize part %d: %w",
+	"summarize.not_shorter":     "The partial summaries did not shorten the text, increase --chunk-size",
+	"summarize.merging":         "🧩 Merging summaries...",
+	"summarize.pdftotext":       "Reading PDFs requires pdftotext (poppler-utils)",
+	"summarize.pdf_failed":      "Failed to extract text from the PDF: %w",
+	"summarize.download_failed": "Failed to download the page: %w",
+	"summarize.download_status": "Failed to download the page: HTTP %d",
+	"summarize.read_failed":     "Failed to read the page: %w",
+
+	// translate 命令
+	"translate.glossary_failed": "Failed to load the glossary: %v",
+	"translate.detected":        "🔍 Detected source language: %s",
+	"translate.failed":          "Translation failed: %v",
+	"translate.blocks_missing":  "%d code blocks could not be restored in the translation",
+	"translate.glossary_line":   "Line %d is malformed, expected \"source = translation\"",
+
+	// explain 命令
+	"explain.level_invalid":  "Unsupported explanation level: %s (choose beginner, intermediate or expert)",
+	"file.read_failed":       "Failed to read the file: %v",
+	"explain.range_overflow": "Line range exceeds the file length (%d lines)",
+	"explain.explaining":     "📖 Explaining %s lines %d-%d (%s)",
+	"explain.failed":         "Explanation failed: %v",
+	"explain.range_invalid":  "Invalid line range: %s",
+
+	// review 命令
+	"review.format_invalid": "Unsupported output format: %s (choose text or github)",
+	"review.diff_failed":    "Failed to get the Git diff: %s",
+	"review.no_changes":     "No changes to review",
+	"review.chunk":          "🔍 Reviewing part %d/%d...",
+	"review.failed":         "Review failed: %v",
+	"review.unparsed":       "Could not parse the review of part %d, skipped: %v",
+	"review.no_issues":      "No issues found",
+	"review.summary":        "%d review comments in %d files",
+
+	// proofread 命令
+	"proofread.chunk":      "📝 Proofreading part %d/%d...",
+	"proofread.failed":     "Proofreading failed: %v",
+	"proofread.diff_label": "%s (proofread)",
+	"proofread.no_changes": "Nothing needs changing",
+	"proofread.write_hint": "Use --write to write the changes back to the file",
+	"file.write_failed":    "Failed to write the file: %v",
+	"proofread.written":    "Written to %s",
+
+	// queue 命令
+	"queue.empty":             "📭 No requests in the queue",
+	"queue.attempts":          "Failed %d times: %s",
+	"queue.total":             "%d requests, run 'ai-chat-cli queue flush' to send them",
+	"queue.interrupted":       "Interrupted, %d requests are still queued",
+	"queue.offline":           "Network still unavailable, %d requests are still queued",
+	"queue.wait_hint":         "Use --wait to send automatically once the network is back",
+	"queue.retrying":          "Network still unavailable, retrying in %s (%d requests waiting)",
+	"queue.partial":           "Sent %d requests, %d failed and remain in the queue",
+	"queue.flushed":           "Sent %d requests, the queue is now empty",
+	"queue.remove_failed":     "Failed to remove %s from the queue: %v",
+	"provider.not_configured": "Provider '%s' is not configured",
+	"queue.not_found":         "No request %s in the queue",
+	"queue.delete_failed":     "Failed to delete %s: %v",
+	"queue.deleted":           "Deleted %s",
+
+	// ab 命令
+	"ab.inputs_required":  "Specify the test case file with --inputs",
+	"ab.inputs_empty":     "Test case file %s is empty",
+	"ab.create_failed":    "Failed to create the result file: %v",
+	"ab.write_failed":     "Failed to write the result: %v",
+	"ab.interrupted":      "⏹️  Interrupted, %d/%d test cases done",
+	"ab.written":          "Results written to %s",
+	"ab.variant_conflict": "--template-%s and --prompt-%s cannot be used together",
+	"ab.variant_required": "Specify variant %[3]s with --template-%[1]s or --prompt-%[2]s",
+	"ab.judge_error":      "judge: %v",
+	"ab.judge_unparsed":   "Cannot understand the judge model's answer: %s",
+	"ab.identical":        "The two replies are identical",
+	"ab.winner":           "⚖️  %s wins: %s",
+	"ab.tie":              "⚖️  Tie: %s",
+	"ab.summary":          "%d test cases, %d failed, %d identical replies",
+	"ab.judge_hint":       "Use --judge <model> to let a judge model pick winners and compute win rates",
+	"ab.win_rates":        "🏆 %s won %d (%.1f%%), %s won %d (%.1f%%), %d ties (%.1f%%)",
+
+	// estimate 命令
+	"estimate.title":          "📏 Cost estimate",
+	"estimate.model":          "Model: %s",
+	"estimate.input":          "Input: about %s tokens (%s bytes)",
+	"estimate.output":         "Output: %s ~ %s tokens",
+	"estimate.model_hint":     "Use -m to choose a model or -p to choose a provider to estimate the cost",
+	"estimate.no_price":       "No price found for model '%s', cannot estimate the cost",
+	"estimate.price":          "Price: input $%g / output $%g per million tokens (%s)",
+	"estimate.input_cost":     "Input cost: $%.4f",
+	"estimate.output_cost":    "Output cost: $%.4f ~ $%.4f",
+	"estimate.total":          "Total: $%.4f ~ $%.4f",
+	"estimate.source_config":  "provider config",
+	"estimate.source_builtin": "built-in price table",
+
+	// ping 命令
+	"ping.count_invalid":     "--count must be greater than 0",
+	"ping.interrupted":       "Interrupted",
+	"ping.all_failed":        "All requests to %s failed",
+	"ping.header":            "🏓 PING %s: %d requests",
+	"ping.reply":             "%d: %s  first token %s",
+	"ping.stats_title":       "--- %s statistics ---",
+	"ping.stats_counts":      "%d requests, %d succeeded, %d failed",
+	"ping.stats_latency":     "latency     min/avg/p95/max = %s",
+	"ping.stats_first_token": "first token min/avg/p95/max = %s",
+
+	// purge 命令
+	"purge.filter_required": "Specify --older-than, --provider or --all",
+	"purge.kind_session":    "session",
+	"purge.kind_archive":    "archive",
+	"purge.none":            "📝 Nothing matches",
+	"purge.dry_run":         "📋 Dry run: would delete the %d items above, nothing was changed",
+	"purge.confirm":         "🗑️  Delete the %d items above?",
+	"confirm.cancelled":     "Cancelled",
+	"purge.delete_failed":   "Failed to delete %s: %v",
+	"purge.deleted":         "Deleted %d items",
+	"purge.kind_log":        "log",
+	"purge.age_invalid":     "Invalid duration: %s (e.g. 90d, 2w, 12h)",
+
+	// issue 命令
+	"issue.draft_failed":    "Drafting failed: %v",
+	"issue.post_hint":       "Use --post to submit to GitHub or Jira",
+	"issue.confirm":         "📤 Submit to %s?",
+	"issue.confirm_failed":  "Cannot confirm the submission: %v, use --yes",
+	"issue.created":         "Issue created: %s",
+	"issue.repo_with_jira":  "--repo and --jira cannot be used together",
+	"issue.tracker_invalid": "Unsupported issue tracker: %s (choose github or jira)",
+
+	// history 命令
+	"history.by_month":    "By month",
+	"history.by_provider": "By provider",
+	"history.by_model":    "By model",
+	"history.by_invalid":  "Invalid grouping: %s (choose month, provider or model)",
+	"history.save_hint":   "Set advanced.save_history: true to save conversations automatically",
+	"history.title":       "Conversation history statistics",
+	"history.totals":      "Conversations: %d    Messages: %d",
+	"history.tokens":      "Tokens: %s (input %s, output %s)",
+	"history.cost":        "Cost: %s",
+	"history.row":         "%s%s  %s%s  %4d convs  %5d msgs  %9s tokens  %s",
+
+	// howto 命令
+	"howto.print_with_exec": "--print and --exec cannot be used together",
+	"howto.failed":          "Query failed: %v",
+	"howto.no_command":      "The AI returned no command",
+	"howto.confirm":         "▶️  Run the command above?",
+	"howto.exec_failed":     "Command failed: %v",
+
+	// ocr 命令
+	"ocr.input_required": "Specify one image file, or use --from-clipboard to read an image from the clipboard",
+	"ocr.failed":         "Recognition failed: %v",
+	"ocr.read_failed":    "Failed to read the image: %w",
+	"ocr.too_large":      "Image too large: %s (%.1f MB, limit %d MB)",
+
+	// doctor 命令
+	"doctor.config_fix":        "Run 'ai-chat-cli config init' to create a config file, or check the YAML syntax",
+	"doctor.config":            "Config file",
+	"doctor.no_providers_fix":  "Add a provider with 'ai-chat-cli config set providers.openai.api_key YOUR_API_KEY'",
+	"doctor.no_providers":      "No providers configured",
+	"doctor.provider":          "Providers",
+	"doctor.failed":            "%d checks failed, %d warnings",
+	"doctor.passed_warnings":   "All checks passed, %d warnings",
+	"doctor.passed":            "All checks passed",
+	"doctor.proxy":             "Proxy",
+	"doctor.proxy_fix":         "The proxy address should look like http://host:port",
+	"doctor.proxy_invalid":     "Invalid value for %s: %s",
+	"doctor.proxy_none":        "No proxy set, connecting directly",
+	"doctor.plugin":            "Plugin",
+	"doctor.mock_detail":       "Sends no network requests",
+	"doctor.mock":              "Mock provider",
+	"doctor.auth":              "Authentication",
+	"doctor.auth_fix":          "Check the %[2]s auth settings in providers.%[1]s.auth",
+	"doctor.auth_ok":           "%s access token obtained",
+	"doctor.api_key":           "API key",
+	"doctor.capabilities":      "Capabilities",
+	"doctor.network":           "Network",
+	"doctor.base_url_invalid":  "Invalid base_url: %s",
+	"doctor.base_url_fix":      "base_url should look like https://host/v1",
+	"doctor.direct":            "direct connection",
+	"doctor.via_proxy":         "via proxy %s",
+	"doctor.network_fix":       "Check the network connection and whether base_url is correct",
+	"doctor.proxy_network_fix": "Check that the proxy works, or add this address to NO_PROXY",
+	"doctor.unreachable":       "Cannot connect to %s (%s): %v",
+	"doctor.reachable":         "%s is reachable (%s, %dms)",
+	"doctor.clock_skew":        "Local time differs from the server by about %s",
+	"doctor.clock":             "Clock",
+	"doctor.clock_fix":         "Synchronize the system time (e.g. enable NTP); a large skew can break TLS or authentication",
+	"doctor.clock_ok":          "About %s from the server",
+	"doctor.completion":        "Test request",
+	"doctor.completion_ok":     "%s responded normally (%.1fs)",
+	"doctor.auth_fix_key":      "The API key is invalid or lacks permission, update it with 'ai-chat-cli config set providers.%s.api_key YOUR_API_KEY'",
+	"doctor.budget_fix":        "Insufficient account balance or quota, check the provider's billing settings",
+	"doctor.not_found_fix":     "The model or endpoint does not exist, check providers.%s.model and base_url",
+	"doctor.retry_fix":         "Check the model setting, or try again later",
+}
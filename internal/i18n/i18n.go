@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 支持的界面语言
+const (
+	ZhCN = "zh-CN"
+	EnUS = "en-US"
+)
+
+// catalogs 各语言的消息目录，键为消息ID，值为 fmt 格式字符串
+var catalogs = map[string]map[string]string{
+	ZhCN: zhCN,
+	EnUS: enUS,
+}
+
+// current 当前界面语言
+var current = ZhCN
+
+// decorate 处理消息后再返回，如按界面主题去掉表情符号，为nil时原样返回
+var decorate func(string) string
+
+// SetDecorator 设置消息的后处理函数
+func SetDecorator(fn func(string) string) {
+	decorate = fn
+}
+
+// Setup 设置界面语言：优先使用配置的 ui.language，为空时根据 LC_ALL、LC_MESSAGES、LANG 环境变量选择，
+// 无法识别时使用中文
+func Setup(language string) {
+	if language == "" {
+		for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+			if language = os.Getenv(env); language != "" {
+				break
+			}
+		}
+	}
+	current = Normalize(language)
+}
+
+// Normalize 将 en、en_US.UTF-8、zh-TW 等语言标识转换为支持的界面语言
+func Normalize(language string) string {
+	language = strings.ToLower(language)
+	switch {
+	case strings.HasPrefix(language, "en"):
+		return EnUS
+	default:
+		return ZhCN
+	}
+}
+
+// Language 获取当前界面语言
+func Language() string {
+	return current
+}
+
+// T 获取当前语言的消息并按参数格式化，当前语言缺少该消息时使用中文，仍缺少时返回消息ID
+func T(id string, a ...interface{}) string {
+	format, ok := catalogs[current][id]
+	if !ok {
+		if format, ok = catalogs[ZhCN][id]; !ok {
+			format = id
+		}
+	}
+	msg := format
+	if len(a) > 0 {
+		msg = fmt.Sprintf(format, a...)
+	}
+	if decorate != nil {
+		msg = decorate(msg)
+	}
+	return msg
+}
+
+// Error 显示时按当前界面语言翻译的错误，值为消息ID，可以作为 errors.Is 比较的哨兵错误
+type Error string
+
+func (e Error) Error() string {
+	return T(string(e))
+}
@@ -0,0 +1,46 @@
+package i18n
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// verbPattern 匹配格式化动词，可以带 %[n]s 形式的参数序号
+var verbPattern = regexp.MustCompile(`%[-+# 0]*(?:\[([0-9]+)\])?[0-9]*(?:\.[0-9]+)?([a-zA-Z%])`)
+
+// verbs 返回消息中每个参数位置使用的格式化动词，译文可以用 %[n]s 调整参数顺序
+func verbs(msg string) map[int]string {
+	out := map[int]string{}
+	next := 1
+	for _, m := range verbPattern.FindAllStringSubmatch(msg, -1) {
+		if m[2] == "%" {
+			continue
+		}
+		if m[1] != "" {
+			next, _ = strconv.Atoi(m[1])
+		}
+		out[next] += m[2]
+		next++
+	}
+	return out
+}
+
+func TestCatalogsMatch(t *testing.T) {
+	for key, zh := range zhCN {
+		en, ok := enUS[key]
+		if !ok {
+			t.Errorf("en_US 缺少 %q", key)
+			continue
+		}
+		if got, want := verbs(en), verbs(zh); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q 的格式化动词不一致: en_US %v，zh_CN %v", key, got, want)
+		}
+	}
+	for key := range enUS {
+		if _, ok := zhCN[key]; !ok {
+			t.Errorf("zh_CN 缺少 %q", key)
+		}
+	}
+}
@@ -0,0 +1,597 @@
+package i18n
+
+// zhCN 中文消息目录
+var zhCN = map[string]string{
+	// 通用
+	"config.using":               "使用配置文件: %s",
+	"config.load_failed":         "配置加载失败: %v",
+	"config.init_hint":           "请先运行 'ai-chat-cli config init' 初始化配置",
+	"provider.auto":              "💡 自动选择提供商: %s",
+	"provider.not_found":         "提供商 '%s' 未找到",
+	"provider.available":         "📋 可用的提供商:",
+	"provider.no_key":            "提供商 '%s' 的API密钥未设置",
+	"provider.key_hint":          "请运行以下命令设置API密钥：\n   ai-chat-cli config set providers.%s.api_key YOUR_API_KEY",
+	"provider.using":             "🚀 使用提供商: %s",
+	"provider.base_url":          "🌐 API地址: %s",
+	"provider.model":             "🤖 使用模型: %s",
+	"provider.cached":            "⚡ 使用缓存的回复（运行 ai-chat-cli cache clear 清除缓存）",
+	"provider.unsupported":       "提供商 '%s' 不支持 %s",
+	"provider.capabilities_hint": "如果提供商实际支持，请在配置文件的 providers.%s.capabilities 中列出支持的功能",
+	"moderation.unknown":         "未知的 advanced.moderate_inputs 值 '%s'（可选 warn、block），已跳过输入审核",
+	"moderation.failed":          "内容审核失败: %v",
+	"moderation.flagged":         "输入内容被标记: %s",
+	"session.save_failed":        "保存会话失败: %v",
+	"session.title_failed":       "保存会话标题失败: %v",
+	"stats.latency":              "耗时: %.2fs",
+	"stats.first_token":          "首字: %.2fs",
+	"stats.speed":                "速度: %.1f tokens/s",
+	"stats.unknown_mode":         "未知的 ui.stats 值 '%s'（可选 on、off、verbose），已使用 on",
+
+	// chat 命令
+	"chat.seed_with_session":         "--seed 和 --session 不能同时使用",
+	"chat.resumed":                   "📂 继续会话: %s (%d 轮对话)",
+	"chat.continue_none":             "📂 当前目录 %s 没有保存的会话，开始新会话",
+	"chat.seed_loaded":               "🌱 已加载初始对话: %s (%d 条消息)",
+	"chat.preset_loaded":             "🧩 使用预设: %s",
+	"chat.empty_input":               "输入内容为空",
+	"chat.stdin_as_invalid":          "不支持的 --stdin-as 值: %s（可选: context, prompt, ignore）",
+	"chat.stdin_as_prompt_args":      "--stdin-as prompt 使用管道输入作为问题，不能再指定问题参数",
+	"chat.history_role_invalid":      "advanced.history_roles 中的角色 '%s' 无效（可选 user、assistant），已忽略",
+	"chat.failed":                    "对话失败: %v",
+	"chat.notify_done":               "✅ 回答已完成: %s",
+	"chat.notify_failed":             "❌ 对话失败: %v",
+	"chat.interrupted":               "已中断，没有收到回复",
+	"chat.partial_kept":              "✂️  已保留中断前收到的部分回答，在会话中标记为不完整",
+	"chat.route":                     "🧭 路由: %s → %s（%s）",
+	"chat.route_reason_short":        "简短对话",
+	"chat.route_reason_long":         "输入较长",
+	"chat.route_reason_code":         "包含代码",
+	"chat.route_reason_reasoning":    "需要推理或分析",
+	"chat.route_reason_classifier":   "分类模型判断",
+	"chat.route_reason_override":     "手动指定",
+	"chat.route_invalid":             "不支持的 --route 值: %s（可选 auto、fast、smart、off）",
+	"chat.route_unconfigured":        "没有配置 routing.%s 模型，使用提供商的默认模型",
+	"chat.route_classifier_invalid":  "未知的 routing.classifier 值 '%s'（可选 heuristic、model），使用 heuristic",
+	"chat.route_classifier_failed":   "分类模型判断失败，改用启发式规则: %v",
+	"chat.pipeline_invalid":          "无效的 --pipeline 值: %s（格式为 draft=<模型>,refine=<模型>）",
+	"chat.pipeline_with_route":       "--pipeline 不能与 --route 同时使用",
+	"chat.pipeline_draft":            "✏️  起草: %s",
+	"chat.pipeline_refine":           "🔍 修订: %s",
+	"chat.offline_hint":              "使用 --queue 将问题加入队列，联网后运行 'ai-chat-cli queue flush' 发送",
+	"chat.queued":                    "📥 网络不可用，问题已加入队列（%s），联网后运行 'ai-chat-cli queue flush' 发送",
+	"chat.tee":                       "📝 回答同时写入: %s",
+	"chat.clipboard_failed":          "读取剪贴板失败: %v",
+	"chat.clipboard_empty":           "剪贴板中没有文本",
+	"chat.copied":                    "📋 回答已复制到剪贴板",
+	"chat.copy_failed":               "复制到剪贴板失败: %v",
+	"chat.tee_failed":                "无法写入 %s: %v",
+	"chat.stream_json_with_pipeline": "--stream-json 不能与 --pipeline 同时使用",
+	"chat.stream_json_interactive":   "--stream-json 只能用于单次对话，请在参数或管道中提供问题",
+	"chat.length_truncated":          "回答达到 max_tokens 上限，内容不完整",
+	"chat.continue_hint_repl":        "输入 /continue 获取剩余部分",
+	"chat.continue_hint_once":        "使用 --continue-output 自动获取剩余部分并拼接",
+	"chat.continue_output_repl":      "--continue-output 只能用于单次对话，交互模式中使用 /continue",
+	"chat.content_filtered":          "回答被提供商的内容安全策略过滤，可能为空或不完整",
+	"chat.refused":                   "模型拒绝回答了这个问题",
+	"chat.json_interactive":          "--json 只能用于单次对话，请在参数或管道中提供问题",
+	"chat.samples_interactive":       "--n 只能用于单次对话，请在参数或管道中提供问题",
+	"chat.samples_range":             "--n 应在 1 到 %d 之间",
+	"chat.best_of_needs_n":           "--best-of 需要 --n 大于1",
+	"chat.samples_with_pipeline":     "--n 不能与 --pipeline 同时使用",
+	"chat.samples_failed":            "%d/%d 个回答请求失败: %v",
+	"chat.judge_failed":              "评审失败，显示所有回答: %v",
+	"chat.judge_chose":               "🏆 评审模型 %s 选择了第 %d 个回答（共 %d 个）",
+	"chat.sample_heading":            "回答 %d/%d",
+	"chat.idle_exit":                 "⏰ 空闲超过 %s，退出对话",
+	"chat.idle_locked":               "🔒 空闲超时，对话已锁定并从内存中清除。输入解锁口令后恢复（需要重新读取会话密钥）",
+	"chat.idle_unlocked":             "🔓 已解锁，恢复了 %d 轮对话",
+	"chat.idle_unlock_failed":        "解锁失败: %v",
+	"chat.idle_lock_unavailable":     "ui.idle_action 为 lock 需要启用 advanced.encrypt_sessions 并保存会话，空闲超时后将退出",
+	"chat.idle_lock_no_passphrase":   "ui.idle_action 为 lock 需要先运行 'ai-chat-cli session passphrase' 设置解锁口令，空闲超时后将退出",
+	"chat.idle_lock_bad_passphrase":  "无法读取解锁口令（%v），空闲超时后将退出",
+	"chat.idle_passphrase":           "🔑 解锁口令: ",
+	"chat.idle_passphrase_wrong":     "❌ 口令错误",
+	"chat.idle_action_invalid":       "未知的 ui.idle_action: %s（可选 exit、lock），空闲超时后将退出",
+	"chat.ai":                        "AI: ",
+	"chat.you":                       "你: ",
+	"chat.usage":                     "Token使用: %d (输入: %d, 输出: %d) | 对话轮次: %d",
+	"chat.banner":                    "🤖 AI Chat CLI - 交互模式 (支持上下文记忆)",
+	"chat.start":                     "💡 输入问题开始对话",
+	"chat.commands":                  "💡 特殊命令:",
+	"chat.cmd_quit":                  "   • quit/exit - 退出程序",
+	"chat.cmd_clear":                 "   • clear - 清屏",
+	"chat.cmd_reset":                 "   • reset - 重置对话历史",
+	"chat.cmd_history":               "   • history - 显示对话历史",
+	"chat.cmd_undo":                  "   • /undo - 撤销上一轮对话",
+	"chat.cmd_drop":                  "   • /drop <n> - 删除第n条消息",
+	"chat.cmd_redact":                "   • /redact <n> [文本] - 隐藏第n条消息或其中的指定文本",
+	"chat.cmd_find":                  "   • /find <文本> - 查找包含指定文本的消息",
+	"chat.cmd_pin":                   "   • /pin <n>、/note <n> <备注> - 标记第n条消息或添加备注，保存在会话中",
+	"chat.cmd_diff":                  "   • /diff [n m] - 比较最后两条AI回复（或第n条和第m条消息）的差异",
+	"chat.cmd_route":                 "   • /fast <问题>、/smart <问题> - 本条消息使用快速模型或推理模型",
+	"chat.cmd_memory":                "   • /remember key=value - 记住一条事实，之后的对话和会话都会带上；/memory list、/memory forget <key> 查看或删除",
+	"chat.cmd_models":                "   • /models [筛选文字] - 从提供商的模型列表中选择本次对话使用的模型",
+	"chat.cmd_continue":              "   • /continue - 回答因长度上限被截断时，获取剩余部分并接在后面",
+	"chat.cmd_help":                  "   • help - 显示帮助",
+	"chat.retry_hint":                "💡 如果输入出现问题，直接按回车重新输入",
+	"chat.input_error":               "输入错误: %v",
+	"chat.invalid_input":             "输入包含无效字符，请重新输入",
+	"chat.resume_hint":               "💡 继续对话: ai-chat-cli chat --session %s",
+	"chat.bye":                       "👋 再见！",
+	"chat.history_count":             "💡 当前对话历史: %d 轮次",
+	"chat.start_help":                "💡 输入问题开始对话，输入 'help' 查看命令",
+	"chat.reset_done":                "🔄 对话历史已重置",
+	"chat.help_title":                "🆘 可用命令:",
+	"chat.help_history":              "   • history - 显示对话历史（带消息编号）",
+	"chat.help_undo":                 "   • /undo - 撤销上一轮问答，不再作为后续对话的上下文",
+	"chat.help_redact":               "   • /redact <n> [文本] - 隐藏第n条消息，或只隐藏其中的指定文本",
+	"chat.help_help":                 "   • help - 显示此帮助",
+	"chat.help_ask":                  "   • 直接输入问题开始对话",
+	"chat.cleaned":                   "📝 已清理输入: %s",
+	"chat.failed_repl":               "❌ 对话失败: %v",
+	"chat.network_hint":              "💡 请检查网络连接或重试，输入 'help' 查看可用命令",
+	"chat.history_empty":             "📝 暂无对话历史",
+	"chat.history_title":             "📝 对话历史:",
+	"chat.history_total":             "📊 总计 %d 轮对话",
+
+	// 交互模式中的 / 命令
+	"chat.dropped":            "🗑️  已删除第 %d 条消息",
+	"chat.redact_not_found":   "❌ 第 %d 条消息中没有找到指定的文本",
+	"chat.redacted":           "🙈 已隐藏第 %d 条消息的内容",
+	"chat.redacted_content":   "[内容已隐藏]",
+	"chat.pinned":             "📌 已标记第 %d 条消息，session show --pinned 查看标记的消息",
+	"chat.unpinned":           "📌 已取消标记第 %d 条消息",
+	"chat.noted":              "📝 已为第 %d 条消息添加备注",
+	"chat.note_removed":       "📝 已删除第 %d 条消息的备注",
+	"chat.mark_unsaved":       "❌ 第 %d 条消息没有保存到会话中，无法标记（需要开启 advanced.save_history）",
+	"chat.find_usage":         "❌ 用法: /find <文本>",
+	"chat.find_title":         "🔍 包含 \"%s\" 的消息:",
+	"chat.find_none":          "🔍 没有包含 \"%s\" 的消息",
+	"chat.find_total":         "📊 共 %d 条消息",
+	"chat.diff_none":          "💡 至少需要两条AI回复才能比较",
+	"chat.diff_usage":         "💡 用法: /diff 比较最后两条AI回复，/diff <n> <m> 比较第n条和第m条消息",
+	"chat.diff_label":         "第%d条消息",
+	"chat.diff_same":          "✓ 第%d条和第%d条消息内容相同",
+	"chat.undo_none":          "📝 没有可以撤销的对话",
+	"chat.undone":             "↩️  已撤销上一轮对话",
+	"chat.unknown_command":    "未知命令: %s，输入 'help' 查看可用命令",
+	"chat.index_usage":        "❌ 用法: %s <消息编号>，输入 'history' 查看消息编号",
+	"chat.index_invalid":      "❌ 消息编号无效: %s，当前共 %d 条消息",
+	"chat.remember_usage":     "❌ 用法: /remember key=value，如 /remember stack=Go + PostgreSQL",
+	"chat.remembered":         "🧠 已记住 %s = %s",
+	"chat.memory_empty":       "🧠 还没有记住任何事实，使用 /remember key=value 添加",
+	"chat.memory_title":       "🧠 记住的事实（配置档 %s）:",
+	"chat.forgotten":          "🧠 已忘记 %s",
+	"chat.forget_not_found":   "❌ 没有记住 %s，输入 /memory list 查看",
+	"chat.memory_usage":       "❌ 用法: /memory list 或 /memory forget <key>",
+	"chat.memory_failed":      "保存记忆失败: %v",
+	"chat.memory_unavailable": "无法读取记忆，本次对话不使用记忆: %v",
+	"chat.models_failed":      "获取模型列表失败: %v",
+	"chat.model_unchanged":    "🤖 模型未改变",
+	"chat.model_switched":     "🤖 本次对话改用模型: %s",
+	"chat.continue_no_answer": "💬 没有可以继续的回答",
+	"chat.continue_failed":    "❌ 获取剩余部分失败: %v",
+
+	// 提供商、缓存、过滤和其他命令
+	"provider.metadata_invalid":  "提供商配置的 metadata 无效，已忽略: %v",
+	"provider.compat_invalid":    "providers.%s.compat: %v，使用 max_tokens",
+	"provider.capabilities_none": "无",
+	"provider.auth_unsupported":  "不支持的认证方式: %s（可选 api_key、azure_ad、oauth、google_adc）",
+	"ui.theme_invalid":           "%v，使用默认主题",
+	"list.separator":             "，",
+	"cache.expired_cleared":      "已删除 %d 条过期的缓存",
+	"cache.cleared":              "已删除 %d 条缓存",
+	"cache.unavailable":          "无法使用回复缓存: %v",
+	"chat.stdin_failed":          "读取标准输入失败: %v",
+	"chat.memory_off":            "记忆不可用",
+	"chat.best_of_invalid":       "--best-of 的格式应为 judge=<模型>，如 judge=gpt-4o-mini",
+	"chat.judge_unparsed":        "无法从评审的回复中识别候选编号: %q",
+	"pii.masked":                 "🛡️  已替换 %d 处敏感信息后发送: %s",
+	"filter.invalid":             "过滤规则配置无效，已拒绝发送",
+	"filter.blocked":             "提示词包含受限内容，已拒绝发送（%s）",
+	"filter.separator":           "；",
+	"filter.matched":             "提示词匹配过滤规则 %s: %s",
+	"filter.confirm_required":    "提示词包含需要确认的受限内容，已拒绝发送",
+	"filter.confirm":             "仍然发送?",
+	"filter.cancelled":           "提示词包含受限内容，已取消发送",
+	"guard.too_large":            "输入内容过大，已取消发送",
+	"guard.estimate":             "约 %d tokens",
+	"guard.estimate_cost":        "，约 $%.2f",
+	"guard.over_limit":           "本次请求的输入%s，超过确认阈值 %d tokens",
+	"guard.confirm":              "继续发送?",
+	"guard.hint":                 "可以调整 advanced.confirm_input_tokens，设置为 -1 关闭检查",
+	"guard.non_interactive":      "%w（%s，无法在非交互模式下确认）",
+	"audit.disabled":             "审计日志未启用: %v",
+	"dryrun.request":             "🧪 演练模式，未发送请求: POST %s",
+	"dryrun.build_failed":        "构建请求失败: %v",
+	"dryrun.estimate":            "📏 预计输入约 %d tokens（%d 条消息）",
+	"session.key_generated":      "🔑 已生成会话加密密钥并保存到系统钥匙串",
+	"session.key_saved_file":     "没有可用的系统钥匙串，会话加密密钥已保存到 %s，请妥善保管",
+	"models.save_needs_pick":     "--save 需要与 --pick 一起使用",
+	"models.pick_needs_terminal": "--pick 需要在终端中运行",
+	"models.none_matched":        "📭 没有匹配 %q 的模型",
+	"models.matched":             "🔍 匹配 %q 的模型（共 %d 个）:",
+	"models.available":           "📋 可用模型（共 %d 个）:",
+	"models.more":                "  … 还有 %d 个，输入文字缩小范围",
+	"models.prompt":              "输入编号选择，输入文字筛选，直接回车取消: ",
+	"models.invalid":             "❌ 编号无效: %d",
+	"models.current":             "（当前）",
+	"models.cancelled":           "已取消",
+	"models.save_hint":           "使用 --save 将 %s 设为提供商 %s 默认使用的模型",
+	"models.no_config":           "没有找到配置文件",
+	"models.save_failed":         "保存配置失败: %v",
+	"models.saved":               "已将提供商 %s 的默认模型设为 %s",
+	"lock.passphrase_terminal":   "session passphrase 需要在终端中运行",
+	"lock.passphrase_new":        "🔑 新的解锁口令: ",
+	"lock.passphrase_again":      "🔑 再次输入: ",
+	"lock.passphrase_empty":      "口令不能为空",
+	"lock.passphrase_mismatch":   "两次输入的口令不一致",
+	"lock.passphrase_failed":     "保存解锁口令失败: %v",
+	"lock.passphrase_saved":      "已设置解锁口令（校验值保存在 %s）",
+
+	// config 命令
+	"config.path_failed":      "无法获取配置文件路径: %v",
+	"config.exists":           "配置文件已存在: %s",
+	"config.exists_hint":      "如果要重新初始化，请先删除现有配置文件。",
+	"config.create_failed":    "创建配置文件失败: %v",
+	"config.created":          "配置文件已创建: %s",
+	"config.edit_keys":        "请编辑配置文件，设置您的API密钥：",
+	"config.openai_key":       "- OpenAI API密钥: 设置 OPENAI_API_KEY 环境变量",
+	"config.anthropic_key":    "- Anthropic API密钥: 设置 ANTHROPIC_API_KEY 环境变量",
+	"config.key_field":        "或者直接在配置文件中设置 api_key 字段。",
+	"config.current":          "当前配置:",
+	"config.default_provider": "默认提供商: %s",
+	"config.stream":           "流式输出: %t",
+	"config.max_retries":      "最大重试: %d",
+	"config.timeout":          "超时时间: %d秒",
+	"config.cost_limit":       "成本限制: $%.2f",
+	"config.providers":        "已配置的提供商:",
+	"config.key_unset":        "未设置",
+	"config.key_token":        "不使用（%s 认证）",
+	"config.key_set":          "已设置",
+	"config.key_env":          "环境变量",
+	"config.model":            "模型: %s",
+	"config.api_key":          "API密钥: %s",
+	"config.max_tokens":       "最大Token: %d",
+	"config.not_found":        "没有找到配置文件",
+	"config.save_failed":      "保存配置失败: %v",
+	"config.set":              "已设置 %s = %s",
+
+	// session 命令
+	"session.none_tagged":          "📝 没有标签为 %s 的会话",
+	"session.none":                 "📝 暂无保存的会话",
+	"session.list_title":           "📝 保存的会话:",
+	"session.list_row":             "%s  %s  %-12s %3d条  %s",
+	"session.no_tags":              "📝 会话没有标签",
+	"session.tags_required":        "请指定标签，多个标签用逗号分隔",
+	"session.tags_cleared":         "已更新会话 %s 的标签，当前没有标签",
+	"session.tags_updated":         "已更新会话 %s 的标签: %s",
+	"session.provider":             "提供商: %s",
+	"session.model":                "模型: %s",
+	"session.tags":                 "标签: %s",
+	"session.dir":                  "目录: %s",
+	"session.created":              "创建时间: %s",
+	"session.marked":               "标记的消息: %s",
+	"session.no_marked":            "📌 会话中没有标记的消息，在交互模式中使用 /pin <n> 或 /note <n> <备注> 标记",
+	"session.truncated":            "✂️  回答被中断，内容不完整",
+	"session.tool_result":          "结果",
+	"session.tool_args":            "参数",
+	"session.tool_folded":          "…（还有 %d 行，--tools 显示完整内容）",
+	"session.deleted":              "已删除会话: %s",
+	"session.pdf_terminal":         "PDF 不能输出到终端，请使用 -o 指定输出文件",
+	"session.format_invalid":       "不支持的导出格式: %s（可选 markdown、html、pdf）",
+	"session.export_failed":        "导出失败: %v",
+	"session.create_failed":        "创建文件失败: %v",
+	"session.exported":             "已导出到 %s",
+	"session.keep_negative":        "--keep 不能为负数",
+	"session.compact_none":         "📝 会话只有最近 %d 轮对话，无需压缩",
+	"session.compacting":           "🗜️  正在总结 %d 条较早的消息...",
+	"session.summary_failed":       "生成摘要失败: %v",
+	"session.compacted":            "已压缩会话 %s: %d 条消息 → %d 条",
+	"session.archive":              "📦 完整副本: %s",
+	"session.gist_token":           "分享到 gist 需要 GitHub token",
+	"session.gist_token_hint":      "使用 'ai-chat-cli config set share.gist_token <token>' 或设置 GITHUB_TOKEN 环境变量",
+	"session.paste_url":            "分享到 paste 需要设置 share.paste_url",
+	"session.share_target_invalid": "不支持的分享目标: %s（可选 gist、0x0、paste）",
+	"session.secrets_found":        "会话中发现 %d 处疑似密钥:",
+	"session.secret_line":          "• 第 %d 行 %s: %s",
+	"session.share_cancelled":      "已取消上传，请先隐藏这些内容",
+	"session.share_redact_hint":    "在交互模式中使用 /redact 隐藏后重试，确认无误时可使用 --allow-secrets 继续上传",
+	"session.uploading":            "📤 正在上传到 %s...",
+	"session.share_failed":         "分享失败: %v",
+	"session.encrypted":            "已加密 %d 个会话",
+	"session.encrypt_hint":         "使用 'ai-chat-cli config set advanced.encrypt_sessions true' 加密之后保存的会话",
+	"session.decrypted":            "已解密 %d 个会话",
+	"session.not_found":            "会话不存在: %s",
+	"session.list_hint":            "使用 'ai-chat-cli session list' 查看保存的会话",
+
+	// serve 命令
+	"serve.dry_run":              "serve 不支持 --dry-run",
+	"serve.log_file":             "📝 日志写入: %s",
+	"serve.started":              "🚀 网关已启动: http://%s/v1",
+	"serve.default_provider":     "🤖 默认提供商: %s",
+	"serve.keys_enabled":         "🔑 已启用访问密钥认证 (%d 个密钥)",
+	"serve.no_keys":              "未配置访问密钥 (serve.keys)，局域网内任何人都可以使用您的提供商",
+	"serve.stop_hint":            "按 Ctrl+C 停止服务",
+	"serve.failed":               "服务异常退出: %v",
+	"serve.metrics":              "上游请求 %d 次，失败 %d 次，平均耗时 %s",
+	"serve.merged":               "♻️  %d 个请求与同时进行的相同请求合并",
+	"serve.stopped":              "👋 服务已停止",
+	"serve.no_providers":         "没有已设置API密钥的提供商",
+	"serve.provider_unavailable": "提供商 '%s' 未找到或未设置API密钥",
+	"serve.unpriced":             "提供商 '%s' 的默认模型没有价格，其用量不计入 daily_cost",
+	"provider.price_hint":        "在提供商配置中设置 input_price 和 output_price（每百万token的价格，美元）",
+	"path.home_failed":           "获取用户目录失败: %w",
+	"serve.key_empty":            "访问密钥 '%s' 未设置 key，已忽略",
+	"serve.key_duplicate":        "访问密钥 '%s' 与 '%s' 的 key 重复",
+	"serve.reload_invalid":       "%s 配置文件无效，继续使用之前的配置: %v",
+	"serve.reloaded":             "🔄 %s 已重新加载配置: %d 个提供商，默认 %s，%d 个访问密钥",
+	"serve.watch_failed":         "无法监听配置文件变化: %v",
+
+	// schedule 命令
+	"schedule.prompt_required": "请使用 --template 指定模板或直接给出提示词",
+	"schedule.var_invalid":     "变量格式错误: %s，应为 key=value",
+	"schedule.added":           "已添加定时任务 %s",
+	"schedule.next":            "下次执行: %s",
+	"schedule.run_hint":        "使用 'ai-chat-cli schedule run' 启动守护进程",
+	"schedule.none":            "📝 还没有定时任务",
+	"schedule.add_hint":        "使用 'ai-chat-cli schedule add' 添加任务",
+	"schedule.template":        "模板 %s",
+	"schedule.cron_invalid":    "表达式无效",
+	"schedule.stdout":          "标准输出",
+	"schedule.next_output":     "下次执行: %s | 输出: %s",
+	"schedule.succeeded":       "成功",
+	"schedule.failed_status":   "失败: %s",
+	"schedule.last_run":        "上次执行: %s %s",
+	"schedule.removed":         "已删除定时任务 %s",
+	"schedule.not_found":       "定时任务 %s 不存在",
+	"schedule.daemon_started":  "🚀 定时任务守护进程已启动，按 Ctrl+C 退出",
+	"schedule.daemon_stopped":  "👋 守护进程已退出",
+	"schedule.job_invalid":     "任务 %s: %v",
+	"schedule.running":         "[%s] ▶ 执行任务 %s",
+	"schedule.job_failed":      "任务 %s 失败: %v",
+	"schedule.job_done":        "任务 %s 完成，结果已写入 %s",
+	"schedule.record_failed":   "保存执行记录失败: %v",
+	"schedule.provider_failed": "加载AI提供商失败",
+	"schedule.mkdir_failed":    "创建输出目录失败: %w",
+	"schedule.write_failed":    "写入结果失败: %w",
+	"schedule.path_failed":     "获取任务文件路径失败: %v",
+
+	// batch 命令
+	"batch.output_required":     "请使用 --output 指定结果文件",
+	"batch.resuming":            "♻️  已完成 %d 条，继续处理剩余 %d 条",
+	"batch.all_done":            "所有任务均已完成",
+	"batch.open_failed":         "打开结果文件失败: %v",
+	"batch.concurrency_rpm":     "🚦 并发数: %d，限流: 每分钟 %d 次请求",
+	"batch.concurrency":         "🚦 并发数: %d",
+	"batch.write_failed":        "写入结果失败: %v",
+	"batch.interrupted":         "⏹️  已中断: 完成 %d 条，失败 %d 条，结果已写入 %s，重新运行相同的命令可继续处理剩余任务",
+	"batch.done":                "完成 %d 条，失败 %d 条，结果已写入 %s",
+	"batch.merged":              "♻️  %d 条任务与同时处理的相同任务合并，没有重复发送请求",
+	"batch.progress":            "⏳ 进度 %d/%d | 失败 %d | 已用 %s | 预计剩余 %s",
+	"batch.dry_run":             "🧪 演练模式，未发送请求: %d 条任务，预计输入约 %d tokens",
+	"batch.empty_prompt":        "提示词为空",
+	"batch.read_failed":         "读取任务文件失败: %w",
+	"batch.line_invalid":        "第 %d 行格式错误: %w",
+	"batch.duplicate_id":        "第 %d 行的ID '%s' 重复",
+	"batch.read_results_failed": "读取结果文件失败: %w",
+
+	// batch submit/status/fetch 命令
+	"batch.unsupported_option": "异步批处理的结果在之后下载，无法还原脱敏内容或记录审计日志，请关闭 %s 后再提交",
+	"batch.job_error":          "任务 %s: %v",
+	"batch.job_rejected":       "任务 %s: %w",
+	"batch.uploading":          "📤 正在上传 %d 条请求...",
+	"batch.submit_failed":      "提交批处理失败: %v",
+	"batch.created":            "已创建批处理任务: %s",
+	"batch.status_hint":        "查询进度: ai-chat-cli batch status %s",
+	"batch.fetch_hint":         "下载结果: ai-chat-cli batch fetch %s --output results.jsonl",
+	"batch.interval_min":       "--interval 不能小于 %s",
+	"batch.get_failed":         "查询批处理失败: %v",
+	"batch.unfinished_hint":    "任务尚未完成，可使用 --wait 等待完成后自动下载",
+	"batch.download_failed":    "下载结果失败: %v",
+	"batch.create_failed":      "创建结果文件失败: %v",
+	"batch.downloaded":         "已下载 %d 条结果（失败 %d 条），写入 %s",
+	"batch.unsupported":        "提供商 '%s' 不支持异步批处理",
+	"batch.info":               "📦 批处理任务: %s",
+	"batch.status":             "状态: %s",
+	"batch.counts":             "进度: 完成 %d，失败 %d，共 %d",
+	"batch.created_at":         "创建时间: %s",
+	"batch.completed_at":       "完成时间: %s",
+
+	// summarize 命令
+	"summarize.length_invalid":  "不支持的摘要长度: %s（可选: short, medium, long）",
+	"summarize.format_invalid":  "不支持的摘要格式: %s（可选: bullets, paragraph, outline）",
+	"chunk.size_invalid":        "--chunk-size 必须大于0",
+	"summarize.empty":           "没有可总结的内容",
+	"summarize.failed":          "总结失败: %v",
+	"summarize.chunk":           "📄 正在总结第 %d/%d 部分...",
+	"summarize.chunk_failed":    "总结第 %d 部分失败: %w",
+	"summarize.not_shorter":     "部分摘要没有缩短文本，请增大 --chunk-size",
+	"summarize.merging":         "🧩 正在合并摘要...",
+	"summarize.pdftotext":       "读取PDF需要安装 pdftotext（poppler-utils）",
+	"summarize.pdf_failed":      "提取PDF文本失败: %w",
+	"summarize.download_failed": "下载网页失败: %w",
+	"summarize.download_status": "下载网页失败: HTTP %d",
+	"summarize.read_failed":     "读取网页失败: %w",
+
+	// translate 命令
+	"translate.glossary_failed": "加载术语表失败: %v",
+	"translate.detected":        "🔍 检测到源语言: %s",
+	"translate.failed":          "翻译失败: %v",
+	"translate.blocks_missing":  "有 %d 个代码块未能在译文中还原",
+	"translate.glossary_line":   "第 %d 行格式错误，应为 \"原文 = 译文\"",
+
+	// explain 命令
+	"explain.level_invalid":  "不支持的讲解深度: %s（可选: beginner, intermediate, expert）",
+	"file.read_failed":       "读取文件失败: %v",
+	"explain.range_overflow": "行范围超出文件长度（共 %d 行）",
+	"explain.explaining":     "📖 正在讲解 %s 第 %d-%d 行 (%s)",
+	"explain.failed":         "讲解失败: %v",
+	"explain.range_invalid":  "无效的行范围: %s",
+
+	// review 命令
+	"review.format_invalid": "不支持的输出格式: %s（可选: text, github）",
+	"review.diff_failed":    "获取Git差异失败: %s",
+	"review.no_changes":     "没有需要评审的改动",
+	"review.chunk":          "🔍 正在评审第 %d/%d 部分...",
+	"review.failed":         "评审失败: %v",
+	"review.unparsed":       "第 %d 部分的评审结果无法解析，已跳过: %v",
+	"review.no_issues":      "没有发现问题",
+	"review.summary":        "共 %d 条评审意见，涉及 %d 个文件",
+
+	// proofread 命令
+	"proofread.chunk":      "📝 正在校对第 %d/%d 部分...",
+	"proofread.failed":     "校对失败: %v",
+	"proofread.diff_label": "%s (校对后)",
+	"proofread.no_changes": "没有发现需要修改的地方",
+	"proofread.write_hint": "使用 --write 将修改写回文件",
+	"file.write_failed":    "写入文件失败: %v",
+	"proofread.written":    "已写入 %s",
+
+	// queue 命令
+	"queue.empty":             "📭 队列中没有请求",
+	"queue.attempts":          "失败 %d 次: %s",
+	"queue.total":             "共 %d 个请求，运行 'ai-chat-cli queue flush' 发送",
+	"queue.interrupted":       "已中断，%d 个请求仍在队列中",
+	"queue.offline":           "网络仍不可用，%d 个请求仍在队列中",
+	"queue.wait_hint":         "使用 --wait 等待网络恢复后自动发送",
+	"queue.retrying":          "网络仍不可用，%s 后重试（%d 个请求等待发送）",
+	"queue.partial":           "已发送 %d 个请求，%d 个失败，仍保留在队列中",
+	"queue.flushed":           "已发送 %d 个请求，队列已清空",
+	"queue.remove_failed":     "从队列中删除 %s 失败: %v",
+	"provider.not_configured": "提供商 '%s' 未配置",
+	"queue.not_found":         "队列中没有请求 %s",
+	"queue.delete_failed":     "删除 %s 失败: %v",
+	"queue.deleted":           "已删除 %s",
+
+	// ab 命令
+	"ab.inputs_required":  "请使用 --inputs 指定用例文件",
+	"ab.inputs_empty":     "用例文件 %s 为空",
+	"ab.create_failed":    "创建结果文件失败: %v",
+	"ab.write_failed":     "写入结果失败: %v",
+	"ab.interrupted":      "⏹️  已中断，完成 %d/%d 条用例",
+	"ab.written":          "结果已写入 %s",
+	"ab.variant_conflict": "--template-%s 和 --prompt-%s 不能同时使用",
+	"ab.variant_required": "请使用 --template-%s 或 --prompt-%s 指定变体%s",
+	"ab.judge_error":      "评审: %v",
+	"ab.judge_unparsed":   "无法识别评审模型的回答: %s",
+	"ab.identical":        "两个回复完全相同",
+	"ab.winner":           "⚖️  %s 胜: %s",
+	"ab.tie":              "⚖️  平局: %s",
+	"ab.summary":          "共 %d 条用例，失败 %d 条，回复完全相同 %d 条",
+	"ab.judge_hint":       "使用 --judge <模型> 由评审模型判断胜负并统计胜率",
+	"ab.win_rates":        "🏆 %s 胜 %d 条（%.1f%%），%s 胜 %d 条（%.1f%%），平局 %d 条（%.1f%%）",
+
+	// estimate 命令
+	"estimate.title":          "📏 成本估算",
+	"estimate.model":          "模型: %s",
+	"estimate.input":          "输入: 约 %s tokens（%s 字节）",
+	"estimate.output":         "输出: %s ~ %s tokens",
+	"estimate.model_hint":     "使用 -m 指定模型或 -p 指定提供商以估算成本",
+	"estimate.no_price":       "没有找到模型 '%s' 的价格，无法估算成本",
+	"estimate.price":          "价格: 输入 $%g / 输出 $%g 每百万token（%s）",
+	"estimate.input_cost":     "输入成本: $%.4f",
+	"estimate.output_cost":    "输出成本: $%.4f ~ $%.4f",
+	"estimate.total":          "合计: $%.4f ~ $%.4f",
+	"estimate.source_config":  "提供商配置",
+	"estimate.source_builtin": "内置价格表",
+
+	// ping 命令
+	"ping.count_invalid":     "--count 必须大于0",
+	"ping.interrupted":       "已中断",
+	"ping.all_failed":        "%s 的所有请求都失败了",
+	"ping.header":            "🏓 PING %s: %d 次请求",
+	"ping.reply":             "%d: %s  首个token %s",
+	"ping.stats_title":       "--- %s 统计 ---",
+	"ping.stats_counts":      "%d 次请求，成功 %d，失败 %d",
+	"ping.stats_latency":     "延迟      min/avg/p95/max = %s",
+	"ping.stats_first_token": "首个token min/avg/p95/max = %s",
+
+	// purge 命令
+	"purge.filter_required": "请指定 --older-than、--provider 或 --all",
+	"purge.kind_session":    "会话",
+	"purge.kind_archive":    "归档",
+	"purge.none":            "📝 没有符合条件的数据",
+	"purge.dry_run":         "📋 演练模式: 将删除以上 %d 项，未做任何修改",
+	"purge.confirm":         "🗑️  删除以上 %d 项?",
+	"confirm.cancelled":     "已取消",
+	"purge.delete_failed":   "删除 %s 失败: %v",
+	"purge.deleted":         "已删除 %d 项",
+	"purge.kind_log":        "日志",
+	"purge.age_invalid":     "无效的时长: %s（示例: 90d、2w、12h）",
+
+	// issue 命令
+	"issue.draft_failed":    "起草失败: %v",
+	"issue.post_hint":       "使用 --post 提交到 GitHub 或 Jira",
+	"issue.confirm":         "📤 提交到 %s?",
+	"issue.confirm_failed":  "无法确认提交: %v，请使用 --yes",
+	"issue.created":         "已创建问题: %s",
+	"issue.repo_with_jira":  "--repo 和 --jira 不能同时使用",
+	"issue.tracker_invalid": "不支持的问题跟踪系统: %s（可选 github、jira）",
+
+	// history 命令
+	"history.by_month":    "按月份",
+	"history.by_provider": "按提供商",
+	"history.by_model":    "按模型",
+	"history.by_invalid":  "无效的分组方式: %s（可选 month、provider、model）",
+	"history.save_hint":   "配置 advanced.save_history: true 后对话会自动保存",
+	"history.title":       "对话历史统计",
+	"history.totals":      "对话: %d    消息: %d",
+	"history.tokens":      "Token: %s（输入 %s，输出 %s）",
+	"history.cost":        "成本: %s",
+	"history.row":         "%s%s  %s%s  %4d 对话  %5d 消息  %9s tokens  %s",
+
+	// howto 命令
+	"howto.print_with_exec": "--print 和 --exec 不能同时使用",
+	"howto.failed":          "查询失败: %v",
+	"howto.no_command":      "AI没有返回命令",
+	"howto.confirm":         "▶️  执行以上命令?",
+	"howto.exec_failed":     "命令执行失败: %v",
+
+	// ocr 命令
+	"ocr.input_required": "请指定一个图片文件，或使用 --from-clipboard 读取剪贴板中的图片",
+	"ocr.failed":         "识别失败: %v",
+	"ocr.read_failed":    "读取图片失败: %w",
+	"ocr.too_large":      "图片过大: %s（%.1f MB，上限 %d MB）",
+
+	// doctor 命令
+	"doctor.config_fix":        "运行 'ai-chat-cli config init' 创建配置文件，或检查YAML格式",
+	"doctor.config":            "配置文件",
+	"doctor.no_providers_fix":  "使用 'ai-chat-cli config set providers.openai.api_key YOUR_API_KEY' 添加提供商",
+	"doctor.no_providers":      "没有配置任何提供商",
+	"doctor.provider":          "提供商",
+	"doctor.failed":            "%d 项检查失败，%d 项警告",
+	"doctor.passed_warnings":   "全部检查通过，%d 项警告",
+	"doctor.passed":            "全部检查通过",
+	"doctor.proxy":             "代理设置",
+	"doctor.proxy_fix":         "代理地址应为 http://host:port 格式",
+	"doctor.proxy_invalid":     "%s 的值无效: %s",
+	"doctor.proxy_none":        "未设置代理，直接连接",
+	"doctor.plugin":            "插件",
+	"doctor.mock_detail":       "不发送网络请求",
+	"doctor.mock":              "模拟提供商",
+	"doctor.auth":              "认证",
+	"doctor.auth_fix":          "检查 providers.%s.auth 中的 %s 认证配置",
+	"doctor.auth_ok":           "%s 访问令牌获取成功",
+	"doctor.api_key":           "API密钥",
+	"doctor.capabilities":      "功能",
+	"doctor.network":           "网络",
+	"doctor.base_url_invalid":  "base_url 无效: %s",
+	"doctor.base_url_fix":      "base_url 应为 https://host/v1 格式",
+	"doctor.direct":            "直接连接",
+	"doctor.via_proxy":         "经代理 %s",
+	"doctor.network_fix":       "检查网络连接和 base_url 是否正确",
+	"doctor.proxy_network_fix": "检查代理是否可用，或将该地址加入 NO_PROXY",
+	"doctor.unreachable":       "无法连接 %s（%s）: %v",
+	"doctor.reachable":         "%s 可以访问（%s，%dms）",
+	"doctor.clock_skew":        "本机时间与服务器相差约 %s",
+	"doctor.clock":             "时钟",
+	"doctor.clock_fix":         "同步系统时间（如启用NTP），时间偏差过大可能导致TLS或认证失败",
+	"doctor.clock_ok":          "与服务器相差约 %s",
+	"doctor.completion":        "测试请求",
+	"doctor.completion_ok":     "%s 响应正常（%.1fs）",
+	"doctor.auth_fix_key":      "API密钥无效或没有权限，使用 'ai-chat-cli config set providers.%s.api_key YOUR_API_KEY' 更新",
+	"doctor.budget_fix":        "账户余额或额度不足，请检查提供商的账单设置",
+	"doctor.not_found_fix":     "模型或接口不存在，检查 providers.%s.model 和 base_url",
+	"doctor.retry_fix":         "检查 model 配置，或稍后重试",
+}
@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"ai-chat-cli/pkg/session"
+)
+
+// chatGPTConversation ChatGPT导出中的一个对话
+type chatGPTConversation struct {
+	ID             string                 `json:"id"`
+	ConversationID string                 `json:"conversation_id"`
+	Title          string                 `json:"title"`
+	CreateTime     float64                `json:"create_time"`
+	UpdateTime     float64                `json:"update_time"`
+	CurrentNode    string                 `json:"current_node"`
+	Mapping        map[string]chatGPTNode `json:"mapping"`
+}
+
+// chatGPTNode 对话树中的一个节点
+type chatGPTNode struct {
+	Parent  string          `json:"parent"`
+	Message *chatGPTMessage `json:"message"`
+}
+
+// chatGPTMessage 节点中的消息
+type chatGPTMessage struct {
+	Author struct {
+		Role string `json:"role"`
+		Name string `json:"name"` // role 为 tool 时是工具名称，如 python
+	} `json:"author"`
+	// Recipient 消息的接收方，助手调用工具时为工具名称，普通回复为 all
+	Recipient  string  `json:"recipient"`
+	CreateTime float64 `json:"create_time"`
+	Content    struct {
+		ContentType string            `json:"content_type"`
+		Parts       []json.RawMessage `json:"parts"`
+		Text        string            `json:"text"`
+	} `json:"content"`
+	Metadata struct {
+		ModelSlug string `json:"model_slug"`
+	} `json:"metadata"`
+}
+
+// parseChatGPT 解析ChatGPT导出。对话以树的形式保存（编辑和重新生成会产生分支），
+// 从 current_node 沿父节点回溯即可得到用户最后看到的那条对话线
+func parseChatGPT(data []byte) ([]*session.Session, error) {
+	var conversations []chatGPTConversation
+	if err := json.Unmarshal(data, &conversations); err != nil {
+		return nil, fmt.Errorf("解析ChatGPT导出失败: %w", err)
+	}
+
+	sessions := make([]*session.Session, 0, len(conversations))
+	for _, c := range conversations {
+		id := c.ConversationID
+		if id == "" {
+			id = c.ID
+		}
+		if id == "" {
+			continue
+		}
+
+		s := &session.Session{
+			ID:        FormatChatGPT + "-" + id,
+			Title:     c.Title,
+			Source:    FormatChatGPT,
+			CreatedAt: unixTime(c.CreateTime),
+			UpdatedAt: unixTime(c.UpdateTime),
+		}
+
+		// 回溯得到的节点是从新到旧的，反转后再组装消息
+		var line []*chatGPTMessage
+		seen := map[string]bool{}
+		for nodeID := c.CurrentNode; nodeID != "" && !seen[nodeID]; {
+			seen[nodeID] = true
+			node, ok := c.Mapping[nodeID]
+			if !ok {
+				break
+			}
+			if m := node.Message; m != nil {
+				line = append(line, m)
+				if s.Model == "" && m.Metadata.ModelSlug != "" {
+					s.Model = m.Metadata.ModelSlug
+				}
+			}
+			nodeID = node.Parent
+		}
+		for i, j := 0, len(line)-1; i < j; i, j = i+1, j-1 {
+			line[i], line[j] = line[j], line[i]
+		}
+
+		messages := chatGPTMessages(line)
+		if len(messages) == 0 {
+			continue
+		}
+		s.Messages = messages
+		fillDefaults(s)
+		sessions = append(sessions, s)
+	}
+
+	return sessions, nil
+}
+
+// chatGPTMessages 将对话线中的消息按时间顺序组装为会话消息。助手发给工具的消息（recipient 不是 all）
+// 和工具返回的消息（role 为 tool）合并为工具调用，附加到之后的助手回复上
+func chatGPTMessages(line []*chatGPTMessage) []session.Message {
+	var messages []session.Message
+	var pending []session.ToolCall
+	var started []float64
+	flush := func() {
+		if len(pending) > 0 {
+			// 调用工具后没有文字回复时单独保存为一条助手消息
+			messages = append(messages, session.Message{Role: "assistant", CreatedAt: unixTime(started[0]), ToolCalls: pending})
+			pending, started = nil, nil
+		}
+	}
+
+	for _, m := range line {
+		text := chatGPTText(m)
+		switch role := m.Author.Role; {
+		case role == "assistant" && m.Recipient != "" && m.Recipient != "all":
+			pending = append(pending, session.ToolCall{Name: m.Recipient, Arguments: text})
+			started = append(started, m.CreateTime)
+		case role == "tool":
+			// 结果对应最近一次调用同一工具（浏览等工具的名称带有子命令，如 browser.search）
+			for i := len(pending) - 1; i >= 0; i-- {
+				if pending[i].Result != "" || !chatGPTSameTool(pending[i].Name, m.Author.Name) {
+					continue
+				}
+				pending[i].Result = text
+				if started[i] > 0 && m.CreateTime > started[i] {
+					pending[i].DurationMs = int64((m.CreateTime - started[i]) * 1000)
+				}
+				break
+			}
+		case role == "assistant" && text != "":
+			messages = append(messages, session.Message{Role: role, Content: text, CreatedAt: unixTime(m.CreateTime), ToolCalls: pending})
+			pending, started = nil, nil
+		case (role == "user" || role == "system") && text != "":
+			flush()
+			messages = append(messages, session.Message{Role: role, Content: text, CreatedAt: unixTime(m.CreateTime)})
+		}
+	}
+	flush()
+	return messages
+}
+
+// chatGPTSameTool 判断工具结果是否属于该调用，名称为空时视为匹配
+func chatGPTSameTool(call, result string) bool {
+	if call == "" || result == "" {
+		return true
+	}
+	base := func(name string) string {
+		name, _, _ = strings.Cut(name, ".")
+		return name
+	}
+	return base(call) == base(result)
+}
+
+// chatGPTText 提取消息中的文本，图片等非文本内容会被忽略
+func chatGPTText(m *chatGPTMessage) string {
+	if m.Content.Text != "" {
+		return strings.TrimSpace(m.Content.Text)
+	}
+
+	var parts []string
+	for _, raw := range m.Content.Parts {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil && s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
+
+// unixTime 将浮点数形式的Unix时间戳转换为时间
+func unixTime(ts float64) time.Time {
+	if ts <= 0 {
+		return time.Time{}
+	}
+	sec, frac := math.Modf(ts)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-chat-cli/pkg/session"
+)
+
+// claudeConversation Claude导出中的一个对话
+type claudeConversation struct {
+	UUID         string          `json:"uuid"`
+	Name         string          `json:"name"`
+	CreatedAt    string          `json:"created_at"`
+	UpdatedAt    string          `json:"updated_at"`
+	ChatMessages []claudeMessage `json:"chat_messages"`
+}
+
+// claudeMessage 对话中的一条消息
+type claudeMessage struct {
+	Sender    string          `json:"sender"`
+	Text      string          `json:"text"`
+	CreatedAt string          `json:"created_at"`
+	Content   []claudeContent `json:"content"`
+}
+
+// claudeContent 消息内容的一部分: text、tool_use（工具调用）或 tool_result（调用结果）
+type claudeContent struct {
+	Type           string          `json:"type"`
+	Text           string          `json:"text"`
+	Name           string          `json:"name"`
+	Input          json.RawMessage `json:"input"`
+	Content        json.RawMessage `json:"content"` // tool_result 的结果，可以是文本或内容列表
+	IsError        bool            `json:"is_error"`
+	StartTimestamp string          `json:"start_timestamp"`
+	StopTimestamp  string          `json:"stop_timestamp"`
+}
+
+// parseClaude 解析Claude导出
+func parseClaude(data []byte) ([]*session.Session, error) {
+	var conversations []claudeConversation
+	if err := json.Unmarshal(data, &conversations); err != nil {
+		return nil, fmt.Errorf("解析Claude导出失败: %w", err)
+	}
+
+	sessions := make([]*session.Session, 0, len(conversations))
+	for _, c := range conversations {
+		if c.UUID == "" {
+			continue
+		}
+
+		s := &session.Session{
+			ID:        FormatClaude + "-" + c.UUID,
+			Title:     c.Name,
+			Source:    FormatClaude,
+			CreatedAt: parseTime(c.CreatedAt),
+			UpdatedAt: parseTime(c.UpdatedAt),
+		}
+
+		for _, m := range c.ChatMessages {
+			role := m.Sender
+			if role == "human" {
+				role = "user"
+			}
+			if role != "user" && role != "assistant" {
+				continue
+			}
+
+			text, calls := claudeText(m), claudeToolCalls(m)
+			if text == "" && len(calls) == 0 {
+				continue
+			}
+			s.Messages = append(s.Messages, session.Message{Role: role, Content: text, CreatedAt: parseTime(m.CreatedAt), ToolCalls: calls})
+		}
+		if len(s.Messages) == 0 {
+			continue
+		}
+		fillDefaults(s)
+		sessions = append(sessions, s)
+	}
+
+	return sessions, nil
+}
+
+// claudeText 提取消息中的文本，优先使用结构化的content字段
+func claudeText(m claudeMessage) string {
+	var parts []string
+	for _, c := range m.Content {
+		if c.Type == "text" && c.Text != "" {
+			parts = append(parts, c.Text)
+		}
+	}
+	if len(parts) > 0 {
+		return strings.TrimSpace(strings.Join(parts, "\n"))
+	}
+	return strings.TrimSpace(m.Text)
+}
+
+// claudeToolCalls 提取消息中的工具调用，tool_result 对应之前最近一次同名且还没有结果的调用。
+// 耗时为调用生成完成到结果返回的时间
+func claudeToolCalls(m claudeMessage) []session.ToolCall {
+	var calls []session.ToolCall
+	var ends []time.Time
+	var done []bool
+	for _, c := range m.Content {
+		switch c.Type {
+		case "tool_use":
+			args := ""
+			if len(c.Input) > 0 && string(c.Input) != "null" {
+				var indented bytes.Buffer
+				if json.Indent(&indented, c.Input, "", "  ") == nil {
+					args = indented.String()
+				} else {
+					args = string(c.Input)
+				}
+			}
+			calls = append(calls, session.ToolCall{Name: c.Name, Arguments: args})
+			ends = append(ends, parseTime(c.StopTimestamp))
+			done = append(done, false)
+		case "tool_result":
+			for i := len(calls) - 1; i >= 0; i-- {
+				if done[i] || (c.Name != "" && calls[i].Name != c.Name) {
+					continue
+				}
+				done[i] = true
+				calls[i].Result = claudeResultText(c.Content)
+				calls[i].Error = c.IsError
+				if stop := parseTime(c.StopTimestamp); !stop.IsZero() && !ends[i].IsZero() && stop.After(ends[i]) {
+					calls[i].DurationMs = stop.Sub(ends[i]).Milliseconds()
+				}
+				break
+			}
+		}
+	}
+	return calls
+}
+
+// claudeResultText 提取工具结果中的文本
+func claudeResultText(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return strings.TrimSpace(text)
+	}
+	var parts []claudeContent
+	if json.Unmarshal(raw, &parts) != nil {
+		return ""
+	}
+	var texts []string
+	for _, p := range parts {
+		if p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n"))
+}
+
+// parseTime 解析RFC 3339格式的时间，无法解析时返回零值
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,109 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"ai-chat-cli/pkg/session"
+)
+
+const (
+	// FormatChatGPT ChatGPT官方数据导出
+	FormatChatGPT = "chatgpt"
+	// FormatClaude Claude官方数据导出
+	FormatClaude = "claude"
+)
+
+// ReadConversations 读取导出文件中的 conversations.json，支持导出的zip包或解压后的json文件
+func ReadConversations(filename string) ([]byte, error) {
+	if !strings.EqualFold(path.Ext(filename), ".zip") {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("读取文件失败: %w", err)
+		}
+		return data, nil
+	}
+
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, fmt.Errorf("打开zip文件失败: %w", err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if path.Base(f.Name) != "conversations.json" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 失败: %w", f.Name, err)
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 失败: %w", f.Name, err)
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("zip文件中没有找到 conversations.json")
+}
+
+// DetectFormat 根据对话记录的字段判断导出格式
+func DetectFormat(data []byte) (string, error) {
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return "", fmt.Errorf("解析 conversations.json 失败: %w", err)
+	}
+
+	for _, item := range items {
+		if _, ok := item["mapping"]; ok {
+			return FormatChatGPT, nil
+		}
+		if _, ok := item["chat_messages"]; ok {
+			return FormatClaude, nil
+		}
+	}
+	return "", fmt.Errorf("无法识别导出格式，请使用 --format 指定")
+}
+
+// Parse 将导出的对话记录解析为会话
+func Parse(data []byte, format string) ([]*session.Session, error) {
+	switch format {
+	case FormatChatGPT:
+		return parseChatGPT(data)
+	case FormatClaude:
+		return parseClaude(data)
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// fillDefaults 补全导出数据中缺失的标题和时间
+func fillDefaults(s *session.Session) {
+	first, last := s.Messages[0], s.Messages[len(s.Messages)-1]
+
+	if s.Title == "" {
+		for _, m := range s.Messages {
+			if m.Role == "user" {
+				s.Title = session.DefaultTitle(m.Content)
+				break
+			}
+		}
+	}
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = first.CreatedAt
+	}
+	if s.UpdatedAt.IsZero() {
+		s.UpdatedAt = last.CreatedAt
+	}
+	if s.UpdatedAt.IsZero() {
+		s.UpdatedAt = s.CreatedAt
+	}
+}
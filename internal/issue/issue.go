@@ -0,0 +1,195 @@
+package issue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 支持的问题跟踪系统
+const (
+	TrackerGitHub = "github"
+	TrackerJira   = "jira"
+)
+
+// Draft 问题草稿
+type Draft struct {
+	Title  string
+	Body   string   // Markdown
+	Labels []string // 只用于 GitHub
+}
+
+// Options 提交问题的设置
+type Options struct {
+	Tracker string
+	Token   string // GitHub token 或 Jira API token
+
+	Repo string // GitHub 仓库，格式为 owner/name
+
+	JiraURL   string // Jira 站点地址，如 https://example.atlassian.net
+	JiraEmail string // Jira 账号邮箱，与 API token 一起用于基本认证
+	Project   string // Jira 项目键
+	IssueType string // Jira 问题类型，默认为 Bug
+}
+
+// client 提交问题使用的HTTP客户端
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Target 描述提交的位置，用于确认提示
+func (o Options) Target() string {
+	if o.Tracker == TrackerJira {
+		return fmt.Sprintf("Jira %s（%s）", o.Project, o.JiraURL)
+	}
+	return "GitHub " + o.Repo
+}
+
+// Validate 检查提交所需的设置是否完整
+func (o Options) Validate() error {
+	switch o.Tracker {
+	case TrackerGitHub:
+		if o.Repo == "" || strings.Count(o.Repo, "/") != 1 {
+			return fmt.Errorf("GitHub 仓库格式应为 owner/name: %q", o.Repo)
+		}
+		if o.Token == "" {
+			return fmt.Errorf("提交到 GitHub 需要 token，请设置 issues.github_token 或 GITHUB_TOKEN 环境变量")
+		}
+	case TrackerJira:
+		if o.JiraURL == "" || o.Project == "" {
+			return fmt.Errorf("提交到 Jira 需要设置 issues.jira_url 和项目键（issues.jira_project 或 --jira）")
+		}
+		if o.JiraEmail == "" || o.Token == "" {
+			return fmt.Errorf("提交到 Jira 需要设置 issues.jira_email 和 issues.jira_token（或 JIRA_API_TOKEN 环境变量）")
+		}
+	default:
+		return fmt.Errorf("不支持的问题跟踪系统: %s（可选 github、jira）", o.Tracker)
+	}
+	return nil
+}
+
+// Post 提交问题，返回问题的链接
+func Post(ctx context.Context, d Draft, opts Options) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+	if opts.Tracker == TrackerJira {
+		return postJira(ctx, d, opts)
+	}
+	return postGitHub(ctx, d, opts)
+}
+
+// postGitHub 在 GitHub 仓库中创建 issue
+func postGitHub(ctx context.Context, d Draft, opts Options) (string, error) {
+	payload := map[string]interface{}{"title": d.Title, "body": d.Body}
+	if len(d.Labels) > 0 {
+		payload["labels"] = d.Labels
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/repos/"+opts.Repo+"/issues", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+opts.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	data, err := send(req)
+	if err != nil {
+		return "", err
+	}
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(data, &created); err != nil || created.HTMLURL == "" {
+		return "", fmt.Errorf("解析 GitHub 响应失败: %s", strings.TrimSpace(string(data)))
+	}
+	return created.HTMLURL, nil
+}
+
+// postJira 使用 Jira REST API v2 创建问题，描述为 Markdown 原文
+func postJira(ctx context.Context, d Draft, opts Options) (string, error) {
+	issueType := opts.IssueType
+	if issueType == "" {
+		issueType = "Bug"
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": opts.Project},
+			"summary":     d.Title,
+			"description": d.Body,
+			"issuetype":   map[string]string{"name": issueType},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	base := strings.TrimRight(opts.JiraURL, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/rest/api/2/issue", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(opts.JiraEmail, opts.Token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	data, err := send(req)
+	if err != nil {
+		return "", err
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := json.Unmarshal(data, &created); err != nil || created.Key == "" {
+		return "", fmt.Errorf("解析 Jira 响应失败: %s", strings.TrimSpace(string(data)))
+	}
+	return base + "/browse/" + created.Key, nil
+}
+
+// send 发送请求并读取响应内容，状态码不是2xx时返回错误
+func send(req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("提交问题失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("提交问题失败: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// ParseDraft 解析模型生成的草稿：第一个非空行是标题（可以带 # 或 "Title:" 前缀），其余为正文
+func ParseDraft(reply string) (Draft, error) {
+	reply = strings.TrimSpace(reply)
+	// 模型有时把整个草稿放在代码块中
+	if strings.HasPrefix(reply, "```") {
+		if _, rest, ok := strings.Cut(reply, "\n"); ok {
+			reply = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), "```"))
+		}
+	}
+
+	title, body, _ := strings.Cut(reply, "\n")
+	title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+	for _, prefix := range []string{"Title:", "title:", "标题：", "标题:"} {
+		title = strings.TrimSpace(strings.TrimPrefix(title, prefix))
+	}
+	title = strings.Trim(title, "*\"")
+	if title == "" {
+		return Draft{}, fmt.Errorf("AI没有返回问题标题")
+	}
+	return Draft{Title: title, Body: strings.TrimSpace(body)}, nil
+}
@@ -0,0 +1,102 @@
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// commandTimeout 调用系统钥匙串命令的最长等待时间
+const commandTimeout = 10 * time.Second
+
+// ErrNotFound 钥匙串中没有该条目
+var ErrNotFound = errors.New("钥匙串中没有该条目")
+
+// ErrUnsupported 当前系统没有可用的钥匙串
+var ErrUnsupported = errors.New("没有可用的系统钥匙串")
+
+// Get 从系统钥匙串读取密码：macOS 使用 security，Linux 使用 secret-tool（libsecret）
+func Get(service, account string) (string, error) {
+	var name string
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		name, args = "security", []string{"find-generic-password", "-s", service, "-a", account, "-w"}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		name, args = "secret-tool", []string{"lookup", "service", service, "account", account}
+	default:
+		return "", ErrUnsupported
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return "", ErrUnsupported
+	}
+
+	out, err := run(name, args, "")
+	if err != nil {
+		// secret-tool 在条目不存在时没有输出，security 以状态码44退出
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && (strings.TrimSpace(out) == "" || exitErr.ExitCode() == 44) {
+			return "", ErrNotFound
+		}
+		return "", commandError("读取钥匙串失败", err, out)
+	}
+	secret := strings.TrimRight(out, "\r\n")
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set 将密码保存到系统钥匙串，已存在时覆盖
+func Set(service, account, secret string) error {
+	var name, stdin string
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		name, args = "security", []string{"add-generic-password", "-U", "-s", service, "-a", account, "-w", secret}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		// secret-tool 从标准输入读取密码，避免出现在进程列表中
+		name, args, stdin = "secret-tool", []string{"store", "--label=" + service, "service", service, "account", account}, secret
+	default:
+		return ErrUnsupported
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return ErrUnsupported
+	}
+
+	if out, err := run(name, args, stdin); err != nil {
+		return commandError("保存到钥匙串失败", err, out)
+	}
+	return nil
+}
+
+// commandError 将命令的错误输出附加到错误信息中
+func commandError(action string, err error, stderr string) error {
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return fmt.Errorf("%s: %v: %s", action, err, msg)
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// run 执行钥匙串命令，返回标准输出；失败时返回标准错误的内容
+func run(name string, args []string, stdin string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stderr.String(), err
+	}
+	return stdout.String(), nil
+}
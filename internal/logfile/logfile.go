@@ -0,0 +1,170 @@
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 默认的轮转设置
+const (
+	DefaultMaxSizeMB  = 10
+	DefaultMaxBackups = 5
+)
+
+// 轮转后文件名中的时间格式：精确到毫秒，同一秒内多次轮转时不会覆盖之前的备份；
+// 解析时秒后的小数部分可有可无，兼容按秒命名的旧备份
+const (
+	backupTimeFormat  = "20060102-150405.000"
+	backupParseFormat = "20060102-150405"
+)
+
+// Options 日志轮转设置
+type Options struct {
+	MaxSizeMB  int // 单个日志文件的最大大小（MB），超过后轮转，0 使用默认值
+	MaxAgeDays int // 轮转后的文件保留天数，0 表示不按时间清理
+	MaxBackups int // 最多保留的轮转文件数，0 使用默认值，负数表示不限制
+}
+
+// Writer 按大小轮转的日志文件，可以并发写入。
+// 文件超过大小限制时重命名为 <名称>-<时间><扩展名>，并按数量和时间清理旧文件
+type Writer struct {
+	path    string
+	maxSize int64
+	maxAge  time.Duration
+	backups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Open 打开日志文件，文件不存在时创建，目录不存在时一并创建
+func Open(path string, opts Options) (*Writer, error) {
+	if opts.MaxSizeMB <= 0 {
+		opts.MaxSizeMB = DefaultMaxSizeMB
+	}
+	if opts.MaxBackups == 0 {
+		opts.MaxBackups = DefaultMaxBackups
+	}
+
+	w := &Writer{
+		path:    path,
+		maxSize: int64(opts.MaxSizeMB) * 1024 * 1024,
+		maxAge:  time.Duration(opts.MaxAgeDays) * 24 * time.Hour,
+		backups: opts.MaxBackups,
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建日志目录失败: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	w.cleanup()
+	return w, nil
+}
+
+// Write 写入日志，写入后超过大小限制时先轮转
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close 关闭日志文件
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// open 以追加方式打开日志文件并记录当前大小
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("读取日志文件失败: %w", err)
+	}
+	w.file, w.size = f, info.Size()
+	return nil
+}
+
+// rotate 将当前日志文件重命名为带时间的备份文件，然后打开新文件，调用方需持有锁
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("关闭日志文件失败: %w", err)
+	}
+
+	ext := filepath.Ext(w.path)
+	backup := ""
+	for t := time.Now(); ; t = t.Add(time.Millisecond) {
+		backup = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(w.path, ext), t.Format(backupTimeFormat), ext)
+		if _, err := os.Stat(backup); os.IsNotExist(err) {
+			break
+		}
+	}
+	if err := os.Rename(w.path, backup); err != nil {
+		return fmt.Errorf("轮转日志文件失败: %w", err)
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+	go w.cleanup()
+	return nil
+}
+
+// cleanup 删除超过保留天数或超出保留数量的轮转文件
+func (w *Writer) cleanup() {
+	for i, name := range Backups(w.path) {
+		expired := w.backups > 0 && i >= w.backups
+		if !expired && w.maxAge > 0 {
+			if info, err := os.Stat(name); err == nil && time.Since(info.ModTime()) > w.maxAge {
+				expired = true
+			}
+		}
+		if expired {
+			os.Remove(name)
+		}
+	}
+}
+
+// Backups 列出日志文件轮转生成的备份文件，最新的在前
+func Backups(path string) []string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext) + "-"
+	matches, err := filepath.Glob(base + "*" + ext)
+	if err != nil {
+		return nil
+	}
+
+	// 只处理轮转生成的文件，避免误删名称相近的其他日志
+	var backups []string
+	for _, name := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, base), ext)
+		if _, err := time.Parse(backupParseFormat, stamp); err == nil {
+			backups = append(backups, name)
+		}
+	}
+
+	// 文件名中的时间可以按字符串排序，最新的在前
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-chat-cli/internal/filelock"
+)
+
+// DefaultProfile 使用默认配置文件时的配置档名称
+const DefaultProfile = "default"
+
+// Fact 用 /remember 记住的一条事实，如 stack=Go + PostgreSQL
+type Fact struct {
+	Key     string    `json:"key"`
+	Value   string    `json:"value"`
+	Updated time.Time `json:"updated"`
+}
+
+// Store 一个配置档的记忆，保存在 ~/.ai-chat-cli/memory/<配置档>.json。
+// 多个对话可能同时修改，写入时加锁并重新读取文件
+type Store struct {
+	profile string
+	path    string
+	facts   []Fact
+}
+
+// Dir 获取记忆目录 ~/.ai-chat-cli/memory
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ai-chat-cli", "memory"), nil
+}
+
+// Open 读取配置档的记忆，文件不存在时为空
+func Open(profile string) (*Store, error) {
+	if profile == "" || strings.ContainsAny(profile, `/\`) || profile == "." || profile == ".." {
+		return nil, fmt.Errorf("无效的配置档名称: %q", profile)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return nil, fmt.Errorf("获取记忆目录失败: %w", err)
+	}
+	s := &Store{profile: profile, path: filepath.Join(dir, profile+".json")}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load 重新读取记忆文件
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.facts = nil
+			return nil
+		}
+		return fmt.Errorf("读取记忆失败: %w", err)
+	}
+	var facts []Fact
+	if err := json.Unmarshal(data, &facts); err != nil {
+		return fmt.Errorf("解析记忆文件 %s 失败: %w", s.path, err)
+	}
+	s.facts = facts
+	return nil
+}
+
+// update 加锁后重新读取文件，修改后写回
+func (s *Store) update(fn func()) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("创建记忆目录失败: %w", err)
+	}
+	lock, err := filelock.Acquire(s.path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+	fn()
+
+	facts := s.facts
+	if facts == nil {
+		facts = []Fact{}
+	}
+	data, err := json.MarshalIndent(facts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化记忆失败: %w", err)
+	}
+	if err := filelock.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("保存记忆失败: %w", err)
+	}
+	return nil
+}
+
+// Profile 配置档名称
+func (s *Store) Profile() string {
+	return s.profile
+}
+
+// Facts 按键名排序的所有事实
+func (s *Store) Facts() []Fact {
+	facts := append([]Fact(nil), s.facts...)
+	sort.Slice(facts, func(i, j int) bool { return facts[i].Key < facts[j].Key })
+	return facts
+}
+
+// Set 记住一条事实，键名已存在时覆盖原来的值。键名不区分大小写
+func (s *Store) Set(key, value string) error {
+	return s.update(func() {
+		fact := Fact{Key: key, Value: value, Updated: time.Now()}
+		for i, f := range s.facts {
+			if strings.EqualFold(f.Key, key) {
+				s.facts[i] = fact
+				return
+			}
+		}
+		s.facts = append(s.facts, fact)
+	})
+}
+
+// Forget 删除一条事实，没有该键名时返回false
+func (s *Store) Forget(key string) (bool, error) {
+	found := false
+	err := s.update(func() {
+		for i, f := range s.facts {
+			if strings.EqualFold(f.Key, key) {
+				s.facts = append(s.facts[:i], s.facts[i+1:]...)
+				found = true
+				return
+			}
+		}
+	})
+	return found, err
+}
+
+// Prompt 注入系统上下文的记忆内容，没有事实时为空
+func (s *Store) Prompt() string {
+	facts := s.Facts()
+	if len(facts) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Facts the user asked you to remember across conversations. Take them into account when relevant, without repeating them back unprompted:\n")
+	for _, f := range facts {
+		fmt.Fprintf(&b, "- %s: %s\n", f.Key, f.Value)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// ParseFact 解析 /remember 的参数 key=value，键名和值两侧的空白会被去掉
+func ParseFact(arg string) (key, value string, err error) {
+	key, value, ok := strings.Cut(arg, "=")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !ok || key == "" || value == "" {
+		return "", "", fmt.Errorf("格式应为 key=value")
+	}
+	return key, value, nil
+}
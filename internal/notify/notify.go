@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// sendTimeout 发送桌面通知的最长等待时间
+const sendTimeout = 10 * time.Second
+
+// Bell 在终端中响铃
+func Bell() {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		// 没有控制终端（如Windows）时写入标准错误
+		fmt.Fprint(os.Stderr, "\a")
+		return
+	}
+	defer tty.Close()
+	fmt.Fprint(tty, "\a")
+}
+
+// Send 发送桌面通知：Linux 使用 notify-send，macOS 使用 osascript，Windows 使用 PowerShell 气泡通知
+func Send(title, message string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(5000, %s, %s, 'Info')
+Start-Sleep -Seconds 3
+$n.Dispose()`, powerShellString(title), powerShellString(message))
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	default:
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=ai-chat-cli", title, message)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("发送桌面通知失败: %v: %s", err, msg)
+		}
+		return fmt.Errorf("发送桌面通知失败: %w", err)
+	}
+	return nil
+}
+
+// appleScriptString 转换为 AppleScript 字符串字面量
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// powerShellString 转换为 PowerShell 单引号字符串字面量
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,182 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// GoogleScope Vertex AI 等 Google Cloud 接口使用的默认权限范围
+const GoogleScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// googleTokenURL 未在凭据文件中指定时使用的令牌地址
+const googleTokenURL = "https://oauth2.googleapis.com/token"
+
+// metadataTokenURL GCE、Cloud Run 等环境中元数据服务器的令牌地址
+const metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// googleCredentials 应用默认凭据（ADC）文件，支持服务账号和 gcloud 用户凭据
+type googleCredentials struct {
+	Type         string `json:"type"` // service_account 或 authorized_user
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// NewGoogleADC 创建使用 Google 应用默认凭据的令牌来源。
+// 依次查找 file、GOOGLE_APPLICATION_CREDENTIALS 和 gcloud 的默认凭据文件，都不存在时使用元数据服务器
+func NewGoogleADC(file, scope string) (*Source, error) {
+	if scope == "" {
+		scope = GoogleScope
+	}
+	path, err := findGoogleCredentials(file)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return &Source{name: "Google", fetch: metadataToken}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 Google 凭据失败: %w", err)
+	}
+	var creds googleCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("解析 Google 凭据 %s 失败: %w", path, err)
+	}
+	if creds.TokenURI == "" {
+		creds.TokenURI = googleTokenURL
+	}
+
+	switch creds.Type {
+	case "service_account":
+		key, err := parsePrivateKey(creds.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("解析 Google 服务账号私钥失败: %w", err)
+		}
+		return &Source{name: "Google", fetch: func(ctx context.Context) (string, time.Time, error) {
+			assertion, err := signJWT(key, creds, scope)
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			return postToken(ctx, creds.TokenURI, url.Values{
+				"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+				"assertion":  {assertion},
+			})
+		}}, nil
+	case "authorized_user":
+		return &Source{name: "Google", fetch: func(ctx context.Context) (string, time.Time, error) {
+			return postToken(ctx, creds.TokenURI, url.Values{
+				"grant_type":    {"refresh_token"},
+				"client_id":     {creds.ClientID},
+				"client_secret": {creds.ClientSecret},
+				"refresh_token": {creds.RefreshToken},
+			})
+		}}, nil
+	default:
+		return nil, fmt.Errorf("不支持的 Google 凭据类型: %s（支持 service_account、authorized_user）", creds.Type)
+	}
+}
+
+// findGoogleCredentials 查找凭据文件，都不存在时返回空字符串
+func findGoogleCredentials(file string) (string, error) {
+	if file != "" {
+		return file, nil
+	}
+	if env := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); env != "" {
+		return env, nil
+	}
+
+	dir := os.Getenv("CLOUDSDK_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config", "gcloud")
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			dir = filepath.Join(appData, "gcloud")
+		}
+	}
+	path := filepath.Join(dir, "application_default_credentials.json")
+	if _, err := os.Stat(path); err != nil {
+		return "", nil
+	}
+	return path, nil
+}
+
+// metadataToken 从元数据服务器获取当前实例服务账号的令牌
+func metadataToken(ctx context.Context) (string, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataTokenURL, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	token, expiry, err := doToken(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("未找到 Google 凭据文件，也无法访问元数据服务器（可运行 'gcloud auth application-default login'）: %w", err)
+	}
+	return token, expiry, nil
+}
+
+// parsePrivateKey 解析PEM格式的RSA私钥，支持PKCS#8和PKCS#1
+func parsePrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("不是PEM格式")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("不是RSA私钥")
+		}
+		return rsaKey, nil
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// signJWT 生成服务账号换取令牌使用的 RS256 JWT
+func signJWT(key *rsa.PrivateKey, creds googleCredentials, scope string) (string, error) {
+	now := time.Now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": creds.PrivateKeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   creds.ClientEmail,
+		"scope": scope,
+		"aud":   creds.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
@@ -0,0 +1,161 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 令牌在过期前多久刷新，避免请求途中过期
+const refreshBefore = time.Minute
+
+// requestTimeout 获取令牌的超时时间
+const requestTimeout = 30 * time.Second
+
+// fetchFunc 获取新的访问令牌及其过期时间
+type fetchFunc func(ctx context.Context) (string, time.Time, error)
+
+// Source 缓存访问令牌，过期前自动重新获取，可以被多个goroutine同时使用
+type Source struct {
+	name  string
+	fetch fetchFunc
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// Token 返回有效的访问令牌，缓存的令牌即将过期时重新获取
+func (s *Source) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Until(s.expiry) > refreshBefore {
+		return s.token, nil
+	}
+	token, expiry, err := s.fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("获取 %s 访问令牌失败: %w", s.name, err)
+	}
+	s.token, s.expiry = token, expiry
+	return token, nil
+}
+
+// Invalidate 丢弃缓存的令牌，服务端拒绝令牌时调用，下次请求会重新获取
+func (s *Source) Invalidate() {
+	s.mu.Lock()
+	s.token = ""
+	s.mu.Unlock()
+}
+
+// NewClientCredentials 创建使用 OAuth 2.0 客户端凭据流程的令牌来源
+func NewClientCredentials(tokenURL, clientID, clientSecret, scope string) (*Source, error) {
+	if tokenURL == "" || clientID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("客户端凭据认证需要 token_url、client_id 和 client_secret")
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+	}
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+	return &Source{name: "OAuth", fetch: func(ctx context.Context) (string, time.Time, error) {
+		return postToken(ctx, tokenURL, form)
+	}}, nil
+}
+
+// AzureScope Azure OpenAI 使用的默认权限范围
+const AzureScope = "https://cognitiveservices.azure.com/.default"
+
+// NewAzureAD 创建使用 Azure AD（Microsoft Entra ID）服务主体认证的令牌来源，scope 为空时使用 Azure OpenAI 的权限范围
+func NewAzureAD(tenantID, clientID, clientSecret, scope string) (*Source, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("Azure AD 认证需要 tenant_id")
+	}
+	if scope == "" {
+		scope = AzureScope
+	}
+	tokenURL := "https://login.microsoftonline.com/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+	s, err := NewClientCredentials(tokenURL, clientID, clientSecret, scope)
+	if err != nil {
+		return nil, err
+	}
+	s.name = "Azure AD"
+	return s, nil
+}
+
+// tokenResponse 令牌接口的响应
+type tokenResponse struct {
+	AccessToken string          `json:"access_token"`
+	ExpiresIn   json.RawMessage `json:"expires_in"` // 部分服务返回字符串
+	Error       string          `json:"error"`
+	Description string          `json:"error_description"`
+}
+
+// postToken 以表单方式请求令牌接口
+func postToken(ctx context.Context, tokenURL string, form url.Values) (string, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doToken(req)
+}
+
+// doToken 发送令牌请求并解析响应
+func doToken(req *http.Request) (string, time.Time, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return "", time.Time{}, fmt.Errorf("令牌接口返回 %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if resp.StatusCode != http.StatusOK || tr.AccessToken == "" {
+		msg := tr.Description
+		if msg == "" {
+			msg = tr.Error
+		}
+		if msg == "" {
+			msg = strings.TrimSpace(string(body))
+		}
+		return "", time.Time{}, fmt.Errorf("令牌接口返回 %d: %s", resp.StatusCode, msg)
+	}
+
+	// 没有返回有效期时按1小时处理
+	seconds := 3600
+	if n, err := parseExpiresIn(tr.ExpiresIn); err == nil && n > 0 {
+		seconds = n
+	}
+	return tr.AccessToken, time.Now().Add(time.Duration(seconds) * time.Second), nil
+}
+
+// parseExpiresIn 解析数字或字符串形式的有效期（秒）
+func parseExpiresIn(raw json.RawMessage) (int, error) {
+	var n int
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, err
+	}
+	_, err := fmt.Sscan(s, &n)
+	return n, err
+}
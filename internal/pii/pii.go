@@ -0,0 +1,168 @@
+package pii
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"ai-chat-cli/internal/secrets"
+)
+
+// 敏感信息的类型，也是占位符的前缀
+const (
+	KindEmail = "EMAIL"
+	KindPhone = "PHONE"
+	KindID    = "ID"   // 身份证号、社会安全号等证件号码
+	KindCard  = "CARD" // 银行卡号
+	KindKey   = "KEY"  // API密钥、令牌、私钥等
+)
+
+// maxPlaceholderLen 占位符的最大长度，流式恢复时据此判断是否需要等待后续内容
+const maxPlaceholderLen = 16
+
+// pattern 一类敏感信息的识别规则
+type pattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// patterns 按优先级排列，位置重叠时保留先匹配的类型
+var patterns = []pattern{
+	{KindEmail, regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{KindID, regexp.MustCompile(`\b[1-9]\d{5}(?:19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]\b`)},
+	{KindID, regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{KindCard, regexp.MustCompile(`\b(?:\d{4}[ -]?){3}\d{4}(?:\d{3})?\b`)},
+	{KindPhone, regexp.MustCompile(`(?:\+?86[ -]?)?\b1[3-9]\d{9}\b`)},
+	{KindPhone, regexp.MustCompile(`\+\d{1,3}[ -]?\(?\d{1,4}\)?(?:[ -]?\d{2,4}){2,3}\b`)},
+	{KindPhone, regexp.MustCompile(`(?:\(\d{3}\)\s?|\b\d{3}-)\d{3}-\d{4}\b`)},
+}
+
+// Entry 一个被替换的值
+type Entry struct {
+	Kind        string
+	Placeholder string // 如 [EMAIL_1]
+	Value       string
+}
+
+// Masker 将敏感信息替换为占位符并在回复中恢复。
+// 同一个值始终使用同一个占位符，对话历史再次发送时占位符保持不变
+type Masker struct {
+	placeholders map[string]string // 值 -> 占位符
+	counts       map[string]int
+	entries      []Entry
+	replacer     *strings.Replacer
+}
+
+// NewMasker 创建替换器
+func NewMasker() *Masker {
+	return &Masker{placeholders: map[string]string{}, counts: map[string]int{}}
+}
+
+// span 文本中一处敏感信息
+type span struct {
+	kind       string
+	start, end int
+}
+
+// Mask 将文本中的敏感信息替换为占位符
+func (m *Masker) Mask(text string) string {
+	var spans []span
+	for _, s := range secrets.Locate(text) {
+		spans = append(spans, span{KindKey, s.Start, s.End})
+	}
+	for _, p := range patterns {
+		for _, loc := range p.re.FindAllStringIndex(text, -1) {
+			spans = append(spans, span{p.kind, loc[0], loc[1]})
+		}
+	}
+	if len(spans) == 0 {
+		return text
+	}
+	// 按位置排序，起点相同时保留先加入（优先级更高）的规则
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s.start < last {
+			continue
+		}
+		b.WriteString(text[last:s.start])
+		b.WriteString(m.placeholder(s.kind, text[s.start:s.end]))
+		last = s.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// placeholder 返回值对应的占位符，第一次出现时分配新的编号
+func (m *Masker) placeholder(kind, value string) string {
+	if p, ok := m.placeholders[value]; ok {
+		return p
+	}
+	m.counts[kind]++
+	p := fmt.Sprintf("[%s_%d]", kind, m.counts[kind])
+	m.placeholders[value] = p
+	m.entries = append(m.entries, Entry{Kind: kind, Placeholder: p, Value: value})
+	m.replacer = nil
+	return p
+}
+
+// Restore 将文本中的占位符恢复为原来的值
+func (m *Masker) Restore(text string) string {
+	if len(m.entries) == 0 {
+		return text
+	}
+	if m.replacer == nil {
+		pairs := make([]string, 0, len(m.entries)*2)
+		for _, e := range m.entries {
+			pairs = append(pairs, e.Placeholder, e.Value)
+		}
+		m.replacer = strings.NewReplacer(pairs...)
+	}
+	return m.replacer.Replace(text)
+}
+
+// Entries 返回所有被替换的值，按第一次出现的顺序排列
+func (m *Masker) Entries() []Entry {
+	return m.entries
+}
+
+// StreamRestorer 恢复流式回复中的占位符，占位符可能被拆分到多个数据块中
+type StreamRestorer struct {
+	masker  *Masker
+	pending string
+}
+
+// NewStreamRestorer 创建流式恢复器
+func (m *Masker) NewStreamRestorer() *StreamRestorer {
+	return &StreamRestorer{masker: m}
+}
+
+// Write 接收一个数据块，返回可以输出的内容，可能是占位符开头的部分会保留到下一个数据块
+func (r *StreamRestorer) Write(chunk string) string {
+	text := r.pending + chunk
+	r.pending = ""
+	if i := strings.LastIndexByte(text, '['); i >= 0 && !strings.Contains(text[i:], "]") && len(text)-i < maxPlaceholderLen {
+		r.pending = text[i:]
+		text = text[:i]
+	}
+	return r.masker.Restore(text)
+}
+
+// Flush 返回保留的剩余内容
+func (r *StreamRestorer) Flush() string {
+	text := r.pending
+	r.pending = ""
+	return r.masker.Restore(text)
+}
+
+// Redact 生成报告中显示的值，只保留开头和结尾的少量字符
+func Redact(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 6 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:2]) + strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-2:])
+}
@@ -0,0 +1,105 @@
+package pii
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"邮箱", "请回复 alice@example.com", "请回复 [EMAIL_1]"},
+		{"身份证号", "证件号 11010519491231002X 已登记", "证件号 [ID_1] 已登记"},
+		{"社会安全号", "SSN 123-45-6789", "SSN [ID_1]"},
+		{"银行卡号", "卡号 6222 0212 3456 7890", "卡号 [CARD_1]"},
+		{"手机号", "电话 13812345678", "电话 [PHONE_1]"},
+		{"带国家码的手机号", "电话 +86 13812345678", "电话 [PHONE_1]"},
+		{"国际电话", "call +44 20 7946 0958", "call [PHONE_1]"},
+		{"美国电话", "call (555) 123-4567", "call [PHONE_1]"},
+		{"密钥", "key sk-abcdefghijklmnopqrstuvwx", "key [KEY_1]"},
+		{"编号递增", "a@example.com b@example.com", "[EMAIL_1] [EMAIL_2]"},
+		{"相同的值使用相同的占位符", "a@example.com a@example.com", "[EMAIL_1] [EMAIL_1]"},
+		{"没有敏感信息", "今天天气不错", "今天天气不错"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMasker()
+			if got := m.Mask(tt.text); got != tt.want {
+				t.Errorf("Mask(%q) = %q，应为 %q", tt.text, got, tt.want)
+			}
+			if got := m.Restore(m.Mask(tt.text)); got != tt.text {
+				t.Errorf("Restore 得到 %q，应恢复为 %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestMaskKeepsPlaceholdersAcrossCalls(t *testing.T) {
+	m := NewMasker()
+	first := m.Mask("发给 alice@example.com")
+	second := m.Mask("抄送 bob@example.com，再发给 alice@example.com")
+	if first != "发给 [EMAIL_1]" {
+		t.Errorf("第一次替换为 %q", first)
+	}
+	if second != "抄送 [EMAIL_2]，再发给 [EMAIL_1]" {
+		t.Errorf("第二次替换为 %q，同一个值应使用之前的占位符", second)
+	}
+	if n := len(m.Entries()); n != 2 {
+		t.Errorf("记录了 %d 个值，应为2个", n)
+	}
+}
+
+func TestStreamRestorer(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+	}{
+		{"占位符在一个数据块中", []string{"你好 [EMAIL_1]", "，收到"}},
+		{"占位符被拆开", []string{"你好 [EMA", "IL_1]，收到"}},
+		{"占位符逐字到达", strings.Split("你好 [EMAIL_1]，收到", "")},
+		{"不是占位符的方括号", []string{"数组 [1, 2", "] 和 [EMAIL_1]，收到"}},
+	}
+	m := NewMasker()
+	m.Mask("alice@example.com")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := m.NewStreamRestorer()
+			var b strings.Builder
+			for _, c := range tt.chunks {
+				out := r.Write(c)
+				if strings.Contains(out, "[EMA") && !strings.Contains(out, "[EMAIL_1]") {
+					t.Errorf("输出了不完整的占位符: %q", out)
+				}
+				b.WriteString(out)
+			}
+			b.WriteString(r.Flush())
+			want := m.Restore(strings.Join(tt.chunks, ""))
+			if b.String() != want {
+				t.Errorf("恢复结果为 %q，应为 %q", b.String(), want)
+			}
+			if strings.Contains(b.String(), "[EMAIL_1]") {
+				t.Errorf("占位符没有恢复: %q", b.String())
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"", ""},
+		{"abc", "***"},
+		{"abcdef", "******"},
+		{"alice@example.com", "al*************om"},
+		{"13812345678", "13*******78"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.value); got != tt.want {
+			t.Errorf("Redact(%q) = %q，应为 %q", tt.value, got, tt.want)
+		}
+	}
+}
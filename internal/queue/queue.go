@@ -0,0 +1,142 @@
+package queue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// ErrNotFound 队列中没有该请求
+var ErrNotFound = errors.New("队列中没有该请求")
+
+// Item 网络不可用时保存的一次性请求，联网后由 queue flush 发送
+type Item struct {
+	ID              string              `json:"id"`
+	Created         time.Time           `json:"created"`
+	Provider        string              `json:"provider"`
+	Model           string              `json:"model,omitempty"` // 为空时使用提供商配置的模型
+	Messages        []providers.Message `json:"messages"`
+	Temperature     *float64            `json:"temperature,omitempty"` // 为nil时使用提供商配置的默认值
+	Sampling        providers.Sampling  `json:"sampling"`
+	AssistantPrefix string              `json:"assistant_prefix,omitempty"`
+	Attempts        int                 `json:"attempts,omitempty"`   // 发送失败的次数
+	LastError       string              `json:"last_error,omitempty"` // 最近一次发送失败的原因
+}
+
+// Prompt 返回最后一条用户消息，用于列表显示
+func (it *Item) Prompt() string {
+	for i := len(it.Messages) - 1; i >= 0; i-- {
+		if it.Messages[i].Role == "user" {
+			return it.Messages[i].Content
+		}
+	}
+	return ""
+}
+
+// Queue 保存在目录中的请求队列，每个请求一个文件
+type Queue struct {
+	dir string
+}
+
+// Open 打开队列目录
+func Open(dir string) *Queue {
+	return &Queue{dir: dir}
+}
+
+// OpenDefault 打开默认的队列目录 ~/.ai-chat-cli/queue
+func OpenDefault() (*Queue, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return Open(filepath.Join(home, ".ai-chat-cli", "queue")), nil
+}
+
+// Add 将请求加入队列，自动生成ID和创建时间
+func (q *Queue) Add(it *Item) error {
+	if it.Created.IsZero() {
+		it.Created = time.Now()
+	}
+	if it.ID == "" {
+		b := make([]byte, 3)
+		rand.Read(b)
+		it.ID = it.Created.Format("20060102-150405") + "-" + hex.EncodeToString(b)
+	}
+	return q.Save(it)
+}
+
+// Save 保存请求，先写入临时文件再重命名
+func (q *Queue) Save(it *Item) error {
+	if err := os.MkdirAll(q.dir, 0700); err != nil {
+		return fmt.Errorf("创建队列目录失败: %w", err)
+	}
+	data, err := json.MarshalIndent(it, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := q.path(it.ID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("保存队列请求失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("保存队列请求失败: %w", err)
+	}
+	return nil
+}
+
+// List 按加入的先后顺序返回队列中的请求
+func (q *Queue) List() ([]*Item, error) {
+	entries, err := os.ReadDir(q.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取队列失败: %w", err)
+	}
+
+	var items []*Item
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(q.dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("读取队列失败: %w", err)
+		}
+		var it Item
+		if err := json.Unmarshal(data, &it); err != nil {
+			return nil, fmt.Errorf("解析队列请求 %s 失败: %w", e.Name(), err)
+		}
+		items = append(items, &it)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Created.Before(items[j].Created) })
+	return items, nil
+}
+
+// Remove 从队列中删除请求
+func (q *Queue) Remove(id string) error {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return ErrNotFound
+	}
+	err := os.Remove(q.path(id))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// path 请求文件的路径
+func (q *Queue) path(id string) string {
+	return filepath.Join(q.dir, id+".json")
+}
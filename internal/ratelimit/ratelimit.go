@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter 令牌桶限流器
+type Limiter struct {
+	mu       sync.Mutex
+	rate     float64   // 每秒补充的令牌数
+	burst    float64   // 桶容量
+	tokens   float64   // 当前令牌数
+	lastFill time.Time // 上次补充令牌的时间
+}
+
+// NewLimiter 创建令牌桶限流器，rate为每秒允许的请求数，burst为允许的突发请求数
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// PerMinute 创建按每分钟请求数限流的限流器，rpm不大于0时返回nil（不限流）
+func PerMinute(rpm int) *Limiter {
+	if rpm <= 0 {
+		return nil
+	}
+
+	// 允许少量突发，避免刚启动时所有worker排队
+	burst := rpm / 10
+	return NewLimiter(float64(rpm)/60, burst)
+}
+
+// Wait 阻塞直到获得一个令牌或ctx被取消，nil限流器不限流
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Allow 尝试立即获得一个令牌，不等待，nil限流器总是允许
+func (l *Limiter) Allow() bool {
+	if l == nil {
+		return true
+	}
+	return l.reserve() <= 0
+}
+
+// reserve 尝试取出一个令牌，成功时返回0，否则返回需要等待的时间
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.lastFill).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.lastFill = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Limiter{}
+)
+
+// For 获取指定提供商共享的限流器，同一进程内相同名称的调用方共用一个令牌桶
+func For(name string, rpm int) *Limiter {
+	if rpm <= 0 {
+		return nil
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if l, ok := registry[name]; ok {
+		return l
+	}
+	l := PerMinute(rpm)
+	registry[name] = l
+	return l
+}
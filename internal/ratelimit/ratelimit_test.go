@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPerMinute(t *testing.T) {
+	tests := []struct {
+		name      string
+		rpm       int
+		wantNil   bool
+		wantBurst float64
+	}{
+		{"零表示不限流", 0, true, 0},
+		{"负数表示不限流", -5, true, 0},
+		{"突发至少为1", 5, false, 1},
+		{"突发为每分钟的十分之一", 120, false, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := PerMinute(tt.rpm)
+			if (l == nil) != tt.wantNil {
+				t.Fatalf("PerMinute(%d) = %v，期望 nil: %v", tt.rpm, l, tt.wantNil)
+			}
+			if l != nil && l.burst != tt.wantBurst {
+				t.Errorf("PerMinute(%d) 的突发为 %v，期望 %v", tt.rpm, l.burst, tt.wantBurst)
+			}
+		})
+	}
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *Limiter
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Error("nil 限流器应总是允许")
+		}
+		if err := l.Wait(ctx); err != nil {
+			t.Errorf("nil 限流器的 Wait 返回了错误: %v", err)
+		}
+	}
+}
+
+func TestAllowBurst(t *testing.T) {
+	l := NewLimiter(0.001, 3)
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("第 %d 次请求应在突发范围内", i+1)
+		}
+	}
+	if l.Allow() {
+		t.Error("突发用完后应拒绝请求")
+	}
+}
+
+func TestWait(t *testing.T) {
+	l := NewLimiter(1000, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// 令牌每毫秒补充一个，第二次等待很快返回
+	if err := l.Wait(context.Background()); err != nil {
+		t.Errorf("Wait 返回了错误: %v", err)
+	}
+}
+
+func TestWaitCancelled(t *testing.T) {
+	l := NewLimiter(0.001, 1)
+	l.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait 错误 = %v，期望 %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ctx 取消后 Wait 过了 %v 才返回", elapsed)
+	}
+}
+
+func TestFor(t *testing.T) {
+	if l := For("test-unlimited", 0); l != nil {
+		t.Errorf("For(rpm=0) = %v，期望 nil", l)
+	}
+
+	a := For("test-shared", 60)
+	b := For("test-shared", 600)
+	if a == nil || a != b {
+		t.Error("相同名称应共用一个限流器")
+	}
+	if c := For("test-other", 60); c == a {
+		t.Error("不同名称不应共用限流器")
+	}
+}
@@ -0,0 +1,94 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// 模型档位
+const (
+	TierFast  = "fast"  // 简单问题使用的快速模型
+	TierSmart = "smart" // 复杂问题使用的推理能力更强的模型
+)
+
+// 分类的原因
+const (
+	ReasonShort      = "short"      // 简短的一般对话
+	ReasonLong       = "long"       // 输入较长
+	ReasonCode       = "code"       // 包含代码
+	ReasonReasoning  = "reasoning"  // 需要推理、分析或设计
+	ReasonClassifier = "classifier" // 由分类模型判断
+	ReasonOverride   = "override"   // 用户指定
+)
+
+// 启发式规则的阈值
+const (
+	longRunes = 1000
+	longLines = 20
+)
+
+// Decision 路由结果
+type Decision struct {
+	Tier   string
+	Reason string
+}
+
+// codePattern 常见编程语言的语法特征
+var codePattern = regexp.MustCompile("```|(?m)^\\s*(func|def|class|import|package|#include|public|private|SELECT|CREATE)\\b|=>|:=|\\)\\s*\\{|;\\s*$")
+
+// reasoningKeywords 通常需要多步推理的请求
+var reasoningKeywords = []string{
+	"为什么", "证明", "推导", "分析", "比较", "对比", "设计", "架构", "优化", "调试", "排查", "算法", "复杂度", "权衡", "一步一步", "逐步",
+	"why", "prove", "derive", "analyze", "analyse", "compare", "design", "architecture", "optimize", "debug", "algorithm", "complexity", "trade-off", "tradeoff", "step by step",
+}
+
+// Classify 使用启发式规则判断问题的复杂度
+func Classify(prompt string) Decision {
+	if utf8.RuneCountInString(prompt) > longRunes || strings.Count(prompt, "\n") >= longLines {
+		return Decision{Tier: TierSmart, Reason: ReasonLong}
+	}
+	if codePattern.MatchString(prompt) {
+		return Decision{Tier: TierSmart, Reason: ReasonCode}
+	}
+	lower := strings.ToLower(prompt)
+	for _, k := range reasoningKeywords {
+		if strings.Contains(lower, k) {
+			return Decision{Tier: TierSmart, Reason: ReasonReasoning}
+		}
+	}
+	return Decision{Tier: TierFast, Reason: ReasonShort}
+}
+
+// classifierPrompt 分类模型的系统提示词
+const classifierPrompt = `判断用户的请求需要哪种模型回答，只输出一个单词：
+FAST：闲聊、简单事实问答、翻译、改写等简单任务
+SMART：编程、数学、多步推理、分析、设计等复杂任务`
+
+// ClassifyWithModel 请模型判断问题的复杂度，模型的回答无法识别时返回错误
+func ClassifyWithModel(ctx context.Context, provider providers.Provider, model, prompt string) (Decision, error) {
+	resp, err := provider.Chat(ctx, &providers.ChatRequest{
+		Messages: []providers.Message{
+			{Role: "system", Content: classifierPrompt},
+			{Role: "user", Content: prompt},
+		},
+		Model:     model,
+		MaxTokens: 3,
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+
+	answer := strings.ToUpper(resp.Content)
+	switch {
+	case strings.Contains(answer, "SMART"):
+		return Decision{Tier: TierSmart, Reason: ReasonClassifier}, nil
+	case strings.Contains(answer, "FAST"):
+		return Decision{Tier: TierFast, Reason: ReasonClassifier}, nil
+	}
+	return Decision{}, fmt.Errorf("无法识别分类模型的回答: %s", strings.TrimSpace(resp.Content))
+}
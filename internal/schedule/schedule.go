@@ -0,0 +1,105 @@
+package schedule
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotFound 定时任务不存在
+var ErrNotFound = errors.New("定时任务不存在")
+
+// Job 定时任务
+type Job struct {
+	ID       string            `json:"id"`
+	Cron     string            `json:"cron"`               // cron表达式（分 时 日 月 周）
+	Template string            `json:"template,omitempty"` // 提示词模板名称或文件路径
+	Prompt   string            `json:"prompt,omitempty"`   // 不使用模板时的提示词
+	Vars     map[string]string `json:"vars,omitempty"`     // 模板变量
+	Output   string            `json:"output,omitempty"`   // 结果输出目录或文件，为空时打印到标准输出
+	Provider string            `json:"provider,omitempty"` // 指定AI提供商
+	LastRun  time.Time         `json:"last_run,omitempty"`
+	LastErr  string            `json:"last_error,omitempty"`
+}
+
+// Name 获取任务的显示名称，用于生成输出文件名
+func (j *Job) Name() string {
+	if j.Template != "" {
+		base := filepath.Base(j.Template)
+		return base[:len(base)-len(filepath.Ext(base))]
+	}
+	return j.ID
+}
+
+// DefaultPath 获取默认的任务文件路径 ~/.ai-chat-cli/schedules.json
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ai-chat-cli", "schedules.json"), nil
+}
+
+// Load 读取任务文件，文件不存在时返回空列表
+func Load(path string) ([]*Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取定时任务失败: %w", err)
+	}
+
+	var jobs []*Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("解析定时任务文件 %s 失败: %w", path, err)
+	}
+	return jobs, nil
+}
+
+// Save 保存任务文件，先写入临时文件再重命名，避免写入中断导致文件损坏
+func Save(path string, jobs []*Job) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("创建配置目录失败: %w", err)
+	}
+
+	if jobs == nil {
+		jobs = []*Job{}
+	}
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化定时任务失败: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("保存定时任务失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("保存定时任务失败: %w", err)
+	}
+	return nil
+}
+
+// Find 按ID查找任务
+func Find(jobs []*Job, id string) (*Job, error) {
+	for _, j := range jobs {
+		if j.ID == id {
+			return j, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// NewID 生成随机的任务ID
+func NewID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
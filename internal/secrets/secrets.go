@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Finding 文本中一处疑似密钥
+type Finding struct {
+	Kind  string // 密钥类型，如 OpenAI API Key
+	Line  int    // 所在行号，从1开始
+	Match string // 打码后的内容，只保留开头几个字符
+}
+
+// rule 密钥的识别规则
+type rule struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// rules 常见密钥的格式，优先匹配更具体的规则
+var rules = []rule{
+	{"Anthropic API Key", regexp.MustCompile(`sk-ant-[A-Za-z0-9_\-]{20,}`)},
+	{"OpenAI API Key", regexp.MustCompile(`sk-(?:proj-)?[A-Za-z0-9_\-]{20,}`)},
+	{"GitHub Token", regexp.MustCompile(`(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{22,}`)},
+	{"AWS Access Key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"Google API Key", regexp.MustCompile(`AIza[0-9A-Za-z_\-]{35}`)},
+	{"Slack Token", regexp.MustCompile(`xox[abposr]-[A-Za-z0-9\-]{10,}`)},
+	{"Private Key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----`)},
+	{"JWT", regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{10,}\.eyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`)},
+	{"Password", regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|secret|api_key|apikey|token)\s*[:=]\s*["']?[^\s"']{8,}`)},
+}
+
+// Scan 查找文本中的疑似密钥，同一位置只报告第一条匹配的规则
+func Scan(text string) []Finding {
+	var findings []Finding
+	for i, line := range strings.Split(text, "\n") {
+		var covered [][]int
+		for _, r := range rules {
+			for _, loc := range r.re.FindAllStringIndex(line, -1) {
+				if overlaps(covered, loc) {
+					continue
+				}
+				covered = append(covered, loc)
+				findings = append(findings, Finding{Kind: r.kind, Line: i + 1, Match: mask(line[loc[0]:loc[1]])})
+			}
+		}
+	}
+	return findings
+}
+
+// Span 文本中一处疑似密钥的位置
+type Span struct {
+	Kind       string
+	Start, End int // 字节偏移
+}
+
+// Locate 返回文本中疑似密钥的位置，同一位置只保留第一条匹配的规则，结果没有排序
+func Locate(text string) []Span {
+	var spans []Span
+	var covered [][]int
+	for _, r := range rules {
+		for _, loc := range r.re.FindAllStringIndex(text, -1) {
+			if overlaps(covered, loc) {
+				continue
+			}
+			covered = append(covered, loc)
+			spans = append(spans, Span{Kind: r.kind, Start: loc[0], End: loc[1]})
+		}
+	}
+	return spans
+}
+
+// overlaps 判断位置是否与已报告的匹配重叠
+func overlaps(covered [][]int, loc []int) bool {
+	for _, c := range covered {
+		if loc[0] < c[1] && c[0] < loc[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// mask 只保留开头几个字符，避免在提示信息中再次泄露密钥
+func mask(s string) string {
+	runes := []rune(s)
+	if len(runes) <= 8 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:6]) + strings.Repeat("*", 6)
+}
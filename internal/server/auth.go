@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-chat-cli/internal/ratelimit"
+	"ai-chat-cli/pkg/providers"
+)
+
+// ClientKey 网关客户端访问密钥
+type ClientKey struct {
+	Name        string   // 客户端名称，用于日志和会话归属
+	Key         string   // 访问密钥
+	RateLimit   int      // 每分钟最大请求数，0表示不限制
+	Models      []string // 允许的模型，支持通配符，如 "free-oai/*"；不含 "/" 时表示整个提供商
+	DailyTokens int      // 每日token配额，0表示不限制
+	DailyCost   float64  // 每日成本上限（美元），0表示不限制
+}
+
+// client 已认证客户端及其当日用量。设置了用量文件时用量同时保存到文件，服务重启后继续累计
+type client struct {
+	ClientKey
+	limiter *ratelimit.Limiter
+	usage   *usageFile
+
+	mu         sync.Mutex
+	day        string
+	usedTokens int
+	usedCost   float64
+}
+
+// newClient 创建客户端状态，usage不为nil时从用量文件读取当日用量
+func newClient(key ClientKey, usage *usageFile) *client {
+	c := &client{ClientKey: key, limiter: ratelimit.PerMinute(key.RateLimit), usage: usage}
+	c.day = today()
+	if usage != nil {
+		r := usage.get(usageID(key.Key), c.day)
+		c.usedTokens, c.usedCost = r.Tokens, r.Cost
+	}
+	return c
+}
+
+// allowModel 检查客户端是否可以使用指定提供商的模型，model为空表示提供商默认模型
+func (c *client) allowModel(provider, model string) bool {
+	if len(c.Models) == 0 {
+		return true
+	}
+
+	target := provider + "/" + model
+	for _, pattern := range c.Models {
+		if !strings.Contains(pattern, "/") {
+			if pattern == provider {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// quotaExceeded 检查今日的token配额或成本上限是否已用完
+func (c *client) quotaExceeded() error {
+	return c.wouldExceed(0, 0)
+}
+
+// wouldExceed 检查再使用tokens个token、cost美元后是否会超过今日配额。
+// tokens和cost都为0时检查配额是否已经用完
+func (c *client) wouldExceed(tokens int, cost float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rollover()
+
+	strict := tokens == 0 && cost == 0
+	over := func(used, limit float64) bool {
+		if limit <= 0 {
+			return false
+		}
+		if strict {
+			return used >= limit
+		}
+		return used > limit
+	}
+	if over(float64(c.usedTokens+tokens), float64(c.DailyTokens)) {
+		return errQuotaExceeded
+	}
+	if over(c.usedCost+cost, c.DailyCost) {
+		return errCostExceeded
+	}
+	return nil
+}
+
+// addUsage 记录token用量和成本，设置了用量文件时写入文件，并同步其他网关进程记录的用量
+func (c *client) addUsage(tokens int, cost float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rollover()
+
+	if c.usage != nil {
+		r, err := c.usage.add(usageID(c.Key), c.Name, c.day, tokens, cost)
+		if err == nil {
+			c.usedTokens, c.usedCost = r.Tokens, r.Cost
+			return
+		}
+		log.Printf("保存客户端 %s 的用量失败: %v", c.Name, err)
+	}
+	c.usedTokens += tokens
+	c.usedCost += cost
+}
+
+// inheritUsage 配置更新后沿用旧客户端的当日用量，有用量文件时以文件中的用量为准
+func (c *client) inheritUsage(old *client) {
+	if c.usage != nil {
+		return
+	}
+	old.mu.Lock()
+	defer old.mu.Unlock()
+	c.day, c.usedTokens, c.usedCost = old.day, old.usedTokens, old.usedCost
+}
+
+// rollover 跨天时清零用量，调用方需持有锁
+func (c *client) rollover() {
+	if d := today(); c.day != d {
+		c.day = d
+		c.usedTokens, c.usedCost = 0, 0
+	}
+}
+
+// today 用量统计使用的日期，按本地时间
+func today() string {
+	return time.Now().Format("2006-01-02")
+}
+
+type clientContextKey struct{}
+
+// clientFrom 获取请求对应的客户端，未启用认证时返回nil
+func clientFrom(ctx context.Context) *client {
+	c, _ := ctx.Value(clientContextKey{}).(*client)
+	return c
+}
+
+// ClientName 获取请求对应的客户端名称，未启用认证时返回空字符串
+func ClientName(ctx context.Context) string {
+	if c := clientFrom(ctx); c != nil {
+		return c.Name
+	}
+	return ""
+}
+
+// authenticate 验证 /v1/ 接口的访问密钥并检查请求频率，通过时返回带有客户端信息的请求。
+// 未配置访问密钥时不需要认证
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	clients := s.current().clients
+	if len(clients) == 0 || !strings.HasPrefix(r.URL.Path, "/v1/") {
+		return r, true
+	}
+
+	key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	c, ok := clients[key]
+	if !ok || key == "" {
+		writeError(w, http.StatusUnauthorized, "invalid_request_error", "invalid API key")
+		return nil, false
+	}
+	if !c.limiter.Allow() {
+		writeError(w, http.StatusTooManyRequests, "rate_limit_error", "rate limit exceeded")
+		return nil, false
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), clientContextKey{}, c)), true
+}
+
+var (
+	// errQuotaExceeded 今日token配额已用完
+	errQuotaExceeded = errors.New("daily token quota exceeded")
+	// errCostExceeded 今日成本上限已用完
+	errCostExceeded = errors.New("daily cost quota exceeded")
+)
+
+// authorize 检查客户端是否可以使用指定模型以及今日配额是否充足
+func authorize(w http.ResponseWriter, r *http.Request, provider, model string) bool {
+	c := clientFrom(r.Context())
+	if c == nil {
+		return true
+	}
+
+	if !c.allowModel(provider, model) {
+		writeError(w, http.StatusForbidden, "permission_error", "model not allowed for this key")
+		return false
+	}
+	if err := c.quotaExceeded(); err != nil {
+		writeError(w, http.StatusTooManyRequests, "insufficient_quota", err.Error())
+		return false
+	}
+	return true
+}
+
+// recordUsage 记录客户端的token用量和按price计算的成本，响应未返回用量时按内容长度估算
+func recordUsage(r *http.Request, price providers.Price, usage providers.Usage, messages []providers.Message, reply string) {
+	c := clientFrom(r.Context())
+	if c == nil {
+		return
+	}
+
+	if usage.TotalTokens == 0 {
+		usage.CompletionTokens = providers.EstimateTokens(reply)
+		usage.PromptTokens = 0
+		for _, m := range messages {
+			usage.PromptTokens += providers.EstimateTokens(m.Content)
+		}
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	c.addUsage(usage.TotalTokens, price.Cost(usage.PromptTokens, usage.CompletionTokens))
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// newQuotaServer 创建使用模拟提供商和用量文件的网关，模拟提供商每百万token 1美元
+func newQuotaServer(usageFile string, key ClientKey) *Server {
+	mock := providers.NewMockProvider(providers.MockName, providers.MockConfig{Responses: []string{"ok"}})
+	return New(Options{
+		Providers:       map[string]providers.Provider{providers.MockName: mock},
+		DefaultProvider: providers.MockName,
+		Keys:            []ClientKey{key},
+		Price: func(provider, model string) providers.Price {
+			return providers.Price{Input: 1, Output: 1}
+		},
+		UsageFile: usageFile,
+	})
+}
+
+// chat 发送一次对话请求，返回状态码
+func chat(s *Server, key string) int {
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions",
+		strings.NewReader(`{"messages":[{"role":"user","content":"hello"}]}`))
+	req.Header.Set("Authorization", "Bearer "+key)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestUsagePersistsAcrossRestart(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "usage.json")
+	key := ClientKey{Name: "alice", Key: "sk-alice"}
+
+	first := newQuotaServer(file, key)
+	for i := 0; i < 3; i++ {
+		if code := chat(first, key.Key); code != http.StatusOK {
+			t.Fatalf("状态码 %d", code)
+		}
+	}
+	used := first.current().clients[key.Key].usedTokens
+	if used == 0 {
+		t.Fatal("没有记录用量")
+	}
+
+	// 重启后从用量文件恢复，配额按之前的用量计算
+	key.DailyTokens = used
+	second := newQuotaServer(file, key)
+	if got := second.current().clients[key.Key].usedTokens; got != used {
+		t.Fatalf("重启后的用量为 %d，期望 %d", got, used)
+	}
+	if code := chat(second, key.Key); code != http.StatusTooManyRequests {
+		t.Fatalf("配额用完后状态码为 %d，期望 429", code)
+	}
+}
+
+func TestDailyCost(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "usage.json")
+	key := ClientKey{Name: "bob", Key: "sk-bob", DailyCost: 0.000001}
+
+	s := newQuotaServer(file, key)
+	if code := chat(s, key.Key); code != http.StatusOK {
+		t.Fatalf("状态码 %d", code)
+	}
+	c := s.current().clients[key.Key]
+	if c.usedCost <= 0 {
+		t.Fatal("没有记录成本")
+	}
+	if code := chat(s, key.Key); code != http.StatusTooManyRequests {
+		t.Fatalf("成本上限用完后状态码为 %d，期望 429", code)
+	}
+	if err := c.quotaExceeded(); err != errCostExceeded {
+		t.Fatalf("quotaExceeded() = %v，期望 %v", err, errCostExceeded)
+	}
+}
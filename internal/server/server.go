@@ -0,0 +1,498 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+)
+
+// defaultTemperature 提供商没有配置温度且请求未指定时使用的温度
+const defaultTemperature = 0.7
+
+// Server 本地OpenAI兼容网关
+type Server struct {
+	sessions *session.Store
+	locks    sessionLocks
+	usage    *usageFile
+	mux      *http.ServeMux
+
+	mu     sync.RWMutex
+	routes *routes
+}
+
+// routes 提供商和访问密钥，配置更新时整体替换
+type routes struct {
+	providers       map[string]providers.Provider
+	capabilities    map[string]providers.Capabilities
+	temperatures    map[string]float64
+	price           PriceFunc
+	defaultProvider string
+	clients         map[string]*client
+	autoTitle       bool
+	titleModel      string
+}
+
+// PriceFunc 返回提供商的模型每百万token的价格，model为空表示提供商默认模型
+type PriceFunc func(provider, model string) providers.Price
+
+// Options 网关配置
+type Options struct {
+	Providers       map[string]providers.Provider     // 可用的提供商
+	Capabilities    map[string]providers.Capabilities // 各提供商支持的功能，未列出的提供商不做检查
+	Temperatures    map[string]float64                // 各提供商的默认温度，请求未指定温度时使用，未列出的提供商使用0.7
+	DefaultProvider string                            // 请求未指定提供商时使用的提供商
+	Sessions        *session.Store                    // 会话存储，不为nil时提供会话管理接口和网页聊天界面
+	Keys            []ClientKey                       // 客户端访问密钥，为空时不需要认证
+	AutoTitle       bool                              // 会话第一轮对话后自动生成标题
+	TitleModel      string                            // 生成标题使用的模型，为空时使用会话的模型
+	Price           PriceFunc                         // 计算客户端成本使用的价格，为nil时不计成本
+	UsageFile       string                            // 保存客户端每日用量的文件，为空时用量只保存在内存中，服务重启后重新计算
+}
+
+// New 创建网关
+func New(opts Options) *Server {
+	s := &Server{
+		sessions: opts.Sessions,
+		mux:      http.NewServeMux(),
+	}
+	if opts.UsageFile != "" {
+		s.usage = &usageFile{path: opts.UsageFile}
+	}
+	s.Update(opts)
+
+	s.mux.HandleFunc("/v1/chat/completions", s.handleChatCompletions)
+	s.mux.HandleFunc("/v1/models", s.handleModels)
+	if s.sessions != nil {
+		s.registerSessionRoutes()
+		s.registerWebRoutes()
+	}
+	return s
+}
+
+// Update 替换提供商、默认提供商、访问密钥和标题设置，正在处理的请求不受影响。
+// 密钥不变的客户端保留当日用量；Sessions 和 UsageFile 不会被替换
+func (s *Server) Update(opts Options) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rt := &routes{
+		providers:       opts.Providers,
+		capabilities:    opts.Capabilities,
+		temperatures:    opts.Temperatures,
+		price:           opts.Price,
+		defaultProvider: opts.DefaultProvider,
+		clients:         map[string]*client{},
+		autoTitle:       opts.AutoTitle,
+		titleModel:      opts.TitleModel,
+	}
+	for _, key := range opts.Keys {
+		if key.Key == "" {
+			continue
+		}
+		c := newClient(key, s.usage)
+		if s.routes != nil {
+			if old, ok := s.routes.clients[key.Key]; ok {
+				c.inheritUsage(old)
+			}
+		}
+		rt.clients[key.Key] = c
+	}
+	s.routes = rt
+}
+
+// current 获取当前的路由配置
+func (s *Server) current() *routes {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.routes
+}
+
+// ServeHTTP 实现http.Handler，验证访问密钥并记录每个请求的访问日志
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	who := "-"
+	if req, ok := s.authenticate(rec, r); ok {
+		if c := clientFrom(req.Context()); c != nil {
+			who = c.Name
+		}
+		s.mux.ServeHTTP(rec, req)
+	}
+	log.Printf("%s %s %s %d %s", who, r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Millisecond))
+}
+
+// chatCompletionRequest OpenAI对话接口请求
+type chatCompletionRequest struct {
+	Model       string           `json:"model"`
+	Messages    []requestMessage `json:"messages"`
+	MaxTokens   int              `json:"max_tokens"`
+	Temperature *float64         `json:"temperature"`
+	TopP        *float64         `json:"top_p"`
+	Stop        stopSequences    `json:"stop"`
+	Stream      bool             `json:"stream"`
+}
+
+// stopSequences 请求中的停止序列，可以是字符串或字符串数组
+type stopSequences []string
+
+func (s *stopSequences) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		if one != "" {
+			*s = []string{one}
+		}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return errors.New("stop must be a string or an array of strings")
+	}
+	*s = many
+	return nil
+}
+
+// requestMessage 请求中的消息，content可以是字符串或内容片段数组
+type requestMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// text 提取消息的文本内容，内容片段数组中只保留文本片段
+func (m requestMessage) text() string {
+	var s string
+	if err := json.Unmarshal(m.Content, &s); err == nil {
+		return s
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(m.Content, &parts); err != nil {
+		return ""
+	}
+
+	var texts []string
+	for _, p := range parts {
+		if p.Type == "text" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		return
+	}
+
+	var body chatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body: "+err.Error())
+		return
+	}
+	if len(body.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "messages is required")
+		return
+	}
+
+	rt := s.current()
+	name, model, provider, err := rt.route(body.Model)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "invalid_request_error", err.Error())
+		return
+	}
+	if !authorize(w, r, name, model) {
+		return
+	}
+	if body.Stream && !rt.supports(w, name, providers.CapStreaming) {
+		return
+	}
+
+	req := &providers.ChatRequest{
+		Model:     model,
+		MaxTokens: body.MaxTokens,
+		Stream:    body.Stream,
+	}
+	rt.applySampling(req, name, body.Temperature, body.TopP, body.Stop)
+	for _, m := range body.Messages {
+		req.Messages = append(req.Messages, providers.Message{Role: m.Role, Content: m.text()})
+	}
+
+	if body.Stream {
+		price := rt.priceOf(name, model)
+		reply, usage, _ := streamCompletion(w, r.Context(), provider, req, price, nil)
+		recordUsage(r, price, usage.Usage(), req.Messages, reply)
+		return
+	}
+
+	resp, err := provider.Chat(r.Context(), req)
+	if err != nil {
+		writeProviderError(w, err)
+		return
+	}
+	recordUsage(r, rt.priceOf(name, model), resp.Usage, req.Messages, resp.Content)
+
+	respModel := resp.Model
+	if respModel == "" {
+		respModel = model
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      newCompletionID(),
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   respModel,
+		"choices": []map[string]interface{}{{
+			"index":         0,
+			"message":       map[string]string{"role": "assistant", "content": resp.Content},
+			"finish_reason": finishReason(resp.FinishReason),
+		}},
+		"usage": map[string]int{
+			"prompt_tokens":     resp.Usage.PromptTokens,
+			"completion_tokens": resp.Usage.CompletionTokens,
+			"total_tokens":      resp.Usage.TotalTokens,
+		},
+	})
+}
+
+// streamCompletion 以OpenAI的SSE格式转发流式响应，返回完整的回复内容和用量。
+// 流式响应开始前失败时输出错误响应；回复不完整时返回错误。timer 不为nil时记录首个token的时间。
+// 用量边接收边统计并按 price 计算成本，客户端的今日配额在回复途中用完时停止转发
+func streamCompletion(w http.ResponseWriter, ctx context.Context, provider providers.Provider, req *providers.ChatRequest, price providers.Price, timer *providers.Timer) (string, *providers.StreamUsage, error) {
+	usage := providers.NewStreamUsage(req)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chunks, err := provider.ChatStream(ctx, req)
+	if err != nil {
+		writeProviderError(w, err)
+		return "", usage, err
+	}
+	c := clientFrom(ctx)
+
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	id := newCompletionID()
+	created := time.Now().Unix()
+	send := func(delta map[string]string, finish interface{}) {
+		data, _ := json.Marshal(map[string]interface{}{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": created,
+			"model":   req.Model,
+			"choices": []map[string]interface{}{{"index": 0, "delta": delta, "finish_reason": finish}},
+		})
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	var content strings.Builder
+	streamErr := errors.New("stream interrupted")
+	send(map[string]string{"role": "assistant"}, nil)
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			streamErr = chunk.Error
+			data, _ := json.Marshal(map[string]interface{}{"error": map[string]string{"message": chunk.Error.Error()}})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			break
+		}
+		usage.Add(chunk)
+		if err := quotaExceededBy(c, price, usage.Usage()); err != nil {
+			streamErr = err
+			data, _ := json.Marshal(map[string]interface{}{"error": map[string]string{
+				"message": err.Error(), "type": "insufficient_quota"}})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			// 取消上游请求并读完剩余的数据块，提供商的goroutine才能退出
+			cancel()
+			for range chunks {
+			}
+			break
+		}
+		if chunk.Content != "" {
+			timer.FirstToken()
+			content.WriteString(chunk.Content)
+			send(map[string]string{"content": chunk.Content}, nil)
+		}
+		if chunk.Done {
+			streamErr = nil
+			send(map[string]string{}, finishReason(chunk.FinishReason))
+			break
+		}
+	}
+
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	if flusher != nil {
+		flusher.Flush()
+	}
+	return content.String(), usage, streamErr
+}
+
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	ps := s.current().providers
+	names := make([]string, 0, len(ps))
+	for name := range ps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// 每个提供商以 "<提供商>/<模型>" 的形式列出可用模型，获取失败时只列出提供商名称。
+	// 启用认证时只列出当前密钥允许使用的模型
+	c := clientFrom(r.Context())
+	data := []map[string]interface{}{}
+	for _, name := range names {
+		models, err := ps[name].GetModels(r.Context())
+		if err != nil {
+			if c == nil || c.allowModel(name, "") {
+				data = append(data, map[string]interface{}{"id": name, "object": "model", "owned_by": name})
+			}
+			continue
+		}
+		for _, m := range models {
+			if c == nil || c.allowModel(name, m) {
+				data = append(data, map[string]interface{}{"id": name + "/" + m, "object": "model", "owned_by": name})
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"object": "list", "data": data})
+}
+
+// supports 检查提供商是否支持功能，不支持时返回400错误，避免发送注定失败的请求
+func (rt *routes) supports(w http.ResponseWriter, name string, capability providers.Capability) bool {
+	caps, ok := rt.capabilities[name]
+	if !ok || caps.Has(capability) {
+		return true
+	}
+	writeError(w, http.StatusBadRequest, "invalid_request_error",
+		fmt.Sprintf("provider %s does not support %s", name, capability))
+	return false
+}
+
+// applySampling 设置请求的温度：客户端指定时使用指定值，否则使用提供商配置的默认温度。
+// 客户端指定了 top_p 和 stop 时一并转发，未指定时由提供商使用配置的默认值
+func (rt *routes) applySampling(req *providers.ChatRequest, name string, temperature, topP *float64, stop []string) {
+	req.Temperature = defaultTemperature
+	if t, ok := rt.temperatures[name]; ok {
+		req.Temperature = t
+	}
+	if temperature != nil {
+		req.Temperature = *temperature
+	}
+	req.Sampling.TopP = topP
+	req.Sampling.Stop = stop
+}
+
+// route 根据请求中的模型名称选择提供商。
+// 模型名为 "<提供商>/<模型>" 时使用对应提供商，为提供商名称时使用其默认模型，否则使用默认提供商。
+// 返回提供商名称、实际使用的模型名（为空表示提供商默认模型）和提供商。
+func (rt *routes) route(model string) (string, string, providers.Provider, error) {
+	if p, ok := rt.providers[model]; ok {
+		return model, "", p, nil
+	}
+	if name, rest, found := strings.Cut(model, "/"); found {
+		if p, ok := rt.providers[name]; ok {
+			return name, rest, p, nil
+		}
+	}
+
+	p, ok := rt.providers[rt.defaultProvider]
+	if !ok {
+		return "", "", nil, fmt.Errorf("no provider available for model %q", model)
+	}
+	return rt.defaultProvider, model, p, nil
+}
+
+// priceOf 计算客户端成本使用的价格，没有设置价格时不计成本
+func (rt *routes) priceOf(name, model string) providers.Price {
+	if rt.price == nil {
+		return providers.Price{}
+	}
+	return rt.price(name, model)
+}
+
+// quotaExceededBy 检查客户端再使用 usage 后是否超过今日配额，未启用认证时不检查
+func quotaExceededBy(c *client, price providers.Price, usage providers.Usage) error {
+	if c == nil {
+		return nil
+	}
+	return c.wouldExceed(usage.TotalTokens, price.Cost(usage.PromptTokens, usage.CompletionTokens))
+}
+
+// statusRecorder 记录响应状态码，同时保留流式输出所需的Flush能力
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writeJSON 输出JSON响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 输出OpenAI格式的错误响应
+func writeError(w http.ResponseWriter, status int, errType, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]string{"type": errType, "message": message},
+	})
+}
+
+// writeProviderError 将提供商错误转换为HTTP错误响应，上游的HTTP状态码会被保留，未通过输入审核或过滤规则时返回400
+func writeProviderError(w http.ResponseWriter, err error) {
+	status, errType := http.StatusBadGateway, "upstream_error"
+	var perr *providers.ProviderError
+	if errors.As(err, &perr) {
+		switch {
+		case perr.Code == "content_flagged", perr.Code == "filter_blocked":
+			status, errType = http.StatusBadRequest, "content_policy_violation"
+		case strings.HasPrefix(perr.Code, "http_"):
+			if code, convErr := strconv.Atoi(strings.TrimPrefix(perr.Code, "http_")); convErr == nil {
+				status = code
+			}
+		}
+	}
+	writeError(w, status, errType, err.Error())
+}
+
+// finishReason 返回结束原因，空值按正常结束处理
+func finishReason(reason string) string {
+	if reason == "" {
+		return "stop"
+	}
+	return reason
+}
+
+// newCompletionID 生成响应ID
+func newCompletionID() string {
+	return fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano())
+}
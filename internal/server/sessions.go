@@ -0,0 +1,334 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+)
+
+// sessionSummary 会话列表中的会话摘要
+type sessionSummary struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	Provider     string    `json:"provider,omitempty"`
+	Model        string    `json:"model,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	MessageCount int       `json:"message_count"`
+}
+
+// createSessionRequest 创建会话请求
+type createSessionRequest struct {
+	Title string `json:"title"`
+	Model string `json:"model"`
+}
+
+// appendMessageRequest 追加消息请求
+type appendMessageRequest struct {
+	Content     string        `json:"content"`
+	Model       string        `json:"model"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature *float64      `json:"temperature"`
+	TopP        *float64      `json:"top_p"`
+	Stop        stopSequences `json:"stop"`
+	Stream      bool          `json:"stream"`
+}
+
+// sessionLocks 按会话ID加锁，保证同一会话的读取、追加和保存串行进行，
+// 避免并发请求基于同一份旧会话各自保存而丢失消息
+type sessionLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sessionLock
+}
+
+// sessionLock 单个会话的锁，refs为持有或等待该锁的请求数，归零时从表中删除
+type sessionLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock 锁定会话，返回解锁函数
+func (l *sessionLocks) lock(id string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*sessionLock{}
+	}
+	sl, ok := l.locks[id]
+	if !ok {
+		sl = &sessionLock{}
+		l.locks[id] = sl
+	}
+	sl.refs++
+	l.mu.Unlock()
+
+	sl.Lock()
+	return func() {
+		sl.Unlock()
+		l.mu.Lock()
+		sl.refs--
+		if sl.refs == 0 {
+			delete(l.locks, id)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// registerSessionRoutes 注册会话管理接口
+func (s *Server) registerSessionRoutes() {
+	s.mux.HandleFunc("GET /v1/sessions", s.handleListSessions)
+	s.mux.HandleFunc("POST /v1/sessions", s.handleCreateSession)
+	s.mux.HandleFunc("GET /v1/sessions/{id}", s.handleGetSession)
+	s.mux.HandleFunc("DELETE /v1/sessions/{id}", s.handleDeleteSession)
+	s.mux.HandleFunc("POST /v1/sessions/{id}/messages", s.handleAppendMessage)
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.sessions.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	data := make([]sessionSummary, 0, len(sessions))
+	for _, sess := range sessions {
+		if !canAccess(r, sess) {
+			continue
+		}
+		data = append(data, sessionSummary{
+			ID:           sess.ID,
+			Title:        sess.Title,
+			Provider:     sess.Provider,
+			Model:        sess.Model,
+			Tags:         sess.Tags,
+			CreatedAt:    sess.CreatedAt,
+			UpdatedAt:    sess.UpdatedAt,
+			MessageCount: len(sess.Messages),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"object": "list", "data": data})
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var body createSessionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body: "+err.Error())
+			return
+		}
+	}
+
+	name, model, _, err := s.current().route(body.Model)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "invalid_request_error", err.Error())
+		return
+	}
+
+	if !authorize(w, r, name, model) {
+		return
+	}
+
+	sess := s.sessions.New(name, model)
+	sess.Title = body.Title
+	if c := clientFrom(r.Context()); c != nil {
+		sess.Client = c.Name
+	}
+	if err := s.sessions.Save(sess); err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, sess)
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	sess, ok := s.getSession(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, sess)
+}
+
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	defer s.locks.lock(r.PathValue("id"))()
+
+	sess, ok := s.getSession(w, r)
+	if !ok {
+		return
+	}
+	if err := s.sessions.Delete(sess.ID); err != nil {
+		writeSessionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAppendMessage 向会话追加用户消息并获取回复，成功后用户消息和回复一起保存到会话。
+// 从读取会话到保存期间持有会话锁，同一会话的并发请求依次处理
+func (s *Server) handleAppendMessage(w http.ResponseWriter, r *http.Request) {
+	defer s.locks.lock(r.PathValue("id"))()
+
+	sess, ok := s.getSession(w, r)
+	if !ok {
+		return
+	}
+
+	var body appendMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body: "+err.Error())
+		return
+	}
+	if body.Content == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "content is required")
+		return
+	}
+
+	rt := s.current()
+	name, model, provider, err := rt.sessionRoute(sess, body.Model)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "invalid_request_error", err.Error())
+		return
+	}
+	if !authorize(w, r, name, model) {
+		return
+	}
+	if body.Stream && !rt.supports(w, name, providers.CapStreaming) {
+		return
+	}
+
+	sess.Append("user", body.Content)
+	req := &providers.ChatRequest{
+		Messages:  sess.ChatMessages(),
+		Model:     model,
+		MaxTokens: body.MaxTokens,
+		Stream:    body.Stream,
+	}
+	rt.applySampling(req, name, body.Temperature, body.TopP, body.Stop)
+
+	price := rt.priceOf(name, model)
+	var reply string
+	var usage providers.Usage
+	var stats *providers.Stats
+	timer := providers.StartTimer()
+	var estimated bool
+	if body.Stream {
+		var streamUsage *providers.StreamUsage
+		reply, streamUsage, err = streamCompletion(w, r.Context(), provider, req, price, timer)
+		usage, estimated = streamUsage.Usage(), streamUsage.Estimated()
+		recordUsage(r, price, usage, req.Messages, reply)
+		if err != nil {
+			return
+		}
+		stats = timer.Stop(usage.CompletionTokens)
+	} else {
+		resp, err := provider.Chat(r.Context(), req)
+		if err != nil {
+			writeProviderError(w, err)
+			return
+		}
+		reply, usage = resp.Content, resp.Usage
+		stats = timer.Stop(usage.CompletionTokens)
+		recordUsage(r, price, usage, req.Messages, reply)
+	}
+
+	// 估算的用量不保存到会话
+	if estimated {
+		sess.AppendReply(reply, nil, stats)
+	} else {
+		sess.AppendReply(reply, &usage, stats)
+	}
+	sess.Provider, sess.Model = name, model
+	saveErr := s.sessions.Save(sess)
+	if saveErr == nil && rt.autoTitle && sess.NeedsTitle() {
+		titleModel := rt.titleModel
+		if titleModel == "" {
+			titleModel = model
+		}
+		go s.generateTitle(provider, titleModel, sess)
+	}
+
+	if body.Stream {
+		// 流式响应已经发送，保存失败只能记录日志
+		if saveErr != nil {
+			log.Printf("保存会话 %s 失败: %v", sess.ID, saveErr)
+		}
+		return
+	}
+	if saveErr != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", saveErr.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"session_id": sess.ID,
+		"message":    sess.Messages[len(sess.Messages)-1],
+		"usage":      usage,
+		"stats":      stats,
+	})
+}
+
+// generateTitle 在后台生成会话标题，期间会话标题被修改时不覆盖
+func (s *Server) generateTitle(provider providers.Provider, model string, sess *session.Session) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	title, err := session.GenerateTitle(ctx, provider, model, sess)
+	if err != nil {
+		log.Printf("生成会话 %s 的标题失败: %v", sess.ID, err)
+		return
+	}
+
+	defer s.locks.lock(sess.ID)()
+	latest, err := s.sessions.Get(sess.ID)
+	if err != nil || latest.Title != sess.Title {
+		return
+	}
+	latest.Title = title
+	if err := s.sessions.Save(latest); err != nil {
+		log.Printf("保存会话 %s 的标题失败: %v", sess.ID, err)
+	}
+}
+
+// sessionRoute 选择会话使用的提供商：请求指定模型时按模型路由，否则沿用会话上次使用的提供商和模型
+func (rt *routes) sessionRoute(sess *session.Session, model string) (string, string, providers.Provider, error) {
+	if model == "" {
+		if p, ok := rt.providers[sess.Provider]; ok {
+			return sess.Provider, sess.Model, p, nil
+		}
+		model = sess.Model
+	}
+	return rt.route(model)
+}
+
+// getSession 读取请求路径中的会话，会话不存在或不属于当前客户端时输出错误响应
+func (s *Server) getSession(w http.ResponseWriter, r *http.Request) (*session.Session, bool) {
+	sess, err := s.sessions.Get(r.PathValue("id"))
+	if err == nil && !canAccess(r, sess) {
+		err = session.ErrNotFound
+	}
+	if err != nil {
+		writeSessionError(w, err)
+		return nil, false
+	}
+	return sess, true
+}
+
+// canAccess 检查当前客户端能否访问会话：启用认证时客户端只能访问自己创建的会话
+func canAccess(r *http.Request, sess *session.Session) bool {
+	c := clientFrom(r.Context())
+	return c == nil || sess.Client == c.Name
+}
+
+// writeSessionError 输出会话存储错误
+func writeSessionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, session.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "invalid_request_error", "session not found")
+		return
+	}
+	writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+)
+
+func TestAppendMessageConcurrent(t *testing.T) {
+	store := session.NewStore(t.TempDir())
+	mock := providers.NewMockProvider(providers.MockName, providers.MockConfig{Latency: 5 * time.Millisecond})
+	s := New(Options{
+		Providers:       map[string]providers.Provider{providers.MockName: mock},
+		DefaultProvider: providers.MockName,
+		Sessions:        store,
+	})
+
+	sess := store.New(providers.MockName, "mock-model")
+	if err := store.Save(sess); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+sess.ID+"/messages", strings.NewReader(`{"content":"hi"}`))
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("状态码 %d: %s", rec.Code, rec.Body.String())
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, err := store.Get(sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Messages) != 2*n {
+		t.Fatalf("会话中有 %d 条消息，期望 %d 条", len(got.Messages), 2*n)
+	}
+	if len(s.locks.locks) != 0 {
+		t.Fatalf("请求结束后仍有 %d 个会话锁", len(s.locks.locks))
+	}
+}
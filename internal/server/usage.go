@@ -0,0 +1,90 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ai-chat-cli/internal/filelock"
+)
+
+// usageFile 保存客户端每日用量的JSON文件，服务重启后继续累计。
+// 多个网关进程可能共用同一个文件，每次写入都加锁并重新读取
+type usageFile struct {
+	path string
+}
+
+// usageRecord 一个客户端当日的用量
+type usageRecord struct {
+	Name   string  `json:"name"`
+	Day    string  `json:"day"`
+	Tokens int     `json:"tokens"`
+	Cost   float64 `json:"cost"`
+}
+
+// usageID 用量文件中客户端的标识，使用访问密钥的哈希，文件中不保存密钥本身
+func usageID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// load 读取所有客户端的用量，文件不存在时为空
+func (f *usageFile) load() (map[string]usageRecord, error) {
+	records := map[string]usageRecord{}
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取用量文件失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("解析用量文件 %s 失败: %w", f.path, err)
+	}
+	return records, nil
+}
+
+// get 读取客户端在指定日期的用量，没有记录或读取失败时为0
+func (f *usageFile) get(id, day string) usageRecord {
+	records, err := f.load()
+	if err != nil || records[id].Day != day {
+		return usageRecord{Day: day}
+	}
+	return records[id]
+}
+
+// add 累加客户端在指定日期的用量并写回文件，返回累加后的用量。
+// 记录的日期不是当天时先清零，其他日期的记录一并删除
+func (f *usageFile) add(id, name, day string, tokens int, cost float64) (usageRecord, error) {
+	lock, err := filelock.Acquire(f.path)
+	if err != nil {
+		return usageRecord{}, err
+	}
+	defer lock.Release()
+
+	records, err := f.load()
+	if err != nil {
+		return usageRecord{}, err
+	}
+	for k, r := range records {
+		if r.Day != day {
+			delete(records, k)
+		}
+	}
+	r := records[id]
+	r.Name, r.Day = name, day
+	r.Tokens += tokens
+	r.Cost += cost
+	records[id] = r
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return usageRecord{}, err
+	}
+	if err := filelock.WriteFile(f.path, data, 0600); err != nil {
+		return usageRecord{}, fmt.Errorf("保存用量文件失败: %w", err)
+	}
+	return r, nil
+}
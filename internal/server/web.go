@@ -0,0 +1,22 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webAssets 内置网页聊天界面的静态文件
+//
+//go:embed web
+var webAssets embed.FS
+
+// registerWebRoutes 注册网页聊天界面，首页为 /，静态资源位于 /static/
+func (s *Server) registerWebRoutes() {
+	static, _ := fs.Sub(webAssets, "web")
+
+	s.mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, static, "index.html")
+	})
+	s.mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+}
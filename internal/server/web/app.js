@@ -0,0 +1,202 @@
+(function () {
+  "use strict";
+
+  const $ = (id) => document.getElementById(id);
+  const state = { sessionId: null, busy: false };
+
+  // 转义HTML，并将 ``` 代码块渲染为 <pre>
+  function render(text) {
+    const escaped = text
+      .replace(/&/g, "&amp;")
+      .replace(/</g, "&lt;")
+      .replace(/>/g, "&gt;");
+    return escaped.replace(/```[\w+-]*\n?([\s\S]*?)(```|$)/g, "<pre><code>$1</code></pre>");
+  }
+
+  function addMessage(role, content) {
+    const el = document.createElement("div");
+    el.className = "message " + role;
+    const label = { user: "你", assistant: "AI", error: "错误" }[role] || role;
+    el.innerHTML = '<div class="role"></div><div class="body"></div>';
+    el.querySelector(".role").textContent = label;
+    el.querySelector(".body").innerHTML = render(content);
+    $("messages").appendChild(el);
+    $("messages").scrollTop = $("messages").scrollHeight;
+    return el.querySelector(".body");
+  }
+
+  // 网关启用访问密钥时，首次请求返回401后提示输入密钥并保存在浏览器中
+  async function api(method, path, body) {
+    const headers = { "Content-Type": "application/json" };
+    const key = localStorage.getItem("apiKey");
+    if (key) headers["Authorization"] = "Bearer " + key;
+
+    const resp = await fetch(path, {
+      method: method,
+      headers: headers,
+      body: body ? JSON.stringify(body) : undefined,
+    });
+    if (resp.status === 401) {
+      const input = prompt("请输入访问密钥");
+      if (input) {
+        localStorage.setItem("apiKey", input.trim());
+        return api(method, path, body);
+      }
+    }
+    if (!resp.ok) {
+      let message = resp.status + " " + resp.statusText;
+      try { message = (await resp.json()).error.message; } catch (e) { /* 非JSON错误 */ }
+      throw new Error(message);
+    }
+    return resp.status === 204 ? null : resp;
+  }
+
+  async function loadModels() {
+    const select = $("model");
+    select.innerHTML = '<option value="">默认模型</option>';
+    try {
+      const data = await (await api("GET", "/v1/models")).json();
+      for (const m of data.data || []) {
+        const opt = document.createElement("option");
+        opt.value = opt.textContent = m.id;
+        select.appendChild(opt);
+      }
+    } catch (e) {
+      console.warn("获取模型列表失败", e);
+    }
+  }
+
+  async function loadSessions() {
+    const list = $("sessions");
+    try {
+      const data = await (await api("GET", "/v1/sessions")).json();
+      list.innerHTML = "";
+      for (const s of data.data || []) {
+        const li = document.createElement("li");
+        li.textContent = s.title || "新对话";
+        li.title = s.updated_at;
+        li.className = s.id === state.sessionId ? "active" : "";
+        li.onclick = () => openSession(s.id);
+        list.appendChild(li);
+      }
+    } catch (e) {
+      console.warn("获取会话列表失败", e);
+    }
+  }
+
+  async function openSession(id) {
+    if (state.busy) return;
+    try {
+      const s = await (await api("GET", "/v1/sessions/" + encodeURIComponent(id))).json();
+      state.sessionId = s.id;
+      $("title").textContent = s.title || "新对话";
+      $("messages").innerHTML = "";
+      for (const m of s.messages) addMessage(m.role, m.content);
+      selectModel(s.provider, s.model);
+      loadSessions();
+    } catch (e) {
+      addMessage("error", e.message);
+    }
+  }
+
+  function selectModel(provider, model) {
+    const select = $("model");
+    const value = model ? provider + "/" + model : provider;
+    select.value = Array.from(select.options).some((o) => o.value === value) ? value : "";
+  }
+
+  function newSession() {
+    if (state.busy) return;
+    state.sessionId = null;
+    $("title").textContent = "新对话";
+    $("messages").innerHTML = "";
+    loadSessions();
+    $("input").focus();
+  }
+
+  // 读取OpenAI格式的SSE流，逐段追加到消息中
+  async function readStream(resp, body) {
+    const reader = resp.body.getReader();
+    const decoder = new TextDecoder();
+    let buffer = "";
+    let text = "";
+
+    for (;;) {
+      const { value, done } = await reader.read();
+      if (done) break;
+      buffer += decoder.decode(value, { stream: true });
+
+      let idx;
+      while ((idx = buffer.indexOf("\n\n")) >= 0) {
+        const event = buffer.slice(0, idx);
+        buffer = buffer.slice(idx + 2);
+        for (const line of event.split("\n")) {
+          if (!line.startsWith("data:")) continue;
+          const data = line.slice(5).trim();
+          if (data === "[DONE]") return;
+          const chunk = JSON.parse(data);
+          if (chunk.error) throw new Error(chunk.error.message);
+          const delta = chunk.choices && chunk.choices[0].delta.content;
+          if (delta) {
+            text += delta;
+            body.innerHTML = render(text);
+            $("messages").scrollTop = $("messages").scrollHeight;
+          }
+        }
+      }
+    }
+  }
+
+  async function send(content) {
+    state.busy = true;
+    $("send").disabled = true;
+    addMessage("user", content);
+    const body = addMessage("assistant", "…");
+
+    try {
+      const model = $("model").value;
+      if (!state.sessionId) {
+        const s = await (await api("POST", "/v1/sessions", { model: model })).json();
+        state.sessionId = s.id;
+      }
+      const resp = await api("POST", "/v1/sessions/" + encodeURIComponent(state.sessionId) + "/messages", {
+        content: content,
+        model: model,
+        stream: true,
+      });
+      body.textContent = "";
+      await readStream(resp, body);
+    } catch (e) {
+      body.parentElement.className = "message error";
+      body.textContent = e.message;
+    } finally {
+      state.busy = false;
+      $("send").disabled = false;
+      if (state.sessionId) {
+        const title = $("title");
+        if (title.textContent === "新对话") title.textContent = content.slice(0, 40);
+      }
+      loadSessions();
+    }
+  }
+
+  $("composer").addEventListener("submit", (e) => {
+    e.preventDefault();
+    const content = $("input").value.trim();
+    if (!content || state.busy) return;
+    $("input").value = "";
+    send(content);
+  });
+
+  $("input").addEventListener("keydown", (e) => {
+    if (e.key === "Enter" && !e.shiftKey && !e.isComposing) {
+      e.preventDefault();
+      $("composer").requestSubmit();
+    }
+  });
+
+  $("new-session").onclick = newSession;
+  $("toggle-sidebar").onclick = () => $("sidebar").classList.toggle("hidden");
+
+  loadModels().then(loadSessions);
+})();
@@ -0,0 +1,152 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 支持的分享目标
+const (
+	TargetGist  = "gist"  // GitHub Gist，需要 token
+	TargetZeroX = "0x0"   // https://0x0.st
+	TargetPaste = "paste" // 自定义的粘贴服务，以POST请求发送原文，响应内容为链接
+)
+
+// Options 分享设置
+type Options struct {
+	Target   string // 分享目标
+	Token    string // GitHub token，用于 gist
+	Public   bool   // gist 是否公开，默认为私密
+	PasteURL string // 自定义粘贴服务的地址
+	Filename string // 上传的文件名
+}
+
+// client 上传使用的HTTP客户端
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Upload 将内容上传到指定的分享目标，返回访问链接
+func Upload(ctx context.Context, content string, opts Options) (string, error) {
+	switch opts.Target {
+	case TargetGist:
+		return uploadGist(ctx, content, opts)
+	case TargetZeroX:
+		return uploadZeroX(ctx, content, opts)
+	case TargetPaste:
+		return uploadPaste(ctx, content, opts)
+	default:
+		return "", fmt.Errorf("不支持的分享目标: %s（可选 gist、0x0、paste）", opts.Target)
+	}
+}
+
+// uploadGist 创建 GitHub Gist
+func uploadGist(ctx context.Context, content string, opts Options) (string, error) {
+	if opts.Token == "" {
+		return "", fmt.Errorf("分享到 gist 需要 GitHub token，请设置 share.gist_token 或 GITHUB_TOKEN 环境变量")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"description": "ai-chat-cli 对话",
+		"public":      opts.Public,
+		"files": map[string]interface{}{
+			opts.Filename: map[string]string{"content": content},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/gists", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+opts.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	data, err := send(req)
+	if err != nil {
+		return "", err
+	}
+	var gist struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(data, &gist); err != nil || gist.HTMLURL == "" {
+		return "", fmt.Errorf("解析 gist 响应失败: %s", strings.TrimSpace(string(data)))
+	}
+	return gist.HTMLURL, nil
+}
+
+// uploadZeroX 上传到 0x0.st
+func uploadZeroX(ctx context.Context, content string, opts Options) (string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", opts.Filename)
+	if err != nil {
+		return "", err
+	}
+	io.WriteString(part, content)
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://0x0.st", &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("User-Agent", "ai-chat-cli")
+
+	data, err := send(req)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// uploadPaste 上传到自定义的粘贴服务
+func uploadPaste(ctx context.Context, content string, opts Options) (string, error) {
+	if opts.PasteURL == "" {
+		return "", fmt.Errorf("分享到 paste 需要设置 share.paste_url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.PasteURL, strings.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/markdown; charset=utf-8")
+
+	data, err := send(req)
+	if err != nil {
+		return "", err
+	}
+	url := strings.TrimSpace(string(data))
+	if url == "" {
+		return "", fmt.Errorf("粘贴服务没有返回链接")
+	}
+	return url, nil
+}
+
+// send 发送请求并读取响应内容，状态码不是2xx时返回错误
+func send(req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("上传失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("上传失败: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
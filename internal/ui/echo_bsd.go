@@ -0,0 +1,11 @@
+//go:build darwin || freebsd || openbsd || netbsd || dragonfly
+
+package ui
+
+import "golang.org/x/sys/unix"
+
+// 读取和设置 termios 的 ioctl 请求
+const (
+	ioctlGetTermios = unix.TIOCGETA
+	ioctlSetTermios = unix.TIOCSETA
+)
@@ -0,0 +1,9 @@
+package ui
+
+import "golang.org/x/sys/unix"
+
+// 读取和设置 termios 的 ioctl 请求
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
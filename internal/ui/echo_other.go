@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly || windows)
+
+package ui
+
+import (
+	"errors"
+	"os"
+)
+
+// setEcho 其他系统无法关闭终端回显
+func setEcho(f *os.File, on bool) error {
+	return errors.New("不支持关闭终端回显")
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package ui
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// setEcho 通过控制台输入模式的 ENABLE_ECHO_INPUT 打开或关闭回显
+func setEcho(f *os.File, on bool) error {
+	h := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(h, &mode); err != nil {
+		return err
+	}
+	if on {
+		mode |= windows.ENABLE_ECHO_INPUT
+	} else {
+		mode &^= windows.ENABLE_ECHO_INPUT
+	}
+	return windows.SetConsoleMode(h, mode)
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// 无法获取终端大小时使用的默认值
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// defaultPager 没有设置 PAGER 环境变量时使用的分页程序，-R 保留颜色
+const defaultPager = "less -R"
+
+// TerminalSize 获取标准输出终端的列数和行数，无法获取时使用 COLUMNS、LINES 环境变量，默认为 80x24
+func TerminalSize() (int, int) {
+	if w, h, ok := terminalSize(os.Stdout); ok {
+		return w, h
+	}
+	return envSize("COLUMNS", defaultWidth), envSize("LINES", defaultHeight)
+}
+
+// envSize 从环境变量读取终端大小
+func envSize(name string, def int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n > 0 {
+		return n
+	}
+	return def
+}
+
+// Page 输出文本，终端中超过一屏时交给 $PAGER（默认 less -R）分页显示，分页程序无法启动时直接输出
+func Page(text string) {
+	text = strings.TrimRight(text, "\n")
+	_, height := TerminalSize()
+	if !stdoutTerminal || strings.Count(text, "\n")+1 < height {
+		fmt.Fprintln(Stdout, text)
+		return
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = defaultPager
+	}
+	args := strings.Fields(pager)
+	if len(args) == 0 {
+		fmt.Fprintln(Stdout, text)
+		return
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text + "\n")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintln(Stdout, text)
+		return
+	}
+	cmd.Wait()
+}
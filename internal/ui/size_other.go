@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly || windows)
+
+package ui
+
+import "os"
+
+// terminalSize 其他系统无法获取终端大小
+func terminalSize(f *os.File) (int, int, bool) {
+	return 0, 0, false
+}
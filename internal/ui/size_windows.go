@@ -0,0 +1,20 @@
+//go:build windows
+
+package ui
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// terminalSize 使用控制台缓冲区信息获取窗口的列数和行数
+func terminalSize(f *os.File) (int, int, bool) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(f.Fd()), &info); err != nil {
+		return 0, 0, false
+	}
+	w := int(info.Window.Right-info.Window.Left) + 1
+	h := int(info.Window.Bottom-info.Window.Top) + 1
+	return w, h, w > 0
+}
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/logrusorgru/aurora"
+)
+
+// 主题中可以设置符号和颜色的界面元素
+const (
+	ElemUser    = "user"    // 交互模式的输入提示符和历史中的用户消息
+	ElemAI      = "ai"      // AI回复的前缀
+	ElemStats   = "stats"   // 回复后的用量统计
+	ElemError   = "error"   // 错误信息
+	ElemWarn    = "warn"    // 警告信息
+	ElemHint    = "hint"    // 操作提示
+	ElemSuccess = "success" // 操作成功信息
+)
+
+// elements 所有界面元素
+var elements = []string{ElemUser, ElemAI, ElemStats, ElemError, ElemWarn, ElemHint, ElemSuccess}
+
+// Theme 界面元素使用的符号和颜色
+type Theme struct {
+	Symbols map[string]string // 元素前的符号，包含后面的空格
+	Colors  map[string]string // 元素的颜色，为空表示不着色
+	Emoji   bool              // 为false时去掉状态信息开头的表情符号
+}
+
+// Themes 内置主题
+var Themes = map[string]Theme{
+	// default 表情符号，不着色
+	"default": {
+		Symbols: map[string]string{
+			ElemUser: "👤 ", ElemAI: "🤖 ", ElemStats: "📊 ",
+			ElemError: "❌ ", ElemWarn: "⚠️  ", ElemHint: "💡 ", ElemSuccess: "✓ ",
+		},
+		Emoji: true,
+	},
+	// vivid 表情符号，提示符和状态信息着色
+	"vivid": {
+		Symbols: map[string]string{
+			ElemUser: "👤 ", ElemAI: "🤖 ", ElemStats: "📊 ",
+			ElemError: "❌ ", ElemWarn: "⚠️  ", ElemHint: "💡 ", ElemSuccess: "✓ ",
+		},
+		Colors: map[string]string{
+			ElemUser: "green", ElemAI: "cyan", ElemStats: "gray",
+			ElemError: "red", ElemWarn: "yellow", ElemHint: "blue", ElemSuccess: "green",
+		},
+		Emoji: true,
+	},
+	// plain 不使用表情符号，适合不能显示表情符号的终端和字体
+	"plain": {
+		Symbols: map[string]string{
+			ElemUser: "", ElemAI: "", ElemStats: "",
+			ElemError: "error: ", ElemWarn: "warning: ", ElemHint: "hint: ", ElemSuccess: "ok: ",
+		},
+		Colors: map[string]string{
+			ElemUser: "green", ElemAI: "cyan", ElemStats: "gray",
+			ElemError: "red", ElemWarn: "yellow",
+		},
+	},
+}
+
+// colors 主题中可以使用的颜色名称
+var colors = map[string]aurora.Color{
+	"black":   aurora.BlackFg,
+	"red":     aurora.RedFg,
+	"green":   aurora.GreenFg,
+	"yellow":  aurora.YellowFg,
+	"blue":    aurora.BlueFg,
+	"magenta": aurora.MagentaFg,
+	"cyan":    aurora.CyanFg,
+	"white":   aurora.WhiteFg,
+	"gray":    aurora.BrightFg | aurora.BlackFg,
+	"bold":    aurora.BoldFm,
+}
+
+// theme 当前主题
+var theme = Themes["default"]
+
+// SetTheme 使用内置主题，name 为空时使用 default，symbols 和 colors 覆盖主题中对应元素的设置
+func SetTheme(name string, symbols, colorSpecs map[string]string) error {
+	if name == "" {
+		name = "default"
+	}
+	base, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("未知的主题: %s（可选 %s）", name, strings.Join(ThemeNames(), "、"))
+	}
+
+	t := Theme{Symbols: map[string]string{}, Colors: map[string]string{}, Emoji: base.Emoji}
+	for k, v := range base.Symbols {
+		t.Symbols[k] = v
+	}
+	for k, v := range base.Colors {
+		t.Colors[k] = v
+	}
+	for k, v := range symbols {
+		if !isElement(k) {
+			return fmt.Errorf("未知的界面元素: %s（可选 %s）", k, strings.Join(elements, "、"))
+		}
+		t.Symbols[k] = v
+	}
+	for k, v := range colorSpecs {
+		if !isElement(k) {
+			return fmt.Errorf("未知的界面元素: %s（可选 %s）", k, strings.Join(elements, "、"))
+		}
+		for _, c := range strings.Fields(v) {
+			if _, ok := colors[c]; !ok && c != "none" {
+				return fmt.Errorf("%s 的颜色 %s 无效（可选 %s）", k, c, strings.Join(colorNames(), "、"))
+			}
+		}
+		t.Colors[k] = v
+	}
+	theme = t
+	return nil
+}
+
+// ThemeNames 内置主题的名称
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// colorNames 可以使用的颜色名称
+func colorNames() []string {
+	names := []string{"none"}
+	for name := range colors {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// isElement 判断是否为可以设置的界面元素
+func isElement(name string) bool {
+	for _, e := range elements {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Styled 在文本前加上元素的符号，并按主题着色（关闭颜色时不着色）
+func Styled(elem, text string) string {
+	return colorize(elem, theme.Symbols[elem]+text)
+}
+
+// colorize 按主题中元素的颜色着色，颜色可以组合，如 "bold green"
+func colorize(elem, text string) string {
+	if !color {
+		return text
+	}
+	var c aurora.Color
+	for _, name := range strings.Fields(theme.Colors[elem]) {
+		c |= colors[name]
+	}
+	if c == 0 {
+		return text
+	}
+	return aurora.Colorize(text, c).String()
+}
+
+// Symbol 行中间的标记，如置顶消息前的📌：主题使用表情符号时返回 emoji，否则返回 plain
+func Symbol(emoji, plain string) string {
+	if theme.Emoji {
+		return emoji
+	}
+	return plain
+}
+
+// Status 状态信息，主题不使用表情符号时去掉开头的表情符号
+func Status(text string) string {
+	if theme.Emoji {
+		return text
+	}
+	trimmed := strings.TrimLeftFunc(text, func(r rune) bool {
+		return unicode.Is(unicode.So, r) || r == '\uFE0F' || r == '\u200D'
+	})
+	if trimmed == text {
+		return text
+	}
+	return strings.TrimLeft(trimmed, " ")
+}
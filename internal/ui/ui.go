@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/logrusorgru/aurora"
+)
+
+var (
+	// Stdout 结果输出
+	Stdout io.Writer = os.Stdout
+	// Stderr 错误、警告和状态信息输出
+	Stderr io.Writer = os.Stderr
+
+	color          = true
+	stdoutTerminal = IsTerminal(os.Stdout)
+	stderrTerminal = IsTerminal(os.Stderr)
+)
+
+// Setup 决定是否输出颜色：指定 --no-color、设置了 NO_COLOR 环境变量或标准输出不是终端时关闭颜色
+func Setup(noColor bool) {
+	_, envNoColor := os.LookupEnv("NO_COLOR")
+	color = !noColor && !envNoColor && stdoutTerminal
+}
+
+// ColorEnabled 是否输出颜色
+func ColorEnabled() bool {
+	return color
+}
+
+// StdoutIsTerminal 标准输出是否为终端
+func StdoutIsTerminal() bool {
+	return stdoutTerminal
+}
+
+// StderrIsTerminal 标准错误是否为终端
+func StderrIsTerminal() bool {
+	return stderrTerminal
+}
+
+// IsTerminal 判断文件是否为终端
+func IsTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// SetStdinEcho 打开或关闭标准输入所在终端的回显，用于输入口令
+func SetStdinEcho(on bool) error {
+	return setEcho(os.Stdin, on)
+}
+
+// Colors 获取按当前设置着色的aurora实例，关闭颜色时原样输出文本
+func Colors() aurora.Aurora {
+	return aurora.NewAurora(color)
+}
+
+// Markdown 渲染Markdown用于终端显示，按终端宽度换行：关闭颜色时使用无颜色样式，输出不是终端时返回原文
+func Markdown(text string) (string, error) {
+	if !stdoutTerminal {
+		return text, nil
+	}
+	style := "dark"
+	if !color {
+		style = "notty"
+	}
+	width, _ := TerminalSize()
+	r, err := glamour.NewTermRenderer(glamour.WithStandardStyle(style), glamour.WithWordWrap(width))
+	if err != nil {
+		return "", err
+	}
+	return r.Render(text)
+}
+
+// ClearScreen 清屏，输出不是终端时不做任何操作
+func ClearScreen() {
+	if stdoutTerminal {
+		fmt.Fprint(Stdout, "\033[2J\033[H")
+	}
+}
+
+// ClearLine 清除当前行，用于原地刷新进度
+func ClearLine() {
+	if stdoutTerminal {
+		fmt.Fprint(Stdout, "\r\033[K")
+	}
+}
+
+// Error 向标准错误输出错误信息
+func Error(format string, a ...interface{}) {
+	printf(Stderr, stderrTerminal, ElemError, "error: ", format, a...)
+}
+
+// Warn 向标准错误输出警告信息
+func Warn(format string, a ...interface{}) {
+	printf(Stderr, stderrTerminal, ElemWarn, "warning: ", format, a...)
+}
+
+// Hint 在终端中向标准错误输出操作提示，被脚本调用时不输出
+func Hint(format string, a ...interface{}) {
+	if stderrTerminal {
+		printf(Stderr, true, ElemHint, "", format, a...)
+	}
+}
+
+// Success 向标准输出输出操作成功信息
+func Success(format string, a ...interface{}) {
+	printf(Stdout, stdoutTerminal, ElemSuccess, "", format, a...)
+}
+
+// printf 输出一行信息，终端中使用主题中元素的符号作为前缀，否则使用便于脚本处理的文字前缀
+func printf(w io.Writer, terminal bool, elem, plain, format string, a ...interface{}) {
+	prefix := plain
+	if terminal {
+		prefix = Styled(elem, "")
+	}
+	fmt.Fprint(w, prefix+fmt.Sprintf(format, a...)+"\n")
+}
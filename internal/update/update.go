@@ -0,0 +1,152 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CheckInterval 两次检查新版本之间的最短间隔
+const CheckInterval = 24 * time.Hour
+
+// State 保存在 ~/.ai-chat-cli/update.json 中的检查结果
+type State struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// Checker 通过 GitHub Releases 检查新版本，并将结果缓存到本地
+type Checker struct {
+	Repo    string // GitHub 仓库，如 owner/name
+	Current string // 当前版本
+	Path    string // 检查结果的缓存文件
+
+	client *http.Client
+}
+
+// NewChecker 创建版本检查器，检查结果缓存在 ~/.ai-chat-cli/update.json
+func NewChecker(repo, current string) (*Checker, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Checker{
+		Repo:    repo,
+		Current: current,
+		Path:    filepath.Join(home, ".ai-chat-cli", "update.json"),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+// Cached 读取上次的检查结果，没有缓存时返回零值
+func (c *Checker) Cached() State {
+	var state State
+	if data, err := os.ReadFile(c.Path); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// Due 判断距离上次检查是否已超过 CheckInterval
+func (c *Checker) Due() bool {
+	return time.Since(c.Cached().CheckedAt) >= CheckInterval
+}
+
+// Check 查询最新发布的版本并保存检查结果。请求失败时也会记录检查时间，避免每次启动都重试
+func (c *Checker) Check(ctx context.Context) (State, error) {
+	state := c.Cached()
+	state.CheckedAt = time.Now()
+
+	latest, err := c.latest(ctx)
+	if err == nil {
+		state.Latest = latest
+	}
+	if data, merr := json.MarshalIndent(state, "", "  "); merr == nil {
+		os.MkdirAll(filepath.Dir(c.Path), 0755)
+		os.WriteFile(c.Path, data, 0644)
+	}
+	return state, err
+}
+
+// Newer 判断 latest 是否比当前版本新，任一版本号无法解析时返回false
+func (c *Checker) Newer(latest string) bool {
+	return compareVersions(latest, c.Current) > 0
+}
+
+// ReleaseURL 获取最新版本的发布页面地址
+func (c *Checker) ReleaseURL() string {
+	return "https://github.com/" + c.Repo + "/releases/latest"
+}
+
+// latest 从 GitHub API 获取最新发布的版本号
+func (c *Checker) latest(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/repos/"+c.Repo+"/releases/latest", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("查询最新版本失败: HTTP %d", resp.StatusCode)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("解析最新版本失败: %w", err)
+	}
+	return release.TagName, nil
+}
+
+// compareVersions 比较 1.2.3 或 v1.2.3 形式的版本号，a 较新时返回正数。
+// 任一版本号无法解析时返回0
+func compareVersions(a, b string) int {
+	va, ok := parseVersion(a)
+	if !ok {
+		return 0
+	}
+	vb, ok := parseVersion(b)
+	if !ok {
+		return 0
+	}
+	for i := range va {
+		if va[i] != vb[i] {
+			return va[i] - vb[i]
+		}
+	}
+	return 0
+}
+
+// parseVersion 解析版本号的主、次、修订号，忽略预发布和构建信息
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	fields := strings.Split(v, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,173 @@
+// Package chat 提供带对话记忆的高层客户端，便于在其他Go程序中直接使用本工具的对话能力，
+// 而不需要调用命令行。
+//
+// 基本用法:
+//
+//	c := chat.New(providers.NewOpenAIProvider("openai", providers.Config{
+//		APIKey: os.Getenv("OPENAI_API_KEY"),
+//		Model:  "gpt-4o-mini",
+//	}))
+//	c.System("你是一个简洁的助手")
+//	resp, err := c.Ask(ctx, "介绍一下Go语言")
+//
+// 流式输出:
+//
+//	reply, err := c.Stream(ctx, "再详细一点", func(text string) { fmt.Print(text) })
+//
+// 保存和继续会话:
+//
+//	store, _ := session.OpenDefault()
+//	s := c.Session(store.New("openai", "gpt-4o-mini"))
+//	store.Save(s)
+//	c = chat.Resume(provider, s)
+package chat
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"ai-chat-cli/pkg/providers"
+	"ai-chat-cli/pkg/session"
+)
+
+// DefaultTemperature 未设置温度时使用的默认值，与命令行的 chat 命令一致
+const DefaultTemperature = 0.7
+
+// Client 带对话记忆的客户端，每次提问都会附带之前的对话，可以并发使用
+type Client struct {
+	provider providers.Provider
+
+	// Model 请求使用的模型，为空时使用提供商的默认模型
+	Model string
+	// Temperature 温度参数，为0时使用 DefaultTemperature
+	Temperature float64
+	// MaxTokens 最大输出token数，为0时使用提供商的默认值
+	MaxTokens int
+
+	mu       sync.Mutex
+	messages []providers.Message
+}
+
+// New 创建客户端
+func New(provider providers.Provider) *Client {
+	return &Client{provider: provider}
+}
+
+// Resume 从保存的会话创建客户端，继续之前的对话
+func Resume(provider providers.Provider, s *session.Session) *Client {
+	c := New(provider)
+	c.Model = s.Model
+	c.messages = s.ChatMessages()
+	return c
+}
+
+// System 设置系统提示词，替换之前设置的系统提示词
+func (c *Client) System(prompt string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kept := c.messages[:0]
+	for _, m := range c.messages {
+		if m.Role != "system" {
+			kept = append(kept, m)
+		}
+	}
+	c.messages = append([]providers.Message{{Role: "system", Content: prompt}}, kept...)
+}
+
+// Ask 提问并等待完整回复，成功后问题和回复都会加入对话记忆
+func (c *Client) Ask(ctx context.Context, question string) (*providers.ChatResponse, error) {
+	resp, err := c.provider.Chat(ctx, c.request(question, false))
+	if err != nil {
+		return nil, err
+	}
+	c.remember(question, resp.Content)
+	return resp, nil
+}
+
+// Stream 提问并以流式方式接收回复，每收到一段内容调用一次 onChunk，返回完整的回复。
+// 成功后问题和回复都会加入对话记忆
+func (c *Client) Stream(ctx context.Context, question string, onChunk func(text string)) (string, error) {
+	chunks, err := c.provider.ChatStream(ctx, c.request(question, true))
+	if err != nil {
+		return "", err
+	}
+
+	var reply strings.Builder
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			return "", chunk.Error
+		}
+		if chunk.Content != "" {
+			reply.WriteString(chunk.Content)
+			if onChunk != nil {
+				onChunk(chunk.Content)
+			}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	c.remember(question, reply.String())
+	return reply.String(), nil
+}
+
+// History 获取当前的对话记忆
+func (c *Client) History() []providers.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]providers.Message(nil), c.messages...)
+}
+
+// Reset 清空对话记忆，保留系统提示词
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var kept []providers.Message
+	for _, m := range c.messages {
+		if m.Role == "system" {
+			kept = append(kept, m)
+		}
+	}
+	c.messages = kept
+}
+
+// Session 将对话记忆追加到会话中并返回该会话，会话需要调用方自行保存
+func (c *Client) Session(s *session.Session) *session.Session {
+	for _, m := range c.History() {
+		s.Append(m.Role, m.Content)
+	}
+	return s
+}
+
+// request 构建包含对话记忆和新问题的请求
+func (c *Client) request(question string, stream bool) *providers.ChatRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messages := append([]providers.Message(nil), c.messages...)
+	messages = append(messages, providers.Message{Role: "user", Content: question})
+
+	temperature := c.Temperature
+	if temperature == 0 {
+		temperature = DefaultTemperature
+	}
+	return &providers.ChatRequest{
+		Messages:    messages,
+		Model:       c.Model,
+		MaxTokens:   c.MaxTokens,
+		Temperature: temperature,
+		Stream:      stream,
+	}
+}
+
+// remember 将一轮问答加入对话记忆
+func (c *Client) remember(question, reply string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages,
+		providers.Message{Role: "user", Content: question},
+		providers.Message{Role: "assistant", Content: reply})
+}
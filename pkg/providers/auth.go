@@ -0,0 +1,11 @@
+package providers
+
+import "context"
+
+// CodeAuthError 无法获取访问令牌时的错误代码
+const CodeAuthError = "auth_error"
+
+// TokenSource 提供访问令牌，实现应缓存令牌并在过期前自动刷新
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"context"
+)
+
+// BatchRequest 异步批处理中的单条请求
+type BatchRequest struct {
+	CustomID string       // 自定义ID，用于匹配结果
+	Request  *ChatRequest // 对话请求
+}
+
+// BatchInfo 异步批处理任务信息
+type BatchInfo struct {
+	ID            string `json:"id"`             // 批处理任务ID
+	Status        string `json:"status"`         // 状态: validating, in_progress, completed, failed, expired, cancelled 等
+	InputFileID   string `json:"input_file_id"`  // 输入文件ID
+	OutputFileID  string `json:"output_file_id"` // 结果文件ID
+	ErrorFileID   string `json:"error_file_id"`  // 错误文件ID
+	CreatedAt     int64  `json:"created_at"`     // 创建时间（Unix秒）
+	CompletedAt   int64  `json:"completed_at"`   // 完成时间（Unix秒）
+	RequestCounts struct {
+		Total     int `json:"total"`     // 请求总数
+		Completed int `json:"completed"` // 已完成数
+		Failed    int `json:"failed"`    // 失败数
+	} `json:"request_counts"`
+}
+
+// BatchOutput 异步批处理中单条请求的结果
+type BatchOutput struct {
+	CustomID string        // 自定义ID
+	Response *ChatResponse // 成功时的响应
+	Error    string        // 失败时的错误信息
+}
+
+// BatchProvider 支持异步批处理接口的提供商
+type BatchProvider interface {
+	// SubmitBatch 上传请求并创建批处理任务
+	SubmitBatch(ctx context.Context, reqs []BatchRequest) (*BatchInfo, error)
+
+	// GetBatch 查询批处理任务状态
+	GetBatch(ctx context.Context, id string) (*BatchInfo, error)
+
+	// FetchBatchResults 下载已完成批处理任务的结果
+	FetchBatchResults(ctx context.Context, info *BatchInfo) ([]BatchOutput, error)
+}
@@ -0,0 +1,54 @@
+package providers
+
+import "fmt"
+
+// 发送最大输出token数使用的字段
+const (
+	MaxTokensField           = "max_tokens"            // 大多数兼容服务使用（默认）
+	MaxCompletionTokensField = "max_completion_tokens" // OpenAI 的推理模型（o1、o3 等）只接受该字段
+	MaxTokensNone            = "none"                  // 不发送，由服务端决定
+)
+
+// Compat 兼容OpenAI的服务之间请求格式的差异。不同的服务接受的字段名不同，且常常拒绝不认识的字段，
+// 按提供商设置后同一份配置可以用于不同的网关，而不会收到400错误
+type Compat struct {
+	// MaxTokensField 最大输出token数的字段名: max_tokens（默认）、max_completion_tokens 或 none（不发送）
+	MaxTokensField string
+	// OmitTemperature 不发送 temperature，用于只接受默认温度的模型
+	OmitTemperature bool
+	// StreamUsage 流式请求带上 stream_options.include_usage，让服务在最后一个数据块中返回用量。
+	// 不支持 stream_options 的服务会拒绝请求，因此默认不发送
+	StreamUsage bool
+}
+
+// Validate 检查兼容设置
+func (c Compat) Validate() error {
+	switch c.MaxTokensField {
+	case "", MaxTokensField, MaxCompletionTokensField, MaxTokensNone:
+		return nil
+	default:
+		return fmt.Errorf("max_tokens_field 的值无效: %s（可选 max_tokens、max_completion_tokens、none）", c.MaxTokensField)
+	}
+}
+
+// streamOptions 流式请求的选项
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// apply 按兼容设置调整请求中的字段
+func (c Compat) apply(body *openAIRequest, maxTokens int, temperature float64) {
+	switch c.MaxTokensField {
+	case MaxCompletionTokensField:
+		body.MaxCompletionTokens = maxTokens
+	case MaxTokensNone:
+	default:
+		body.MaxTokens = maxTokens
+	}
+	if !c.OmitTemperature {
+		body.Temperature = &temperature
+	}
+	if body.Stream && c.StreamUsage {
+		body.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+}
@@ -0,0 +1,24 @@
+// Package providers 定义AI提供商接口及内置实现：兼容OpenAI的HTTP API（OpenAIProvider）、
+// 不调用API的模拟提供商（MockProvider）和外部插件（PluginProvider）。
+//
+// 其他Go程序可以直接使用:
+//
+//	p := providers.NewOpenAIProvider("openai", providers.Config{
+//		APIKey: os.Getenv("OPENAI_API_KEY"),
+//		Model:  "gpt-4o-mini",
+//	})
+//	resp, err := p.Chat(ctx, &providers.ChatRequest{
+//		Messages: []providers.Message{{Role: "user", Content: "你好"}},
+//	})
+//
+// 各实现在 init 中通过 Register 注册类型名称、构造函数和支持的功能（Capabilities），
+// New 和 Resolve 按提供商名称选择类型。
+//
+// OpenAIProvider 的HTTP请求经过由 Middleware 组成的传输层链：Config.Middlewares（如 RateLimit、
+// Logging、Metrics）、Retry 和 Auth，新的通用行为只需增加中间件。
+//
+// 消息可以通过 Message.Images 附带图片（NewImage 识别格式），发送时按OpenAI的格式序列化为内容片段，
+// 需要提供商支持 CapVision。
+//
+// 需要对话记忆时使用 ai-chat-cli/pkg/chat。
+package providers
@@ -0,0 +1,115 @@
+package providers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Image 消息附带的图片
+type Image struct {
+	MIMEType string // 如 image/png
+	Data     []byte
+}
+
+// NewImage 根据图片内容创建图片，内容不是常见的图片格式时返回错误
+func NewImage(data []byte) (Image, error) {
+	mime := http.DetectContentType(data)
+	switch mime {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return Image{MIMEType: mime, Data: data}, nil
+	}
+	return Image{}, fmt.Errorf("不支持的图片格式: %s（支持 PNG、JPEG、GIF、WebP）", mime)
+}
+
+// DataURL 图片的 data URL
+func (img Image) DataURL() string {
+	return "data:" + img.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(img.Data)
+}
+
+// contentPart OpenAI格式的消息内容片段
+type contentPart struct {
+	Type     string    `json:"type"` // text 或 image_url
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+// messageJSON 序列化消息时使用的格式，Content 为字符串或内容片段数组
+type messageJSON struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// MarshalJSON 没有图片时内容为字符串，有图片时为文本和图片片段的数组
+func (m Message) MarshalJSON() ([]byte, error) {
+	var content interface{} = m.Content
+	if len(m.Images) > 0 {
+		parts := make([]contentPart, 0, len(m.Images)+1)
+		if m.Content != "" {
+			parts = append(parts, contentPart{Type: "text", Text: m.Content})
+		}
+		for _, img := range m.Images {
+			parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: img.DataURL()}})
+		}
+		content = parts
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(messageJSON{Role: m.Role, Content: data})
+}
+
+// UnmarshalJSON 解析字符串或内容片段数组形式的内容，片段中的 data URL 图片解析为 Images
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var raw messageJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = Message{Role: raw.Role}
+	if len(raw.Content) == 0 || string(raw.Content) == "null" {
+		return nil
+	}
+	if raw.Content[0] == '"' {
+		return json.Unmarshal(raw.Content, &m.Content)
+	}
+
+	var parts []contentPart
+	if err := json.Unmarshal(raw.Content, &parts); err != nil {
+		return err
+	}
+	var texts []string
+	for _, part := range parts {
+		switch {
+		case part.Type == "text":
+			texts = append(texts, part.Text)
+		case part.Type == "image_url" && part.ImageURL != nil:
+			img, err := parseDataURL(part.ImageURL.URL)
+			if err != nil {
+				return err
+			}
+			m.Images = append(m.Images, img)
+		}
+	}
+	m.Content = strings.Join(texts, "\n")
+	return nil
+}
+
+// parseDataURL 解析 base64 编码的 data URL
+func parseDataURL(url string) (Image, error) {
+	meta, encoded, ok := strings.Cut(strings.TrimPrefix(url, "data:"), ",")
+	if !ok || !strings.HasPrefix(url, "data:") || !strings.HasSuffix(meta, ";base64") {
+		return Image{}, fmt.Errorf("只支持 base64 编码的 data URL 图片")
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return Image{}, fmt.Errorf("解析图片失败: %w", err)
+	}
+	return Image{MIMEType: strings.TrimSuffix(meta, ";base64"), Data: data}, nil
+}
@@ -0,0 +1,227 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Middleware 包装发送HTTP请求的传输层，在所有请求上添加认证、重试、限流、日志等通用行为，
+// 不需要修改各提供商的实现
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripFunc 将函数转换为 http.RoundTripper
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip 调用函数发送请求
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain 用中间件依次包装基础传输层，第一个中间件最先收到请求
+func Chain(base http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	rt := base
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		rt = middlewares[i](rt)
+	}
+	return rt
+}
+
+// tokenError 获取访问令牌失败，请求没有发送
+type tokenError struct {
+	err error
+}
+
+func (e *tokenError) Error() string { return e.err.Error() }
+
+func (e *tokenError) Unwrap() error { return e.err }
+
+// Auth 设置 Authorization 头：ts 不为nil时使用其返回的访问令牌，否则使用API密钥。
+// 服务端返回401时丢弃缓存的令牌，下次请求重新获取
+func Auth(apiKey string, ts TokenSource) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			token := apiKey
+			if ts != nil {
+				var err error
+				if token, err = ts.Token(req.Context()); err != nil {
+					return nil, &tokenError{err}
+				}
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			resp, err := next.RoundTrip(req)
+			if err == nil && resp.StatusCode == http.StatusUnauthorized {
+				if inv, ok := ts.(interface{ Invalidate() }); ok {
+					inv.Invalidate()
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+// 重试的等待时间，每次重试翻倍，服务端通过 Retry-After 指定时以其为准，但不超过上限
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// Retry 请求被限流（429）或服务端暂时故障（500、502、503、504）时按指数退避重试，最多 maxRetries 次。
+// 连接失败时不重试，便于尽快发现没有网络；请求体无法重新读取时不重试
+func Retry(maxRetries int) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if maxRetries <= 0 {
+			return next
+		}
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			for attempt := 0; ; attempt++ {
+				resp, err := next.RoundTrip(req)
+				if err != nil || attempt >= maxRetries || !retryableStatus(resp.StatusCode) ||
+					(req.Body != nil && req.GetBody == nil) {
+					return resp, err
+				}
+
+				delay := retryDelay(resp, attempt)
+				io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+				resp.Body.Close()
+				if err := sleepContext(req.Context(), delay); err != nil {
+					return nil, err
+				}
+
+				retry := req.Clone(req.Context())
+				if req.GetBody != nil {
+					if retry.Body, err = req.GetBody(); err != nil {
+						return nil, err
+					}
+				}
+				req = retry
+			}
+		})
+	}
+}
+
+// retryableStatus 判断状态码是否表示可以重试的临时错误
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay 第 attempt 次重试前的等待时间
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s >= 0 {
+		if d := time.Duration(s) * time.Second; d < retryMaxDelay {
+			return d
+		}
+		return retryMaxDelay
+	}
+	delay := retryBaseDelay << attempt
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// sleepContext 等待指定时间，期间ctx被取消时返回错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Waiter 限流器，Wait 阻塞直到允许发送下一个请求
+type Waiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RateLimit 发送每个请求前等待限流器放行，limiter 为nil时不限流
+func RateLimit(limiter Waiter) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if limiter == nil {
+			return next
+		}
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if err := limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// Logging 请求完成后用 logf 记录方法、地址、状态码和耗时，不记录请求头和内容，不会泄露API密钥
+func Logging(logf func(format string, a ...interface{})) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			elapsed := time.Since(start).Round(time.Millisecond)
+			target := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+			if err != nil {
+				logf("%s %s 失败 %s: %v", req.Method, target, elapsed, err)
+			} else {
+				logf("%s %s %d %s", req.Method, target, resp.StatusCode, elapsed)
+			}
+			return resp, err
+		})
+	}
+}
+
+// Metrics 统计发送的HTTP请求，可以被多个提供商共享
+type Metrics struct {
+	mu       sync.Mutex
+	requests int
+	failures int
+	total    time.Duration
+}
+
+// MetricsSnapshot 请求统计
+type MetricsSnapshot struct {
+	Requests   int           // 请求数
+	Failures   int           // 连接失败或状态码不是200的请求数
+	AvgLatency time.Duration // 平均耗时（到收到响应头为止）
+}
+
+// Middleware 返回记录请求次数、失败次数和耗时的中间件
+func (m *Metrics) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			elapsed := time.Since(start)
+
+			m.mu.Lock()
+			m.requests++
+			m.total += elapsed
+			if err != nil || resp.StatusCode != http.StatusOK {
+				m.failures++
+			}
+			m.mu.Unlock()
+			return resp, err
+		})
+	}
+}
+
+// Snapshot 获取当前的统计
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := MetricsSnapshot{Requests: m.requests, Failures: m.failures}
+	if m.requests > 0 {
+		s.AvgLatency = m.total / time.Duration(m.requests)
+	}
+	return s
+}
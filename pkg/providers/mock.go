@@ -0,0 +1,251 @@
+package providers
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MockName 内置模拟提供商的名称
+const MockName = "mock"
+
+// MockConfig 模拟提供商配置
+type MockConfig struct {
+	Responses  []string      // 依次循环返回的回复，为空时回显最后一条用户消息
+	Latency    time.Duration // 每次请求返回前的延迟
+	ChunkDelay time.Duration // 流式响应中每个数据块之间的延迟
+	ErrorRate  float64       // 请求失败的概率，0到1之间
+}
+
+func init() {
+	Register(Registration{
+		Kind: MockName,
+		New: func(name string, cfg Config) (Provider, error) {
+			return NewMockProvider(name, mockConfig(cfg.Extra, cfg.Warn)), nil
+		},
+		// 用于调试任何命令的脚本，声明支持所有功能
+		Capabilities: AllCapabilities,
+		Match: func(name string) bool {
+			return name == MockName
+		},
+	})
+}
+
+// mockConfig 从 providers.mock 的 extra 设置中读取模拟提供商的配置，无效的值会被忽略
+func mockConfig(extra map[string]string, warn func(format string, a ...interface{})) MockConfig {
+	if warn == nil {
+		warn = func(string, ...interface{}) {}
+	}
+	var cfg MockConfig
+
+	if r := extra["response"]; r != "" {
+		cfg.Responses = []string{r}
+	}
+	if file := extra["responses_file"]; file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			warn("读取模拟回复文件失败: %v", err)
+		} else {
+			// 多条回复之间用单独一行的 --- 分隔
+			for _, r := range strings.Split(string(data), "\n---\n") {
+				if r = strings.TrimSpace(r); r != "" {
+					cfg.Responses = append(cfg.Responses, r)
+				}
+			}
+		}
+	}
+
+	for key, d := range map[string]*time.Duration{"latency": &cfg.Latency, "chunk_delay": &cfg.ChunkDelay} {
+		if v := extra[key]; v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				warn("模拟提供商的 %s 值无效: %s（示例: 500ms、2s）", key, v)
+				continue
+			}
+			*d = parsed
+		}
+	}
+	if v := extra["error_rate"]; v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			warn("模拟提供商的 error_rate 值无效: %s（应在0到1之间）", v)
+		} else {
+			cfg.ErrorRate = rate
+		}
+	}
+	return cfg
+}
+
+// MockProvider 不调用任何API的模拟提供商，用于脚本调试、演示和测试
+type MockProvider struct {
+	name string
+	cfg  MockConfig
+
+	mu   sync.Mutex
+	next int
+	rand *rand.Rand
+}
+
+// NewMockProvider 创建模拟提供商
+func NewMockProvider(name string, cfg MockConfig) *MockProvider {
+	return &MockProvider{
+		name: name,
+		cfg:  cfg,
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// GetName 获取提供商名称
+func (p *MockProvider) GetName() string {
+	return p.name
+}
+
+// Chat 返回预设的回复
+func (p *MockProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	content, err := p.reply(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := EstimateMessages(req.Messages)
+	completion := EstimateTokens(content)
+	return &ChatResponse{
+		Content: content,
+		Model:   p.model(req),
+		Usage: Usage{
+			PromptTokens:     prompt,
+			CompletionTokens: completion,
+			TotalTokens:      prompt + completion,
+		},
+		FinishReason: FinishStop,
+	}, nil
+}
+
+// ChatStream 将预设的回复拆分后逐块返回
+func (p *MockProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	content, err := p.reply(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan StreamChunk)
+	go func() {
+		defer close(chunks)
+		for _, part := range splitChunks(content) {
+			if err := p.sleep(ctx, p.cfg.ChunkDelay); err != nil {
+				chunks <- StreamChunk{Error: err}
+				return
+			}
+			chunks <- StreamChunk{Content: part}
+		}
+		prompt, completion := EstimateMessages(req.Messages), EstimateTokens(content)
+		chunks <- StreamChunk{Done: true, Usage: &Usage{
+			PromptTokens:     prompt,
+			CompletionTokens: completion,
+			TotalTokens:      prompt + completion,
+		}, FinishReason: FinishStop}
+	}()
+	return chunks, nil
+}
+
+// GetModels 获取可用模型列表
+func (p *MockProvider) GetModels(ctx context.Context) ([]string, error) {
+	return []string{MockName}, nil
+}
+
+// Moderate 模拟内容审核，不标记任何内容
+func (p *MockProvider) Moderate(ctx context.Context, input string) (*ModerationResult, error) {
+	if err := p.sleep(ctx, p.cfg.Latency); err != nil {
+		return nil, err
+	}
+	return &ModerationResult{Scores: map[string]float64{}}, nil
+}
+
+// ValidateConfig 验证配置
+func (p *MockProvider) ValidateConfig() error {
+	if p.cfg.ErrorRate < 0 || p.cfg.ErrorRate > 1 {
+		return NewProviderError(p.name, "invalid_config", "error_rate 应在0到1之间", nil)
+	}
+	return nil
+}
+
+// reply 等待设定的延迟，按错误率模拟失败，否则返回下一条预设回复
+func (p *MockProvider) reply(ctx context.Context, req *ChatRequest) (string, error) {
+	if err := p.sleep(ctx, p.cfg.Latency); err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cfg.ErrorRate > 0 && p.rand.Float64() < p.cfg.ErrorRate {
+		return "", NewProviderError(p.name, "http_500", "模拟的请求失败", nil)
+	}
+
+	var content string
+	if len(p.cfg.Responses) > 0 {
+		content = p.cfg.Responses[p.next%len(p.cfg.Responses)]
+		p.next++
+	} else {
+		content = "Mock: " + lastUserMessage(req.Messages)
+	}
+
+	if !strings.HasPrefix(content, req.AssistantPrefix) {
+		content = req.AssistantPrefix + content
+	}
+	return content, nil
+}
+
+// model 获取响应中的模型名称
+func (p *MockProvider) model(req *ChatRequest) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return MockName
+}
+
+// sleep 等待指定时间，期间请求被取消时返回错误
+func (p *MockProvider) sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return NewProviderError(p.name, "request_error", "请求已取消", ctx.Err())
+	}
+}
+
+// lastUserMessage 获取最后一条用户消息
+func lastUserMessage(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+// mockChunkRunes 流式响应中每个数据块的字数
+const mockChunkRunes = 4
+
+// splitChunks 将回复按固定字数拆分为数据块，拼接后与原文一致
+func splitChunks(content string) []string {
+	runes := []rune(content)
+	var chunks []string
+	for start := 0; start < len(runes); start += mockChunkRunes {
+		end := start + mockChunkRunes
+		if end > len(runes) {
+			end = len(runes)
+		}
+		chunks = append(chunks, string(runes[start:end]))
+	}
+	return chunks
+}
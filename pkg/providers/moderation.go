@@ -0,0 +1,134 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// ModerationWarn 内容被标记时发出警告并继续发送
+	ModerationWarn = "warn"
+	// ModerationBlock 内容被标记时拒绝发送
+	ModerationBlock = "block"
+)
+
+// ModerationResult 内容审核结果
+type ModerationResult struct {
+	Flagged    bool               // 是否被标记
+	Categories []string           // 被标记的类别
+	Scores     map[string]float64 // 各类别得分
+}
+
+// String 返回被标记类别及得分的简要描述
+func (r *ModerationResult) String() string {
+	parts := make([]string, 0, len(r.Categories))
+	for _, c := range r.Categories {
+		parts = append(parts, fmt.Sprintf("%s (%.2f)", c, r.Scores[c]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Moderator 支持内容审核的提供商
+type Moderator interface {
+	// Moderate 审核文本内容
+	Moderate(ctx context.Context, input string) (*ModerationResult, error)
+}
+
+// ModeratedProvider 在发送对话请求前审核最新一条用户消息的提供商包装
+type ModeratedProvider struct {
+	Provider
+	moderator Moderator
+	mode      string
+	onWarn    func(result *ModerationResult, err error)
+}
+
+// NewModeratedProvider 创建带输入审核的提供商。
+// mode为ModerationWarn时，内容被标记或审核失败都只调用onWarn并继续发送；
+// mode为ModerationBlock时，内容被标记或审核失败都会拒绝发送。
+func NewModeratedProvider(p Provider, moderator Moderator, mode string, onWarn func(result *ModerationResult, err error)) *ModeratedProvider {
+	return &ModeratedProvider{Provider: p, moderator: moderator, mode: mode, onWarn: onWarn}
+}
+
+// Unwrap 返回被包装的提供商
+func (m *ModeratedProvider) Unwrap() Provider {
+	return m.Provider
+}
+
+// Chat 审核通过后发送对话请求（非流式）
+func (m *ModeratedProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if err := m.Check(ctx, req); err != nil {
+		return nil, err
+	}
+	return m.Provider.Chat(ctx, req)
+}
+
+// ChatStream 审核通过后发送对话请求（流式）
+func (m *ModeratedProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	if err := m.Check(ctx, req); err != nil {
+		return nil, err
+	}
+	return m.Provider.ChatStream(ctx, req)
+}
+
+// Check 审核请求中最新一条用户消息，不通过时返回拒绝发送的原因
+func (m *ModeratedProvider) Check(ctx context.Context, req *ChatRequest) error {
+	var input string
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			input = req.Messages[i].Content
+			break
+		}
+	}
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+
+	result, err := m.moderator.Moderate(ctx, input)
+	if err != nil {
+		if m.mode == ModerationBlock {
+			return NewProviderError(m.GetName(), "moderation_error", "内容审核失败，已拒绝发送", err)
+		}
+		if m.onWarn != nil {
+			m.onWarn(nil, err)
+		}
+		return nil
+	}
+
+	if !result.Flagged {
+		return nil
+	}
+	if m.mode == ModerationBlock {
+		return NewProviderError(m.GetName(), "content_flagged", "输入内容未通过审核: "+result.String(), nil)
+	}
+	if m.onWarn != nil {
+		m.onWarn(result, nil)
+	}
+	return nil
+}
+
+// Unwrap 逐层去除提供商包装，返回最内层的提供商
+func Unwrap(p Provider) Provider {
+	for {
+		w, ok := p.(interface{ Unwrap() Provider })
+		if !ok {
+			return p
+		}
+		p = w.Unwrap()
+	}
+}
+
+// flaggedCategories 返回被标记的类别，按得分从高到低排序
+func flaggedCategories(categories map[string]bool, scores map[string]float64) []string {
+	var flagged []string
+	for c, ok := range categories {
+		if ok {
+			flagged = append(flagged, c)
+		}
+	}
+	sort.Slice(flagged, func(i, j int) bool {
+		return scores[flagged[i]] > scores[flagged[j]]
+	})
+	return flagged
+}
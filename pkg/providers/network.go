@@ -0,0 +1,68 @@
+package providers
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// 建立连接的超时时间，没有网络时尽快失败，而不是等到整个请求超时
+const (
+	dialTimeout         = 5 * time.Second
+	tlsHandshakeTimeout = 10 * time.Second
+)
+
+// CodeNetworkUnreachable 无法连接到提供商时的错误代码
+const CodeNetworkUnreachable = "network_unreachable"
+
+// CodeStreamInterrupted 流式响应在回复完成前中断（连接被重置、网关超时等）时的错误代码
+const CodeStreamInterrupted = "stream_interrupted"
+
+// defaultTransport 连接超时较短的默认传输层
+var defaultTransport = func() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	t.TLSHandshakeTimeout = tlsHandshakeTimeout
+	return t
+}()
+
+// IsNetworkUnreachable 判断错误是否因为无法连接到提供商（没有网络、DNS解析失败、连接被拒绝或超时）
+func IsNetworkUnreachable(err error) bool {
+	var pe *ProviderError
+	return errors.As(err, &pe) && pe.Code == CodeNetworkUnreachable
+}
+
+// IsStreamInterrupted 判断错误是否因为流式响应在回复完成前中断，此时已收到的内容是完整回复的开头
+func IsStreamInterrupted(err error) bool {
+	var pe *ProviderError
+	return errors.As(err, &pe) && pe.Code == CodeStreamInterrupted
+}
+
+// unreachable 判断发送请求的错误是否发生在建立连接阶段，此时请求一定没有到达服务器
+func unreachable(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	for _, errno := range []syscall.Errno{syscall.ECONNREFUSED, syscall.ENETUNREACH, syscall.EHOSTUNREACH, syscall.ENETDOWN} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostOf 获取地址中的主机名，用于错误信息
+func hostOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return rawURL
+}
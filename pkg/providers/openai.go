@@ -0,0 +1,455 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultBaseURL OpenAI官方API地址
+	DefaultBaseURL = "https://api.openai.com/v1"
+	// DefaultModel 未配置模型时使用的默认模型
+	DefaultModel = "gpt-3.5-turbo"
+	// DefaultMaxTokens 未配置时使用的默认最大token数
+	DefaultMaxTokens = 2000
+)
+
+// Config 提供商连接配置
+type Config struct {
+	APIKey    string        // API密钥
+	BaseURL   string        // API地址
+	Model     string        // 默认模型
+	MaxTokens int           // 默认最大token数
+	Timeout   time.Duration // 请求超时时间，0表示不限制
+
+	// TokenSource 不为nil时使用其返回的访问令牌代替API密钥，用于 Azure AD、Google 等 OAuth 认证
+	TokenSource TokenSource
+
+	// Transport 发送HTTP请求使用的传输层，为nil时使用连接超时较短的默认传输层，用于录制和回放请求
+	Transport http.RoundTripper
+
+	// MaxRetries 请求被限流或服务端暂时故障时的最大重试次数，0表示不重试
+	MaxRetries int
+	// Middlewares 包装在重试和认证之外的中间件，第一个最先收到请求，如限流、日志和统计
+	Middlewares []Middleware
+
+	// Compat 与OpenAI请求格式的差异，如 max_completion_tokens、不发送 temperature
+	Compat Compat
+	// Sampling 请求未指定时使用的采样参数，如 top_p、stop
+	Sampling Sampling
+	// User 和 Metadata 随每次请求发送的 user 和 metadata 字段，企业网关用于归属统计和滥用追踪
+	User     string
+	Metadata map[string]string
+
+	// Extra 提供商类型特有的设置，如模拟提供商的 response、传给插件的自定义设置
+	Extra map[string]string
+	// Warn 设置中的无效值被忽略时调用，为nil时不提示
+	Warn func(format string, a ...interface{})
+}
+
+// OpenAI 对 metadata 字段的限制
+const (
+	maxMetadataKeys  = 16
+	maxMetadataKey   = 64
+	maxMetadataValue = 512
+)
+
+// ValidateMetadata 检查请求的 metadata 是否符合API的限制：最多16个键，键最长64个字符，值最长512个字符
+func ValidateMetadata(metadata map[string]string) error {
+	if len(metadata) > maxMetadataKeys {
+		return fmt.Errorf("metadata 最多 %d 个键，当前 %d 个", maxMetadataKeys, len(metadata))
+	}
+	for k, v := range metadata {
+		switch {
+		case k == "":
+			return fmt.Errorf("metadata 的键不能为空")
+		case utf8.RuneCountInString(k) > maxMetadataKey:
+			return fmt.Errorf("metadata 的键 %q 超过 %d 个字符", k, maxMetadataKey)
+		case utf8.RuneCountInString(v) > maxMetadataValue:
+			return fmt.Errorf("metadata %q 的值超过 %d 个字符", k, maxMetadataValue)
+		}
+	}
+	return nil
+}
+
+func init() {
+	Register(Registration{
+		Kind: OpenAIKind,
+		New: func(name string, cfg Config) (Provider, error) {
+			return NewOpenAIProvider(name, cfg), nil
+		},
+		// 工具调用和文本向量还没有实现，不声明支持
+		Capabilities: Capabilities{CapStreaming, CapVision},
+	})
+}
+
+// OpenAIProvider OpenAI及兼容API的提供商实现
+type OpenAIProvider struct {
+	name   string
+	cfg    Config
+	client *http.Client
+}
+
+// NewOpenAIProvider 创建OpenAI兼容提供商
+func NewOpenAIProvider(name string, cfg Config) *OpenAIProvider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.MaxTokens == 0 {
+		cfg.MaxTokens = DefaultMaxTokens
+	}
+	transport := cfg.Transport
+	if transport == nil {
+		transport = defaultTransport
+	}
+	middlewares := make([]Middleware, 0, len(cfg.Middlewares)+2)
+	middlewares = append(middlewares, cfg.Middlewares...)
+	middlewares = append(middlewares, Retry(cfg.MaxRetries), Auth(cfg.APIKey, cfg.TokenSource))
+
+	return &OpenAIProvider{
+		name:   name,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout, Transport: Chain(transport, middlewares...)},
+	}
+}
+
+// openAIRequest OpenAI API请求结构
+type openAIRequest struct {
+	Model               string         `json:"model"`
+	Messages            []Message      `json:"messages"`
+	MaxTokens           int            `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int            `json:"max_completion_tokens,omitempty"`
+	Temperature         *float64       `json:"temperature,omitempty"`
+	Stream              bool           `json:"stream,omitempty"`
+	StreamOptions       *streamOptions `json:"stream_options,omitempty"`
+	Sampling
+	User     string            `json:"user,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	N        int               `json:"n,omitempty"`
+}
+
+// openAIResponse OpenAI API响应结构
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+			Refusal string `json:"refusal"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// chatResponse 转换为通用的对话响应，模型拒绝回答时以拒绝的说明作为内容，结束原因为 refusal。
+// 返回多个回答时第一个以外的回答放在 Alternatives 中
+func (r *openAIResponse) chatResponse() *ChatResponse {
+	choices := make([]Choice, len(r.Choices))
+	for i, c := range r.Choices {
+		choices[i] = Choice{Content: c.Message.Content, FinishReason: c.FinishReason}
+		if c.Message.Refusal != "" {
+			choices[i] = Choice{Content: c.Message.Refusal, FinishReason: FinishRefusal}
+		}
+	}
+	resp := &ChatResponse{
+		Content:      choices[0].Content,
+		Model:        r.Model,
+		Usage:        r.Usage,
+		FinishReason: choices[0].FinishReason,
+	}
+	if len(choices) > 1 {
+		resp.Alternatives = choices[1:]
+	}
+	return resp
+}
+
+// openAIStreamResponse OpenAI流式响应的数据块结构
+type openAIStreamResponse struct {
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Content string `json:"content"`
+			Refusal string `json:"refusal"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	// Usage 部分服务在最后一个数据块中返回用量（OpenAI 需要请求 stream_options.include_usage）
+	Usage *Usage `json:"usage"`
+}
+
+// GetName 获取提供商名称
+func (p *OpenAIProvider) GetName() string {
+	return p.name
+}
+
+// Chat 发送对话请求（非流式）
+func (p *OpenAIProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	resp, err := p.do(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var chatResp openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, NewProviderError(p.name, "decode_error", "解析响应失败", err)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return nil, NewProviderError(p.name, "empty_response", "API返回空响应", nil)
+	}
+
+	result := chatResp.chatResponse()
+	if !strings.HasPrefix(result.Content, req.AssistantPrefix) {
+		result.Content = req.AssistantPrefix + result.Content
+	}
+	return result, nil
+}
+
+// ChatStream 发送对话请求（流式）
+func (p *OpenAIProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	resp, err := p.do(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan StreamChunk)
+	go func() {
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		if req.AssistantPrefix != "" {
+			chunks <- StreamChunk{Content: req.AssistantPrefix}
+		}
+
+		var usage *Usage
+		finishReason := "" // 收到 finish_reason 后连接断开也算正常结束，部分兼容服务不发送 [DONE]
+		refused := false
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "[DONE]" {
+				if refused {
+					finishReason = FinishRefusal
+				}
+				chunks <- StreamChunk{Done: true, Usage: usage, FinishReason: finishReason}
+				return
+			}
+
+			var streamResp openAIStreamResponse
+			if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
+				chunks <- StreamChunk{Error: NewProviderError(p.name, "decode_error", "解析流式响应失败", err)}
+				return
+			}
+			for _, choice := range streamResp.Choices {
+				// 流式请求只接收第一个回答，忽略服务额外返回的其他回答
+				if choice.Index != 0 {
+					continue
+				}
+				if choice.Delta.Content != "" {
+					chunks <- StreamChunk{Content: choice.Delta.Content}
+				}
+				if choice.Delta.Refusal != "" {
+					refused = true
+					chunks <- StreamChunk{Content: choice.Delta.Refusal}
+				}
+				if choice.FinishReason != "" {
+					finishReason = choice.FinishReason
+				}
+			}
+			if streamResp.Usage != nil {
+				usage = streamResp.Usage
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			chunks <- StreamChunk{Error: NewProviderError(p.name, CodeStreamInterrupted, "读取流式响应失败", err)}
+			return
+		}
+		if finishReason == "" {
+			chunks <- StreamChunk{Error: NewProviderError(p.name, CodeStreamInterrupted, "流式响应在回复完成前中断", io.ErrUnexpectedEOF)}
+			return
+		}
+		if refused {
+			finishReason = FinishRefusal
+		}
+		chunks <- StreamChunk{Done: true, Usage: usage, FinishReason: finishReason}
+	}()
+
+	return chunks, nil
+}
+
+// GetModels 获取可用模型列表
+func (p *OpenAIProvider) GetModels(ctx context.Context) ([]string, error) {
+	var modelsResp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := p.doJSON(ctx, "GET", "/models", nil, "", &modelsResp); err != nil {
+		return nil, err
+	}
+
+	models := make([]string, 0, len(modelsResp.Data))
+	for _, m := range modelsResp.Data {
+		models = append(models, m.ID)
+	}
+	return models, nil
+}
+
+// Moderate 使用 /moderations 接口审核文本内容
+func (p *OpenAIProvider) Moderate(ctx context.Context, input string) (*ModerationResult, error) {
+	payload, err := json.Marshal(map[string]string{"input": input})
+	if err != nil {
+		return nil, NewProviderError(p.name, "request_error", "构建请求失败", err)
+	}
+
+	var modResp struct {
+		Results []struct {
+			Flagged        bool               `json:"flagged"`
+			Categories     map[string]bool    `json:"categories"`
+			CategoryScores map[string]float64 `json:"category_scores"`
+		} `json:"results"`
+	}
+	if err := p.doJSON(ctx, "POST", "/moderations", bytes.NewReader(payload), "application/json", &modResp); err != nil {
+		return nil, err
+	}
+	if len(modResp.Results) == 0 {
+		return nil, NewProviderError(p.name, "empty_response", "API返回空响应", nil)
+	}
+
+	r := modResp.Results[0]
+	return &ModerationResult{
+		Flagged:    r.Flagged,
+		Categories: flaggedCategories(r.Categories, r.CategoryScores),
+		Scores:     r.CategoryScores,
+	}, nil
+}
+
+// ValidateConfig 验证配置
+func (p *OpenAIProvider) ValidateConfig() error {
+	if p.cfg.APIKey == "" && p.cfg.TokenSource == nil {
+		return NewProviderError(p.name, "missing_api_key", "API密钥未设置", nil)
+	}
+	if err := p.cfg.Compat.Validate(); err != nil {
+		return NewProviderError(p.name, "invalid_config", err.Error(), nil)
+	}
+	return nil
+}
+
+// buildRequest 将通用对话请求转换为OpenAI请求结构，未指定的参数使用提供商默认值，字段按兼容设置调整
+func (p *OpenAIProvider) buildRequest(req *ChatRequest, stream bool) openAIRequest {
+	body := openAIRequest{
+		Model:    req.Model,
+		Messages: req.Messages,
+		Stream:   stream,
+		Sampling: req.Sampling.withDefaults(p.cfg.Sampling),
+		User:     p.cfg.User,
+		Metadata: p.cfg.Metadata,
+	}
+	if req.N > 1 && !stream {
+		body.N = req.N
+	}
+	if body.Model == "" {
+		body.Model = p.cfg.Model
+	}
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = p.cfg.MaxTokens
+	}
+	p.cfg.Compat.apply(&body, maxTokens, req.Temperature)
+	// 以未完成的助手消息结尾，兼容的服务会从这里继续生成
+	if req.AssistantPrefix != "" {
+		body.Messages = append(append([]Message{}, req.Messages...), Message{Role: "assistant", Content: req.AssistantPrefix})
+	}
+	return body
+}
+
+// Payload 获取对话请求实际发送的JSON内容，用于演练模式
+func (p *OpenAIProvider) Payload(req *ChatRequest, stream bool) ([]byte, error) {
+	return json.MarshalIndent(p.buildRequest(req, stream), "", "  ")
+}
+
+// Endpoint 获取接口的完整地址
+func (p *OpenAIProvider) Endpoint(path string) string {
+	return p.cfg.BaseURL + path
+}
+
+// do 构建并发送对话请求，返回状态码为200的响应
+func (p *OpenAIProvider) do(ctx context.Context, req *ChatRequest, stream bool) (*http.Response, error) {
+	jsonData, err := json.Marshal(p.buildRequest(req, stream))
+	if err != nil {
+		return nil, NewProviderError(p.name, "request_error", "构建请求失败", err)
+	}
+
+	return p.send(ctx, "POST", "/chat/completions", bytes.NewReader(jsonData), "application/json")
+}
+
+// doJSON 发送请求并将JSON响应解析到out
+func (p *OpenAIProvider) doJSON(ctx context.Context, method, path string, body io.Reader, contentType string, out interface{}) error {
+	resp, err := p.send(ctx, method, path, body, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return NewProviderError(p.name, "decode_error", "解析响应失败", err)
+	}
+	return nil
+}
+
+// send 发送请求，返回状态码为200的响应。认证、重试和限流由传输层的中间件处理
+func (p *OpenAIProvider) send(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, p.cfg.BaseURL+path, body)
+	if err != nil {
+		return nil, NewProviderError(p.name, "request_error", "创建请求失败", err)
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		var te *tokenError
+		if errors.As(err, &te) {
+			return nil, NewProviderError(p.name, CodeAuthError, "认证失败", te.err)
+		}
+		if ctx.Err() == nil && unreachable(err) {
+			return nil, NewProviderError(p.name, CodeNetworkUnreachable, "无法连接到 "+hostOf(p.cfg.BaseURL)+"，请检查网络连接", err)
+		}
+		return nil, NewProviderError(p.name, "request_error", "请求发送失败", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, p.apiError(resp)
+	}
+	return resp, nil
+}
+
+// apiError 将非200响应转换为提供商错误
+func (p *OpenAIProvider) apiError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	return NewProviderError(p.name, fmt.Sprintf("http_%d", resp.StatusCode),
+		fmt.Sprintf("API返回错误 %d: %s", resp.StatusCode, strings.TrimSpace(string(body))), nil)
+}
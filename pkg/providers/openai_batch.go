@@ -0,0 +1,179 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// openAIBatchLine OpenAI批处理输入文件中的一行
+type openAIBatchLine struct {
+	CustomID string        `json:"custom_id"`
+	Method   string        `json:"method"`
+	URL      string        `json:"url"`
+	Body     openAIRequest `json:"body"`
+}
+
+// openAIBatchResultLine OpenAI批处理结果文件或错误文件中的一行
+type openAIBatchResultLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// SubmitBatch 上传请求并创建批处理任务
+func (p *OpenAIProvider) SubmitBatch(ctx context.Context, reqs []BatchRequest) (*BatchInfo, error) {
+	var input bytes.Buffer
+	encoder := json.NewEncoder(&input)
+	for _, r := range reqs {
+		line := openAIBatchLine{
+			CustomID: r.CustomID,
+			Method:   "POST",
+			URL:      "/v1/chat/completions",
+			Body:     p.buildRequest(r.Request, false),
+		}
+		if err := encoder.Encode(line); err != nil {
+			return nil, NewProviderError(p.name, "request_error", "构建批处理文件失败", err)
+		}
+	}
+
+	fileID, err := p.uploadFile(ctx, "batch.jsonl", "batch", input.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	payload, _ := json.Marshal(map[string]string{
+		"input_file_id":     fileID,
+		"endpoint":          "/v1/chat/completions",
+		"completion_window": "24h",
+	})
+
+	info := &BatchInfo{}
+	if err := p.doJSON(ctx, "POST", "/batches", bytes.NewReader(payload), "application/json", info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetBatch 查询批处理任务状态
+func (p *OpenAIProvider) GetBatch(ctx context.Context, id string) (*BatchInfo, error) {
+	info := &BatchInfo{}
+	if err := p.doJSON(ctx, "GET", "/batches/"+id, nil, "", info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// FetchBatchResults 下载已完成批处理任务的结果，包括结果文件和错误文件
+func (p *OpenAIProvider) FetchBatchResults(ctx context.Context, info *BatchInfo) ([]BatchOutput, error) {
+	var outputs []BatchOutput
+	for _, fileID := range []string{info.OutputFileID, info.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+
+		content, err := p.fileContent(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+
+			var line openAIBatchResultLine
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				return nil, NewProviderError(p.name, "decode_error", "解析批处理结果失败", err)
+			}
+			outputs = append(outputs, p.parseBatchResultLine(line))
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, NewProviderError(p.name, "decode_error", "读取批处理结果失败", err)
+		}
+	}
+
+	return outputs, nil
+}
+
+// parseBatchResultLine 将批处理结果行转换为通用结果
+func (p *OpenAIProvider) parseBatchResultLine(line openAIBatchResultLine) BatchOutput {
+	output := BatchOutput{CustomID: line.CustomID}
+
+	if line.Error != nil {
+		output.Error = fmt.Sprintf("%s: %s", line.Error.Code, line.Error.Message)
+		return output
+	}
+	if line.Response == nil {
+		output.Error = "结果中缺少响应"
+		return output
+	}
+	if line.Response.StatusCode != http.StatusOK {
+		output.Error = fmt.Sprintf("API返回错误 %d: %s", line.Response.StatusCode, string(line.Response.Body))
+		return output
+	}
+
+	var resp openAIResponse
+	if err := json.Unmarshal(line.Response.Body, &resp); err != nil || len(resp.Choices) == 0 {
+		output.Error = "无法解析响应内容"
+		return output
+	}
+
+	output.Response = resp.chatResponse()
+	return output
+}
+
+// uploadFile 上传文件，返回文件ID
+func (p *OpenAIProvider) uploadFile(ctx context.Context, filename, purpose string, content []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("purpose", purpose); err != nil {
+		return "", NewProviderError(p.name, "request_error", "构建上传请求失败", err)
+	}
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return "", NewProviderError(p.name, "request_error", "构建上传请求失败", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", NewProviderError(p.name, "request_error", "构建上传请求失败", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", NewProviderError(p.name, "request_error", "构建上传请求失败", err)
+	}
+
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := p.doJSON(ctx, "POST", "/files", &body, writer.FormDataContentType(), &file); err != nil {
+		return "", err
+	}
+	return file.ID, nil
+}
+
+// fileContent 下载文件内容
+func (p *OpenAIProvider) fileContent(ctx context.Context, fileID string) ([]byte, error) {
+	resp, err := p.send(ctx, "GET", "/files/"+fileID+"/content", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, NewProviderError(p.name, "request_error", "下载文件失败", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,348 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PluginPrefix 外部提供商插件可执行文件的名称前缀，如 ai-chat-provider-acme
+const PluginPrefix = "ai-chat-provider-"
+
+// PluginProtocolVersion 插件协议版本
+const PluginProtocolVersion = 1
+
+// PluginKind 外部插件提供商的类型名称
+const PluginKind = "plugin"
+
+func init() {
+	Register(Registration{
+		Kind: PluginKind,
+		New: func(name string, cfg Config) (Provider, error) {
+			path, ok := FindPlugin(name)
+			if !ok {
+				return nil, fmt.Errorf("PATH 中没有找到插件 %s%s", PluginPrefix, name)
+			}
+			return NewPluginProvider(name, path, PluginConfig{
+				APIKey:    cfg.APIKey,
+				BaseURL:   cfg.BaseURL,
+				Model:     cfg.Model,
+				MaxTokens: cfg.MaxTokens,
+				Extra:     cfg.Extra,
+			}), nil
+		},
+		// 插件协议只传递文本消息
+		Capabilities: Capabilities{CapStreaming},
+		Match: func(name string) bool {
+			_, ok := FindPlugin(name)
+			return ok
+		},
+	})
+}
+
+// 插件请求的方法
+const (
+	PluginMethodChat   = "chat"
+	PluginMethodStream = "stream"
+	PluginMethodModels = "models"
+)
+
+// PluginConfig 传给插件的提供商配置，来自配置文件中同名提供商的设置
+type PluginConfig struct {
+	APIKey    string            `json:"api_key,omitempty"`
+	BaseURL   string            `json:"base_url,omitempty"`
+	Model     string            `json:"model,omitempty"`
+	MaxTokens int               `json:"max_tokens,omitempty"`
+	Extra     map[string]string `json:"extra,omitempty"`
+}
+
+// PluginRequest 每次调用时写入插件标准输入的JSON
+type PluginRequest struct {
+	Version int          `json:"version"`
+	Method  string       `json:"method"`
+	Config  PluginConfig `json:"config"`
+	Request *ChatRequest `json:"request,omitempty"`
+}
+
+// PluginReply 插件在标准输出中逐行返回的JSON。
+// chat 返回一行完整回复；stream 每行返回一段内容，最后一行设置 done（可以带上 usage 和 finish_reason）；models 返回一行模型列表。
+// 任何一行设置 error 都表示请求失败
+type PluginReply struct {
+	Content      string   `json:"content,omitempty"`
+	Model        string   `json:"model,omitempty"`
+	Usage        *Usage   `json:"usage,omitempty"`
+	FinishReason string   `json:"finish_reason,omitempty"`
+	Done         bool     `json:"done,omitempty"`
+	Models       []string `json:"models,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	Code         string   `json:"code,omitempty"`
+}
+
+// FindPlugin 在 PATH 中查找名为 ai-chat-provider-<name> 的插件
+func FindPlugin(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	path, err := exec.LookPath(PluginPrefix + name)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// ListPlugins 列出 PATH 中所有插件提供的提供商名称
+func ListPlugins() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		matches, _ := filepath.Glob(filepath.Join(dir, PluginPrefix+"*"))
+		for _, m := range matches {
+			name := strings.TrimPrefix(filepath.Base(m), PluginPrefix)
+			name = strings.TrimSuffix(name, ".exe")
+			if name == "" || seen[name] {
+				continue
+			}
+			if _, ok := FindPlugin(name); ok {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PluginProvider 通过外部可执行文件实现的提供商，每次请求启动一次插件进程，
+// 以标准输入输出交换JSON，便于在不修改本程序的情况下接入私有的后端
+type PluginProvider struct {
+	name string
+	path string
+	cfg  PluginConfig
+}
+
+// NewPluginProvider 创建插件提供商
+func NewPluginProvider(name, path string, cfg PluginConfig) *PluginProvider {
+	return &PluginProvider{name: name, path: path, cfg: cfg}
+}
+
+// GetName 获取提供商名称
+func (p *PluginProvider) GetName() string {
+	return p.name
+}
+
+// Chat 发送对话请求（非流式）
+func (p *PluginProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	reply, err := p.call(ctx, PluginMethodChat, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ChatResponse{
+		Content:      reply.Content,
+		Model:        reply.Model,
+		FinishReason: reply.FinishReason,
+	}
+	if resp.Model == "" {
+		resp.Model = p.model(req)
+	}
+	if reply.Usage != nil {
+		resp.Usage = *reply.Usage
+	}
+	if !strings.HasPrefix(resp.Content, req.AssistantPrefix) {
+		resp.Content = req.AssistantPrefix + resp.Content
+	}
+	return resp, nil
+}
+
+// ChatStream 发送对话请求（流式），插件每输出一行就返回一个数据块
+func (p *PluginProvider) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamChunk, error) {
+	cmd, stdout, stderr, err := p.start(ctx, PluginMethodStream, req)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan StreamChunk)
+	go func() {
+		defer close(chunks)
+
+		// 提前返回时读完剩余输出再等待插件退出，避免插件阻塞在写入上
+		waited := false
+		defer func() {
+			if !waited {
+				io.Copy(io.Discard, stdout)
+				cmd.Wait()
+			}
+		}()
+
+		if req.AssistantPrefix != "" {
+			chunks <- StreamChunk{Content: req.AssistantPrefix}
+		}
+
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+
+			var reply PluginReply
+			if err := json.Unmarshal(line, &reply); err != nil {
+				chunks <- StreamChunk{Error: NewProviderError(p.name, "decode_error", "解析插件输出失败", err)}
+				return
+			}
+			if reply.Error != "" {
+				chunks <- StreamChunk{Error: p.replyError(reply)}
+				return
+			}
+			if reply.Content != "" {
+				chunks <- StreamChunk{Content: reply.Content}
+			}
+			if reply.Done {
+				chunks <- StreamChunk{Done: true, Usage: reply.Usage, FinishReason: reply.FinishReason}
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			chunks <- StreamChunk{Error: NewProviderError(p.name, "stream_error", "读取插件输出失败", err)}
+			return
+		}
+		waited = true
+		if err := cmd.Wait(); err != nil {
+			chunks <- StreamChunk{Error: p.exitError(err, stderr)}
+			return
+		}
+		chunks <- StreamChunk{Done: true}
+	}()
+	return chunks, nil
+}
+
+// GetModels 获取可用模型列表
+func (p *PluginProvider) GetModels(ctx context.Context) ([]string, error) {
+	reply, err := p.call(ctx, PluginMethodModels, nil)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Models, nil
+}
+
+// ValidateConfig 验证配置
+func (p *PluginProvider) ValidateConfig() error {
+	if p.path == "" {
+		return NewProviderError(p.name, "invalid_config", "未找到插件 "+PluginPrefix+p.name, nil)
+	}
+	return nil
+}
+
+// call 启动插件并读取一行回复
+func (p *PluginProvider) call(ctx context.Context, method string, req *ChatRequest) (*PluginReply, error) {
+	cmd, stdout, stderr, err := p.start(ctx, method, req)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := io.ReadAll(stdout)
+	if err != nil {
+		cmd.Wait()
+		return nil, NewProviderError(p.name, "request_error", "读取插件输出失败", err)
+	}
+	waitErr := cmd.Wait()
+
+	// 插件可能在失败前输出了错误信息，优先使用
+	var reply PluginReply
+	line := bytes.TrimSpace(output)
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	if len(line) > 0 {
+		if err := json.Unmarshal(line, &reply); err != nil {
+			return nil, NewProviderError(p.name, "decode_error", "解析插件输出失败", err)
+		}
+		if reply.Error != "" {
+			return nil, p.replyError(reply)
+		}
+	}
+	if waitErr != nil {
+		return nil, p.exitError(waitErr, stderr)
+	}
+	if len(line) == 0 {
+		return nil, NewProviderError(p.name, "decode_error", "插件没有输出任何内容", nil)
+	}
+	return &reply, nil
+}
+
+// start 启动插件进程并写入请求，请求被取消时结束插件进程
+func (p *PluginProvider) start(ctx context.Context, method string, req *ChatRequest) (*exec.Cmd, io.ReadCloser, *bytes.Buffer, error) {
+	// 请求未指定时使用配置中的模型和最大token数
+	if req != nil {
+		r := *req
+		if r.Model == "" {
+			r.Model = p.cfg.Model
+		}
+		if r.MaxTokens == 0 {
+			r.MaxTokens = p.cfg.MaxTokens
+		}
+		req = &r
+	}
+	input, err := json.Marshal(PluginRequest{
+		Version: PluginProtocolVersion,
+		Method:  method,
+		Config:  p.cfg,
+		Request: req,
+	})
+	if err != nil {
+		return nil, nil, nil, NewProviderError(p.name, "request_error", "序列化插件请求失败", err)
+	}
+
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Stdin = bytes.NewReader(append(input, '\n'))
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, nil, NewProviderError(p.name, "request_error", "启动插件失败", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, nil, NewProviderError(p.name, "request_error", "启动插件失败", err)
+	}
+	return cmd, stdout, stderr, nil
+}
+
+// replyError 将插件返回的错误转换为提供商错误
+func (p *PluginProvider) replyError(reply PluginReply) error {
+	code := reply.Code
+	if code == "" {
+		code = "plugin_error"
+	}
+	return NewProviderError(p.name, code, reply.Error, nil)
+}
+
+// exitError 插件异常退出时返回的错误，附带插件标准错误输出的最后一行
+func (p *PluginProvider) exitError(err error, stderr *bytes.Buffer) error {
+	message := "插件异常退出"
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		message += ": " + last
+	}
+	return NewProviderError(p.name, "plugin_error", message, err)
+}
+
+// model 获取响应中的模型名称
+func (p *PluginProvider) model(req *ChatRequest) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	if p.cfg.Model != "" {
+		return p.cfg.Model
+	}
+	return p.name
+}
@@ -0,0 +1,59 @@
+package providers
+
+import "strings"
+
+// Price 模型每百万token的价格（美元）
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// Cost 按价格计算token用量的成本
+func (p Price) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.Input + float64(completionTokens)*p.Output) / 1e6
+}
+
+// builtinPrices 常用模型的公开标价，按模型名称前缀匹配，仅用于估算，
+// 以提供商当前的价格为准，可以在提供商配置中用 input_price、output_price 覆盖
+var builtinPrices = map[string]Price{
+	"gpt-4o":            {2.50, 10.00},
+	"gpt-4o-mini":       {0.15, 0.60},
+	"gpt-4.1":           {2.00, 8.00},
+	"gpt-4.1-mini":      {0.40, 1.60},
+	"gpt-4.1-nano":      {0.10, 0.40},
+	"gpt-4-turbo":       {10.00, 30.00},
+	"gpt-3.5-turbo":     {0.50, 1.50},
+	"o1":                {15.00, 60.00},
+	"o1-mini":           {1.10, 4.40},
+	"o3":                {2.00, 8.00},
+	"o3-mini":           {1.10, 4.40},
+	"o4-mini":           {1.10, 4.40},
+	"deepseek-chat":     {0.27, 1.10},
+	"deepseek-reasoner": {0.55, 2.19},
+	"claude-3-5-sonnet": {3.00, 15.00},
+	"claude-3-5-haiku":  {0.80, 4.00},
+	"claude-3-opus":     {15.00, 75.00},
+	"gemini-1.5-pro":    {1.25, 5.00},
+	"gemini-1.5-flash":  {0.075, 0.30},
+	"gemini-2.0-flash":  {0.10, 0.40},
+}
+
+// LookupPrice 在内置价格表中查找模型的价格，使用匹配的最长前缀，如 gpt-4o-mini-2024-07-18 匹配 gpt-4o-mini
+func LookupPrice(model string) (Price, bool) {
+	model = strings.ToLower(model)
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		// OpenRouter 等网关的模型名称带有厂商前缀，如 openai/gpt-4o
+		model = model[i+1:]
+	}
+
+	best := ""
+	for prefix := range builtinPrices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return builtinPrices[best], true
+}
@@ -9,6 +9,9 @@ import (
 type Message struct {
 	Role    string `json:"role"`    // "user", "assistant", "system"
 	Content string `json:"content"` // 消息内容
+
+	// Images 消息附带的图片，需要提供商支持 vision。有图片时按OpenAI的格式将内容序列化为文本和图片片段的数组
+	Images []Image `json:"-"`
 }
 
 // ChatRequest 对话请求
@@ -18,6 +21,42 @@ type ChatRequest struct {
 	MaxTokens   int       `json:"max_tokens"`  // 最大token数
 	Temperature float64   `json:"temperature"` // 温度参数
 	Stream      bool      `json:"stream"`      // 是否流式响应
+
+	// AssistantPrefix 预填的助手回复开头，模型从这里继续生成，如 "```json"。
+	// 返回的内容包含该前缀
+	AssistantPrefix string `json:"assistant_prefix,omitempty"`
+
+	// N 一次请求返回的回答数，大于1时第一个以外的回答在 ChatResponse.Alternatives 中。
+	// 不支持的服务只返回一个回答或拒绝请求。流式请求只返回一个回答，不发送该参数
+	N int `json:"n,omitempty"`
+
+	// 未设置的采样参数使用提供商配置的默认值
+	Sampling
+}
+
+// Sampling 温度以外的采样参数，nil 或空表示不指定，都未指定时不发送，使用API的默认值
+type Sampling struct {
+	TopP             *float64 `json:"top_p,omitempty"`             // 核采样概率
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`  // 存在惩罚
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"` // 频率惩罚
+	Stop             []string `json:"stop,omitempty"`              // 停止序列
+}
+
+// withDefaults 用默认值补全未指定的参数
+func (s Sampling) withDefaults(defaults Sampling) Sampling {
+	if s.TopP == nil {
+		s.TopP = defaults.TopP
+	}
+	if s.PresencePenalty == nil {
+		s.PresencePenalty = defaults.PresencePenalty
+	}
+	if s.FrequencyPenalty == nil {
+		s.FrequencyPenalty = defaults.FrequencyPenalty
+	}
+	if len(s.Stop) == 0 {
+		s.Stop = defaults.Stop
+	}
+	return s
 }
 
 // ChatResponse 对话响应
@@ -26,8 +65,25 @@ type ChatResponse struct {
 	Model        string `json:"model"`         // 使用的模型
 	Usage        Usage  `json:"usage"`         // 使用统计
 	FinishReason string `json:"finish_reason"` // 结束原因
+
+	// Alternatives 请求多个回答（ChatRequest.N 大于1）时第一个以外的回答，用量已计入 Usage
+	Alternatives []Choice `json:"alternatives,omitempty"`
 }
 
+// Choice 请求多个回答时的一个回答
+type Choice struct {
+	Content      string `json:"content"`
+	FinishReason string `json:"finish_reason,omitempty"`
+}
+
+// 常见的结束原因（ChatResponse.FinishReason），与OpenAI的取值相同
+const (
+	FinishStop          = "stop"           // 正常结束
+	FinishLength        = "length"         // 达到 max_tokens 上限被截断
+	FinishContentFilter = "content_filter" // 内容被提供商的安全策略过滤
+	FinishRefusal       = "refusal"        // 模型拒绝回答，OpenAI 在 message.refusal 中返回拒绝的说明
+)
+
 // Usage 使用统计
 type Usage struct {
 	PromptTokens     int     `json:"prompt_tokens"`     // 输入token数
@@ -41,6 +97,11 @@ type StreamChunk struct {
 	Content string `json:"content"` // 增量内容
 	Done    bool   `json:"done"`    // 是否完成
 	Error   error  `json:"-"`       // 错误信息
+
+	// Usage 提供商返回的用量，只在 Done 的数据块中设置，提供商不返回时为nil，此时可以用 StreamUsage 估算
+	Usage *Usage `json:"usage,omitempty"`
+	// FinishReason 结束原因，只在 Done 的数据块中设置，提供商不返回时为空
+	FinishReason string `json:"finish_reason,omitempty"`
 }
 
 // Provider AI提供商接口
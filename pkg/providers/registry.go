@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Capability 提供商可能支持的功能，命令在发送请求前检查，避免发送注定失败的请求
+type Capability string
+
+const (
+	CapStreaming  Capability = "streaming"  // 流式响应
+	CapTools      Capability = "tools"      // 工具调用（function calling）
+	CapVision     Capability = "vision"     // 图片输入
+	CapEmbeddings Capability = "embeddings" // 文本向量
+)
+
+// AllCapabilities 所有功能
+var AllCapabilities = []Capability{CapStreaming, CapTools, CapVision, CapEmbeddings}
+
+// ParseCapability 解析功能名称
+func ParseCapability(name string) (Capability, error) {
+	for _, c := range AllCapabilities {
+		if string(c) == name {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("未知的功能: %s（可选 streaming、tools、vision、embeddings）", name)
+}
+
+// Capabilities 提供商支持的功能集合
+type Capabilities []Capability
+
+// Has 判断是否支持指定功能
+func (c Capabilities) Has(capability Capability) bool {
+	for _, v := range c {
+		if v == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// Factory 根据提供商名称和配置创建提供商实例
+type Factory func(name string, cfg Config) (Provider, error)
+
+// Registration 注册的提供商类型
+type Registration struct {
+	Kind         string       // 类型名称，如 openai、mock、plugin
+	New          Factory      // 创建提供商实例
+	Capabilities Capabilities // 默认支持的功能，配置文件可以按提供商覆盖
+
+	// Match 判断提供商名称是否固定使用该类型（如 mock、PATH 中的插件），匹配的提供商不需要配置和API密钥。
+	// 为nil时只有作为默认类型时使用
+	Match func(name string) bool
+}
+
+// OpenAIKind 兼容OpenAI的HTTP API，配置文件中的提供商默认使用该类型
+const OpenAIKind = "openai"
+
+var (
+	registryMu sync.RWMutex
+	// registry 按类型名称注册的提供商类型
+	registry = map[string]Registration{}
+)
+
+// Register 注册提供商类型，通常在实现所在文件的 init 中调用。类型名称重复时 panic
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if r.Kind == "" || r.New == nil {
+		panic("providers: 注册的提供商类型缺少名称或构造函数")
+	}
+	if _, exists := registry[r.Kind]; exists {
+		panic("providers: 重复注册提供商类型 " + r.Kind)
+	}
+	registry[r.Kind] = r
+}
+
+// Kinds 所有注册的类型名称
+func Kinds() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	kinds := make([]string, 0, len(registry))
+	for kind := range registry {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Resolve 根据提供商名称确定类型：按类型名称顺序检查固定名称的类型（如 mock、PATH 中的插件），
+// 都不匹配时使用 openai
+func Resolve(name string) Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	kinds := make([]string, 0, len(registry))
+	for kind, r := range registry {
+		if r.Match != nil {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		if registry[kind].Match(name) {
+			return registry[kind]
+		}
+	}
+	return registry[OpenAIKind]
+}
+
+// Standalone 判断提供商是否不需要配置文件中的设置和API密钥即可使用，如内置的 mock 和 PATH 中的插件
+func Standalone(name string) bool {
+	return Resolve(name).Match != nil
+}
+
+// New 按提供商名称对应的类型创建提供商实例
+func New(name string, cfg Config) (Provider, error) {
+	return Resolve(name).New(name, cfg)
+}
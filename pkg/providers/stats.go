@@ -0,0 +1,45 @@
+package providers
+
+import "time"
+
+// Stats 一次请求的耗时和输出速度
+type Stats struct {
+	LatencyMs       int64   `json:"latency_ms"`                  // 从发送请求到收到完整回复的耗时（毫秒）
+	FirstTokenMs    int64   `json:"first_token_ms,omitempty"`    // 流式响应收到第一段内容的耗时（毫秒）
+	TokensPerSecond float64 `json:"tokens_per_second,omitempty"` // 每秒输出的token数
+}
+
+// Timer 记录一次请求的耗时，流式响应时额外记录首个token的时间
+type Timer struct {
+	start      time.Time
+	firstToken time.Time
+}
+
+// StartTimer 开始计时
+func StartTimer() *Timer {
+	return &Timer{start: time.Now()}
+}
+
+// FirstToken 记录收到第一段内容的时间，只记录第一次调用，t 为nil时不做任何操作
+func (t *Timer) FirstToken() {
+	if t != nil && t.firstToken.IsZero() {
+		t.firstToken = time.Now()
+	}
+}
+
+// Stop 结束计时并根据输出的token数计算统计信息。
+// 有首个token时间时按生成内容的耗时计算速度，不计入等待首个token的时间
+func (t *Timer) Stop(completionTokens int) *Stats {
+	end := time.Now()
+	stats := &Stats{LatencyMs: end.Sub(t.start).Milliseconds()}
+
+	generating := end.Sub(t.start)
+	if !t.firstToken.IsZero() {
+		stats.FirstTokenMs = t.firstToken.Sub(t.start).Milliseconds()
+		generating = end.Sub(t.firstToken)
+	}
+	if completionTokens > 0 && generating > 0 {
+		stats.TokensPerSecond = float64(completionTokens) / generating.Seconds()
+	}
+	return stats
+}
@@ -0,0 +1,94 @@
+package providers
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+// messageOverhead 每条消息在角色和格式上额外占用的token数
+const messageOverhead = 4
+
+// EstimateTokens 粗略估算文本的token数：ASCII字符按平均每4个字符一个token计算，
+// 中文等其他字符按每个字符一个token计算
+func EstimateTokens(text string) int {
+	var c tokenCount
+	c.add(text)
+	return c.tokens()
+}
+
+// tokenCount 分别累计ASCII字符数和其他字符数，分段累计的估算与对拼接后的文本估算一致
+type tokenCount struct {
+	ascii int
+	other int
+}
+
+// add 累计一段文本
+func (c *tokenCount) add(text string) {
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			c.ascii++
+		} else {
+			c.other++
+		}
+	}
+}
+
+// tokens 按累计的字符数换算token数
+func (c tokenCount) tokens() int {
+	return (c.ascii+3)/4 + c.other
+}
+
+// EstimateMessages 粗略估算一组消息作为输入时的token数
+func EstimateMessages(messages []Message) int {
+	tokens := 0
+	for _, m := range messages {
+		tokens += messageOverhead + EstimateTokens(m.Content)
+	}
+	return tokens
+}
+
+// StreamUsage 流式响应的用量：边接收边按内容估算输出token数，提供商在结束时返回用量后以其为准。
+// 可以在接收的同时从其他goroutine读取，用于实时显示用量和检查配额
+type StreamUsage struct {
+	mu       sync.Mutex
+	prompt   int        // 估算的输入token数
+	content  tokenCount // 已收到的内容
+	reported *Usage     // 提供商返回的用量
+}
+
+// NewStreamUsage 开始统计一次流式请求的用量，输入token数按请求的消息估算
+func NewStreamUsage(req *ChatRequest) *StreamUsage {
+	return &StreamUsage{prompt: EstimateMessages(req.Messages)}
+}
+
+// Add 累计数据块的内容，数据块带有提供商返回的用量时记录下来
+func (u *StreamUsage) Add(chunk StreamChunk) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.content.add(chunk.Content)
+	if chunk.Usage != nil && (chunk.Usage.TotalTokens > 0 || chunk.Usage.CompletionTokens > 0) {
+		reported := *chunk.Usage
+		if reported.TotalTokens == 0 {
+			reported.TotalTokens = reported.PromptTokens + reported.CompletionTokens
+		}
+		u.reported = &reported
+	}
+}
+
+// Usage 当前的用量，提供商返回过用量时使用返回值，否则按已收到的内容估算
+func (u *StreamUsage) Usage() Usage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.reported != nil {
+		return *u.reported
+	}
+	completion := u.content.tokens()
+	return Usage{PromptTokens: u.prompt, CompletionTokens: completion, TotalTokens: u.prompt + completion}
+}
+
+// Estimated 用量是否为估算值（提供商没有返回用量）
+func (u *StreamUsage) Estimated() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.reported == nil
+}
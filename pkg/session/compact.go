@@ -0,0 +1,165 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// synopsisPrompt 压缩会话时生成摘要的系统提示词
+const synopsisPrompt = `下面是一段对话的较早部分。请写一份简洁的摘要，供继续这段对话时作为背景使用。
+保留用户的目标、已经确认的事实和结论、做出的决定、仍未解决的问题，以及代码、命令、文件名等关键细节。
+使用与对话相同的语言，只输出摘要本身，不要加标题或解释。`
+
+// SynopsisPrefix 摘要消息的开头，用于标明这是压缩后的早期对话
+const SynopsisPrefix = "以下是之前对话的摘要：\n\n"
+
+// archiveDir 压缩前完整会话副本所在的子目录
+const archiveDir = "archive"
+
+// CompactPoint 获取压缩会话时保留原文的起始位置：最近 keep 轮对话从该位置开始原样保留，
+// 之前的用户和助手消息会被替换为摘要。没有可压缩的消息时返回 -1
+func (s *Session) CompactPoint(keep int) int {
+	if keep < 0 {
+		keep = 0
+	}
+
+	start := len(s.Messages)
+	for i := len(s.Messages) - 1; i >= 0 && keep > 0; i-- {
+		if s.Messages[i].Role == "user" {
+			start = i
+			keep--
+		}
+	}
+
+	for _, m := range s.Messages[:start] {
+		if m.Role == "user" || m.Role == "assistant" {
+			return start
+		}
+	}
+	return -1
+}
+
+// Compact 将 start 之前的用户和助手消息替换为一条摘要消息，系统消息和之后的消息原样保留
+func (s *Session) Compact(start int, synopsis string) {
+	now := time.Now()
+	var messages []Message
+	for _, m := range s.Messages[:start] {
+		if m.Role != "user" && m.Role != "assistant" {
+			messages = append(messages, m)
+		}
+	}
+	messages = append(messages, Message{Role: "system", Content: SynopsisPrefix + synopsis, CreatedAt: now})
+	s.Messages = append(messages, s.Messages[start:]...)
+	s.UpdatedAt = now
+}
+
+// GenerateSynopsis 请模型将消息总结为摘要，model 为空时使用提供商默认模型
+func GenerateSynopsis(ctx context.Context, provider providers.Provider, model string, messages []Message) (string, error) {
+	var conversation strings.Builder
+	for _, m := range messages {
+		if m.Role != "user" && m.Role != "assistant" {
+			continue
+		}
+		fmt.Fprintf(&conversation, "%s: %s\n\n", m.Role, m.Content)
+	}
+
+	resp, err := provider.Chat(ctx, &providers.ChatRequest{
+		Model: model,
+		Messages: []providers.Message{
+			{Role: "system", Content: synopsisPrompt},
+			{Role: "user", Content: conversation.String()},
+		},
+		Temperature: 0.3,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	synopsis := strings.TrimSpace(resp.Content)
+	if synopsis == "" {
+		return "", fmt.Errorf("模型返回的摘要为空")
+	}
+	return synopsis, nil
+}
+
+// Archive 将会话的完整副本保存到会话目录下的 archive 子目录，返回副本的路径。
+// 副本不会出现在会话列表中，可用于查阅压缩前的原始对话
+func (st *Store) Archive(s *Session) (string, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	dir := filepath.Join(st.dir, archiveDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("创建归档目录失败: %w", err)
+	}
+
+	data, err := st.marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, s.ID+"-"+time.Now().Format("20060102-150405")+".json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("保存归档失败: %w", err)
+	}
+	return path, nil
+}
+
+// ArchivedSession 归档目录中的一个会话副本
+type ArchivedSession struct {
+	*Session
+	Path       string    // 副本文件路径
+	ArchivedAt time.Time // 归档时间
+}
+
+// Archives 列出归档目录中的会话副本，按归档时间倒序排列，跳过无法读取的文件
+func (st *Store) Archives() ([]*ArchivedSession, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	dir := filepath.Join(st.dir, archiveDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取归档目录失败: %w", err)
+	}
+
+	var archives []*ArchivedSession
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if data, err = st.decrypt(data); err != nil {
+			continue
+		}
+		s := &Session{}
+		if err := json.Unmarshal(data, s); err != nil {
+			continue
+		}
+		archives = append(archives, &ArchivedSession{Session: s, Path: path, ArchivedAt: info.ModTime()})
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].ArchivedAt.After(archives[j].ArchivedAt)
+	})
+	return archives, nil
+}
@@ -0,0 +1,123 @@
+package session
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// KeySize 会话加密密钥的长度（AES-256）
+const KeySize = 32
+
+// encryptedMagic 加密会话文件的开头标记，其后为随机数和密文
+var encryptedMagic = []byte("AICHAT-ENC1\n")
+
+// ErrEncrypted 会话文件已加密，但存储没有设置密钥
+var ErrEncrypted = errors.New("会话已加密，缺少解密密钥")
+
+// SetKey 设置会话文件的加密密钥，使用 AES-256-GCM 加密。
+// encrypt 为 true 时保存的会话和归档都会加密；为 false 时密钥只用于读取已加密的会话，
+// 之后保存的会话为明文。未加密的会话文件始终可以直接读取。
+func (st *Store) SetKey(key []byte, encrypt bool) error {
+	if _, err := newAEAD(key); err != nil {
+		return err
+	}
+	st.SetKeyFunc(func() ([]byte, error) { return key, nil }, encrypt)
+	return nil
+}
+
+// SetKeyFunc 与 SetKey 相同，但在第一次读写加密会话时才调用 fn 获取密钥，
+// 适用于从系统钥匙串等较慢或可能需要用户确认的来源读取密钥
+func (st *Store) SetKeyFunc(fn func() ([]byte, error), encrypt bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.keyFunc = fn
+	st.keyErr = nil
+	st.aead = nil
+	st.encrypt = encrypt
+}
+
+// cipher 获取加密算法，没有设置密钥时返回 ErrEncrypted，调用方需持有锁
+func (st *Store) cipher() (cipher.AEAD, error) {
+	if st.aead != nil {
+		return st.aead, nil
+	}
+	if st.keyFunc == nil {
+		return nil, ErrEncrypted
+	}
+	if st.keyErr != nil {
+		// 获取密钥失败后不再重试，避免列出会话时反复访问钥匙串
+		return nil, st.keyErr
+	}
+	key, err := st.keyFunc()
+	if err == nil {
+		st.aead, err = newAEAD(key)
+	}
+	st.keyErr = err
+	return st.aead, err
+}
+
+// newAEAD 使用密钥创建 AES-256-GCM
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("会话密钥长度应为 %d 字节，实际为 %d 字节", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// IsEncrypted 判断会话文件内容是否已加密
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+// marshal 序列化会话，设置了加密时返回密文
+func (st *Store) marshal(s *Session) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化会话失败: %w", err)
+	}
+	if !st.encrypt {
+		return data, nil
+	}
+	aead, err := st.cipher()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成随机数失败: %w", err)
+	}
+	out := append([]byte{}, encryptedMagic...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, data, nil), nil
+}
+
+// decrypt 解密会话文件，未加密的文件原样返回
+func (st *Store) decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	aead, err := st.cipher()
+	if err != nil {
+		return nil, err
+	}
+	data = data[len(encryptedMagic):]
+	size := aead.NonceSize()
+	if len(data) < size {
+		return nil, fmt.Errorf("加密的会话文件已损坏")
+	}
+	plain, err := aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("解密失败，密钥不正确或文件已损坏")
+	}
+	return plain, nil
+}
@@ -0,0 +1,11 @@
+// Package session 提供基于JSON文件的对话会话存储，与命令行的 session 命令使用相同的格式，
+// 默认保存在 ~/.ai-chat-cli/sessions。
+//
+//	store, err := session.OpenDefault()
+//	s := store.New("openai", "gpt-4o-mini")
+//	s.Append("user", "你好")
+//	s.AppendReply("你好！", nil, nil)
+//	err = store.Save(s)
+//
+//	sessions, err := store.List()
+package session
@@ -0,0 +1,358 @@
+package session
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// ErrNotFound 会话不存在
+var ErrNotFound = errors.New("会话不存在")
+
+// Message 会话中的一条消息
+type Message struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// 助手回复的用量和耗时统计，用于事后分析
+	Usage *providers.Usage `json:"usage,omitempty"`
+	Stats *providers.Stats `json:"stats,omitempty"`
+
+	// 交互模式中用 /pin 标记的重要消息和用 /note 添加的备注
+	Pinned bool   `json:"pinned,omitempty"`
+	Note   string `json:"note,omitempty"`
+
+	// Truncated 回复在生成过程中被中断，内容不完整
+	Truncated bool `json:"truncated,omitempty"`
+
+	// ToolCalls 生成这条回复时依次调用的工具，如导入的对话中代码解释器和网页搜索的调用
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// ToolCall 一次工具调用及其结果，用于事后审查助手执行了哪些操作
+type ToolCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments,omitempty"` // 调用参数，通常是JSON或代码
+	Result    string `json:"result,omitempty"`
+	// Error 工具返回了错误
+	Error bool `json:"error,omitempty"`
+	// DurationMs 从发起调用到返回结果的耗时（毫秒），0表示未知
+	DurationMs int64 `json:"duration_ms,omitempty"`
+}
+
+// Duration 调用的耗时，未知时为0
+func (c ToolCall) Duration() time.Duration {
+	return time.Duration(c.DurationMs) * time.Millisecond
+}
+
+// Session 保存的对话会话
+type Session struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Provider  string    `json:"provider,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Client    string    `json:"client,omitempty"` // 通过网关创建时的客户端名称
+	Source    string    `json:"source,omitempty"` // 导入来源，如 chatgpt、claude
+	Dir       string    `json:"dir,omitempty"`    // 创建会话时的工作目录，用于 chat --continue
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Messages  []Message `json:"messages"`
+}
+
+// Append 追加一条消息，会话没有标题时使用第一条用户消息作为标题
+func (s *Session) Append(role, content string) {
+	now := time.Now()
+	s.Messages = append(s.Messages, Message{Role: role, Content: content, CreatedAt: now})
+	s.UpdatedAt = now
+
+	if s.Title == "" && role == "user" {
+		s.Title = DefaultTitle(content)
+	}
+}
+
+// AppendReply 追加一条助手回复，并记录该回复的用量和耗时统计
+func (s *Session) AppendReply(content string, usage *providers.Usage, stats *providers.Stats) {
+	s.Append("assistant", content)
+	last := &s.Messages[len(s.Messages)-1]
+	last.Usage, last.Stats = usage, stats
+}
+
+// AddTags 添加标签，已有的标签不会重复添加
+func (s *Session) AddTags(tags ...string) {
+	for _, tag := range tags {
+		if !s.HasTag(tag) {
+			s.Tags = append(s.Tags, tag)
+		}
+	}
+}
+
+// RemoveTags 移除标签
+func (s *Session) RemoveTags(tags ...string) {
+	kept := s.Tags[:0]
+	for _, t := range s.Tags {
+		remove := false
+		for _, tag := range tags {
+			if strings.EqualFold(t, tag) {
+				remove = true
+				break
+			}
+		}
+		if !remove {
+			kept = append(kept, t)
+		}
+	}
+	s.Tags = kept
+}
+
+// HasTag 判断会话是否有指定标签，不区分大小写
+func (s *Session) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Marked 获取被标记或有备注的消息的下标
+func (s *Session) Marked() []int {
+	var marked []int
+	for i, m := range s.Messages {
+		if m.Pinned || m.Note != "" {
+			marked = append(marked, i)
+		}
+	}
+	return marked
+}
+
+// ParseTags 解析逗号分隔的标签列表，忽略空标签
+func ParseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ChatMessages 将会话消息转换为提供商请求所需的消息列表
+func (s *Session) ChatMessages() []providers.Message {
+	messages := make([]providers.Message, 0, len(s.Messages))
+	for _, m := range s.Messages {
+		messages = append(messages, providers.Message{Role: m.Role, Content: m.Content})
+	}
+	return messages
+}
+
+// Store 基于JSON文件的会话存储，每个会话保存为一个文件
+type Store struct {
+	dir string
+	mu  sync.Mutex
+
+	keyFunc func() ([]byte, error) // 获取加密密钥，未设置时只能读取明文会话
+	keyErr  error                  // 获取密钥失败的原因
+	aead    cipher.AEAD            // 第一次使用时根据密钥创建
+	encrypt bool                   // 保存时是否加密
+}
+
+// DefaultDir 获取默认会话目录 ~/.ai-chat-cli/sessions
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ai-chat-cli", "sessions"), nil
+}
+
+// NewStore 创建会话存储
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// OpenDefault 打开默认目录下的会话存储
+func OpenDefault() (*Store, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, fmt.Errorf("获取会话目录失败: %w", err)
+	}
+	return NewStore(dir), nil
+}
+
+// New 创建一个新会话（尚未保存）
+func (st *Store) New(provider, model string) *Session {
+	now := time.Now()
+	return &Session{
+		ID:        newID(now),
+		Provider:  provider,
+		Model:     model,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Messages:  []Message{},
+	}
+}
+
+// Get 读取会话
+func (st *Store) Get(id string) (*Session, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.read(id)
+}
+
+// Save 保存会话，先写入临时文件再重命名，避免写入中断导致文件损坏
+func (st *Store) Save(s *Session) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if err := os.MkdirAll(st.dir, 0700); err != nil {
+		return fmt.Errorf("创建会话目录失败: %w", err)
+	}
+
+	data, err := st.marshal(s)
+	if err != nil {
+		return err
+	}
+
+	path := st.path(s.ID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("保存会话失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("保存会话失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除会话
+func (st *Store) Delete(id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if !validID(id) {
+		return ErrNotFound
+	}
+	if err := os.Remove(st.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("删除会话失败: %w", err)
+	}
+	return nil
+}
+
+// List 列出所有会话，按最近更新时间倒序排列
+func (st *Store) List() ([]*Session, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	entries, err := os.ReadDir(st.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取会话目录失败: %w", err)
+	}
+
+	var sessions []*Session
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		s, err := st.read(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			// 缺少解密密钥时所有加密的会话都无法读取，返回错误而不是跳过
+			if errors.Is(err, ErrEncrypted) || st.keyErr != nil {
+				return nil, err
+			}
+			// 跳过损坏的会话文件，不影响其他会话
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+	return sessions, nil
+}
+
+// LatestInDir 获取在指定工作目录中最近更新的会话，没有时返回 ErrNotFound
+func (st *Store) LatestInDir(dir string) (*Session, error) {
+	sessions, err := st.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		if s.Dir == dir {
+			return s, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// read 读取会话文件，调用方需持有锁
+func (st *Store) read(id string) (*Session, error) {
+	if !validID(id) {
+		return nil, ErrNotFound
+	}
+
+	data, err := os.ReadFile(st.path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("读取会话失败: %w", err)
+	}
+
+	if data, err = st.decrypt(data); err != nil {
+		return nil, fmt.Errorf("读取会话 %s 失败: %w", id, err)
+	}
+
+	s := &Session{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("解析会话 %s 失败: %w", id, err)
+	}
+	return s, nil
+}
+
+// path 获取会话文件路径
+func (st *Store) path(id string) string {
+	return filepath.Join(st.dir, id+".json")
+}
+
+// validID 检查会话ID，防止通过ID访问会话目录以外的文件
+func validID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, `/\.`)
+}
+
+// newID 生成按时间排序的会话ID，如 20240102-150405-a1b2c3
+func newID(t time.Time) string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return t.Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+// DefaultTitle 使用消息开头作为默认标题
+func DefaultTitle(content string) string {
+	title := strings.Join(strings.Fields(content), " ")
+	runes := []rune(title)
+	if len(runes) > 40 {
+		return string(runes[:40]) + "..."
+	}
+	return title
+}
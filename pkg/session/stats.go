@@ -0,0 +1,99 @@
+package session
+
+import (
+	"sort"
+	"time"
+)
+
+// 用量统计的分组方式
+const (
+	GroupMonth    = "month"
+	GroupProvider = "provider"
+	GroupModel    = "model"
+)
+
+// UsageGroup 一个分组的会话数、消息数、token用量和成本
+type UsageGroup struct {
+	Key              string
+	Sessions         int
+	Messages         int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+	// Estimated 部分成本按配置的价格估算，而不是提供商返回的
+	Estimated bool
+}
+
+// TotalTokens 输入和输出token总数
+func (g *UsageGroup) TotalTokens() int {
+	return g.PromptTokens + g.CompletionTokens
+}
+
+// PriceFunc 返回提供商每百万输入和输出token的价格（美元），未配置时为0
+type PriceFunc func(provider string) (input, output float64)
+
+// SummarizeUsage 按 by（month、provider 或 model）统计会话的用量，结果按分组名称排序，
+// 按月份统计时使用消息的时间，跨月的会话计入每个有消息的月份。
+// by 为空时所有会话合为一组。回复没有记录成本时用 price 按token数估算，price 可以为 nil
+func SummarizeUsage(sessions []*Session, by string, price PriceFunc) []*UsageGroup {
+	groups := map[string]*UsageGroup{}
+	for _, s := range sessions {
+		seen := map[string]bool{}
+		for _, m := range s.Messages {
+			key := usageKey(s, m, by)
+			g, ok := groups[key]
+			if !ok {
+				g = &UsageGroup{Key: key}
+				groups[key] = g
+			}
+			if !seen[key] {
+				seen[key] = true
+				g.Sessions++
+			}
+			g.Messages++
+			if m.Usage == nil {
+				continue
+			}
+			g.PromptTokens += m.Usage.PromptTokens
+			g.CompletionTokens += m.Usage.CompletionTokens
+			switch {
+			case m.Usage.Cost > 0:
+				g.Cost += m.Usage.Cost
+			case price != nil:
+				in, out := price(s.Provider)
+				if in > 0 || out > 0 {
+					g.Cost += (float64(m.Usage.PromptTokens)*in + float64(m.Usage.CompletionTokens)*out) / 1e6
+					g.Estimated = true
+				}
+			}
+		}
+	}
+
+	result := make([]*UsageGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, g)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// usageKey 消息所属的分组，没有记录提供商或模型时为 "-"
+func usageKey(s *Session, m Message, by string) string {
+	var key string
+	switch by {
+	case GroupMonth:
+		t := m.CreatedAt
+		if t.IsZero() {
+			t = s.CreatedAt
+		}
+		key = t.In(time.Local).Format("2006-01")
+	case GroupProvider:
+		key = s.Provider
+	case GroupModel:
+		key = s.Model
+	}
+	if key == "" {
+		return "-"
+	}
+	return key
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ai-chat-cli/pkg/providers"
+)
+
+// titlePrompt 生成会话标题的系统提示词
+const titlePrompt = `为下面的对话生成一个简短的标题（不超过10个词），概括用户想解决的问题。
+使用与对话相同的语言，只输出标题本身，不要加引号、标点或任何解释。`
+
+// titleInputRunes 生成标题时每条消息最多使用的字数
+const titleInputRunes = 1000
+
+// NeedsTitle 判断会话是否应该自动生成标题：刚完成第一轮对话，且标题仍是第一条用户消息的默认标题
+func (s *Session) NeedsTitle() bool {
+	var firstUser string
+	replies := 0
+	for _, m := range s.Messages {
+		switch m.Role {
+		case "user":
+			if firstUser == "" {
+				firstUser = m.Content
+			}
+		case "assistant":
+			replies++
+		}
+	}
+	return replies == 1 && firstUser != "" && s.Title == DefaultTitle(firstUser)
+}
+
+// GenerateTitle 使用第一轮对话请模型生成简短的会话标题，model 为空时使用提供商默认模型
+func GenerateTitle(ctx context.Context, provider providers.Provider, model string, s *Session) (string, error) {
+	var conversation strings.Builder
+	for _, m := range s.Messages {
+		if m.Role != "user" && m.Role != "assistant" {
+			continue
+		}
+		content := []rune(m.Content)
+		if len(content) > titleInputRunes {
+			content = append(content[:titleInputRunes], []rune("...")...)
+		}
+		fmt.Fprintf(&conversation, "%s: %s\n\n", m.Role, string(content))
+		if m.Role == "assistant" {
+			break
+		}
+	}
+
+	resp, err := provider.Chat(ctx, &providers.ChatRequest{
+		Model: model,
+		Messages: []providers.Message{
+			{Role: "system", Content: titlePrompt},
+			{Role: "user", Content: conversation.String()},
+		},
+		MaxTokens:   30,
+		Temperature: 0.3,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	title, _, _ := strings.Cut(strings.TrimSpace(resp.Content), "\n")
+	title = strings.Trim(strings.TrimSpace(title), "\"'“”‘’「」《》。.")
+	title = strings.TrimSpace(strings.TrimPrefix(title, "标题："))
+	if title == "" {
+		return "", fmt.Errorf("模型返回的标题为空")
+	}
+	return DefaultTitle(title), nil
+}
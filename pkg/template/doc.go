@@ -0,0 +1,9 @@
+// Package template 加载和渲染YAML格式的提示词模板（~/.ai-chat-cli/templates）、预设对话（--seed）
+// 以及对话预设（--preset，~/.ai-chat-cli/presets）。
+//
+//	t, err := template.Load("review")
+//	system, prompt, err := t.Render(map[string]interface{}{"input": code})
+//
+//	seed, err := template.LoadSeed("convo.yaml")
+//	messages := seed.Conversation()
+package template
@@ -0,0 +1,142 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Preset 预设的对话应用：系统提示词、开场白、初始对话以及使用的模型和温度，
+// 用于 chat --preset，把通用的交互模式变成特定用途的小工具
+type Preset struct {
+	Name        string        `yaml:"name"`        // 预设名称
+	Description string        `yaml:"description"` // 预设说明
+	Title       string        `yaml:"title"`       // 保存会话时使用的标题，默认使用说明
+	System      string        `yaml:"system"`      // 系统提示词
+	Greeting    string        `yaml:"greeting"`    // 开始对话时显示的助手开场白，作为第一条助手回复
+	Messages    []SeedMessage `yaml:"messages"`    // few-shot示例等初始消息，位于开场白之前
+	Provider    string        `yaml:"provider"`    // 使用的提供商，为空时使用默认提供商
+	Model       string        `yaml:"model"`       // 使用的模型，为空时使用提供商配置的模型
+	Temperature *float64      `yaml:"temperature"` // 温度参数，为空时使用默认值
+}
+
+// temperature 返回温度参数的指针，用于定义内置预设
+func temperature(t float64) *float64 {
+	return &t
+}
+
+// builtinPresets 内置预设，预设目录中的同名文件优先
+var builtinPresets = map[string]Preset{
+	"brainstorm": {
+		Description: "头脑风暴",
+		System: "You are a creative brainstorming partner. Generate many diverse, concrete ideas, including unconventional ones. " +
+			"Group related ideas, briefly note the upside and the main risk of each, and build on the user's ideas instead of judging them. " +
+			"Answer in the user's language.",
+		Greeting:    "我们来头脑风暴吧！说说你想解决的问题或目标，以及已有的想法和限制条件。",
+		Temperature: temperature(1.0),
+	},
+	"tutor": {
+		Description: "循序渐进的导师",
+		System: "You are a patient tutor. Find out what the learner already knows, explain one step at a time with simple examples, " +
+			"and end each answer with a short question that checks understanding. Do not give away full solutions to exercises unless asked. " +
+			"Answer in the user's language.",
+		Greeting:    "你好！今天想学习什么？先告诉我你对这个主题已经了解多少。",
+		Temperature: temperature(0.5),
+	},
+	"critic": {
+		Description: "严格的评审",
+		System: "You are a rigorous, constructive critic. For whatever the user shares (plans, writing, designs, code), " +
+			"list the most important weaknesses first, explain why each matters, and suggest a concrete fix. Do not pad with praise. " +
+			"Answer in the user's language.",
+		Greeting:    "把需要评审的方案、文章或代码发给我，并说明它的目标和读者。",
+		Temperature: temperature(0.3),
+	},
+}
+
+// PresetDir 获取预设目录路径
+func PresetDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ai-chat-cli", "presets"), nil
+}
+
+// LoadPreset 按名称或文件路径加载预设，名称对应预设目录下的 <名称>.yaml，
+// 目录中没有时使用同名的内置预设
+func LoadPreset(nameOrPath string) (*Preset, error) {
+	path := nameOrPath
+	if !strings.HasSuffix(path, ".yaml") && !strings.HasSuffix(path, ".yml") {
+		dir, err := PresetDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, nameOrPath+".yaml")
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if p, ok := builtinPresets[nameOrPath]; ok {
+				p.Name = nameOrPath
+				return &p, nil
+			}
+			return nil, fmt.Errorf("预设不存在: %s（可用的预设: %s）", nameOrPath, strings.Join(PresetNames(), "、"))
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取预设失败: %w", err)
+	}
+	p := &Preset{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("解析预设 %s 失败: %w", path, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for i, m := range p.Messages {
+		switch m.Role {
+		case "user", "assistant", "system":
+		default:
+			return nil, fmt.Errorf("预设 %s 第 %d 条消息的角色 %q 无效，应为 user、assistant 或 system", p.Name, i+1, m.Role)
+		}
+	}
+	if p.System == "" && p.Greeting == "" && len(p.Messages) == 0 {
+		return nil, fmt.Errorf("预设 %s 中没有 system、greeting 或 messages", p.Name)
+	}
+	return p, nil
+}
+
+// PresetNames 获取内置预设和预设目录中的预设名称
+func PresetNames() []string {
+	seen := map[string]bool{}
+	for name := range builtinPresets {
+		seen[name] = true
+	}
+	if dir, err := PresetDir(); err == nil {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+		for _, f := range files {
+			seen[strings.TrimSuffix(filepath.Base(f), ".yaml")] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Seed 将预设转换为初始对话，开场白作为最后一条助手消息
+func (p *Preset) Seed() *Seed {
+	s := &Seed{Title: p.Title, System: p.System, Messages: append([]SeedMessage(nil), p.Messages...)}
+	if s.Title == "" {
+		s.Title = p.Description
+	}
+	if p.Greeting != "" {
+		s.Messages = append(s.Messages, SeedMessage{Role: "assistant", Content: p.Greeting})
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Seed 预设的初始对话，用于提供few-shot示例或预置助手回复
+type Seed struct {
+	Title    string        `yaml:"title"`    // 保存会话时使用的标题
+	System   string        `yaml:"system"`   // 系统提示词
+	Messages []SeedMessage `yaml:"messages"` // 按顺序排列的初始消息
+}
+
+// SeedMessage 初始对话中的一条消息
+type SeedMessage struct {
+	Role    string `yaml:"role"`    // user、assistant 或 system
+	Content string `yaml:"content"` // 消息内容
+}
+
+// LoadSeed 读取YAML格式的初始对话文件
+func LoadSeed(path string) (*Seed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取对话文件失败: %w", err)
+	}
+
+	s := &Seed{}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("解析对话文件 %s 失败: %w", path, err)
+	}
+
+	for i, m := range s.Messages {
+		switch m.Role {
+		case "user", "assistant", "system":
+		default:
+			return nil, fmt.Errorf("对话文件 %s 第 %d 条消息的角色 %q 无效，应为 user、assistant 或 system", path, i+1, m.Role)
+		}
+		if strings.TrimSpace(m.Content) == "" {
+			return nil, fmt.Errorf("对话文件 %s 第 %d 条消息内容为空", path, i+1)
+		}
+	}
+	if s.System == "" && len(s.Messages) == 0 {
+		return nil, fmt.Errorf("对话文件 %s 中没有 system 或 messages", path)
+	}
+
+	return s, nil
+}
+
+// Conversation 获取完整的初始消息列表，system 字段作为第一条消息
+func (s *Seed) Conversation() []SeedMessage {
+	messages := make([]SeedMessage, 0, len(s.Messages)+1)
+	if s.System != "" {
+		messages = append(messages, SeedMessage{Role: "system", Content: s.System})
+	}
+	return append(messages, s.Messages...)
+}
@@ -0,0 +1,102 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template 提示词模板
+type Template struct {
+	Name        string    `yaml:"name"`        // 模板名称
+	Description string    `yaml:"description"` // 模板说明
+	System      string    `yaml:"system"`      // 系统提示词，支持模板语法
+	Prompt      string    `yaml:"prompt"`      // 用户提示词，支持模板语法
+	Model       string    `yaml:"model"`       // 指定使用的模型
+	Temperature *float64  `yaml:"temperature"` // 指定温度参数
+	Examples    []Example `yaml:"examples"`    // few-shot示例，作为对话消息发送
+}
+
+// Example few-shot示例，按原样作为一组用户消息和助手回复插入到提示词之前
+type Example struct {
+	User      string `yaml:"user"`
+	Assistant string `yaml:"assistant"`
+}
+
+// Dir 获取模板目录路径
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ai-chat-cli", "templates"), nil
+}
+
+// Load 按名称或文件路径加载模板，名称对应模板目录下的 <名称>.yaml
+func Load(nameOrPath string) (*Template, error) {
+	path := nameOrPath
+	if !strings.HasSuffix(path, ".yaml") && !strings.HasSuffix(path, ".yml") {
+		dir, err := Dir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, nameOrPath+".yaml")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取模板失败: %w", err)
+	}
+
+	t := &Template{}
+	if err := yaml.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("解析模板 %s 失败: %w", path, err)
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if t.Prompt == "" {
+		return nil, fmt.Errorf("模板 %s 缺少 prompt 字段", t.Name)
+	}
+	for i, ex := range t.Examples {
+		if strings.TrimSpace(ex.User) == "" || strings.TrimSpace(ex.Assistant) == "" {
+			return nil, fmt.Errorf("模板 %s 的第 %d 个示例需要同时包含 user 和 assistant", t.Name, i+1)
+		}
+	}
+
+	return t, nil
+}
+
+// Render 使用变量渲染模板，返回系统提示词和用户提示词
+func (t *Template) Render(vars map[string]interface{}) (system, prompt string, err error) {
+	if system, err = render(t.Name+".system", t.System, vars); err != nil {
+		return "", "", err
+	}
+	if prompt, err = render(t.Name+".prompt", t.Prompt, vars); err != nil {
+		return "", "", err
+	}
+	return system, prompt, nil
+}
+
+// render 渲染单段模板文本，引用不存在的变量时报错
+func render(name, text string, vars map[string]interface{}) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("解析模板 %s 失败: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("渲染模板 %s 失败: %w", name, err)
+	}
+	return buf.String(), nil
+}